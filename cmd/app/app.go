@@ -4,6 +4,8 @@ import (
 	"context"
 	"interchange/config"
 	"interchange/internal/cron"
+	"interchange/internal/pkg/outbound"
+	"interchange/internal/service"
 	"net/http"
 	"runtime"
 	"strconv"
@@ -42,8 +44,19 @@ func newHttpServer(
 	}
 }
 
-func newHttpClient() *http.Client {
-	return http.DefaultClient
+// newOutboundClients 各 provider 的上游 http client（由外而內）：
+// 整體 / 串流閒置逾時 → 依策略重試 → 每次嘗試回報熔斷器 → 遇 429 暫停對應上游 key 的排隊佇列 → 依 OUTBOUND 設定調校的 Transport
+func newOutboundClients(
+	conf *config.Configuration,
+	upstreamQueueService *service.UpstreamQueueService,
+	upstreamRetryService *service.UpstreamRetryService,
+	circuitBreakerService *service.CircuitBreakerService,
+) (*outbound.Clients, error) {
+	return outbound.NewClients(conf,
+		upstreamQueueService.Transport,
+		circuitBreakerService.Transport,
+		upstreamRetryService.Transport,
+	)
 }
 
 func newApp(
@@ -102,8 +102,9 @@ func main() {
 		return wireCommand(conf, logger)
 	})
 
+	// cobra 已輸出錯誤訊息，這裡只負責以非零狀態結束
 	if err := rootCmd.Execute(); err != nil {
-		panic(err)
+		os.Exit(1)
 	}
 }
 
@@ -141,8 +142,21 @@ func initConfig() {
 		v.WatchConfig()
 		v.OnConfigChange(func(in fsnotify.Event) {
 			fmt.Println("config file changed:", in.Name)
+			// provider 定義不合法時保留原設定
+			var next config.Configuration
+			if err := v.Unmarshal(&next); err != nil {
+				fmt.Println("unmarshal on change failed:", err)
+				return
+			}
+			if err := next.ValidateProviders(); err != nil {
+				fmt.Println("invalid providers on change, keep previous config:", err)
+				return
+			}
+			// provider 定義以 atomic 發佈，請求端經 ProviderDefinitions 讀取，不受下方 Unmarshal 影響
+			conf.PublishProviders(next.Providers)
 			if err := v.Unmarshal(&conf); err != nil {
 				fmt.Println("unmarshal on change failed:", err)
+				return
 			}
 		})
 	}
@@ -152,6 +166,12 @@ func initConfig() {
 	if err := v.Unmarshal(&conf); err != nil {
 		fmt.Println("unmarshal config failed:", err)
 	}
+	if conf != nil {
+		if err := conf.ValidateProviders(); err != nil {
+			panic(fmt.Errorf("invalid providers config: %w", err))
+		}
+		conf.PublishProviders(conf.Providers)
+	}
 
 }
 func bindEnvs(v *viper.Viper, t reflect.Type, path ...string) {
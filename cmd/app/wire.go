@@ -7,8 +7,11 @@ import (
 	"interchange/config"
 	"interchange/internal/command"
 	"interchange/internal/cron"
+	"interchange/internal/database"
 	"interchange/internal/middleware"
 	"interchange/internal/router"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
 
 	"github.com/google/wire"
 	"go.uber.org/zap"
@@ -18,15 +21,15 @@ import (
 func wireApp(*config.Configuration, *zap.Logger) (*App, func(), error) {
 	panic(
 		wire.Build(
-			// database.ProviderSet,
-			// service.ProviderSet,
+			database.ProviderSet,
+			service.ProviderSet,
 			// handler.ProviderSet,
 			middleware.ProviderSet,
 			router.ProviderSet,
 			cron.ProviderSet,
 			newHttpServer,
-			// newHttpClient,
-			// telemetry.ProviderSet,
+			// newOutboundClients,
+			telemetry.ProviderSet,
 			newApp,
 		),
 	)
@@ -34,5 +37,18 @@ func wireApp(*config.Configuration, *zap.Logger) (*App, func(), error) {
 
 // wireCommand init application.
 func wireCommand(*config.Configuration, *zap.Logger) (*command.Command, func(), error) {
-	panic(wire.Build(command.ProviderSet))
+	panic(
+		wire.Build(
+			database.ProviderSet,
+			telemetry.NewTrace,
+			service.NewInvoiceService,
+			service.NewUserService,
+			service.NewUserAPIKeyService,
+			service.NewEntityCacheService,
+			service.NewActivityBufferService,
+			service.NewAuditService,
+			service.NewNotificationService,
+			command.ProviderSet,
+		),
+	)
 }
@@ -12,8 +12,14 @@ import (
 	"interchange/internal/command"
 	command2 "interchange/internal/command/handler"
 	"interchange/internal/cron"
+	"interchange/internal/database/client"
+	repository3 "interchange/internal/database/fluentd/repository"
+	"interchange/internal/database/mongodb/repository"
+	repository2 "interchange/internal/database/redis/repository"
 	"interchange/internal/middleware"
 	"interchange/internal/router"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
 )
 
 import (
@@ -30,16 +36,148 @@ func wireApp(configuration *config.Configuration, zapLogger *zap.Logger) (*App,
 	response := middleware.NewResponse(zapLogger, configuration)
 	engine := router.NewRouter(configuration, recovery, cors, middlewareLogger, response)
 	server := newHttpServer(configuration, engine)
-	cronCron := cron.NewCron(zapLogger)
+	trace, err := telemetry.NewTrace(configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	mongoClient, cleanup, err := client.NewMongoClient(zapLogger, configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	jobRepository := repository.NewJobRepository(trace, mongoClient)
+	jobRunRepository := repository.NewJobRunRepository(configuration, trace, mongoClient)
+	redisClient, cleanup2, err := client.NewRedisClient(zapLogger, configuration)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	jobLockRepository := repository2.NewJobLockRepository(trace, redisClient)
+	jobService, cleanup3 := service.NewJobService(trace, zapLogger, configuration, jobRepository, jobRunRepository, jobLockRepository)
+	userRepository := repository.NewUserRepository(trace, mongoClient)
+	userAPIKeyRepository := repository.NewUserAPIKeyRepository(trace, mongoClient)
+	rateLimiterRepository := repository2.NewRateLimiterRepository(trace, redisClient)
+	entityCacheRepository := repository2.NewEntityCacheRepository(trace, redisClient)
+	entityCacheService, cleanup4 := service.NewEntityCacheService(trace, zapLogger, configuration, userRepository, userAPIKeyRepository, entityCacheRepository)
+	activityBufferService, cleanup5 := service.NewActivityBufferService(trace, zapLogger, configuration, userRepository, userAPIKeyRepository)
+	auditLogRepository := repository.NewAuditLogRepository(trace, mongoClient)
+	fluentdClient, err := client.NewFluentdClient(zapLogger, configuration)
+	if err != nil {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	logRepository := repository3.NewLogRepository(configuration, fluentdClient, trace)
+	auditService := service.NewAuditService(trace, zapLogger, configuration, auditLogRepository, logRepository)
+	notificationRuleRepository := repository.NewNotificationRuleRepository(trace, mongoClient)
+	notificationDeliveryRepository := repository.NewNotificationDeliveryRepository(configuration, trace, mongoClient)
+	usageRecordRepository := repository.NewUsageRecordRepository(configuration, trace, mongoClient)
+	notificationRepository := repository2.NewNotificationRepository(trace, redisClient)
+	notificationService, cleanup6, err := service.NewNotificationService(trace, zapLogger, configuration, notificationRuleRepository, notificationDeliveryRepository, userAPIKeyRepository, usageRecordRepository, notificationRepository, entityCacheService)
+	if err != nil {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	userAPIKeyService := service.NewUserAPIKeyService(trace, userRepository, userAPIKeyRepository, rateLimiterRepository, configuration, zapLogger, entityCacheService, activityBufferService, auditService, notificationService)
+	apiKeyExpiryJob := cron.NewAPIKeyExpiryJob(zapLogger, configuration, userAPIKeyService)
+	quotaResetJob := cron.NewQuotaResetJob(configuration, userAPIKeyService)
+	lastSeenFlushJob := cron.NewLastSeenFlushJob(configuration, activityBufferService)
+	usageRollupRepository := repository.NewUsageRollupRepository(trace, mongoClient)
+	organizationRepository := repository.NewOrganizationRepository(trace, mongoClient)
+	quotaRepository := repository2.NewQuotaRepository(trace, redisClient)
+	quotaService := service.NewQuotaService(trace, configuration, userRepository, organizationRepository, quotaRepository, entityCacheService)
+	creditAccountRepository := repository.NewCreditAccountRepository(trace, mongoClient)
+	creditLedgerRepository := repository.NewCreditLedgerRepository(trace, mongoClient)
+	creditService := service.NewCreditService(trace, zapLogger, configuration, creditAccountRepository, creditLedgerRepository, organizationRepository, quotaService, entityCacheService)
+	usageService := service.NewUsageService(trace, zapLogger, configuration, logRepository, usageRecordRepository, usageRollupRepository, quotaService, creditService)
+	usageRollupJob := cron.NewUsageRollupJob(configuration, usageService)
+	lifecycleService := service.NewLifecycleService(trace, zapLogger, configuration, userRepository, userAPIKeyRepository, entityCacheService, notificationService, auditService)
+	inactivityJob := cron.NewInactivityJob(configuration, lifecycleService)
+	v := cron.NewJobDefinitions(apiKeyExpiryJob, quotaResetJob, lastSeenFlushJob, usageRollupJob, inactivityJob)
+	cronCron, err := cron.NewCron(zapLogger, jobService, v)
+	if err != nil {
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	app := newApp(configuration, zapLogger, server, cronCron)
 	return app, func() {
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
 	}, nil
 }
 
 // wireCommand init application.
 func wireCommand(configuration *config.Configuration, zapLogger *zap.Logger) (*command.Command, func(), error) {
 	exampleHandler := command2.NewExampleHandler(zapLogger)
-	commandCommand := command.NewCommand(exampleHandler)
+	trace, err := telemetry.NewTrace(configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	mongoClient, cleanup, err := client.NewMongoClient(zapLogger, configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	usageRecordRepository := repository.NewUsageRecordRepository(configuration, trace, mongoClient)
+	invoiceRepository := repository.NewInvoiceRepository(trace, mongoClient)
+	invoiceService := service.NewInvoiceService(trace, zapLogger, configuration, usageRecordRepository, invoiceRepository)
+	invoiceHandler := command2.NewInvoiceHandler(zapLogger, invoiceService)
+	userRepository := repository.NewUserRepository(trace, mongoClient)
+	userAPIKeyRepository := repository.NewUserAPIKeyRepository(trace, mongoClient)
+	redisClient, cleanup2, err := client.NewRedisClient(zapLogger, configuration)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	entityCacheRepository := repository2.NewEntityCacheRepository(trace, redisClient)
+	entityCacheService, cleanup3 := service.NewEntityCacheService(trace, zapLogger, configuration, userRepository, userAPIKeyRepository, entityCacheRepository)
+	activityBufferService, cleanup4 := service.NewActivityBufferService(trace, zapLogger, configuration, userRepository, userAPIKeyRepository)
+	auditLogRepository := repository.NewAuditLogRepository(trace, mongoClient)
+	fluentdClient, err := client.NewFluentdClient(zapLogger, configuration)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	logRepository := repository3.NewLogRepository(configuration, fluentdClient, trace)
+	auditService := service.NewAuditService(trace, zapLogger, configuration, auditLogRepository, logRepository)
+	userService := service.NewUserService(trace, userRepository, entityCacheService, activityBufferService, auditService)
+	rateLimiterRepository := repository2.NewRateLimiterRepository(trace, redisClient)
+	notificationRuleRepository := repository.NewNotificationRuleRepository(trace, mongoClient)
+	notificationDeliveryRepository := repository.NewNotificationDeliveryRepository(configuration, trace, mongoClient)
+	notificationRepository := repository2.NewNotificationRepository(trace, redisClient)
+	notificationService, cleanup5, err := service.NewNotificationService(trace, zapLogger, configuration, notificationRuleRepository, notificationDeliveryRepository, userAPIKeyRepository, usageRecordRepository, notificationRepository, entityCacheService)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	userAPIKeyService := service.NewUserAPIKeyService(trace, userRepository, userAPIKeyRepository, rateLimiterRepository, configuration, zapLogger, entityCacheService, activityBufferService, auditService, notificationService)
+	adminHandler := command2.NewAdminHandler(zapLogger, userService, userAPIKeyService)
+	commandCommand := command.NewCommand(exampleHandler, invoiceHandler, adminHandler)
 	return commandCommand, func() {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
 	}, nil
 }
@@ -0,0 +1,44 @@
+package config
+
+// Anomaly 每把 API Key 的線上異常偵測：速率暴增（相對 EWMA 基準）、新來源 IP / User-Agent、改用未見過的模型、錯誤率暴增。
+// 狀態存於 Redis，所有 replica 共用；同一把 key 同一訊號在冷卻時間內只處置一次。
+// 各訊號的處置為 log / reverify / throttle / suspend，空字串視為 log
+type Anomaly struct {
+	// 總開關
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 統計視窗秒數（速率、錯誤率與限流都以此為單位；預設 60）
+	WindowSeconds int `mapstructure:"WINDOW_SECONDS" json:"windowSeconds" yaml:"windowSeconds"`
+	// 速率基準的 EWMA 平滑係數（0~1，越大越快跟上近期用量；預設 0.1）
+	BaselineAlpha float64 `mapstructure:"BASELINE_ALPHA" json:"baselineAlpha" yaml:"baselineAlpha"`
+	// 學習期：key 開始使用後的前幾個視窗只學習不判斷（預設 60）
+	LearningWindows int64 `mapstructure:"LEARNING_WINDOWS" json:"learningWindows" yaml:"learningWindows"`
+	// 目前視窗請求數超過基準幾倍視為暴增（預設 5）
+	RateMultiplier float64 `mapstructure:"RATE_MULTIPLIER" json:"rateMultiplier" yaml:"rateMultiplier"`
+	// 視窗內至少幾次請求才判斷速率暴增（預設 30）
+	RateMinRequests int64 `mapstructure:"RATE_MIN_REQUESTS" json:"rateMinRequests" yaml:"rateMinRequests"`
+	// 已見過的 IP / User-Agent / 模型保留小時數，期間內未再出現視為新值（預設 168）
+	SeenTTLHours int `mapstructure:"SEEN_TTL_HOURS" json:"seenTTLHours" yaml:"seenTTLHours"`
+	// 每種來源最多記錄幾個值；超過後不再判斷新值（例如共用的 CI key；預設 50）
+	MaxTrackedValues int64 `mapstructure:"MAX_TRACKED_VALUES" json:"maxTrackedValues" yaml:"maxTrackedValues"`
+	// 視窗內上游或伺服器錯誤（HTTP >= 500；不含限流 429 與 4xx 參數錯誤）比例門檻（預設 0.5）
+	ErrorRateThreshold float64 `mapstructure:"ERROR_RATE_THRESHOLD" json:"errorRateThreshold" yaml:"errorRateThreshold"`
+	// 視窗內至少幾次請求才判斷錯誤率（預設 20）
+	ErrorMinRequests int64 `mapstructure:"ERROR_MIN_REQUESTS" json:"errorMinRequests" yaml:"errorMinRequests"`
+	// throttle 處置持續秒數（預設 900）
+	ThrottleSeconds int `mapstructure:"THROTTLE_SECONDS" json:"throttleSeconds" yaml:"throttleSeconds"`
+	// throttle 期間每個視窗最多放行的請求數（預設 10）
+	ThrottleLimit int64 `mapstructure:"THROTTLE_LIMIT" json:"throttleLimit" yaml:"throttleLimit"`
+	// 同一把 key 同一訊號的處置冷卻秒數（預設 900）
+	CooldownSeconds int `mapstructure:"COOLDOWN_SECONDS" json:"cooldownSeconds" yaml:"cooldownSeconds"`
+	// 重新驗證碼有效分鐘數（預設 60）；驗證碼只送到明確指定擁有者或該 key 的通知規則，沒有時寄到使用者信箱，
+	// 兩者皆無的擁有者收不到驗證碼，需由管理端解除
+	ReverifyCodeTTLMinutes int `mapstructure:"REVERIFY_CODE_TTL_MINUTES" json:"reverifyCodeTTLMinutes" yaml:"reverifyCodeTTLMinutes"`
+	// 異常事件保留天數，超過由 Mongo 自動刪除（0 表示不刪除；預設 30）
+	EventRetentionDays int `mapstructure:"EVENT_RETENTION_DAYS" json:"eventRetentionDays" yaml:"eventRetentionDays"`
+	// 各訊號的處置
+	RateSpikeAction    string `mapstructure:"RATE_SPIKE_ACTION" json:"rateSpikeAction" yaml:"rateSpikeAction"`
+	NewClientIPAction  string `mapstructure:"NEW_CLIENT_IP_ACTION" json:"newClientIPAction" yaml:"newClientIPAction"`
+	NewUserAgentAction string `mapstructure:"NEW_USER_AGENT_ACTION" json:"newUserAgentAction" yaml:"newUserAgentAction"`
+	ModelChangeAction  string `mapstructure:"MODEL_CHANGE_ACTION" json:"modelChangeAction" yaml:"modelChangeAction"`
+	ErrorBurstAction   string `mapstructure:"ERROR_BURST_ACTION" json:"errorBurstAction" yaml:"errorBurstAction"`
+}
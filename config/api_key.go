@@ -0,0 +1,7 @@
+package config
+
+// APIKey API Key 生命週期：未指定到期時間的 provider 權限套用預設期限，過期的權限由排程（JOB.API_KEY_EXPIRY_SPEC）標記為 expired
+type APIKey struct {
+	// 建立時未帶 expireTime 的預設有效天數（0 表示不設期限）
+	DefaultLifetimeDays int `mapstructure:"DEFAULT_LIFETIME_DAYS" json:"defaultLifetimeDays" yaml:"defaultLifetimeDays"`
+}
@@ -0,0 +1,9 @@
+package config
+
+// Audit 稽核紀錄：管理端與 API Key 生命週期的異動寫入 Mongo（只新增不修改），可同時轉送 Fluentd
+type Audit struct {
+	// 同時送到 Fluentd（tag 為 TAG_PREFIX + interchange_audit_log）
+	ForwardFluentd bool `mapstructure:"FORWARD_FLUENTD" json:"forwardFluentd" yaml:"forwardFluentd"`
+	// 沒有對應 service 紀錄的管理端異動請求（POST / PUT / PATCH / DELETE）也記一筆 admin.request
+	RecordAdminRequests bool `mapstructure:"RECORD_ADMIN_REQUESTS" json:"recordAdminRequests" yaml:"recordAdminRequests"`
+}
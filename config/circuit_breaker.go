@@ -0,0 +1,21 @@
+package config
+
+// CircuitBreaker 依 provider + endpoint 的熔斷設定
+type CircuitBreaker struct {
+	// 是否啟用
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 統計視窗秒數（預設 60）
+	WindowSeconds int `mapstructure:"WINDOW_SECONDS" json:"windowSeconds" yaml:"windowSeconds"`
+	// 視窗內最少請求數，未達不判斷（預設 20）
+	MinRequests int `mapstructure:"MIN_REQUESTS" json:"minRequests" yaml:"minRequests"`
+	// 失敗率（含慢請求）達此比例即熔斷（預設 0.5）
+	FailureRateThreshold float64 `mapstructure:"FAILURE_RATE_THRESHOLD" json:"failureRateThreshold" yaml:"failureRateThreshold"`
+	// 超過此毫秒視為慢請求並計入失敗（0 代表不計）
+	SlowCallThresholdMs int `mapstructure:"SLOW_CALL_THRESHOLD_MS" json:"slowCallThresholdMs" yaml:"slowCallThresholdMs"`
+	// 熔斷持續秒數，之後進入半開試探（預設 30）
+	OpenSeconds int `mapstructure:"OPEN_SECONDS" json:"openSeconds" yaml:"openSeconds"`
+	// 半開時放行的探測請求數，全部成功才恢復（預設 3）
+	HalfOpenMaxProbes int `mapstructure:"HALF_OPEN_MAX_PROBES" json:"halfOpenMaxProbes" yaml:"halfOpenMaxProbes"`
+	// 是否透過 Redis 在多個 replica 間共享熔斷狀態
+	SharedState bool `mapstructure:"SHARED_STATE" json:"sharedState" yaml:"sharedState"`
+}
@@ -0,0 +1,15 @@
+package config
+
+// Coalescing 相同的冪等上游呼叫（models 列表、確定性 chat / embedding）合併為一次
+type Coalescing struct {
+	// 總開關（程序內合併）
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 透過 Redis 跨 replica 合併
+	Distributed bool `mapstructure:"DISTRIBUTED" json:"distributed" yaml:"distributed"`
+	// 跨 replica 時 leader 鎖的租約毫秒數，也是其他 replica 等待的上限（預設 30000）
+	LockTTLMs int `mapstructure:"LOCK_TTL_MS" json:"lockTTLMs" yaml:"lockTTLMs"`
+	// leader 結果在 Redis 保留的毫秒數（預設 5000）
+	ResultTTLMs int `mapstructure:"RESULT_TTL_MS" json:"resultTTLMs" yaml:"resultTTLMs"`
+	// 等待 leader 結果的輪詢間隔毫秒數（預設 50）
+	PollIntervalMs int `mapstructure:"POLL_INTERVAL_MS" json:"pollIntervalMs" yaml:"pollIntervalMs"`
+}
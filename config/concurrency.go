@@ -0,0 +1,25 @@
+package config
+
+// Concurrency 分散式並行上限（semaphore）設定
+type Concurrency struct {
+	// 槽位租約秒數；實例崩潰時最晚於此秒數後釋放（預設 30）
+	LeaseSeconds int `mapstructure:"LEASE_SECONDS" json:"leaseSeconds" yaml:"leaseSeconds"`
+	// 排隊模式下重試取得槽位的間隔毫秒（預設 100）
+	PollIntervalMs int `mapstructure:"POLL_INTERVAL_MS" json:"pollIntervalMs" yaml:"pollIntervalMs"`
+	// 排隊模式未設定 queueTimeoutMs 時的預設等待毫秒（預設 10000）
+	DefaultQueueTimeoutMs int `mapstructure:"DEFAULT_QUEUE_TIMEOUT_MS" json:"defaultQueueTimeoutMs" yaml:"defaultQueueTimeoutMs"`
+	// 上游飽和時的公平排隊
+	Queue UpstreamQueue `mapstructure:"QUEUE" json:"queue" yaml:"queue"`
+}
+
+// UpstreamQueue 每個上游 provider key 的公平排隊設定（依 API Key 加權、依優先層級出列）
+type UpstreamQueue struct {
+	// 是否啟用
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 最長排隊等待毫秒（預設 30000）
+	MaxWaitMs int `mapstructure:"MAX_WAIT_MS" json:"maxWaitMs" yaml:"maxWaitMs"`
+	// 每個上游 key 最多排隊數，超過直接回 429（0 代表不限）
+	MaxDepth int `mapstructure:"MAX_DEPTH" json:"maxDepth" yaml:"maxDepth"`
+	// 上游回 429 但未帶 Retry-After 時的暫停毫秒（預設 1000）
+	DefaultPauseMs int `mapstructure:"DEFAULT_PAUSE_MS" json:"defaultPauseMs" yaml:"defaultPauseMs"`
+}
@@ -1,10 +1,38 @@
 package config
 
+import (
+	"sync/atomic"
+)
+
 type Configuration struct {
-	App       App             `mapstructure:"APP" json:"app" yaml:"app"`
-	Redis     Redis           `mapstructure:"REDIS" json:"redis" yaml:"redis"`
-	Log       Log             `mapstructure:"LOG" json:"log" yaml:"log"`
-	MongoDB   MongoDB         `mapstructure:"MONGODB" json:"mongodb" yaml:"mongodb"`
-	Telemetry TelemetryConfig `mapstructure:"TELEMETRY" yaml:"telemetry"`
-	Fluentd   Fluentd         `mapstructure:"FLUENTD" yaml:"fluentd"`
+	App            App                 `mapstructure:"APP" json:"app" yaml:"app"`
+	Redis          Redis               `mapstructure:"REDIS" json:"redis" yaml:"redis"`
+	Log            Log                 `mapstructure:"LOG" json:"log" yaml:"log"`
+	MongoDB        MongoDB             `mapstructure:"MONGODB" json:"mongodb" yaml:"mongodb"`
+	Telemetry      TelemetryConfig     `mapstructure:"TELEMETRY" yaml:"telemetry"`
+	Fluentd        Fluentd             `mapstructure:"FLUENTD" yaml:"fluentd"`
+	Pricing        Pricing             `mapstructure:"PRICING" json:"pricing" yaml:"pricing"`
+	Concurrency    Concurrency         `mapstructure:"CONCURRENCY" json:"concurrency" yaml:"concurrency"`
+	Retry          Retry               `mapstructure:"RETRY" json:"retry" yaml:"retry"`
+	CircuitBreaker CircuitBreaker      `mapstructure:"CIRCUIT_BREAKER" json:"circuitBreaker" yaml:"circuitBreaker"`
+	Outbound       Outbound            `mapstructure:"OUTBOUND" json:"outbound" yaml:"outbound"`
+	Providers      map[string]Provider `mapstructure:"PROVIDERS" json:"providers" yaml:"providers"`
+	ResponseCache  ResponseCache       `mapstructure:"RESPONSE_CACHE" json:"responseCache" yaml:"responseCache"`
+	SemanticCache  SemanticCache       `mapstructure:"SEMANTIC_CACHE" json:"semanticCache" yaml:"semanticCache"`
+	Coalescing     Coalescing          `mapstructure:"COALESCING" json:"coalescing" yaml:"coalescing"`
+	EntityCache    EntityCache         `mapstructure:"ENTITY_CACHE" json:"entityCache" yaml:"entityCache"`
+	UsageRecord    UsageRecord         `mapstructure:"USAGE_RECORD" json:"usageRecord" yaml:"usageRecord"`
+	Credit         Credit              `mapstructure:"CREDIT" json:"credit" yaml:"credit"`
+	Invoice        Invoice             `mapstructure:"INVOICE" json:"invoice" yaml:"invoice"`
+	Notification   Notification        `mapstructure:"NOTIFICATION" json:"notification" yaml:"notification"`
+	APIKey         APIKey              `mapstructure:"API_KEY" json:"apiKey" yaml:"apiKey"`
+	Job            Job                 `mapstructure:"JOB" json:"job" yaml:"job"`
+	Lifecycle      Lifecycle           `mapstructure:"LIFECYCLE" json:"lifecycle" yaml:"lifecycle"`
+	Audit          Audit               `mapstructure:"AUDIT" json:"audit" yaml:"audit"`
+	Maintenance    Maintenance         `mapstructure:"MAINTENANCE" json:"maintenance" yaml:"maintenance"`
+	Anomaly        Anomaly             `mapstructure:"ANOMALY" json:"anomaly" yaml:"anomaly"`
+	Session        Session             `mapstructure:"SESSION" json:"session" yaml:"session"`
+
+	// 熱更新後生效中的 provider 定義（由 PublishProviders 寫入，ProviderDefinitions 讀取）
+	providers atomic.Pointer[map[string]Provider]
 }
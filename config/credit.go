@@ -0,0 +1,9 @@
+package config
+
+// Credit 預付額度：有帳戶的使用者 / 組織依 Pricing 計價逐筆扣款，餘額不足時拒絕請求（402）
+type Credit struct {
+	// 總開關（關閉時不檢查餘額也不扣款）
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 餘額低於或等於此值時拒絕請求（預設 0）
+	MinBalance float64 `mapstructure:"MIN_BALANCE" json:"minBalance" yaml:"minBalance"`
+}
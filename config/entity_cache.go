@@ -0,0 +1,17 @@
+package config
+
+// EntityCache 驗證熱路徑上 API Key / 使用者文件的兩層快取（程序內 LRU + Redis），以及 lastSeen / usedCount 的批次寫入
+type EntityCache struct {
+	// 總開關；關閉時每個請求直接讀寫 Mongo
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 每種文件的 LRU 筆數上限（預設 10000）
+	LocalSize int `mapstructure:"LOCAL_SIZE" json:"localSize" yaml:"localSize"`
+	// 程序內快取秒數；失效通知遺失時的最長不一致時間（預設 30）
+	LocalTTLSeconds int `mapstructure:"LOCAL_TTL_SECONDS" json:"localTTLSeconds" yaml:"localTTLSeconds"`
+	// Redis 快取秒數（預設 300）
+	RedisTTLSeconds int `mapstructure:"REDIS_TTL_SECONDS" json:"redisTTLSeconds" yaml:"redisTTLSeconds"`
+	// 加密 Redis 中 provider key 的密鑰；未設定時 API Key 文件只快取於程序內，不寫入 Redis
+	SecretKey string `mapstructure:"SECRET_KEY" json:"-" yaml:"secretKey"`
+	// lastSeen / usedCount 批次寫入 Mongo 的間隔毫秒數（預設 5000）
+	FlushIntervalMs int `mapstructure:"FLUSH_INTERVAL_MS" json:"flushIntervalMs" yaml:"flushIntervalMs"`
+}
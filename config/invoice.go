@@ -0,0 +1,13 @@
+package config
+
+// Invoice 月結帳單（內部 chargeback）：依已儲存的用量紀錄與計價產生，產生後不再變動
+type Invoice struct {
+	// 帳單開立單位名稱（顯示於 HTML 帳單）
+	IssuerName string `mapstructure:"ISSUER_NAME" json:"issuerName" yaml:"issuerName"`
+	// 加成比例（0.1 = 10%），以用量小計計算
+	MarkupRate float64 `mapstructure:"MARKUP_RATE" json:"markupRate" yaml:"markupRate"`
+	// 稅率（0.05 = 5%），以小計加成後金額計算
+	TaxRate float64 `mapstructure:"TAX_RATE" json:"taxRate" yaml:"taxRate"`
+	// 帳期切點時區（IANA，預設 UTC）
+	Timezone string `mapstructure:"TIMEZONE" json:"timezone" yaml:"timezone"`
+}
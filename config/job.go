@@ -0,0 +1,22 @@
+package config
+
+// Job 排程工作：每次執行以 Redis 租約鎖確保只在一個 replica 上跑，執行紀錄寫入 Mongo。
+// 排程為 cron 格式（含秒），空字串使用各工作的預設值
+type Job struct {
+	// 分散式鎖租約秒數，執行中會定期續約（預設 300）
+	LockTTLSeconds int `mapstructure:"LOCK_TTL_SECONDS" json:"lockTTLSeconds" yaml:"lockTTLSeconds"`
+	// 執行紀錄保留天數，超過由 Mongo 自動刪除（0 表示不刪除；預設 30）
+	HistoryRetentionDays int `mapstructure:"HISTORY_RETENTION_DAYS" json:"historyRetentionDays" yaml:"historyRetentionDays"`
+	// API Key 過期掃描（預設每 5 分鐘）
+	APIKeyExpirySpec string `mapstructure:"API_KEY_EXPIRY_SPEC" json:"apiKeyExpirySpec" yaml:"apiKeyExpirySpec"`
+	// provider 限流視窗到期後歸零 Mongo 已用次數（預設每小時）
+	QuotaResetSpec string `mapstructure:"QUOTA_RESET_SPEC" json:"quotaResetSpec" yaml:"quotaResetSpec"`
+	// 強制寫回本機累積的 lastSeen / 用量（每個 replica 各自執行；預設每 5 分鐘）
+	LastSeenFlushSpec string `mapstructure:"LAST_SEEN_FLUSH_SPEC" json:"lastSeenFlushSpec" yaml:"lastSeenFlushSpec"`
+	// 用量紀錄彙總為每日 rollup（預設每小時第 10 分）
+	UsageRollupSpec string `mapstructure:"USAGE_ROLLUP_SPEC" json:"usageRollupSpec" yaml:"usageRollupSpec"`
+	// 每次重算最近幾天的 rollup（含今天；預設 2）
+	UsageRollupDays int `mapstructure:"USAGE_ROLLUP_DAYS" json:"usageRollupDays" yaml:"usageRollupDays"`
+	// 閒置使用者 / provider 存取的通知與停用（天數設定見 LIFECYCLE；預設每天 03:30）
+	InactivitySpec string `mapstructure:"INACTIVITY_SPEC" json:"inactivitySpec" yaml:"inactivitySpec"`
+}
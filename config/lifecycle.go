@@ -0,0 +1,16 @@
+package config
+
+// Lifecycle 閒置帳號生命週期：超過天數未使用的使用者 / provider 存取先通知（WarnDays 天前），
+// 期滿後由排程（JOB.INACTIVITY_SPEC）停用；停用的使用者其 API Key 一併標記。每次狀態變化都寫入稽核紀錄
+type Lifecycle struct {
+	// 使用者超過幾天未使用即停用（0 表示不處理）
+	UserInactiveDays int `mapstructure:"USER_INACTIVE_DAYS" json:"userInactiveDays" yaml:"userInactiveDays"`
+	// provider 存取超過幾天未使用即停用（0 表示不處理）
+	KeyInactiveDays int `mapstructure:"KEY_INACTIVE_DAYS" json:"keyInactiveDays" yaml:"keyInactiveDays"`
+	// 停用前幾天通知擁有者；通知後至少經過這麼久才會停用（預設 7，需小於停用天數）
+	WarnDays int `mapstructure:"WARN_DAYS" json:"warnDays" yaml:"warnDays"`
+	// 每輪最多處理的使用者 / API Key 數（預設 500）
+	BatchSize int64 `mapstructure:"BATCH_SIZE" json:"batchSize" yaml:"batchSize"`
+	// 允許使用者以自己的 API Key 呼叫 /account/reactivate 恢復因閒置停用的帳號與存取
+	SelfServiceReactivation bool `mapstructure:"SELF_SERVICE_REACTIVATION" json:"selfServiceReactivation" yaml:"selfServiceReactivation"`
+}
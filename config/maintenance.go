@@ -0,0 +1,9 @@
+package config
+
+// Maintenance 維護模式：開關存於 Redis，各 replica 以 pub/sub 即時同步並定期重新載入
+type Maintenance struct {
+	// 定期從 Redis 重新載入的秒數；pub/sub 通知遺失時的最長不一致時間（預設 30）
+	RefreshSeconds int `mapstructure:"REFRESH_SECONDS" json:"refreshSeconds" yaml:"refreshSeconds"`
+	// 未設定 Retry-After 且沒有結束時間時回給 client 的秒數（預設 300）
+	DefaultRetryAfterSeconds int `mapstructure:"DEFAULT_RETRY_AFTER_SECONDS" json:"defaultRetryAfterSeconds" yaml:"defaultRetryAfterSeconds"`
+}
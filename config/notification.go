@@ -0,0 +1,49 @@
+package config
+
+// Notification 用量門檻 / Key 到期 / 上游異常等事件通知：依規則送出 HMAC 簽章的 webhook 或 email，
+// 投遞紀錄寫入 Mongo，失敗依指數退避重試，重試用盡轉為 dead-letter
+type Notification struct {
+	// 總開關（關閉時不產生事件也不投遞）
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 投遞 worker 輪詢間隔（預設 2000）
+	WorkerIntervalMs int `mapstructure:"WORKER_INTERVAL_MS" json:"workerIntervalMs" yaml:"workerIntervalMs"`
+	// 每輪最多投遞筆數（預設 50）
+	BatchSize int `mapstructure:"BATCH_SIZE" json:"batchSize" yaml:"batchSize"`
+	// 最多嘗試次數，用盡後轉為 dead（預設 6）
+	MaxAttempts int `mapstructure:"MAX_ATTEMPTS" json:"maxAttempts" yaml:"maxAttempts"`
+	// 重試退避基準秒數，第 n 次失敗後等待 base * 2^(n-1)，上限 1 小時（預設 30）
+	BackoffBaseSeconds int `mapstructure:"BACKOFF_BASE_SECONDS" json:"backoffBaseSeconds" yaml:"backoffBaseSeconds"`
+	// 單次 webhook / SMTP 逾時（預設 10000）
+	TimeoutMs int `mapstructure:"TIMEOUT_MS" json:"timeoutMs" yaml:"timeoutMs"`
+	// 規則快取重新載入間隔（本機修改立即生效；預設 60）
+	RuleRefreshSeconds int `mapstructure:"RULE_REFRESH_SECONDS" json:"ruleRefreshSeconds" yaml:"ruleRefreshSeconds"`
+	// Key 到期檢查間隔（預設 3600）
+	ExpiryScanIntervalSeconds int `mapstructure:"EXPIRY_SCAN_INTERVAL_SECONDS" json:"expiryScanIntervalSeconds" yaml:"expiryScanIntervalSeconds"`
+	// 到期前幾天通知（預設 7）
+	ExpiryWarnDays int `mapstructure:"EXPIRY_WARN_DAYS" json:"expiryWarnDays" yaml:"expiryWarnDays"`
+	// 花費異常檢查間隔（需啟用 USAGE_RECORD；預設 900）
+	SpendScanIntervalSeconds int `mapstructure:"SPEND_SCAN_INTERVAL_SECONDS" json:"spendScanIntervalSeconds" yaml:"spendScanIntervalSeconds"`
+	// 花費異常的觀察視窗分鐘數（預設 60）
+	SpendWindowMinutes int `mapstructure:"SPEND_WINDOW_MINUTES" json:"spendWindowMinutes" yaml:"spendWindowMinutes"`
+	// 基準期間小時數，取視窗之前這段時間的平均花費（預設 24）
+	SpendBaselineHours int `mapstructure:"SPEND_BASELINE_HOURS" json:"spendBaselineHours" yaml:"spendBaselineHours"`
+	// 視窗花費超過基準平均的倍數視為異常（預設 3）
+	SpendMultiplier float64 `mapstructure:"SPEND_MULTIPLIER" json:"spendMultiplier" yaml:"spendMultiplier"`
+	// 視窗花費低於此值不視為異常，避免小額波動（預設 1）
+	SpendMinCost float64 `mapstructure:"SPEND_MIN_COST" json:"spendMinCost" yaml:"spendMinCost"`
+	// 同一上游 endpoint 熔斷通知的最短間隔（預設 900）
+	UpstreamCooldownSeconds int `mapstructure:"UPSTREAM_COOLDOWN_SECONDS" json:"upstreamCooldownSeconds" yaml:"upstreamCooldownSeconds"`
+	// 投遞紀錄保留天數，0 表示不自動刪除
+	DeliveryRetentionDays int `mapstructure:"DELIVERY_RETENTION_DAYS" json:"deliveryRetentionDays" yaml:"deliveryRetentionDays"`
+	// email 管道（未設定 Host 時 email 投遞直接轉為 dead）
+	SMTP NotificationSMTP `mapstructure:"SMTP" json:"smtp" yaml:"smtp"`
+}
+
+// NotificationSMTP 寄信設定（Username 為空時不驗證）
+type NotificationSMTP struct {
+	Host     string `mapstructure:"HOST" json:"host" yaml:"host"`
+	Port     int    `mapstructure:"PORT" json:"port" yaml:"port"`
+	Username string `mapstructure:"USERNAME" json:"username" yaml:"username"`
+	Password string `mapstructure:"PASSWORD" json:"password" yaml:"password"`
+	From     string `mapstructure:"FROM" json:"from" yaml:"from"`
+}
@@ -0,0 +1,37 @@
+package config
+
+// Outbound 對上游 provider 的 http client 設定；provider 未設定的欄位沿用 default
+type Outbound struct {
+	// 預設設定
+	Default OutboundClient `mapstructure:"DEFAULT" json:"default" yaml:"default"`
+	// 依 provider 名稱覆寫（key 為 provider，例如 openai）
+	Providers map[string]OutboundClient `mapstructure:"PROVIDERS" json:"providers" yaml:"providers"`
+}
+
+// OutboundClient 單一 provider 的 http client 設定（0 / 空值代表沿用 default 或內建值）
+type OutboundClient struct {
+	// 建立 TCP 連線逾時毫秒（預設 10000）
+	ConnectTimeoutMs int `mapstructure:"CONNECT_TIMEOUT_MS" json:"connectTimeoutMs" yaml:"connectTimeoutMs"`
+	// TLS 交握逾時毫秒（預設 10000）
+	TLSHandshakeTimeoutMs int `mapstructure:"TLS_HANDSHAKE_TIMEOUT_MS" json:"tlsHandshakeTimeoutMs" yaml:"tlsHandshakeTimeoutMs"`
+	// 送出請求後等待回應標頭逾時毫秒（預設 120000）
+	ResponseHeaderTimeoutMs int `mapstructure:"RESPONSE_HEADER_TIMEOUT_MS" json:"responseHeaderTimeoutMs" yaml:"responseHeaderTimeoutMs"`
+	// 非串流請求的總逾時毫秒（含讀取 body；預設 300000）
+	TotalTimeoutMs int `mapstructure:"TOTAL_TIMEOUT_MS" json:"totalTimeoutMs" yaml:"totalTimeoutMs"`
+	// 串流（text/event-stream）兩次資料間的最長閒置毫秒（預設 60000）
+	StreamIdleTimeoutMs int `mapstructure:"STREAM_IDLE_TIMEOUT_MS" json:"streamIdleTimeoutMs" yaml:"streamIdleTimeoutMs"`
+	// 連線池：所有 host 的閒置連線上限（預設 200）
+	MaxIdleConns int `mapstructure:"MAX_IDLE_CONNS" json:"maxIdleConns" yaml:"maxIdleConns"`
+	// 連線池：單一 host 的閒置連線上限（預設 64）
+	MaxIdleConnsPerHost int `mapstructure:"MAX_IDLE_CONNS_PER_HOST" json:"maxIdleConnsPerHost" yaml:"maxIdleConnsPerHost"`
+	// 連線池：單一 host 的連線上限（0 代表不限）
+	MaxConnsPerHost int `mapstructure:"MAX_CONNS_PER_HOST" json:"maxConnsPerHost" yaml:"maxConnsPerHost"`
+	// 閒置連線保留毫秒（預設 90000）
+	IdleConnTimeoutMs int `mapstructure:"IDLE_CONN_TIMEOUT_MS" json:"idleConnTimeoutMs" yaml:"idleConnTimeoutMs"`
+	// 是否啟用 HTTP/2（未設定代表啟用）
+	HTTP2 *bool `mapstructure:"HTTP2" json:"http2" yaml:"http2"`
+	// 出口代理（http://、https://、socks5://）；未設定時沿用 HTTPS_PROXY / HTTP_PROXY / NO_PROXY 環境變數
+	ProxyURL string `mapstructure:"PROXY_URL" json:"proxyURL" yaml:"proxyURL"`
+	// 額外信任的 CA 憑證檔（PEM）路徑，附加在系統憑證之後
+	CABundles []string `mapstructure:"CA_BUNDLES" json:"caBundles" yaml:"caBundles"`
+}
@@ -0,0 +1,20 @@
+package config
+
+// Pricing 模型計價設定（用於配額成本與帳務計算），單位依 Currency
+type Pricing struct {
+	// 計價幣別
+	Currency string `mapstructure:"CURRENCY" json:"currency" yaml:"currency"`
+	// 未設定模型時的預設單價
+	Default ModelPrice `mapstructure:"DEFAULT" json:"default" yaml:"default"`
+	// 依模型名稱設定單價（key 為模型名稱）
+	Models map[string]ModelPrice `mapstructure:"MODELS" json:"models" yaml:"models"`
+}
+
+type ModelPrice struct {
+	// 每 1K prompt/input tokens 價格
+	PromptPer1K float64 `mapstructure:"PROMPT_PER_1K" json:"promptPer1K" yaml:"promptPer1K"`
+	// 每 1K completion/output tokens 價格
+	CompletionPer1K float64 `mapstructure:"COMPLETION_PER_1K" json:"completionPer1K" yaml:"completionPer1K"`
+	// 每次請求固定費用（影像、語音等非 token 計價）
+	PerRequest float64 `mapstructure:"PER_REQUEST" json:"perRequest" yaml:"perRequest"`
+}
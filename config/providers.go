@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Provider 上游 provider 定義（key 為 provider 名稱，例如 openai）
+type Provider struct {
+	// 是否啟用（未設定代表啟用）
+	Enabled *bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// Base URL（不含版本與結尾 /），例如 https://api.openai.com
+	BaseURL string `mapstructure:"BASE_URL" json:"baseURL" yaml:"baseURL"`
+	// 授權方式：bearer（Authorization: Bearer）、header（自訂標頭）、query（query 參數）
+	AuthScheme string `mapstructure:"AUTH_SCHEME" json:"authScheme" yaml:"authScheme"`
+	// header / query 授權時使用的標頭或參數名稱
+	AuthName string `mapstructure:"AUTH_NAME" json:"authName" yaml:"authName"`
+	// 每個請求都附加的標頭
+	DefaultHeaders map[string]string `mapstructure:"DEFAULT_HEADERS" json:"defaultHeaders" yaml:"defaultHeaders"`
+	// 支援的 API 版本（空代表不限制），例如 [v1]
+	Versions []string `mapstructure:"VERSIONS" json:"versions" yaml:"versions"`
+	// 啟用的功能：chat、images、audio、embeddings、models、passthrough（空代表全部）
+	Capabilities []string `mapstructure:"CAPABILITIES" json:"capabilities" yaml:"capabilities"`
+}
+
+// 授權方式
+const (
+	ProviderAuthBearer = "bearer"
+	ProviderAuthHeader = "header"
+	ProviderAuthQuery  = "query"
+)
+
+// DefaultProviders 未設定 PROVIDERS 時使用的內建定義
+func DefaultProviders() map[string]Provider {
+	return map[string]Provider{
+		"openai": {
+			BaseURL:    "https://api.openai.com",
+			AuthScheme: ProviderAuthBearer,
+			Versions:   []string{"v1"},
+		},
+		"gemini": {
+			BaseURL:    "https://generativelanguage.googleapis.com",
+			AuthScheme: ProviderAuthHeader,
+			AuthName:   "x-goog-api-key",
+			Versions:   []string{"v1", "v1beta"},
+		},
+	}
+}
+
+// ProviderDefinitions 回傳生效中的 provider 定義（PROVIDERS 未設定時使用內建定義）。
+// 已發佈時讀取發佈的副本，不讀 c.Providers，避免與設定熱更新競爭；回傳的 map 不可修改。
+func (c *Configuration) ProviderDefinitions() map[string]Provider {
+	var providers map[string]Provider
+	if published := c.providers.Load(); published != nil {
+		providers = *published
+	} else {
+		providers = c.Providers
+	}
+	if len(providers) == 0 {
+		return DefaultProviders()
+	}
+	return providers
+}
+
+// PublishProviders 以副本整份取代生效中的 provider 定義（啟動與熱更新時呼叫；已移除的 provider 不會殘留）
+func (c *Configuration) PublishProviders(providers map[string]Provider) {
+	published := make(map[string]Provider, len(providers))
+	for name, provider := range providers {
+		published[name] = provider
+	}
+	c.providers.Store(&published)
+}
+
+// ValidateProviders 啟動與熱更新時檢查 provider 定義
+func (c *Configuration) ValidateProviders() error {
+	for name, provider := range c.ProviderDefinitions() {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("providers: empty provider name")
+		}
+		parsed, err := url.Parse(provider.BaseURL)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return fmt.Errorf("providers.%s: invalid base_url %q", name, provider.BaseURL)
+		}
+		switch strings.ToLower(provider.AuthScheme) {
+		case "", ProviderAuthBearer:
+		case ProviderAuthHeader, ProviderAuthQuery:
+			if provider.AuthName == "" {
+				return fmt.Errorf("providers.%s: auth_name is required for auth_scheme %q", name, provider.AuthScheme)
+			}
+		default:
+			return fmt.Errorf("providers.%s: unsupported auth_scheme %q", name, provider.AuthScheme)
+		}
+	}
+	return nil
+}
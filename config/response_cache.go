@@ -0,0 +1,11 @@
+package config
+
+// ResponseCache 確定性請求（temperature=0 的 chat、embedding）的回應快取；是否使用由各 API Key 設定
+type ResponseCache struct {
+	// 總開關
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// API Key 未指定 TTL 時的預設秒數（預設 3600）
+	DefaultTTLSeconds int `mapstructure:"DEFAULT_TTL_SECONDS" json:"defaultTTLSeconds" yaml:"defaultTTLSeconds"`
+	// 單筆快取上限位元組，超過不寫入（預設 1048576）
+	MaxEntryBytes int `mapstructure:"MAX_ENTRY_BYTES" json:"maxEntryBytes" yaml:"maxEntryBytes"`
+}
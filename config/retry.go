@@ -0,0 +1,43 @@
+package config
+
+// Retry 上游請求重試設定；解析順序：endpoint → provider → default
+type Retry struct {
+	// 是否啟用
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 預設重試策略
+	Default RetryPolicy `mapstructure:"DEFAULT" json:"default" yaml:"default"`
+	// 無法重新讀取的 body（例如 MCP 轉傳）最多暫存位元組數，超過則不重試（預設 32MB）
+	MaxBufferBytes int64 `mapstructure:"MAX_BUFFER_BYTES" json:"maxBufferBytes" yaml:"maxBufferBytes"`
+	// 依 provider 名稱覆寫（key 為 provider，例如 openai）
+	Providers map[string]ProviderRetry `mapstructure:"PROVIDERS" json:"providers" yaml:"providers"`
+}
+
+// ProviderRetry 單一 provider 的重試覆寫
+type ProviderRetry struct {
+	// provider 層級策略（maxAttempts 為 0 代表沿用 default）
+	Policy RetryPolicy `mapstructure:"POLICY" json:"policy" yaml:"policy"`
+	// 依 endpoint 路徑覆寫（key 為路徑前綴，例如 /v1/chat/completions）
+	Endpoints map[string]RetryPolicy `mapstructure:"ENDPOINTS" json:"endpoints" yaml:"endpoints"`
+}
+
+// RetryPolicy 重試策略
+type RetryPolicy struct {
+	// 最多嘗試次數（含第一次；1 代表不重試）
+	MaxAttempts int `mapstructure:"MAX_ATTEMPTS" json:"maxAttempts" yaml:"maxAttempts"`
+	// 第一次重試前的等待毫秒（預設 200）
+	InitialBackoffMs int `mapstructure:"INITIAL_BACKOFF_MS" json:"initialBackoffMs" yaml:"initialBackoffMs"`
+	// 等待上限毫秒（預設 5000）
+	MaxBackoffMs int `mapstructure:"MAX_BACKOFF_MS" json:"maxBackoffMs" yaml:"maxBackoffMs"`
+	// 指數倍率（預設 2）
+	Multiplier float64 `mapstructure:"MULTIPLIER" json:"multiplier" yaml:"multiplier"`
+	// 抖動比例 0~1（預設 0.2）
+	Jitter float64 `mapstructure:"JITTER" json:"jitter" yaml:"jitter"`
+	// 需重試的狀態碼（預設 429, 500, 502, 503, 504）
+	RetryOnStatus []int `mapstructure:"RETRY_ON_STATUS" json:"retryOnStatus" yaml:"retryOnStatus"`
+	// 連線錯誤是否重試
+	RetryOnConnectionError bool `mapstructure:"RETRY_ON_CONNECTION_ERROR" json:"retryOnConnectionError" yaml:"retryOnConnectionError"`
+	// 是否以上游 Retry-After 取代退避時間
+	RespectRetryAfter bool `mapstructure:"RESPECT_RETRY_AFTER" json:"respectRetryAfter" yaml:"respectRetryAfter"`
+	// Retry-After 超過此毫秒則不重試，直接回傳上游結果（預設 30000）
+	MaxRetryAfterMs int `mapstructure:"MAX_RETRY_AFTER_MS" json:"maxRetryAfterMs" yaml:"maxRetryAfterMs"`
+}
@@ -0,0 +1,23 @@
+package config
+
+// SemanticCache chat 語意快取：以 embedding 相似度比對近似問題；是否使用由各 API Key 設定
+type SemanticCache struct {
+	// 總開關
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 產生 embedding 的 provider（預設 openai）
+	EmbeddingProvider string `mapstructure:"EMBEDDING_PROVIDER" json:"embeddingProvider" yaml:"embeddingProvider"`
+	// embedding 模型（預設 text-embedding-3-small）
+	EmbeddingModel string `mapstructure:"EMBEDDING_MODEL" json:"embeddingModel" yaml:"embeddingModel"`
+	// embedding 用的上游金鑰；未設定時使用請求 API Key 綁定的 provider key
+	EmbeddingAPIKey string `mapstructure:"EMBEDDING_API_KEY" json:"embeddingAPIKey" yaml:"embeddingAPIKey"`
+	// 預設相似度門檻（cosine，預設 0.95）
+	DefaultThreshold float64 `mapstructure:"DEFAULT_THRESHOLD" json:"defaultThreshold" yaml:"defaultThreshold"`
+	// 快取保留秒數（預設 86400）
+	TTLSeconds int `mapstructure:"TTL_SECONDS" json:"ttlSeconds" yaml:"ttlSeconds"`
+	// 每個命名空間保留的最大筆數（預設 10000）
+	MaxEntriesPerNamespace int `mapstructure:"MAX_ENTRIES_PER_NAMESPACE" json:"maxEntriesPerNamespace" yaml:"maxEntriesPerNamespace"`
+	// 本 replica 記憶體索引的總容量上限（MB，預設 512）；超過時整個淘汰最久未使用的命名空間
+	MaxIndexMemoryMB int `mapstructure:"MAX_INDEX_MEMORY_MB" json:"maxIndexMemoryMB" yaml:"maxIndexMemoryMB"`
+	// 記憶體索引重新從 Mongo 載入的間隔秒數，用於同步其他 replica 寫入的項目（預設 60）
+	RefreshSeconds int `mapstructure:"REFRESH_SECONDS" json:"refreshSeconds" yaml:"refreshSeconds"`
+}
@@ -0,0 +1,10 @@
+package config
+
+// Session 外部登入平台簽發的使用者 session（HS256 JWT，sub 為使用者的 externalID）。
+// 供尚未持有 API Key 的使用者呼叫 self-service 端點，例如申請第一把 key
+type Session struct {
+	// JWT 簽章金鑰（與登入平台共用；空字串代表停用 session 驗證）
+	JWTSecret string `mapstructure:"JWT_SECRET" json:"-" yaml:"jwtSecret"`
+	// 預期的簽發者（iss；空字串代表不檢查）
+	Issuer string `mapstructure:"ISSUER" json:"issuer" yaml:"issuer"`
+}
@@ -0,0 +1,11 @@
+package config
+
+// UsageRecord 用量紀錄寫入 Mongo time-series collection（與 Fluentd 並行），供管理端統計查詢
+type UsageRecord struct {
+	// 總開關（關閉時只送 Fluentd）
+	Enabled bool `mapstructure:"ENABLED" json:"enabled" yaml:"enabled"`
+	// 保留天數，超過由 Mongo 自動刪除（預設 90）
+	RetentionDays int `mapstructure:"RETENTION_DAYS" json:"retentionDays" yaml:"retentionDays"`
+	// 單次查詢 / 匯出的最大筆數（預設 10000）
+	MaxResultRows int `mapstructure:"MAX_RESULT_ROWS" json:"maxResultRows" yaml:"maxResultRows"`
+}
@@ -2,27 +2,79 @@ package command
 
 import (
 	commandHandler "interchange/internal/command/handler"
+	"interchange/internal/core"
 
 	"github.com/google/wire"
 	"github.com/spf13/cobra"
 )
 
-var ProviderSet = wire.NewSet(NewCommand, commandHandler.NewExampleHandler)
+var ProviderSet = wire.NewSet(NewCommand, commandHandler.NewExampleHandler, commandHandler.NewInvoiceHandler, commandHandler.NewAdminHandler)
 
 type Command struct {
 	exampleCommandHandler *commandHandler.ExampleHandler
+	invoiceCommandHandler *commandHandler.InvoiceHandler
+	adminCommandHandler   *commandHandler.AdminHandler
 }
 
 // NewCommand .
 func NewCommand(
 	exampleCommandHandler *commandHandler.ExampleHandler,
+	invoiceCommandHandler *commandHandler.InvoiceHandler,
+	adminCommandHandler *commandHandler.AdminHandler,
 ) *Command {
 	return &Command{
 		exampleCommandHandler: exampleCommandHandler,
+		invoiceCommandHandler: invoiceCommandHandler,
+		adminCommandHandler:   adminCommandHandler,
 	}
 }
 
 func Register(rootCmd *cobra.Command, newCmd func() (*Command, func(), error)) {
+	invoiceCmd := &cobra.Command{
+		Use:   "invoice",
+		Short: "generate monthly chargeback statements",
+		// 執行失敗只輸出錯誤，不附帶 usage
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command, cleanup, err := newCmd()
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			return command.invoiceCommandHandler.Generate(cmd, args)
+		},
+	}
+	invoiceCmd.Flags().String("period", "", "statement period YYYY-MM (default: previous month)")
+	invoiceCmd.Flags().String("scope", string(core.InvoiceScopeProject), "statement scope: project or user")
+	invoiceCmd.Flags().String("id", "", "project name or user ID (default: all with usage)")
+	invoiceCmd.Flags().String("format", string(core.InvoiceFormatCSV), "output format: csv, json or html")
+	invoiceCmd.Flags().String("output", ".", "output directory")
+
+	adminCmd := &cobra.Command{
+		Use:   "admin",
+		Short: "admin account maintenance",
+	}
+	bootstrapCmd := &cobra.Command{
+		Use:          "bootstrap",
+		Short:        "create the first admin user and print its api key",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command, cleanup, err := newCmd()
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			return command.adminCommandHandler.Bootstrap(cmd, args)
+		},
+	}
+	bootstrapCmd.Flags().String("name", "", "admin display name (required)")
+	bootstrapCmd.Flags().String("email", "", "admin email")
+	bootstrapCmd.Flags().String("external-id", "", "external login platform ID, for session sign-in")
+	bootstrapCmd.Flags().String("key-name", "bootstrap", "name of the issued api key")
+	adminCmd.AddCommand(bootstrapCmd)
+
 	rootCmd.AddCommand(
 		&cobra.Command{
 			Use:   "example",
@@ -37,5 +89,7 @@ func Register(rootCmd *cobra.Command, newCmd func() (*Command, func(), error)) {
 				command.exampleCommandHandler.Hello(cmd, args)
 			},
 		},
+		invoiceCmd,
+		adminCmd,
 	)
 }
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"interchange/internal/core"
+	"interchange/internal/dto"
+	"interchange/internal/service"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type AdminHandler struct {
+	logger            *zap.Logger
+	userService       *service.UserService
+	userAPIKeyService *service.UserAPIKeyService
+}
+
+func NewAdminHandler(
+	logger *zap.Logger,
+	userService *service.UserService,
+	userAPIKeyService *service.UserAPIKeyService,
+) *AdminHandler {
+	return &AdminHandler{
+		logger:            logger,
+		userService:       userService,
+		userAPIKeyService: userAPIKeyService,
+	}
+}
+
+// Bootstrap 建立第一位 admin 與其平台 API Key（/admin 只能由既有 admin 操作，新部署需由此初始化）；
+// 已有啟用中的 admin 時拒絕執行，key 只在此輸出一次
+func (handler *AdminHandler) Bootstrap(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	email, _ := cmd.Flags().GetString("email")
+	externalID, _ := cmd.Flags().GetString("external-id")
+	keyName, _ := cmd.Flags().GetString("key-name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	ctx := service.WithAuditRequest(context.Background(), &service.AuditRequest{
+		Actor: service.AuditActor{Type: core.AuditActorSystem, ID: "cli:admin-bootstrap"},
+	})
+
+	admins, err := handler.userService.ListUsers(ctx, bson.M{"role": core.RoleAdmin, "status": core.StatusActive}, 0, 1)
+	if err != nil {
+		return fmt.Errorf("list admins failed: %w", err)
+	}
+	if len(admins) > 0 {
+		return fmt.Errorf("an active admin already exists (%s); create further admins through /admin", admins[0].ID)
+	}
+
+	user, err := handler.userService.CreateUser(ctx, &dto.CreateUserDto{
+		ExternalID:  externalID,
+		DisplayName: name,
+		Email:       email,
+		Role:        core.RoleAdmin,
+		Status:      core.StatusActive,
+	})
+	if err != nil {
+		return fmt.Errorf("create admin user failed: %w", err)
+	}
+	userID, _ := primitive.ObjectIDFromHex(user.ID)
+
+	// 管理端只驗證平台 key 本身，不需要 provider 權限
+	key, secret, err := handler.userAPIKeyService.CreateRevealed(ctx, userID, &dto.CreateUserAPIKeyDto{
+		KeyName:        keyName,
+		ProviderAccess: []dto.ProviderAccessDto{},
+	})
+	if err != nil {
+		handler.logger.Error("create bootstrap admin key failed", zap.String("userID", user.ID), zap.Error(err))
+		return fmt.Errorf("create admin api key for user %s failed: %w", user.ID, err)
+	}
+
+	cmd.Printf("admin user:\t%s\n", user.ID)
+	cmd.Printf("api key id:\t%s\n", key.ID)
+	cmd.Printf("api key:\t%s\n", secret)
+	cmd.Println("store the api key now; it will not be shown again")
+	return nil
+}
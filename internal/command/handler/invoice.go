@@ -0,0 +1,70 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"interchange/internal/core"
+	"interchange/internal/dto"
+	"interchange/internal/service"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type InvoiceHandler struct {
+	logger         *zap.Logger
+	invoiceService *service.InvoiceService
+}
+
+func NewInvoiceHandler(logger *zap.Logger, invoiceService *service.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		logger:         logger,
+		invoiceService: invoiceService,
+	}
+}
+
+// Generate 產生月結帳單並輸出檔案（已產生過的帳期輸出既有帳單）；失敗時回傳錯誤讓命令以非零狀態結束
+func (handler *InvoiceHandler) Generate(cmd *cobra.Command, args []string) error {
+	period, _ := cmd.Flags().GetString("period")
+	scope, _ := cmd.Flags().GetString("scope")
+	scopeID, _ := cmd.Flags().GetString("id")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	if !core.InvoiceFormat(format).Valid() {
+		return errors.New("format must be one of json, csv, html")
+	}
+	if period == "" {
+		period = handler.invoiceService.PreviousPeriod()
+	}
+
+	invoices, err := handler.invoiceService.Generate(context.Background(), &dto.GenerateInvoiceDto{
+		Scope:   core.InvoiceScope(scope),
+		ScopeID: scopeID,
+		Period:  period,
+	})
+	if err != nil {
+		handler.logger.Error("generate invoices failed", zap.String("period", period), zap.Error(err))
+		return fmt.Errorf("generate invoices failed: %w", err)
+	}
+	if err := os.MkdirAll(output, 0o755); err != nil {
+		return fmt.Errorf("create output directory failed: %w", err)
+	}
+
+	for _, invoice := range invoices {
+		body, _, err := handler.invoiceService.Render(invoice, core.InvoiceFormat(format))
+		if err != nil {
+			return fmt.Errorf("render invoice %s failed: %w", invoice.Number, err)
+		}
+		filename := filepath.Join(output, invoice.Number+"."+format)
+		if err := os.WriteFile(filename, body, 0o644); err != nil {
+			return fmt.Errorf("write invoice %s failed: %w", filename, err)
+		}
+		cmd.Printf("%s\t%s\t%s\t%.2f %s\n", filename, invoice.Scope, invoice.ScopeID, invoice.Total, invoice.Currency)
+	}
+	cmd.Printf("%d invoice(s) for %s\n", len(invoices), period)
+	return nil
+}
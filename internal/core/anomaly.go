@@ -0,0 +1,32 @@
+package core
+
+// AnomalySignal 異常偵測訊號
+type AnomalySignal string
+
+const (
+	AnomalySignalRateSpike    AnomalySignal = "rate_spike"     // 請求速率遠高於此 key 的基準
+	AnomalySignalNewClientIP  AnomalySignal = "new_client_ip"  // 出現未見過的來源 IP
+	AnomalySignalNewUserAgent AnomalySignal = "new_user_agent" // 出現未見過的 User-Agent
+	AnomalySignalModelChange  AnomalySignal = "model_change"   // 改用未見過的模型
+	AnomalySignalErrorBurst   AnomalySignal = "error_burst"    // 短時間內錯誤率暴增
+)
+
+// AnomalyAction 偵測到異常時的處置（依訊號分別設定）
+type AnomalyAction string
+
+const (
+	AnomalyActionLog      AnomalyAction = "log"      // 只記錄
+	AnomalyActionReverify AnomalyAction = "reverify" // 整把 key 暫停，擁有者以驗證碼重新驗證後恢復
+	AnomalyActionThrottle AnomalyAction = "throttle" // 暫時降低速率上限
+	AnomalyActionSuspend  AnomalyAction = "suspend"  // 該 provider 存取改為 suspended 並通知
+)
+
+// AnomalyObservation 一次請求計入後此 key 的統計
+type AnomalyObservation struct {
+	Requests     int64   // 目前視窗請求數（含本次）
+	Baseline     float64 // 之前各視窗請求數的 EWMA
+	Windows      int64   // 開始使用至今經過的視窗數（含閒置視窗）
+	NewClientIP  bool    // 來源 IP 未見過（已記錄過其他值時才成立）
+	NewUserAgent bool
+	NewModel     bool
+}
@@ -0,0 +1,59 @@
+package core
+
+// AuditActorType 操作者類型
+type AuditActorType string
+
+const (
+	AuditActorAdmin  AuditActorType = "admin"  // 管理端
+	AuditActorUser   AuditActorType = "user"   // 使用者本人（self-service）
+	AuditActorSystem AuditActorType = "system" // 排程 / 系統自動
+)
+
+// AuditTargetType 操作對象類型
+type AuditTargetType string
+
+const (
+	AuditTargetUser           AuditTargetType = "user"
+	AuditTargetAPIKey         AuditTargetType = "api_key"
+	AuditTargetProviderAccess AuditTargetType = "provider_access"
+	AuditTargetKeyRequest     AuditTargetType = "key_request"
+	AuditTargetMaintenance    AuditTargetType = "maintenance"
+	AuditTargetEndpoint       AuditTargetType = "endpoint" // 沒有對應 service 紀錄的管理端請求
+)
+
+// AuditAction 稽核動作
+type AuditAction string
+
+const (
+	AuditActionUserCreated               AuditAction = "user.created"
+	AuditActionUserUpdated               AuditAction = "user.updated"
+	AuditActionUserStatusChanged         AuditAction = "user.status_changed"
+	AuditActionUserRoleChanged           AuditAction = "user.role_changed"
+	AuditActionUserDeleted               AuditAction = "user.deleted"
+	AuditActionAPIKeyCreated             AuditAction = "api_key.created"
+	AuditActionAPIKeyRenamed             AuditAction = "api_key.renamed"
+	AuditActionAPIKeyRotated             AuditAction = "api_key.rotated" // 更換 key 值
+	AuditActionAPIKeyDeleted             AuditAction = "api_key.deleted"
+	AuditActionProviderAccessReplaced    AuditAction = "provider_access.replaced" // 整批覆寫
+	AuditActionProviderAccessUpdated     AuditAction = "provider_access.updated"  // 單一 provider 欄位異動
+	AuditActionProviderAccessExpired     AuditAction = "provider_access.expired"  // 到期標記為 expired
+	AuditActionKeyRequestSubmitted       AuditAction = "key_request.submitted"
+	AuditActionKeyRequestApproved        AuditAction = "key_request.approved"
+	AuditActionKeyRequestRejected        AuditAction = "key_request.rejected"
+	AuditActionMaintenanceUpdated        AuditAction = "maintenance.updated"
+	AuditActionMaintenanceRemoved        AuditAction = "maintenance.removed"
+	AuditActionAdminRequest              AuditAction = "admin.request"                      // 其他管理端異動請求
+	AuditActionUserInactivityWarned      AuditAction = "user.inactivity_warned"             // 已通知即將因未使用停用
+	AuditActionUserInactivityCleared     AuditAction = "user.inactivity_cleared"            // 通知後恢復使用，取消停用
+	AuditActionUserSuspended             AuditAction = "user.suspended"                     // 因未使用停用
+	AuditActionUserReactivated           AuditAction = "user.reactivated"                   // 重新啟用
+	AuditActionAPIKeyFlagged             AuditAction = "api_key.flagged"                    // 擁有者停用，API Key 標記停用
+	AuditActionAPIKeyUnflagged           AuditAction = "api_key.unflagged"                  // 擁有者恢復，移除標記
+	AuditActionProviderInactivityWarned  AuditAction = "provider_access.inactivity_warned"  // provider 存取即將因未使用停用
+	AuditActionProviderInactivityCleared AuditAction = "provider_access.inactivity_cleared" // 通知後恢復使用
+	AuditActionProviderSuspended         AuditAction = "provider_access.suspended"          // 因未使用停用
+	AuditActionProviderReactivated       AuditAction = "provider_access.reactivated"        // 重新啟用
+	AuditActionProviderAnomalySuspended  AuditAction = "provider_access.anomaly_suspended"  // 因異常用量停用
+	AuditActionAPIKeyReverifyRequired    AuditAction = "api_key.reverify_required"          // 因異常用量需重新驗證
+	AuditActionAPIKeyReverified          AuditAction = "api_key.reverified"                 // 完成重新驗證（或管理端解除）
+)
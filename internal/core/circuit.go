@@ -0,0 +1,10 @@
+package core
+
+// CircuitState 熔斷器狀態
+type CircuitState string
+
+const (
+	CircuitStateClosed   CircuitState = "closed"    // 正常放行
+	CircuitStateOpen     CircuitState = "open"      // 熔斷中，直接拒絕
+	CircuitStateHalfOpen CircuitState = "half_open" // 試探中，只放行少量探測請求
+)
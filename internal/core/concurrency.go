@@ -0,0 +1,38 @@
+package core
+
+// ConcurrencyMode 超過並行上限時的處理方式
+type ConcurrencyMode string
+
+const (
+	ConcurrencyModeReject ConcurrencyMode = "reject" // 直接回 429（fail fast）
+	ConcurrencyModeQueue  ConcurrencyMode = "queue"  // 排隊等待，逾時回 429
+)
+
+// PriorityTier 上游飽和排隊時的優先層級（高層級一律先於低層級出列）
+type PriorityTier string
+
+const (
+	PriorityTierProduction PriorityTier = "production"
+	PriorityTierStandard   PriorityTier = "standard"
+	PriorityTierBatch      PriorityTier = "batch"
+)
+
+// Rank 數字越大越優先；未設定視為 standard
+func (t PriorityTier) Rank() int {
+	switch t {
+	case PriorityTierProduction:
+		return 2
+	case PriorityTierBatch:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// SemaphoreScope 並行槽位的層級
+type SemaphoreScope string
+
+const (
+	SemaphoreScopeKey      SemaphoreScope = "key"      // 單一 API Key + provider
+	SemaphoreScopeUpstream SemaphoreScope = "upstream" // 上游 provider key（真實憑證）
+)
@@ -0,0 +1,26 @@
+package core
+
+// CreditScope 預付額度帳戶層級（使用者屬於有帳戶的組織時由組織扣款）
+type CreditScope string
+
+const (
+	CreditScopeUser CreditScope = "user"
+	CreditScopeOrg  CreditScope = "org"
+)
+
+// CreditEntryType 帳本分錄類型（金額正數為入帳、負數為扣款）
+type CreditEntryType string
+
+const (
+	CreditEntryTopUp    CreditEntryType = "top_up"   // 儲值
+	CreditEntryAdjust   CreditEntryType = "adjust"   // 人工調整（可正可負）
+	CreditEntryRefund   CreditEntryType = "refund"   // 退款
+	CreditEntryDebit    CreditEntryType = "debit"    // 依用量自動扣款
+	CreditEntryReversal CreditEntryType = "reversal" // 沖銷餘額未更新成功的分錄（不影響餘額）
+)
+
+// 呼叫端重試時帶相同的 Idempotency-Key，扣款以 apiKeyID + 該值為冪等鍵；未帶或超過長度上限時改用閘道請求 ID
+const (
+	HeaderIdempotencyKey    = "Idempotency-Key"
+	MaxIdempotencyKeyLength = 255
+)
@@ -37,23 +37,60 @@ const (
 
 // MongoDB collections
 const (
-	MongoCollectionUsers       MongoCollection = "interchange_users"
-	MongoCollectionUserAPIKeys MongoCollection = "interchange_user_api_keys"
+	MongoCollectionUsers                  MongoCollection = "interchange_users"
+	MongoCollectionUserAPIKeys            MongoCollection = "interchange_user_api_keys"
+	MongoCollectionOrganizations          MongoCollection = "interchange_organizations"
+	MongoCollectionPromptTemplates        MongoCollection = "interchange_prompt_templates"
+	MongoCollectionSemanticCache          MongoCollection = "interchange_semantic_cache"
+	MongoCollectionUsageRecords           MongoCollection = "interchange_usage_records"
+	MongoCollectionCreditAccounts         MongoCollection = "interchange_credit_accounts"
+	MongoCollectionCreditLedger           MongoCollection = "interchange_credit_ledger"
+	MongoCollectionInvoices               MongoCollection = "interchange_invoices"
+	MongoCollectionNotificationRules      MongoCollection = "interchange_notification_rules"
+	MongoCollectionNotificationDeliveries MongoCollection = "interchange_notification_deliveries"
+	MongoCollectionJobs                   MongoCollection = "interchange_jobs"
+	MongoCollectionJobRuns                MongoCollection = "interchange_job_runs"
+	MongoCollectionUsageRollups           MongoCollection = "interchange_usage_rollups"
+	MongoCollectionKeyRequests            MongoCollection = "interchange_key_requests"
+	MongoCollectionAuditLogs              MongoCollection = "interchange_audit_logs"
+	MongoCollectionAnomalyEvents          MongoCollection = "interchange_anomaly_events"
 )
 
 // ─── Redis Keys ────────────────────────────────────────────────────────────────
 
 const (
-	RedisKeySession      RedisKey = "session"         // 使用者 session 資料
-	RedisKeyBlacklist    RedisKey = "blacklist_token" // 黑名單 token
-	RedisKeyRefreshToken RedisKey = "refresh_token"   // refresh token
-	RedisKeyServerName   RedisKey = "interchange"     // 伺服器名稱
+	RedisKeySession            RedisKey = "session"             // 使用者 session 資料
+	RedisKeyBlacklist          RedisKey = "blacklist_token"     // 黑名單 token
+	RedisKeyRefreshToken       RedisKey = "refresh_token"       // refresh token
+	RedisKeyServerName         RedisKey = "interchange"         // 伺服器名稱
+	RedisKeyQuota              RedisKey = "quota"               // user/org 配額計數
+	RedisKeySemaphore          RedisKey = "semaphore"           // 並行槽位（分散式 semaphore）
+	RedisKeyCircuit            RedisKey = "circuit"             // 熔斷器共享狀態
+	RedisKeyResponseCache      RedisKey = "response_cache"      // 確定性請求的回應快取
+	RedisKeyCoalesce           RedisKey = "coalesce"            // 跨 replica 請求合併（leader 鎖與結果）
+	RedisKeyEntity             RedisKey = "entity"              // API Key / 使用者文件快取
+	RedisKeyEntityChannel      RedisKey = "entity_invalidate"   // 文件快取失效通知（pub/sub）
+	RedisKeyNotify             RedisKey = "notify"              // 通知事件去重
+	RedisKeyJobLock            RedisKey = "job_lock"            // 排程工作的分散式租約鎖
+	RedisKeyJobSlot            RedisKey = "job_slot"            // 排程時段認領（每個排程時段只由一個 replica 執行）
+	RedisKeyMaintenance        RedisKey = "maintenance"         // 維護模式開關（hash）
+	RedisKeyMaintenanceChannel RedisKey = "maintenance_changed" // 維護模式異動通知（pub/sub）
+	RedisKeyAnomaly            RedisKey = "anomaly"             // 異常偵測（速率基準、已見來源、錯誤率、限流與驗證碼）
+)
+
+// 文件快取種類
+type CacheEntity string
+
+const (
+	CacheEntityAPIKey CacheEntity = "api_key"
+	CacheEntityUser   CacheEntity = "user"
 )
 
 const (
 	FluentdRequest  FluentdSubTag = "request_log"
 	FluentdResponse FluentdSubTag = "response_log"
 	FluentUsage     FluentdSubTag = "interchange_usage_log"
+	FluentdAudit    FluentdSubTag = "interchange_audit_log"
 )
 
 type ListOptions struct {
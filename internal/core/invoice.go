@@ -0,0 +1,27 @@
+package core
+
+// InvoiceScope 帳單對象（project 對應 API Key 名稱 / ProjectName）
+type InvoiceScope string
+
+const (
+	InvoiceScopeProject InvoiceScope = "project"
+	InvoiceScopeUser    InvoiceScope = "user"
+)
+
+// InvoiceFormat 帳單輸出格式
+type InvoiceFormat string
+
+const (
+	InvoiceFormatJSON InvoiceFormat = "json"
+	InvoiceFormatCSV  InvoiceFormat = "csv"
+	InvoiceFormatHTML InvoiceFormat = "html"
+)
+
+// Valid 是否為支援的輸出格式
+func (format InvoiceFormat) Valid() bool {
+	switch format {
+	case InvoiceFormatJSON, InvoiceFormatCSV, InvoiceFormatHTML:
+		return true
+	}
+	return false
+}
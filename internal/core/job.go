@@ -0,0 +1,18 @@
+package core
+
+// JobTrigger 排程工作的觸發來源
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule" // 依 cron 排程
+	JobTriggerManual   JobTrigger = "manual"   // 管理端手動觸發
+)
+
+// JobRunStatus 單次執行結果
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
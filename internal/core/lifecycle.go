@@ -0,0 +1,12 @@
+package core
+
+// SuspendReason 自動停用原因（管理端手動改狀態時不帶原因）
+type SuspendReason string
+
+const (
+	SuspendReasonInactive      SuspendReason = "inactive"       // 使用者長期未使用
+	SuspendReasonOwnerInactive SuspendReason = "owner_inactive" // API Key 擁有者因長期未使用被停用
+	SuspendReasonStale         SuspendReason = "stale"          // provider 存取長期未使用
+	SuspendReasonAnomaly       SuspendReason = "anomaly"        // provider 存取因異常用量被停用
+	SuspendReasonReverify      SuspendReason = "reverify"       // API Key 因異常用量需擁有者重新驗證
+)
@@ -0,0 +1,48 @@
+package core
+
+// NotificationScope 通知規則層級（global 接收所有事件，例如上游異常）
+type NotificationScope string
+
+const (
+	NotificationScopeKey    NotificationScope = "key"
+	NotificationScopeUser   NotificationScope = "user"
+	NotificationScopeOrg    NotificationScope = "org"
+	NotificationScopeGlobal NotificationScope = "global"
+)
+
+// NotificationEvent 通知事件類型（同時作為 webhook 的 X-Interchange-Event 標頭）
+type NotificationEvent string
+
+const (
+	NotificationEventQuotaThreshold  NotificationEvent = "quota.threshold"       // 配額用量跨過門檻（預設 50 / 80 / 100%）
+	NotificationEventKeyExpiring     NotificationEvent = "key.expiring"          // API Key 即將過期
+	NotificationEventKeyExpired      NotificationEvent = "key.expired"           // API Key 已過期（排程標記為 expired）
+	NotificationEventUserInactive    NotificationEvent = "user.inactive"         // 使用者即將因未使用停用
+	NotificationEventUserSuspended   NotificationEvent = "user.suspended"        // 使用者因未使用已停用
+	NotificationEventKeyInactive     NotificationEvent = "key.inactive"          // provider 存取即將因未使用停用
+	NotificationEventKeySuspended    NotificationEvent = "key.suspended"         // provider 存取因未使用已停用
+	NotificationEventKeyRequested    NotificationEvent = "key_request.submitted" // 使用者送出 API Key 申請（待審核）
+	NotificationEventKeyApproved     NotificationEvent = "key_request.approved"  // API Key 申請核准並已核發
+	NotificationEventKeyRejected     NotificationEvent = "key_request.rejected"  // API Key 申請駁回
+	NotificationEventSpendAnomaly    NotificationEvent = "spend.anomaly"         // 花費異常
+	NotificationEventKeyAnomaly      NotificationEvent = "key.anomaly"           // API Key 用量異常並已處置（重新驗證 / 限流 / 停用）
+	NotificationEventUpstreamFailure NotificationEvent = "upstream.failure"      // 上游連續失敗（熔斷）
+	NotificationEventTest            NotificationEvent = "notification.test"
+)
+
+// NotificationChannel 通知管道
+type NotificationChannel string
+
+const (
+	NotificationChannelWebhook NotificationChannel = "webhook"
+	NotificationChannelEmail   NotificationChannel = "email"
+)
+
+// NotificationDeliveryStatus 投遞狀態（dead 為重試用盡的 dead-letter）
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending   NotificationDeliveryStatus = "pending"
+	NotificationDeliveryDelivered NotificationDeliveryStatus = "delivered"
+	NotificationDeliveryDead      NotificationDeliveryStatus = "dead"
+)
@@ -36,12 +36,20 @@ const (
 	ApiScopeAll                   ApiScope = "*"
 )
 
-type OpenAIEndpoint string
+// ProviderCapability provider 啟用的功能（對應 PROVIDERS.<name>.CAPABILITIES）
+type ProviderCapability string
 
 const (
-	OpenAIAPIBaseURL = "https://api.openai.com"
-	GeminiAPIBaseURL = "https://generativelanguage.googleapis.com"
+	ProviderCapabilityChat        ProviderCapability = "chat"
+	ProviderCapabilityImages      ProviderCapability = "images"
+	ProviderCapabilityAudio       ProviderCapability = "audio"
+	ProviderCapabilityEmbeddings  ProviderCapability = "embeddings"
+	ProviderCapabilityModels      ProviderCapability = "models"
+	ProviderCapabilityPassthrough ProviderCapability = "passthrough"
 )
+
+type OpenAIEndpoint string
+
 const (
 	OpenAiChatEndpoint           OpenAIEndpoint = "/chat/completions"
 	OpenAISpeechEndpoint         OpenAIEndpoint = "/audio/speech"
@@ -53,3 +61,6 @@ const (
 	OpenAIImageVariationEndpoint OpenAIEndpoint = "/images/variations"
 	OpenAIModelsEndpoint         OpenAIEndpoint = "/models"
 )
+
+// MaxModelInspectBytes 檢查 JSON body 的 model 欄位時可暫存的 body 上限
+const MaxModelInspectBytes = 10 << 20
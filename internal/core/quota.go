@@ -0,0 +1,60 @@
+package core
+
+// QuotaScope 配額層級（依序檢查 key → user → org）
+type QuotaScope string
+
+const (
+	QuotaScopeKey  QuotaScope = "key"
+	QuotaScopeUser QuotaScope = "user"
+	QuotaScopeOrg  QuotaScope = "org"
+)
+
+// QuotaMetric 配額計量單位
+type QuotaMetric string
+
+const (
+	QuotaMetricRequests QuotaMetric = "requests"
+	QuotaMetricTokens   QuotaMetric = "tokens"
+	QuotaMetricCost     QuotaMetric = "cost"
+)
+
+// QuotaUsage 單一視窗內的累計用量
+type QuotaUsage struct {
+	Requests int64   `json:"requests"`
+	Tokens   int64   `json:"tokens"`
+	Cost     float64 `json:"cost"`
+}
+
+// QuotaBudget 某層級某計量單位的剩餘額度
+type QuotaBudget struct {
+	Scope        QuotaScope  `json:"scope"`
+	ScopeID      string      `json:"scopeID"`
+	Metric       QuotaMetric `json:"metric"`
+	Limit        float64     `json:"limit"`
+	Remaining    float64     `json:"remaining"`
+	ResetSeconds int64       `json:"resetSeconds"`
+}
+
+// Exhausted 額度是否已用盡
+func (b QuotaBudget) Exhausted() bool {
+	return b.Remaining <= 0
+}
+
+// RemainingRatio 剩餘比例（0~1），用於比較哪個額度最緊
+func (b QuotaBudget) RemainingRatio() float64 {
+	if b.Limit <= 0 {
+		return 0
+	}
+	return b.Remaining / b.Limit
+}
+
+// TightestBudget 回傳剩餘比例最低的額度；若無任何額度回傳 nil
+func TightestBudget(budgets []QuotaBudget) *QuotaBudget {
+	var tightest *QuotaBudget
+	for i := range budgets {
+		if tightest == nil || budgets[i].RemainingRatio() < tightest.RemainingRatio() {
+			tightest = &budgets[i]
+		}
+	}
+	return tightest
+}
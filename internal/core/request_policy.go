@@ -0,0 +1,15 @@
+package core
+
+// LimitAction 數值超出範圍時的處理方式
+type LimitAction string
+
+const (
+	LimitActionClamp  LimitAction = "clamp"  // 夾到上下限後轉發
+	LimitActionReject LimitAction = "reject" // 回 400 並列出違規欄位
+)
+
+// 強制值可使用的替換變數
+const (
+	PolicyVarUserID     = "{{userID}}"     // API Key 擁有者的使用者 ID
+	PolicyVarExternalID = "{{externalID}}" // 呼叫端帶入的 userID query
+)
@@ -7,14 +7,18 @@ const ContextTraceKey = "telemetry_trace_ctx"
 type TraceSpanName string
 
 const (
-	SpanHttpRequest         TraceSpanName = "http_request"
-	SpanLoggerMiddleware    TraceSpanName = "logger_middleware"
-	SpanRecoveryMiddleware  TraceSpanName = "recovery_middleware"
-	SpanCorsMiddleware      TraceSpanName = "cors_middleware"
-	SpanResponseMiddleware  TraceSpanName = "response_middleware"
-	SpanAPIKeyMiddleware    TraceSpanName = "api_key_middleware"
-	SpanRateLimitMiddleware TraceSpanName = "ratelimit_middleware"
-	SpanUserMiddleware      TraceSpanName = "user_middleware"
+	SpanHttpRequest           TraceSpanName = "http_request"
+	SpanLoggerMiddleware      TraceSpanName = "logger_middleware"
+	SpanRecoveryMiddleware    TraceSpanName = "recovery_middleware"
+	SpanCorsMiddleware        TraceSpanName = "cors_middleware"
+	SpanResponseMiddleware    TraceSpanName = "response_middleware"
+	SpanAPIKeyMiddleware      TraceSpanName = "api_key_middleware"
+	SpanRateLimitMiddleware   TraceSpanName = "ratelimit_middleware"
+	SpanUserMiddleware        TraceSpanName = "user_middleware"
+	SpanMaintenanceMiddleware TraceSpanName = "maintenance_middleware"
+	SpanAnomalyMiddleware     TraceSpanName = "anomaly_middleware"
+	SpanAdminMiddleware       TraceSpanName = "admin_middleware"
+	SpanSessionMiddleware     TraceSpanName = "session_middleware"
 )
 
 // 指標名稱常數
@@ -28,6 +32,11 @@ const (
 	MetricKeyBlockedTotal     MetricName = "key_blocked_total"
 	MetricKeyUsageGauge       MetricName = "key_usage_gauge"
 	MetricRateLimitTotal      MetricName = "rate_limited_total"
+	MetricUpstreamQueueDepth  MetricName = "upstream_queue_depth"
+	MetricUpstreamQueueWait   MetricName = "upstream_queue_wait_seconds"
+	MetricUpstreamThrottled   MetricName = "upstream_throttled_total"
+	MetricSemanticCacheLookup MetricName = "semantic_cache_lookups_total"
+	MetricCoalescedRequests   MetricName = "coalesced_requests_total"
 )
 
 // label name 常數
@@ -37,6 +46,10 @@ const (
 	MetricLabelEndpoint MetricLabelName = "endpoint"
 	MetricLabelStatus   MetricLabelName = "status"
 	MetricLabelReason   MetricLabelName = "reason"
+	MetricLabelProvider MetricLabelName = "provider"
+	MetricLabelUpstream MetricLabelName = "upstream"
+	MetricLabelTier     MetricLabelName = "tier"
+	MetricLabelOutcome  MetricLabelName = "outcome"
 )
 
 type LoggerRequestMeta struct {
@@ -212,6 +225,15 @@ type TraceUsageLogMeta struct {
 	Version          string `trace:"log.version"`
 	LoggedAt         string `trace:"http.logged_at"`
 }
+type TraceAuditLogMeta struct {
+	AuditID    string `trace:"audit.id"`
+	RequestID  string `trace:"http.request.request_id,omitempty"`
+	Action     string `trace:"audit.action"`
+	TargetType string `trace:"audit.target_type"`
+	TargetID   string `trace:"audit.target_id"`
+	Version    string `trace:"log.version"`
+	LoggedAt   string `trace:"http.logged_at"`
+}
 type TraceAPIKeyMiddlewareMeta struct {
 	Provider string   `trace:"auth.provider"`
 	Where    string   `trace:"auth.where"`
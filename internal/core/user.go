@@ -26,6 +26,8 @@ const (
 	StatusRevoked     Status = "revoked"     // 被手動撤銷
 	StatusMaintenance Status = "maintenance" // 系統維護中（暫時停用）
 	StatusPending     Status = "pending"     // 尚未啟用（等待審核/激活）
+	StatusApproved    Status = "approved"    // 審核通過（API Key 申請）
+	StatusRejected    Status = "rejected"    // 審核駁回（API Key 申請）
 	StatusDeleted     Status = "deleted"     // 已刪除（軟刪除）
 )
 
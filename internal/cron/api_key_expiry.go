@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAPIKeyExpirySpec = "0 */5 * * * *"
+	apiKeyExpiryBatchSize   = 500
+)
+
+// APIKeyExpiryJob 將 expireTime 已到的 provider 權限標記為 expired（key.expired 通知由 UserAPIKeyService 送出）
+type APIKeyExpiryJob struct {
+	logger            *zap.Logger
+	config            *config.Configuration
+	userAPIKeyService *service.UserAPIKeyService
+}
+
+func NewAPIKeyExpiryJob(
+	logger *zap.Logger,
+	config *config.Configuration,
+	userAPIKeyService *service.UserAPIKeyService,
+) *APIKeyExpiryJob {
+	return &APIKeyExpiryJob{
+		logger:            logger,
+		config:            config,
+		userAPIKeyService: userAPIKeyService,
+	}
+}
+
+func (j *APIKeyExpiryJob) Definition() service.JobDefinition {
+	return service.JobDefinition{
+		Name:        "api-key-expiry",
+		Description: "標記已過期的 API Key provider 權限並通知",
+		Spec:        specOrDefault(j.config.Job.APIKeyExpirySpec, defaultAPIKeyExpirySpec),
+		Timeout:     time.Minute,
+		Run:         j.run,
+	}
+}
+
+// run 每輪最多處理 apiKeyExpiryBatchSize 把 Key，剩下的留給下一輪
+func (j *APIKeyExpiryJob) run(ctx context.Context) (string, error) {
+	expired, err := j.userAPIKeyService.ExpireDueKeys(ctx, apiKeyExpiryBatchSize)
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range expired {
+		j.logger.Info("api key provider access expired",
+			zap.String("apiKeyID", item.APIKeyID.Hex()),
+			zap.String("userID", item.UserID.Hex()),
+			zap.String("provider", string(item.Provider)),
+			zap.Time("expireTime", item.ExpireTime),
+		)
+	}
+	return fmt.Sprintf("expired %d provider access", len(expired)), nil
+}
+
+func specOrDefault(spec, fallback string) string {
+	if spec == "" {
+		return fallback
+	}
+	return spec
+}
@@ -2,42 +2,115 @@ package cron
 
 import (
 	"context"
+	"time"
+
+	"interchange/internal/service"
 
 	"github.com/google/wire"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
 
-var ProviderSet = wire.NewSet(NewCron)
+// slotLookback 推算排程時段時往回找的範圍（觸發延遲超過此值時改以目前秒數為時段）
+const slotLookback = time.Minute
+
+var ProviderSet = wire.NewSet(
+	NewCron,
+	NewJobDefinitions,
+	NewAPIKeyExpiryJob,
+	NewQuotaResetJob,
+	NewLastSeenFlushJob,
+	NewUsageRollupJob,
+	NewInactivityJob,
+)
 
 type Cron struct {
-	logger *zap.Logger
-	server *cron.Cron
+	logger     *zap.Logger
+	server     *cron.Cron
+	jobService *service.JobService
 }
 
-// NewCron .
-func NewCron(logger *zap.Logger) *Cron {
+// NewCron 將所有工作登錄到 JobService；排程觸發時由 JobService 處理暫停、分散式鎖與執行紀錄
+func NewCron(
+	logger *zap.Logger,
+	jobService *service.JobService,
+	definitions []service.JobDefinition,
+) (*Cron, error) {
 	server := cron.New(
-		cron.WithSeconds(),
+		cron.WithParser(specParser),
 	)
 
+	for _, definition := range definitions {
+		if err := jobService.Register(definition); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Cron{
-		logger: logger,
-		server: server,
+		logger:     logger,
+		server:     server,
+		jobService: jobService,
+	}, nil
+}
+
+// NewJobDefinitions 所有排程工作（新增工作時在此加入）
+func NewJobDefinitions(
+	apiKeyExpiryJob *APIKeyExpiryJob,
+	quotaResetJob *QuotaResetJob,
+	lastSeenFlushJob *LastSeenFlushJob,
+	usageRollupJob *UsageRollupJob,
+	inactivityJob *InactivityJob,
+) []service.JobDefinition {
+	return []service.JobDefinition{
+		apiKeyExpiryJob.Definition(),
+		quotaResetJob.Definition(),
+		lastSeenFlushJob.Definition(),
+		usageRollupJob.Definition(),
+		inactivityJob.Definition(),
 	}
 }
 
 func (c *Cron) Run() error {
-	// cron example
-	//if _, err := c.server.AddFunc("*/5 * * * * *", c.exampleJob.Hello); err != nil {
-	//   return err
-	//}
+	for _, definition := range c.jobService.Definitions() {
+		name := definition.Name
+		schedule, err := specParser.Parse(definition.Spec)
+		if err != nil {
+			return err
+		}
+		c.server.Schedule(schedule, cron.FuncJob(func() {
+			c.jobService.RunScheduled(name, scheduledSlot(schedule, time.Now()))
+		}))
+		c.logger.Info("cron job scheduled", zap.String("job", name), zap.String("spec", definition.Spec))
+	}
 
 	c.server.Start()
 	return nil
 }
 
+// Stop 停止排程並等待執行中的工作結束（最多等到 ctx 逾時）
 func (c *Cron) Stop(ctx context.Context) error {
-	c.server.Stop()
+	stopped := c.server.Stop()
+	select {
+	case <-stopped.Done():
+	case <-ctx.Done():
+	}
 	return nil
 }
+
+// specParser 與 cron.WithSeconds() 相同的格式（含秒）
+var specParser = cron.NewParser(
+	cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// scheduledSlot 觸發當下對應的排程時間（不晚於 now 的最近一次）；各 replica 的觸發時間略有差異，
+// 但推算出的時段相同，作為分散式認領的 key
+func scheduledSlot(schedule cron.Schedule, now time.Time) time.Time {
+	slot := schedule.Next(now.Add(-slotLookback))
+	if slot.After(now) {
+		return now.Truncate(time.Second)
+	}
+	for next := schedule.Next(slot); !next.After(now); next = schedule.Next(slot) {
+		slot = next
+	}
+	return slot
+}
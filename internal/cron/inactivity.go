@@ -0,0 +1,53 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/service"
+)
+
+const (
+	inactivityJobName      = "inactivity-lifecycle"
+	defaultInactivitySpec  = "0 30 3 * * *"
+	defaultInactivityLimit = 10 * time.Minute
+)
+
+// InactivityJob 閒置使用者 / provider 存取的通知與停用（天數設定見 LIFECYCLE）
+type InactivityJob struct {
+	config           *config.Configuration
+	lifecycleService *service.LifecycleService
+}
+
+func NewInactivityJob(config *config.Configuration, lifecycleService *service.LifecycleService) *InactivityJob {
+	return &InactivityJob{config: config, lifecycleService: lifecycleService}
+}
+
+func (j *InactivityJob) Definition() service.JobDefinition {
+	return service.JobDefinition{
+		Name:        inactivityJobName,
+		Description: "通知並停用長期未使用的使用者與 provider 存取",
+		Spec:        specOrDefault(j.config.Job.InactivitySpec, defaultInactivitySpec),
+		Timeout:     defaultInactivityLimit,
+		Run:         j.run,
+	}
+}
+
+func (j *InactivityJob) run(ctx context.Context) (string, error) {
+	if j.config.Lifecycle.UserInactiveDays <= 0 && j.config.Lifecycle.KeyInactiveDays <= 0 {
+		return "disabled", nil
+	}
+	result, err := j.lifecycleService.Sweep(ctx, service.AuditActor{Type: core.AuditActorSystem, ID: inactivityJobName})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"users warned %d / suspended %d / cleared %d, keys flagged %d / unflagged %d, providers warned %d / suspended %d / cleared %d",
+		result.UsersWarned, result.UsersSuspended, result.UsersCleared,
+		result.KeysFlagged, result.KeysUnflagged,
+		result.ProvidersWarned, result.ProvidersSuspended, result.ProvidersCleared,
+	), nil
+}
@@ -0,0 +1,37 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+
+	"interchange/config"
+	"interchange/internal/service"
+)
+
+const defaultLastSeenFlushSpec = "0 */5 * * * *"
+
+// LastSeenFlushJob 強制寫回本機累積的 lastSeen / 用量；緩衝本身也會依 FLUSH_INTERVAL_MS 寫回，
+// 此工作讓管理端可手動觸發，並在執行紀錄中留下寫回筆數
+type LastSeenFlushJob struct {
+	config   *config.Configuration
+	activity *service.ActivityBufferService
+}
+
+func NewLastSeenFlushJob(config *config.Configuration, activity *service.ActivityBufferService) *LastSeenFlushJob {
+	return &LastSeenFlushJob{config: config, activity: activity}
+}
+
+func (j *LastSeenFlushJob) Definition() service.JobDefinition {
+	return service.JobDefinition{
+		Name:        "lastseen-flush",
+		Description: "寫回本機累積的 lastSeen 與 API Key 用量（每個 replica 各自執行）",
+		Spec:        specOrDefault(j.config.Job.LastSeenFlushSpec, defaultLastSeenFlushSpec),
+		Local:       true,
+		Run:         j.run,
+	}
+}
+
+func (j *LastSeenFlushJob) run(ctx context.Context) (string, error) {
+	users, keys := j.activity.Flush()
+	return fmt.Sprintf("flushed %d users, %d key usages", users, keys), nil
+}
@@ -0,0 +1,40 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/service"
+)
+
+const defaultQuotaResetSpec = "0 0 * * * *"
+
+// QuotaResetJob 限流視窗結束後將 Mongo 中的 provider 已用次數歸零，與 Redis 視窗對齊
+type QuotaResetJob struct {
+	config            *config.Configuration
+	userAPIKeyService *service.UserAPIKeyService
+}
+
+func NewQuotaResetJob(config *config.Configuration, userAPIKeyService *service.UserAPIKeyService) *QuotaResetJob {
+	return &QuotaResetJob{config: config, userAPIKeyService: userAPIKeyService}
+}
+
+func (j *QuotaResetJob) Definition() service.JobDefinition {
+	return service.JobDefinition{
+		Name:        "quota-reset",
+		Description: "歸零限流視窗已結束的 provider 已用次數",
+		Spec:        specOrDefault(j.config.Job.QuotaResetSpec, defaultQuotaResetSpec),
+		Timeout:     5 * time.Minute,
+		Run:         j.run,
+	}
+}
+
+func (j *QuotaResetJob) run(ctx context.Context) (string, error) {
+	reset, err := j.userAPIKeyService.ResetElapsedQuotaWindows(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("reset %d api keys", reset), nil
+}
@@ -0,0 +1,41 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+
+	"interchange/config"
+	"interchange/internal/service"
+)
+
+const defaultUsageRollupSpec = "0 10 * * * *"
+
+// UsageRollupJob 重算最近幾天的每日用量彙總
+type UsageRollupJob struct {
+	config       *config.Configuration
+	usageService *service.UsageService
+}
+
+func NewUsageRollupJob(config *config.Configuration, usageService *service.UsageService) *UsageRollupJob {
+	return &UsageRollupJob{config: config, usageService: usageService}
+}
+
+func (j *UsageRollupJob) Definition() service.JobDefinition {
+	return service.JobDefinition{
+		Name:        "usage-rollup",
+		Description: "彙總用量紀錄為每日 rollup",
+		Spec:        specOrDefault(j.config.Job.UsageRollupSpec, defaultUsageRollupSpec),
+		Run:         j.run,
+	}
+}
+
+func (j *UsageRollupJob) run(ctx context.Context) (string, error) {
+	if !j.config.UsageRecord.Enabled {
+		return "usage record disabled", nil
+	}
+	written, err := j.usageService.Rollup(ctx, j.config.Job.UsageRollupDays)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("upserted %d rollups", written), nil
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	client "interchange/internal/database/client"
+	fluentdRepo "interchange/internal/database/fluentd/repository"
 	mongoRepo "interchange/internal/database/mongodb/repository"
+	redisRepo "interchange/internal/database/redis/repository"
 
 	"github.com/google/wire"
 )
@@ -11,4 +13,8 @@ import (
 var ProviderSet = wire.NewSet(
 	client.NewMongoClient,
 	mongoRepo.ProviderSet,
+	client.NewRedisClient,
+	redisRepo.ProviderSet,
+	client.NewFluentdClient,
+	fluentdRepo.ProviderSet,
 )
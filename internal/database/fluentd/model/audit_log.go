@@ -0,0 +1,26 @@
+package model
+
+// AuditLog 稽核紀錄（與 Mongo 的 interchange_audit_logs 同步轉送）
+type AuditLog struct {
+	AuditID    string        `bson:"audit_id" json:"audit_id"`
+	RequestID  string        `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	ActorType  string        `bson:"actor_type" json:"actor_type"`
+	Actor      string        `bson:"actor,omitempty" json:"actor,omitempty"`
+	Action     string        `bson:"action" json:"action"`
+	TargetType string        `bson:"target_type" json:"target_type"`
+	TargetID   string        `bson:"target_id" json:"target_id"`
+	Provider   string        `bson:"provider,omitempty" json:"provider,omitempty"`
+	Changes    []AuditChange `bson:"changes,omitempty" json:"changes,omitempty"`
+	Reason     string        `bson:"reason,omitempty" json:"reason,omitempty"`
+	ClientIP   string        `bson:"client_ip,omitempty" json:"client_ip,omitempty"`
+	UserAgent  string        `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	CreatedAt  string        `bson:"created_at" json:"created_at"`
+	Version    string        `bson:"version" json:"version"`
+	LoggedAt   string        `bson:"logged_at" json:"logged_at"`
+}
+
+type AuditChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before any    `bson:"before,omitempty" json:"before,omitempty"`
+	After  any    `bson:"after,omitempty" json:"after,omitempty"`
+}
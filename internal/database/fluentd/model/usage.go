@@ -3,7 +3,11 @@ package model
 type AIUsageLog struct {
 	// 身份/追蹤
 	RequestID        string `bson:"request_id,omitempty" json:"request_id"`
+	GatewayRequestID string `bson:"gateway_request_id,omitempty" json:"gateway_request_id,omitempty"` // 閘道產生的請求 ID（不受呼叫端 traceparent 影響）
+	IdempotencyKey   string `bson:"idempotency_key,omitempty" json:"idempotency_key,omitempty"`       // 呼叫端帶入的 Idempotency-Key（重試時相同）
 	ExternalID       string `bson:"external_id,omitempty" json:"external_id,omitempty"`
+	UserID           string `bson:"user_id,omitempty" json:"user_id,omitempty"`       // 內部使用者 ID
+	APIKeyID         string `bson:"api_key_id,omitempty" json:"api_key_id,omitempty"` // 請求使用的 API Key ID
 	DisplayName      string `bson:"display_name,omitempty" json:"display_name,omitempty"`
 	ProjectName      string `bson:"project_name,omitempty" json:"project_name,omitempty"`
 	Provider         string `bson:"provider" json:"provider"`
@@ -17,6 +21,11 @@ type AIUsageLog struct {
 	InputToken       int    `bson:"input_tokens,omitempty" json:"input_tokens,omitempty"`
 	OutputToken      int    `bson:"output_tokens,omitempty" json:"output_tokens,omitempty"`
 	TokensTotal      int    `bson:"tokens_total,omitempty" json:"tokens_total,omitempty"`
+	TemplateName     string `bson:"template_name,omitempty" json:"template_name,omitempty"`
+	TemplateVersion  int    `bson:"template_version,omitempty" json:"template_version,omitempty"`
+	Cached           bool   `bson:"cached,omitempty" json:"cached,omitempty"`
+	CacheType        string `bson:"cache_type,omitempty" json:"cache_type,omitempty"` // exact / semantic
+	Coalesced        bool   `bson:"coalesced,omitempty" json:"coalesced,omitempty"`   // 與其他請求共用同一次上游呼叫
 	Version          string `bson:"version" json:"version"`
 	LoggedAt         string `bson:"logged_at" json:"logged_at"`
 }
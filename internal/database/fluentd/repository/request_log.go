@@ -12,7 +12,7 @@ import (
 	"interchange/internal/telemetry"
 )
 
-// LogRepository 統一負責發送 Request/Response/Usage Log 到 Fluentd
+// LogRepository 統一負責發送 Request/Response/Usage/Audit Log 到 Fluentd
 type LogRepository struct {
 	fluentdClient *client.FluentdClient
 	trace         *telemetry.Trace
@@ -130,3 +130,33 @@ func (repository *LogRepository) LogUsage(ctx context.Context, usage model.AIUsa
 	}
 	return err
 }
+
+func (repository *LogRepository) LogAudit(ctx context.Context, audit model.AuditLog) error {
+	ctx, span, end := repository.trace.WithSpan(ctx, "fluentd_"+string(core.FluentdAudit))
+	defer end(nil)
+
+	if audit.LoggedAt == "" {
+		audit.LoggedAt = time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC")
+	}
+	if audit.Version == "" {
+		audit.Version = repository.version
+	}
+	attributes := core.TraceAuditLogMeta{
+		AuditID:    audit.AuditID,
+		RequestID:  audit.RequestID,
+		Action:     audit.Action,
+		TargetType: audit.TargetType,
+		TargetID:   audit.TargetID,
+		Version:    audit.Version,
+		LoggedAt:   audit.LoggedAt,
+	}
+	repository.trace.ApplyTraceAttributes(span, attributes)
+	b, _ := json.Marshal(audit)
+	var fluentdMessage map[string]any
+	_ = json.Unmarshal(b, &fluentdMessage)
+	err := repository.fluentdClient.Post(ctx, string(core.FluentdAudit), fluentdMessage)
+	if err != nil {
+		end(err)
+	}
+	return err
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnomalyEvent 偵測到的異常與處置（只新增不修改）
+type AnomalyEvent struct {
+	ID        primitive.ObjectID     `json:"id" bson:"_id"`                                  // 唯一識別碼
+	APIKeyID  primitive.ObjectID     `json:"apiKeyID" bson:"apiKeyID"`                       // API Key
+	UserID    primitive.ObjectID     `json:"userID" bson:"userID"`                           // 擁有者
+	Provider  core.ProviderName      `json:"provider" bson:"provider"`                       // 觸發請求的 provider
+	Endpoint  string                 `json:"endpoint" bson:"endpoint"`                       // 觸發請求的端點
+	Signal    core.AnomalySignal     `json:"signal" bson:"signal"`                           // 訊號
+	Action    core.AnomalyAction     `json:"action" bson:"action"`                           // 處置
+	Applied   bool                   `json:"applied" bson:"applied"`                         // 處置是否實際生效（例如已是停用狀態則為 false）
+	Detail    map[string]interface{} `json:"detail,omitempty" bson:"detail,omitempty"`       // 判斷依據（速率、基準、錯誤率等）
+	ClientIP  string                 `json:"clientIP,omitempty" bson:"clientIP,omitempty"`   // 來源 IP
+	UserAgent string                 `json:"userAgent,omitempty" bson:"userAgent,omitempty"` // 來源 User-Agent
+	Model     string                 `json:"model,omitempty" bson:"model,omitempty"`         // 請求的模型
+	CreatedAt time.Time              `json:"createdAt" bson:"createdAt"`                     // 發生時間
+}
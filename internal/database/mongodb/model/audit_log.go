@@ -0,0 +1,32 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditLog 稽核紀錄（只新增不修改）
+type AuditLog struct {
+	ID         primitive.ObjectID   `json:"id" bson:"_id"`                                  // 唯一識別碼
+	ActorType  core.AuditActorType  `json:"actorType" bson:"actorType"`                     // 操作者類型
+	Actor      string               `json:"actor,omitempty" bson:"actor,omitempty"`         // 操作者（使用者 ID 或排程名稱）
+	Action     core.AuditAction     `json:"action" bson:"action"`                           // 動作
+	TargetType core.AuditTargetType `json:"targetType" bson:"targetType"`                   // 對象類型
+	TargetID   string               `json:"targetID" bson:"targetID"`                       // 對象 ID
+	Provider   core.ProviderName    `json:"provider,omitempty" bson:"provider,omitempty"`   // provider（provider 存取相關動作）
+	Changes    []AuditChange        `json:"changes,omitempty" bson:"changes,omitempty"`     // 變更前後差異（只記有變動的欄位）
+	Reason     string               `json:"reason,omitempty" bson:"reason,omitempty"`       // 說明
+	ClientIP   string               `json:"clientIP,omitempty" bson:"clientIP,omitempty"`   // 來源 IP（HTTP 觸發時）
+	UserAgent  string               `json:"userAgent,omitempty" bson:"userAgent,omitempty"` // 來源 User-Agent
+	RequestID  string               `json:"requestID,omitempty" bson:"requestID,omitempty"` // trace ID，可與 request / response log 對照
+	CreatedAt  time.Time            `json:"createdAt" bson:"createdAt"`                     // 發生時間
+}
+
+// AuditChange 單一欄位的變更；巢狀欄位以 "." 串接，provider 存取以 provider 名稱作為索引，例如 providerAccess.openai.status
+type AuditChange struct {
+	Field  string      `json:"field" bson:"field"`
+	Before interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" bson:"after,omitempty"`
+}
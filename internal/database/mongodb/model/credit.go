@@ -0,0 +1,35 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreditAccount 預付額度帳戶（餘額由帳本分錄累加而來）
+type CreditAccount struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`              // 唯一識別碼
+	Scope     core.CreditScope   `json:"scope" bson:"scope"`         // 帳戶層級
+	ScopeID   string             `json:"scopeID" bson:"scopeID"`     // 使用者或組織 ID
+	Balance   float64            `json:"balance" bson:"balance"`     // 目前餘額
+	Currency  string             `json:"currency" bson:"currency"`   // 幣別（建立時的 Pricing 幣別）
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"` // 建立時間
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"` // 更新時間
+}
+
+// CreditLedgerEntry 帳本分錄（只新增不修改）
+type CreditLedgerEntry struct {
+	ID             primitive.ObjectID   `json:"id" bson:"_id"`                                        // 唯一識別碼
+	Scope          core.CreditScope     `json:"scope" bson:"scope"`                                   // 帳戶層級
+	ScopeID        string               `json:"scopeID" bson:"scopeID"`                               // 使用者或組織 ID
+	Type           core.CreditEntryType `json:"type" bson:"type"`                                     // 分錄類型
+	Amount         float64              `json:"amount" bson:"amount"`                                 // 金額（正數入帳、負數扣款）
+	IdempotencyKey string               `json:"idempotencyKey" bson:"idempotencyKey"`                 // 冪等鍵（扣款為 apiKeyID + Idempotency-Key，未帶時為閘道請求 ID）
+	UserID         string               `json:"userID,omitempty" bson:"userID,omitempty"`             // 產生扣款的使用者
+	Model          string               `json:"model,omitempty" bson:"model,omitempty"`               // 扣款模型
+	InputTokens    int                  `json:"inputTokens,omitempty" bson:"inputTokens,omitempty"`   // 扣款 input tokens
+	OutputTokens   int                  `json:"outputTokens,omitempty" bson:"outputTokens,omitempty"` // 扣款 output tokens
+	Note           string               `json:"note,omitempty" bson:"note,omitempty"`                 // 備註
+	CreatedAt      time.Time            `json:"createdAt" bson:"createdAt"`                           // 建立時間
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invoice 月結帳單（產生後不可修改）
+type Invoice struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`                  // 唯一識別碼
+	Number      string             `json:"number" bson:"number"`           // 帳單編號
+	Scope       core.InvoiceScope  `json:"scope" bson:"scope"`             // 帳單對象
+	ScopeID     string             `json:"scopeID" bson:"scopeID"`         // 專案名稱或使用者 ID
+	Period      string             `json:"period" bson:"period"`           // 帳期（YYYY-MM）
+	PeriodStart time.Time          `json:"periodStart" bson:"periodStart"` // 帳期起（含）
+	PeriodEnd   time.Time          `json:"periodEnd" bson:"periodEnd"`     // 帳期迄（不含）
+	Currency    string             `json:"currency" bson:"currency"`       // 幣別
+	LineItems   []InvoiceLineItem  `json:"lineItems" bson:"lineItems"`     // 明細（provider / model / endpoint）
+	Requests    int64              `json:"requests" bson:"requests"`       // 請求數合計
+	TokensTotal int64              `json:"tokensTotal" bson:"tokensTotal"` // tokens 合計
+	Subtotal    float64            `json:"subtotal" bson:"subtotal"`       // 用量小計
+	MarkupRate  float64            `json:"markupRate" bson:"markupRate"`   // 加成比例
+	Markup      float64            `json:"markup" bson:"markup"`           // 加成金額
+	TaxRate     float64            `json:"taxRate" bson:"taxRate"`         // 稅率
+	Tax         float64            `json:"tax" bson:"tax"`                 // 稅額
+	Total       float64            `json:"total" bson:"total"`             // 應付總額
+	GeneratedAt time.Time          `json:"generatedAt" bson:"generatedAt"` // 產生時間
+}
+
+// InvoiceLineItem 帳單明細
+type InvoiceLineItem struct {
+	Provider       string  `json:"provider" bson:"provider"`             // 模型提供者
+	Model          string  `json:"model" bson:"model"`                   // 模型
+	Endpoint       string  `json:"endpoint" bson:"endpoint"`             // 請求路徑
+	Requests       int64   `json:"requests" bson:"requests"`             // 請求數
+	CachedRequests int64   `json:"cachedRequests" bson:"cachedRequests"` // 命中快取請求數
+	InputTokens    int64   `json:"inputTokens" bson:"inputTokens"`       // prompt / input tokens
+	OutputTokens   int64   `json:"outputTokens" bson:"outputTokens"`     // completion / output tokens
+	TokensTotal    int64   `json:"tokensTotal" bson:"tokensTotal"`       // 總 tokens
+	Cost           float64 `json:"cost" bson:"cost"`                     // 成本小計
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobState 排程工作的共享狀態（_id 為工作名稱；暫停狀態所有 replica 共用）
+type JobState struct {
+	Name           string            `json:"name" bson:"_id"`                                          // 工作名稱
+	Paused         bool              `json:"paused" bson:"paused"`                                     // 是否暫停排程
+	PausedBy       string            `json:"pausedBy,omitempty" bson:"pausedBy,omitempty"`             // 暫停者
+	PausedAt       *time.Time        `json:"pausedAt,omitempty" bson:"pausedAt,omitempty"`             // 暫停時間
+	LastRunAt      *time.Time        `json:"lastRunAt,omitempty" bson:"lastRunAt,omitempty"`           // 最後一次開始時間
+	LastStatus     core.JobRunStatus `json:"lastStatus,omitempty" bson:"lastStatus,omitempty"`         // 最後一次結果
+	LastDurationMs int64             `json:"lastDurationMs,omitempty" bson:"lastDurationMs,omitempty"` // 最後一次耗時
+	UpdatedAt      time.Time         `json:"updatedAt" bson:"updatedAt"`                               // 更新時間
+}
+
+// JobRun 單次執行紀錄
+type JobRun struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`                                      // 唯一識別碼
+	Job         string             `json:"job" bson:"job"`                                     // 工作名稱
+	Trigger     core.JobTrigger    `json:"trigger" bson:"trigger"`                             // 觸發來源
+	TriggeredBy string             `json:"triggeredBy,omitempty" bson:"triggeredBy,omitempty"` // 手動觸發者
+	Instance    string             `json:"instance" bson:"instance"`                           // 執行的 replica（hostname-pid）
+	Status      core.JobRunStatus  `json:"status" bson:"status"`                               // 執行結果
+	Message     string             `json:"message,omitempty" bson:"message,omitempty"`         // 執行摘要
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`             // 失敗原因
+	StartedAt   time.Time          `json:"startedAt" bson:"startedAt"`                         // 開始時間
+	FinishedAt  *time.Time         `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`   // 結束時間
+	DurationMs  int64              `json:"durationMs" bson:"durationMs"`                       // 耗時（毫秒）
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// KeyRequest API Key 申請（審核後保留，不刪除）
+type KeyRequest struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id"`                                            // 唯一識別碼
+	UserID         primitive.ObjectID  `json:"userID" bson:"userID"`                                     // 申請者
+	KeyName        string              `json:"keyName" bson:"keyName"`                                   // 希望的 API Key 名稱
+	ProviderAccess []KeyRequestAccess  `json:"providerAccess" bson:"providerAccess"`                     // 申請的 provider、scope 與配額
+	Justification  string              `json:"justification" bson:"justification"`                       // 申請理由
+	Status         core.Status         `json:"status" bson:"status"`                                     // pending / approved / rejected
+	ReviewedBy     string              `json:"reviewedBy,omitempty" bson:"reviewedBy,omitempty"`         // 審核者 user ID
+	ReviewNote     string              `json:"reviewNote,omitempty" bson:"reviewNote,omitempty"`         // 審核說明（駁回時為原因）
+	ReviewedAt     *time.Time          `json:"reviewedAt,omitempty" bson:"reviewedAt,omitempty"`         // 審核時間
+	ApprovedAccess []KeyRequestAccess  `json:"approvedAccess,omitempty" bson:"approvedAccess,omitempty"` // 核准內容（審核者可修改）
+	APIKeyID       *primitive.ObjectID `json:"apiKeyID,omitempty" bson:"apiKeyID,omitempty"`             // 核發的 API Key
+	CreatedAt      time.Time           `json:"createdAt" bson:"createdAt"`                               // 申請時間
+	UpdatedAt      time.Time           `json:"updatedAt" bson:"updatedAt"`                               // 更新時間
+}
+
+// KeyRequestAccess 單一 provider 的申請內容
+type KeyRequestAccess struct {
+	Provider    core.ProviderName `json:"provider" bson:"provider"`                           // openai, gemini...
+	ApiScopes   []core.ApiScope   `json:"apiScopes" bson:"apiScopes"`                         // 需要的 endpoint
+	LimitPeriod *core.LimitPeriod `json:"limitPeriod,omitempty" bson:"limitPeriod,omitempty"` // 配額期間
+	LimitCount  *int              `json:"limitCount,omitempty" bson:"limitCount,omitempty"`   // 期間內次數上限
+	ExpireTime  *time.Time        `json:"expireTime,omitempty" bson:"expireTime,omitempty"`   // 希望的到期時間
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationRule 通知訂閱規則（ScopeID 為空時套用至該層級所有對象）
+type NotificationRule struct {
+	ID            primitive.ObjectID       `json:"id" bson:"_id"`                                    // 唯一識別碼
+	Name          string                   `json:"name" bson:"name"`                                 // 規則名稱
+	Scope         core.NotificationScope   `json:"scope" bson:"scope"`                               // 規則層級
+	ScopeID       string                   `json:"scopeID,omitempty" bson:"scopeID,omitempty"`       // API Key / 使用者 / 組織 ID
+	Events        []core.NotificationEvent `json:"events" bson:"events"`                             // 訂閱的事件
+	Thresholds    []int                    `json:"thresholds,omitempty" bson:"thresholds,omitempty"` // 配額門檻百分比（未設定為 50 / 80 / 100）
+	WebhookURL    string                   `json:"webhookURL,omitempty" bson:"webhookURL,omitempty"` // webhook 位址
+	WebhookSecret string                   `json:"-" bson:"webhookSecret,omitempty"`                 // HMAC-SHA256 簽章金鑰
+	Emails        []string                 `json:"emails,omitempty" bson:"emails,omitempty"`         // 收件人
+	Enabled       bool                     `json:"enabled" bson:"enabled"`                           // 是否啟用
+	CreatedBy     string                   `json:"createdBy,omitempty" bson:"createdBy,omitempty"`   // 建立者
+	CreatedAt     time.Time                `json:"createdAt" bson:"createdAt"`                       // 建立時間
+	UpdatedAt     time.Time                `json:"updatedAt" bson:"updatedAt"`                       // 更新時間
+}
+
+// NotificationDelivery 單一管道的投遞紀錄（重試用盡後 status 為 dead，保留作為 dead-letter）
+type NotificationDelivery struct {
+	ID            primitive.ObjectID              `json:"id" bson:"_id"`                                      // 唯一識別碼
+	RuleID        primitive.ObjectID              `json:"ruleID" bson:"ruleID"`                               // 觸發的規則
+	EventID       string                          `json:"eventID" bson:"eventID"`                             // 事件 ID（同一事件的各管道共用）
+	Event         core.NotificationEvent          `json:"event" bson:"event"`                                 // 事件類型
+	Channel       core.NotificationChannel        `json:"channel" bson:"channel"`                             // 投遞管道
+	Target        string                          `json:"target" bson:"target"`                               // webhook 位址或收件人
+	Subject       string                          `json:"subject" bson:"subject"`                             // 摘要
+	Payload       string                          `json:"payload" bson:"payload"`                             // JSON 內容（webhook body）
+	Status        core.NotificationDeliveryStatus `json:"status" bson:"status"`                               // 投遞狀態
+	Attempts      int                             `json:"attempts" bson:"attempts"`                           // 已嘗試次數
+	LastError     string                          `json:"lastError,omitempty" bson:"lastError,omitempty"`     // 最後一次失敗原因
+	NextAttemptAt time.Time                       `json:"nextAttemptAt" bson:"nextAttemptAt"`                 // 下次嘗試時間
+	DeliveredAt   *time.Time                      `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"` // 成功時間
+	Secret        bool                            `json:"secret,omitempty" bson:"secret,omitempty"`           // payload 的 Secret 欄位已遮蔽，只由建立當下直接投遞；worker 不送出、不可重送
+	CreatedAt     time.Time                       `json:"createdAt" bson:"createdAt"`                         // 建立時間
+	UpdatedAt     time.Time                       `json:"updatedAt" bson:"updatedAt"`                         // 更新時間
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"interchange/internal/core"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Organization struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`                                      // 組織唯一識別碼
+	Name        string             `json:"name" bson:"name"`                                   // 組織/團隊名稱
+	Description string             `json:"description,omitempty" bson:"description,omitempty"` // 說明
+	Status      core.Status        `json:"status" bson:"status"`                               // 組織狀態
+	QuotaPolicy *QuotaPolicy       `json:"quotaPolicy,omitempty" bson:"quotaPolicy,omitempty"` // 組織層級配額
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`                         // 建立時間
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`                         // 更新時間
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PromptTemplate 提示詞樣板；每次修改新增一個版本（name + version 唯一）
+type PromptTemplate struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`                                      // 唯一識別碼
+	Name        string             `json:"name" bson:"name"`                                   // 樣板名稱，例如 support-bot
+	Version     int                `json:"version" bson:"version"`                             // 版本號（由 1 起遞增）
+	Description string             `json:"description,omitempty" bson:"description,omitempty"` // 說明
+	Messages    []PromptMessage    `json:"messages" bson:"messages"`                           // 樣板訊息（content 可使用 {{.var}}）
+	Variables   []string           `json:"variables,omitempty" bson:"variables,omitempty"`     // 必填變數
+	CreatedBy   string             `json:"createdBy,omitempty" bson:"createdBy,omitempty"`     // 建立者
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`                         // 建立時間
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`                         // 更新時間
+}
+
+// PromptMessage 樣板內的單一訊息
+type PromptMessage struct {
+	Role    string `json:"role" bson:"role"`       // system / user / assistant / developer
+	Content string `json:"content" bson:"content"` // 內容（text/template 語法）
+}
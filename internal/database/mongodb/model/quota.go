@@ -0,0 +1,11 @@
+package model
+
+import "interchange/internal/core"
+
+// QuotaPolicy 使用者/組織層級的彙總配額（跨所有 API Key 累計）
+type QuotaPolicy struct {
+	LimitPeriod  core.LimitPeriod `json:"limitPeriod" bson:"limitPeriod"`                       // daily, weekly, monthly...
+	RequestLimit *int64           `json:"requestLimit,omitempty" bson:"requestLimit,omitempty"` // 該 period 的請求次數上限
+	TokenLimit   *int64           `json:"tokenLimit,omitempty" bson:"tokenLimit,omitempty"`     // 該 period 的 token 上限
+	CostLimit    *float64         `json:"costLimit,omitempty" bson:"costLimit,omitempty"`       // 該 period 的成本上限（依 Pricing 幣別）
+}
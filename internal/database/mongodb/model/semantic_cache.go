@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SemanticCacheEntry 語意快取項目（向量持久化於 Mongo，查詢使用記憶體索引）
+type SemanticCacheEntry struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`                  // 唯一識別碼
+	Namespace   string             `json:"namespace" bson:"namespace"`     // 命名空間
+	Model       string             `json:"model" bson:"model"`             // chat 模型
+	ContextHash string             `json:"contextHash" bson:"contextHash"` // 最後一則 user 訊息以外內容的雜湊（system prompt、歷史訊息）
+	Prompt      string             `json:"prompt" bson:"prompt"`           // 最後一則 user 訊息
+	Embedding   []float32          `json:"embedding" bson:"embedding"`     // 正規化後的向量
+	Result      string             `json:"result" bson:"result"`           // 序列化的 ChatResult
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`     // 建立時間
+	ExpiresAt   time.Time          `json:"expiresAt" bson:"expiresAt"`     // 到期時間（TTL index）
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UsageRecord 單次請求的用量紀錄（time-series collection，timeField=timestamp、metaField=meta）
+type UsageRecord struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`                                    // 唯一識別碼
+	Timestamp        time.Time          `json:"timestamp" bson:"timestamp"`                                 // 記錄時間
+	Meta             UsageRecordMeta    `json:"meta" bson:"meta"`                                           // 分組維度
+	RequestID        string             `json:"requestID,omitempty" bson:"requestID,omitempty"`             // 追蹤 ID
+	ExternalID       string             `json:"externalID,omitempty" bson:"externalID,omitempty"`           // 呼叫端帶入的使用者識別
+	DisplayName      string             `json:"displayName,omitempty" bson:"displayName,omitempty"`         // API Key 顯示名稱
+	TokensPrompt     int                `json:"tokensPrompt" bson:"tokensPrompt"`                           // prompt tokens
+	TokensCompletion int                `json:"tokensCompletion" bson:"tokensCompletion"`                   // completion tokens
+	TextToken        int                `json:"textTokens" bson:"textTokens"`                               // 文字 tokens
+	AudioToken       int                `json:"audioTokens" bson:"audioTokens"`                             // 音訊 tokens
+	ImageToken       int                `json:"imageTokens" bson:"imageTokens"`                             // 圖片 tokens
+	InputToken       int                `json:"inputTokens" bson:"inputTokens"`                             // input tokens
+	OutputToken      int                `json:"outputTokens" bson:"outputTokens"`                           // output tokens
+	TokensTotal      int                `json:"tokensTotal" bson:"tokensTotal"`                             // 總 tokens
+	Cost             float64            `json:"cost" bson:"cost"`                                           // 依 Pricing 估算的成本
+	TemplateName     string             `json:"templateName,omitempty" bson:"templateName,omitempty"`       // 提示詞樣板
+	TemplateVersion  int                `json:"templateVersion,omitempty" bson:"templateVersion,omitempty"` // 樣板版本
+	Cached           bool               `json:"cached,omitempty" bson:"cached,omitempty"`                   // 是否命中快取
+	CacheType        string             `json:"cacheType,omitempty" bson:"cacheType,omitempty"`             // exact / semantic
+	Coalesced        bool               `json:"coalesced,omitempty" bson:"coalesced,omitempty"`             // 與其他請求共用上游呼叫
+}
+
+// UsageRecordMeta 用量紀錄的分組維度（time-series metaField）
+type UsageRecordMeta struct {
+	UserID      string `json:"userID,omitempty" bson:"userID,omitempty"`           // 內部使用者 ID
+	APIKeyID    string `json:"apiKeyID,omitempty" bson:"apiKeyID,omitempty"`       // API Key ID
+	ProjectName string `json:"projectName,omitempty" bson:"projectName,omitempty"` // 專案
+	Provider    string `json:"provider" bson:"provider"`                           // 模型提供者
+	Model       string `json:"model,omitempty" bson:"model,omitempty"`             // 模型
+	Endpoint    string `json:"endpoint" bson:"endpoint"`                           // 請求路徑
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UsageRollup 每日用量彙總（day + userID + apiKeyID + provider + model 唯一），
+// 由排程自用量紀錄重算，保留期限不受 time-series 的 RETENTION_DAYS 限制
+type UsageRollup struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`                  // 唯一識別碼
+	Day              time.Time          `json:"day" bson:"day"`                           // 日期（UTC 00:00）
+	UserID           string             `json:"userID,omitempty" bson:"userID"`           // 內部使用者 ID
+	APIKeyID         string             `json:"apiKeyID,omitempty" bson:"apiKeyID"`       // API Key ID
+	Provider         string             `json:"provider" bson:"provider"`                 // 模型提供者
+	Model            string             `json:"model,omitempty" bson:"model"`             // 模型
+	Requests         int64              `json:"requests" bson:"requests"`                 // 請求數
+	CachedRequests   int64              `json:"cachedRequests" bson:"cachedRequests"`     // 命中快取的請求數
+	TokensPrompt     int64              `json:"tokensPrompt" bson:"tokensPrompt"`         // prompt tokens
+	TokensCompletion int64              `json:"tokensCompletion" bson:"tokensCompletion"` // completion tokens
+	InputTokens      int64              `json:"inputTokens" bson:"inputTokens"`           // input tokens
+	OutputTokens     int64              `json:"outputTokens" bson:"outputTokens"`         // output tokens
+	TokensTotal      int64              `json:"tokensTotal" bson:"tokensTotal"`           // 總 tokens
+	Cost             float64            `json:"cost" bson:"cost"`                         // 成本
+	UpdatedAt        time.Time          `json:"updatedAt" bson:"updatedAt"`               // 最後重算時間
+}
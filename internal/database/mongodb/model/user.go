@@ -8,13 +8,19 @@ import (
 )
 
 type User struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id"`                                      // 使用者唯一識別碼
-	ExternalID  string             `json:"externalID,omitempty" bson:"externalID,omitempty"`   // 外部登入平台的使用者 ID
-	DisplayName string             `json:"displayName,omitempty" bson:"displayName,omitempty"` // 使用者顯示名稱
-	Email       string             `json:"email,omitempty" bson:"email,omitempty"`             // 使用者信箱
-	Role        core.Role          `json:"role" bson:"role"`                                   // 使用者角色
-	Status      core.Status        `json:"status" bson:"status"`                               // 帳號狀態
-	LastSeen    *time.Time         `json:"lastSeen,omitempty" bson:"lastSeen,omitempty"`       // 最後使用時間
-	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`                         // 建立時間
-	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`                         // 更新時間
+	ID          primitive.ObjectID  `json:"id" bson:"_id"`                                      // 使用者唯一識別碼
+	ExternalID  string              `json:"externalID,omitempty" bson:"externalID,omitempty"`   // 外部登入平台的使用者 ID
+	DisplayName string              `json:"displayName,omitempty" bson:"displayName,omitempty"` // 使用者顯示名稱
+	Email       string              `json:"email,omitempty" bson:"email,omitempty"`             // 使用者信箱
+	Role        core.Role           `json:"role" bson:"role"`                                   // 使用者角色
+	Status      core.Status         `json:"status" bson:"status"`                               // 帳號狀態
+	OrgID       *primitive.ObjectID `json:"orgID,omitempty" bson:"orgID,omitempty"`             // 所屬組織 ID
+	QuotaPolicy *QuotaPolicy        `json:"quotaPolicy,omitempty" bson:"quotaPolicy,omitempty"` // 使用者層級配額
+	LastSeen    *time.Time          `json:"lastSeen,omitempty" bson:"lastSeen,omitempty"`       // 最後使用時間
+	// 閒置生命週期（由排程寫入，重新啟用時清除）
+	InactivityWarnedAt *time.Time         `json:"inactivityWarnedAt,omitempty" bson:"inactivityWarnedAt,omitempty"` // 已通知即將停用的時間
+	SuspendReason      core.SuspendReason `json:"suspendReason,omitempty" bson:"suspendReason,omitempty"`           // 自動停用原因
+	SuspendedAt        *time.Time         `json:"suspendedAt,omitempty" bson:"suspendedAt,omitempty"`               // 自動停用時間
+	CreatedAt          time.Time          `json:"createdAt" bson:"createdAt"`                                       // 建立時間
+	UpdatedAt          time.Time          `json:"updatedAt" bson:"updatedAt"`                                       // 更新時間
 }
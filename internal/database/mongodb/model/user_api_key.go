@@ -8,13 +8,15 @@ import (
 )
 
 type UserAPIKey struct {
-	ID             primitive.ObjectID `json:"id" bson:"_id"`                              // 使用者 API Key 唯一識別碼
-	UserID         primitive.ObjectID `json:"userID" bson:"userID"`                       // 所屬使用者 ID
-	KeyName        string             `json:"keyName,omitempty" bson:"keyName,omitempty"` // API Key 名稱
-	KeyValue       string             `json:"keyValue" bson:"keyValue"`                   // API Key 值
-	ProviderAccess []ProviderAccess   `json:"providerAccess" bson:"providerAccess"`       // 各個 provider 的存取權限
-	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`                 // 建立時間
-	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`                 // 更新時間
+	ID             primitive.ObjectID `json:"id" bson:"_id"`                                          // 使用者 API Key 唯一識別碼
+	UserID         primitive.ObjectID `json:"userID" bson:"userID"`                                   // 所屬使用者 ID
+	KeyName        string             `json:"keyName,omitempty" bson:"keyName,omitempty"`             // API Key 名稱
+	KeyValue       string             `json:"keyValue" bson:"keyValue"`                               // API Key 值
+	ProviderAccess []ProviderAccess   `json:"providerAccess" bson:"providerAccess"`                   // 各個 provider 的存取權限
+	SuspendReason  core.SuspendReason `json:"suspendReason,omitempty" bson:"suspendReason,omitempty"` // 停用標記（擁有者停用時整把 key 不可用）
+	SuspendedAt    *time.Time         `json:"suspendedAt,omitempty" bson:"suspendedAt,omitempty"`     // 標記時間
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`                             // 建立時間
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`                             // 更新時間
 }
 type ProviderAccess struct {
 	Provider    core.ProviderName `json:"provider" bson:"provider"`                           // openai, gemini...
@@ -27,4 +29,65 @@ type ProviderAccess struct {
 	ApiScopes   []core.ApiScope   `json:"apiScopes" bson:"apiScopes"`                         // 可用的 endpoint 清單
 	ExpireTime  *time.Time        `json:"expireTime,omitempty" bson:"expireTime,omitempty"`   // API Key 過期時間
 	LastSeen    *time.Time        `json:"lastSeen,omitempty" bson:"lastSeen,omitempty"`       // 最後使用時間
+	// 閒置生命週期（由排程寫入，重新啟用時清除）
+	InactivityWarnedAt *time.Time           `json:"inactivityWarnedAt,omitempty" bson:"inactivityWarnedAt,omitempty"` // 已通知即將停用的時間
+	SuspendReason      core.SuspendReason   `json:"suspendReason,omitempty" bson:"suspendReason,omitempty"`           // 自動停用原因（status 為 suspended 時）
+	Concurrency        *ConcurrencyPolicy   `json:"concurrency,omitempty" bson:"concurrency,omitempty"`               // 並行上限設定
+	Models             *ModelPolicy         `json:"models,omitempty" bson:"models,omitempty"`                         // 可用模型與別名
+	Request            *RequestPolicy       `json:"request,omitempty" bson:"request,omitempty"`                       // 請求參數防護
+	Prompt             *PromptPolicy        `json:"prompt,omitempty" bson:"prompt,omitempty"`                         // 強制 system prompt
+	Cache              *CachePolicy         `json:"cache,omitempty" bson:"cache,omitempty"`                           // 回應快取
+	Semantic           *SemanticCachePolicy `json:"semantic,omitempty" bson:"semantic,omitempty"`                     // 語意快取
+}
+
+// SemanticCachePolicy 語意快取設定（chat；以最後一則 user 訊息的 embedding 相似度比對）
+type SemanticCachePolicy struct {
+	Enabled   bool     `json:"enabled" bson:"enabled"`                         // 是否啟用
+	Threshold *float64 `json:"threshold,omitempty" bson:"threshold,omitempty"` // 相似度門檻（cosine，未設定使用系統預設）
+	Namespace string   `json:"namespace,omitempty" bson:"namespace,omitempty"` // 快取命名空間（未設定時為 API Key ID；多把 key 可共用）
+}
+
+// CachePolicy 回應快取設定（僅 temperature=0 的 chat 與 embedding）
+type CachePolicy struct {
+	Enabled    bool   `json:"enabled" bson:"enabled"`                           // 是否啟用
+	TTLSeconds *int   `json:"ttlSeconds,omitempty" bson:"ttlSeconds,omitempty"` // 快取秒數（未設定使用系統預設）
+	Namespace  string `json:"namespace,omitempty" bson:"namespace,omitempty"`   // 快取命名空間（未設定時為 API Key ID；多把 key 可共用）
+}
+
+// PromptPolicy 由 gateway 強制加入的 system message（例如合規聲明、語氣指引）
+type PromptPolicy struct {
+	Prepend string `json:"prepend,omitempty" bson:"prepend,omitempty"` // 置於所有訊息之前
+	Append  string `json:"append,omitempty" bson:"append,omitempty"`   // 置於所有訊息之後
+}
+
+// RequestPolicy 請求參數防護（key 皆為請求欄位名稱，例如 max_completion_tokens、n、size）
+type RequestPolicy struct {
+	Limits  map[string]NumericLimit `json:"limits,omitempty" bson:"limits,omitempty"`   // 數值範圍（字串為長度、上傳檔案為位元組數）
+	Allowed map[string][]string     `json:"allowed,omitempty" bson:"allowed,omitempty"` // 允許值（例如 size、quality）
+	Force   map[string]interface{}  `json:"force,omitempty" bson:"force,omitempty"`     // 強制值（例如 store=false、safety_identifier={{userID}}）
+	Strip   []string                `json:"strip,omitempty" bson:"strip,omitempty"`     // 轉發前移除的欄位（例如 tools、web_search_options）
+}
+
+// NumericLimit 數值上下限
+type NumericLimit struct {
+	Min    *float64         `json:"min,omitempty" bson:"min,omitempty"`
+	Max    *float64         `json:"max,omitempty" bson:"max,omitempty"`
+	Action core.LimitAction `json:"action" bson:"action"` // clamp / reject（預設 clamp）
+}
+
+// ModelPolicy 可用模型限制（glob，例如 gpt-4o-mini*）；deny 優先於 allow，allow 為空代表不限制
+type ModelPolicy struct {
+	Allow   []string          `json:"allow,omitempty" bson:"allow,omitempty"`     // 允許的模型
+	Deny    []string          `json:"deny,omitempty" bson:"deny,omitempty"`       // 禁止的模型
+	Aliases map[string]string `json:"aliases,omitempty" bson:"aliases,omitempty"` // 別名 → 實際模型（轉發前改寫）
+}
+
+// ConcurrencyPolicy 同時進行中（in-flight）請求的上限
+type ConcurrencyPolicy struct {
+	MaxInFlight         *int                 `json:"maxInFlight,omitempty" bson:"maxInFlight,omitempty"`                 // 此 API Key 於該 provider 的並行上限
+	UpstreamMaxInFlight *int                 `json:"upstreamMaxInFlight,omitempty" bson:"upstreamMaxInFlight,omitempty"` // 綁定的上游 provider key 並行上限（跨所有 API Key）
+	Mode                core.ConcurrencyMode `json:"mode" bson:"mode"`                                                   // reject / queue
+	QueueTimeoutMs      *int                 `json:"queueTimeoutMs,omitempty" bson:"queueTimeoutMs,omitempty"`           // 排隊最長等待毫秒
+	Priority            core.PriorityTier    `json:"priority,omitempty" bson:"priority,omitempty"`                       // 上游飽和時的優先層級
+	Weight              *int                 `json:"weight,omitempty" bson:"weight,omitempty"`                           // 同層級內的公平排隊權重（預設 1）
 }
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type AnomalyEventRepository struct {
+	trace      *telemetry.Trace
+	config     *config.Configuration
+	collection *mongo.Collection
+}
+
+func NewAnomalyEventRepository(config *config.Configuration, trace *telemetry.Trace, mongoClient *client.MongoClient) *AnomalyEventRepository {
+	repository := &AnomalyEventRepository{
+		trace:      trace,
+		config:     config,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionAnomalyEvents)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：依 API Key、使用者、訊號查詢（新到舊）；設定保留天數時以 TTL 索引自動刪除
+func (repository *AnomalyEventRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "apiKeyID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_apiKeyID_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_userID_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "signal", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_signal_createdAt"),
+		},
+	}
+	if days := repository.config.Anomaly.EventRetentionDays; days > 0 {
+		indexModels = append(indexModels, mongo.IndexModel{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("ttl_createdAt").SetExpireAfterSeconds(int32(days * 24 * 60 * 60)),
+		})
+	} else {
+		indexModels = append(indexModels, mongo.IndexModel{
+			Keys:    bson.D{{Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_createdAt_desc"),
+		})
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Insert：新增一筆異常事件
+func (repository *AnomalyEventRepository) Insert(
+	contextValue context.Context,
+	event *model.AnomalyEvent,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("api_key.id", event.APIKeyID.Hex()),
+		attribute.String("anomaly.signal", string(event.Signal)),
+	)
+
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	_, returnedError = repository.collection.InsertOne(contextValue, event)
+	return returnedError
+}
+
+// List：分頁查詢（新到舊，page 為 0 起算）
+func (repository *AnomalyEventRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.AnomalyEvent, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, findError := repository.collection.Find(contextValue, listOptions.Filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var events []*model.AnomalyEvent
+	if returnedError = cursor.All(contextValue, &events); returnedError != nil {
+		return nil, returnedError
+	}
+	return events, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type AuditLogRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewAuditLogRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *AuditLogRepository {
+	repository := &AuditLogRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionAuditLogs)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：依對象、動作、操作者、request ID 查詢（新到舊）
+func (repository *AuditLogRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "targetType", Value: 1}, {Key: "targetID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_target_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "action", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_action_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "actor", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_actor_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "requestID", Value: 1}},
+			Options: options.Index().SetName("idx_requestID").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_createdAt_desc"),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Insert：新增一筆稽核紀錄（不提供更新 / 刪除）
+func (repository *AuditLogRepository) Insert(
+	contextValue context.Context,
+	entry *model.AuditLog,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("audit.action", string(entry.Action)))
+
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	_, returnedError = repository.collection.InsertOne(contextValue, entry)
+	return returnedError
+}
+
+// GetByID：單筆讀取
+func (repository *AuditLogRepository) GetByID(
+	contextValue context.Context,
+	auditIdentifier primitive.ObjectID,
+) (_ *model.AuditLog, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("audit.id", auditIdentifier.Hex()))
+
+	var entry model.AuditLog
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": auditIdentifier}).Decode(&entry); returnedError != nil {
+		return nil, returnedError
+	}
+	return &entry, nil
+}
+
+// List：分頁查詢（新到舊，page 為 0 起算）
+func (repository *AuditLogRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.AuditLog, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, findError := repository.collection.Find(contextValue, listOptions.Filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var entries []*model.AuditLog
+	if returnedError = cursor.All(contextValue, &entries); returnedError != nil {
+		return nil, returnedError
+	}
+	return entries, nil
+}
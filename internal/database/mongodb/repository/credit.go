@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type CreditAccountRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewCreditAccountRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *CreditAccountRepository {
+	repository := &CreditAccountRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionCreditAccounts)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：scope + scopeID 唯一
+func (repository *CreditAccountRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "scopeID", Value: 1}},
+			Options: options.Index().SetName("uniq_scope_scopeID").SetUnique(true),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// GetByScope：取得帳戶（不存在時回傳 mongo.ErrNoDocuments）
+func (repository *CreditAccountRepository) GetByScope(
+	contextValue context.Context,
+	scope core.CreditScope,
+	scopeID string,
+) (_ *model.CreditAccount, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("credit.scope", string(scope)),
+		attribute.String("credit.scope_id", scopeID),
+	)
+
+	var account model.CreditAccount
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"scope": scope, "scopeID": scopeID}).Decode(&account); returnedError != nil {
+		return nil, returnedError
+	}
+	return &account, nil
+}
+
+// IncrBalance：原子累加餘額（帳戶不存在時建立），回傳更新後的帳戶
+func (repository *CreditAccountRepository) IncrBalance(
+	contextValue context.Context,
+	scope core.CreditScope,
+	scopeID string,
+	amount float64,
+	currency string,
+) (_ *model.CreditAccount, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("credit.scope", string(scope)),
+		attribute.String("credit.scope_id", scopeID),
+		attribute.Float64("credit.amount", amount),
+	)
+
+	update := withUpdatedAt(bson.M{
+		"$inc": bson.M{"balance": amount},
+		"$setOnInsert": bson.M{
+			"_id":       primitive.NewObjectID(),
+			"currency":  currency,
+			"createdAt": time.Now().UTC(),
+		},
+	})
+	updateOptions := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var account model.CreditAccount
+	if returnedError = repository.collection.FindOneAndUpdate(
+		contextValue,
+		bson.M{"scope": scope, "scopeID": scopeID},
+		update,
+		updateOptions,
+	).Decode(&account); returnedError != nil {
+		return nil, returnedError
+	}
+	return &account, nil
+}
+
+type CreditLedgerRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewCreditLedgerRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *CreditLedgerRepository {
+	repository := &CreditLedgerRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionCreditLedger)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：scope + scopeID + createdAt、idempotencyKey 唯一
+func (repository *CreditLedgerRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "scopeID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_scope_scopeID_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "idempotencyKey", Value: 1}},
+			Options: options.Index().SetName("uniq_idempotencyKey").SetUnique(true),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Insert：新增分錄；冪等鍵重複時回傳 duplicate key 錯誤（mongo.IsDuplicateKeyError）
+func (repository *CreditLedgerRepository) Insert(
+	contextValue context.Context,
+	entry *model.CreditLedgerEntry,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("credit.scope", string(entry.Scope)),
+		attribute.String("credit.scope_id", entry.ScopeID),
+		attribute.String("credit.type", string(entry.Type)),
+	)
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	_, returnedError = repository.collection.InsertOne(contextValue, entry)
+	return returnedError
+}
+
+// ExistsByIdempotencyKey：是否已有此冪等鍵的分錄
+func (repository *CreditLedgerRepository) ExistsByIdempotencyKey(
+	contextValue context.Context,
+	idempotencyKey string,
+) (_ bool, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	count, countError := repository.collection.CountDocuments(
+		contextValue,
+		bson.M{"idempotencyKey": idempotencyKey},
+		options.Count().SetLimit(1),
+	)
+	if countError != nil {
+		returnedError = countError
+		return false, returnedError
+	}
+	return count > 0, nil
+}
+
+// ListByScope：帳戶分錄（新到舊，page 為 0 起算）；filter 可帶 type、createdAt 條件
+func (repository *CreditLedgerRepository) ListByScope(
+	contextValue context.Context,
+	scope core.CreditScope,
+	scopeID string,
+	listOptions core.ListOptions,
+) (_ []*model.CreditLedgerEntry, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	filter := bson.M{}
+	for key, value := range listOptions.Filter {
+		filter[key] = value
+	}
+	filter["scope"] = scope
+	filter["scopeID"] = scopeID
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, findError := repository.collection.Find(contextValue, filter, findOptions)
+	if findError != nil {
+		returnedError = findError
+		return nil, returnedError
+	}
+	defer cursor.Close(contextValue)
+
+	var entries []*model.CreditLedgerEntry
+	if returnedError = cursor.All(contextValue, &entries); returnedError != nil {
+		return nil, returnedError
+	}
+	return entries, nil
+}
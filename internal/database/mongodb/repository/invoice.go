@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type InvoiceRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewInvoiceRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *InvoiceRepository {
+	repository := &InvoiceRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionInvoices)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：同一對象同一帳期唯一、帳單編號唯一
+func (repository *InvoiceRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "scopeID", Value: 1}, {Key: "period", Value: 1}},
+			Options: options.Index().SetName("uniq_scope_scopeID_period").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "number", Value: 1}},
+			Options: options.Index().SetName("uniq_number").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "period", Value: -1}, {Key: "scope", Value: 1}},
+			Options: options.Index().SetName("idx_period_scope"),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Insert：新增帳單（帳單只新增不修改）；同帳期重複時回傳 duplicate key 錯誤
+func (repository *InvoiceRepository) Insert(
+	contextValue context.Context,
+	invoice *model.Invoice,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("invoice.scope", string(invoice.Scope)),
+		attribute.String("invoice.scope_id", invoice.ScopeID),
+		attribute.String("invoice.period", invoice.Period),
+	)
+	if invoice.ID.IsZero() {
+		invoice.ID = primitive.NewObjectID()
+	}
+	_, returnedError = repository.collection.InsertOne(contextValue, invoice)
+	return returnedError
+}
+
+// GetByID：單文件讀取
+func (repository *InvoiceRepository) GetByID(
+	contextValue context.Context,
+	invoiceIdentifier primitive.ObjectID,
+) (_ *model.Invoice, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("invoice.id", invoiceIdentifier.Hex()))
+
+	var invoice model.Invoice
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": invoiceIdentifier}).Decode(&invoice); returnedError != nil {
+		return nil, returnedError
+	}
+	return &invoice, nil
+}
+
+// GetByPeriod：取得對象在帳期的帳單
+func (repository *InvoiceRepository) GetByPeriod(
+	contextValue context.Context,
+	scope core.InvoiceScope,
+	scopeID string,
+	period string,
+) (_ *model.Invoice, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("invoice.scope", string(scope)),
+		attribute.String("invoice.scope_id", scopeID),
+		attribute.String("invoice.period", period),
+	)
+
+	var invoice model.Invoice
+	filter := bson.M{"scope": scope, "scopeID": scopeID, "period": period}
+	if returnedError = repository.collection.FindOne(contextValue, filter).Decode(&invoice); returnedError != nil {
+		return nil, returnedError
+	}
+	return &invoice, nil
+}
+
+// List：分頁查詢（page 為 0 起算，不含明細）
+func (repository *InvoiceRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.Invoice, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.D{{Key: "period", Value: -1}, {Key: "scope", Value: 1}, {Key: "scopeID", Value: 1}}).
+		SetProjection(bson.M{"lineItems": 0})
+
+	filter := listOptions.Filter
+	if filter == nil {
+		filter = bson.M{}
+	}
+	cursor, findError := repository.collection.Find(contextValue, filter, findOptions)
+	if findError != nil {
+		returnedError = findError
+		return nil, returnedError
+	}
+	defer cursor.Close(contextValue)
+
+	var invoices []*model.Invoice
+	if returnedError = cursor.All(contextValue, &invoices); returnedError != nil {
+		return nil, returnedError
+	}
+	return invoices, nil
+}
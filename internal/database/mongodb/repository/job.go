@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type JobRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewJobRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *JobRepository {
+	return &JobRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionJobs)),
+	}
+}
+
+// List：所有工作狀態
+func (repository *JobRepository) List(
+	contextValue context.Context,
+) (_ []*model.JobState, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	cursor, findError := repository.collection.Find(contextValue, bson.M{})
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var states []*model.JobState
+	if returnedError = cursor.All(contextValue, &states); returnedError != nil {
+		return nil, returnedError
+	}
+	return states, nil
+}
+
+// GetByName：單一工作狀態（尚未有紀錄時回傳 mongo.ErrNoDocuments）
+func (repository *JobRepository) GetByName(
+	contextValue context.Context,
+	name string,
+) (_ *model.JobState, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("job.name", name))
+
+	var state model.JobState
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": name}).Decode(&state); returnedError != nil {
+		return nil, returnedError
+	}
+	return &state, nil
+}
+
+// SetPaused：暫停 / 恢復排程（不存在時建立）
+func (repository *JobRepository) SetPaused(
+	contextValue context.Context,
+	name string,
+	paused bool,
+	pausedBy string,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("job.name", name), attribute.Bool("job.paused", paused))
+
+	update := bson.M{}
+	if paused {
+		update["$set"] = bson.M{"paused": true, "pausedBy": pausedBy, "pausedAt": time.Now().UTC()}
+	} else {
+		update["$set"] = bson.M{"paused": false}
+		update["$unset"] = bson.M{"pausedBy": "", "pausedAt": ""}
+	}
+	_, returnedError = repository.collection.UpdateOne(contextValue, bson.M{"_id": name}, withUpdatedAt(update), options.Update().SetUpsert(true))
+	return returnedError
+}
+
+// RecordLastRun：更新最後一次執行結果（不存在時建立）
+func (repository *JobRepository) RecordLastRun(
+	contextValue context.Context,
+	run *model.JobRun,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("job.name", run.Job))
+
+	update := bson.M{
+		"$set": bson.M{
+			"lastRunAt":      run.StartedAt,
+			"lastStatus":     run.Status,
+			"lastDurationMs": run.DurationMs,
+		},
+		"$setOnInsert": bson.M{"paused": false},
+	}
+	_, returnedError = repository.collection.UpdateOne(contextValue, bson.M{"_id": run.Job}, withUpdatedAt(update), options.Update().SetUpsert(true))
+	return returnedError
+}
+
+type JobRunRepository struct {
+	trace      *telemetry.Trace
+	config     *config.Configuration
+	collection *mongo.Collection
+}
+
+func NewJobRunRepository(config *config.Configuration, trace *telemetry.Trace, mongoClient *client.MongoClient) *JobRunRepository {
+	repository := &JobRunRepository{
+		trace:      trace,
+		config:     config,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionJobRuns)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：job + startedAt、status；設定保留天數時以 TTL 索引自動刪除
+func (repository *JobRunRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "job", Value: 1}, {Key: "startedAt", Value: -1}},
+			Options: options.Index().SetName("idx_job_startedAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetName("idx_status"),
+		},
+	}
+	if days := repository.config.Job.HistoryRetentionDays; days > 0 {
+		indexModels = append(indexModels, mongo.IndexModel{
+			Keys:    bson.D{{Key: "startedAt", Value: 1}},
+			Options: options.Index().SetName("ttl_startedAt").SetExpireAfterSeconds(int32(days * 24 * 60 * 60)),
+		})
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Insert：新增執行紀錄（開始時寫入，結束時以 Finish 更新）
+func (repository *JobRunRepository) Insert(
+	contextValue context.Context,
+	run *model.JobRun,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	if run.ID.IsZero() {
+		run.ID = primitive.NewObjectID()
+	}
+	span.SetAttributes(attribute.String("job.name", run.Job), attribute.String("job.run_id", run.ID.Hex()))
+	_, returnedError = repository.collection.InsertOne(contextValue, run)
+	return returnedError
+}
+
+// Finish：寫入執行結果
+func (repository *JobRunRepository) Finish(
+	contextValue context.Context,
+	run *model.JobRun,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("job.run_id", run.ID.Hex()), attribute.String("job.status", string(run.Status)))
+
+	set := bson.M{
+		"status":     run.Status,
+		"finishedAt": run.FinishedAt,
+		"durationMs": run.DurationMs,
+	}
+	if run.Message != "" {
+		set["message"] = run.Message
+	}
+	if run.Error != "" {
+		set["error"] = run.Error
+	}
+	_, returnedError = repository.collection.UpdateOne(contextValue, bson.M{"_id": run.ID}, bson.M{"$set": set})
+	return returnedError
+}
+
+// GetByID：單筆讀取
+func (repository *JobRunRepository) GetByID(
+	contextValue context.Context,
+	runIdentifier primitive.ObjectID,
+) (_ *model.JobRun, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("job.run_id", runIdentifier.Hex()))
+
+	var run model.JobRun
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": runIdentifier}).Decode(&run); returnedError != nil {
+		return nil, returnedError
+	}
+	return &run, nil
+}
+
+// List：分頁查詢（新到舊，page 為 0 起算）
+func (repository *JobRunRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.JobRun, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.D{{Key: "startedAt", Value: -1}})
+
+	cursor, findError := repository.collection.Find(contextValue, listOptions.Filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var runs []*model.JobRun
+	if returnedError = cursor.All(contextValue, &runs); returnedError != nil {
+		return nil, returnedError
+	}
+	return runs, nil
+}
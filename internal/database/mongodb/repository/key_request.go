@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type KeyRequestRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewKeyRequestRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *KeyRequestRepository {
+	repository := &KeyRequestRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionKeyRequests)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：申請者、審核狀態（新到舊）
+func (repository *KeyRequestRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_userID_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_status_createdAt"),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Create：新增申請
+func (repository *KeyRequestRepository) Create(
+	contextValue context.Context,
+	request *model.KeyRequest,
+) (_ *model.KeyRequest, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("user.id", request.UserID.Hex()))
+
+	now := time.Now().UTC()
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	request.CreatedAt = now
+	request.UpdatedAt = now
+	if _, returnedError = repository.collection.InsertOne(contextValue, request); returnedError != nil {
+		return nil, returnedError
+	}
+	return request, nil
+}
+
+// GetByID：單筆讀取
+func (repository *KeyRequestRepository) GetByID(
+	contextValue context.Context,
+	requestIdentifier primitive.ObjectID,
+) (_ *model.KeyRequest, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("key_request.id", requestIdentifier.Hex()))
+
+	var request model.KeyRequest
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": requestIdentifier}).Decode(&request); returnedError != nil {
+		return nil, returnedError
+	}
+	return &request, nil
+}
+
+// List：分頁查詢（新到舊，page 為 0 起算）
+func (repository *KeyRequestRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.KeyRequest, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, findError := repository.collection.Find(contextValue, listOptions.Filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var requests []*model.KeyRequest
+	if returnedError = cursor.All(contextValue, &requests); returnedError != nil {
+		return nil, returnedError
+	}
+	return requests, nil
+}
+
+// CountPending：使用者待審核的申請數
+func (repository *KeyRequestRepository) CountPending(
+	contextValue context.Context,
+	userIdentifier primitive.ObjectID,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("user.id", userIdentifier.Hex()))
+
+	return repository.collection.CountDocuments(contextValue, bson.M{"userID": userIdentifier, "status": core.StatusPending})
+}
+
+// Decide：待審核的申請寫入審核結果（條件更新，回傳是否由這次呼叫取得；已被其他審核者處理時為 false）
+func (repository *KeyRequestRepository) Decide(
+	contextValue context.Context,
+	requestIdentifier primitive.ObjectID,
+	status core.Status,
+	reviewer string,
+	note string,
+	approvedAccess []model.KeyRequestAccess,
+	now time.Time,
+) (_ bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("key_request.id", requestIdentifier.Hex()),
+		attribute.String("key_request.status", string(status)),
+	)
+
+	set := bson.M{
+		"status":     status,
+		"reviewedBy": reviewer,
+		"reviewNote": note,
+		"reviewedAt": now,
+		"updatedAt":  now,
+	}
+	if len(approvedAccess) > 0 {
+		set["approvedAccess"] = approvedAccess
+	}
+	result, err := repository.collection.UpdateOne(
+		contextValue,
+		bson.M{"_id": requestIdentifier, "status": core.StatusPending},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// SetAPIKeyID：記錄核發的 API Key
+func (repository *KeyRequestRepository) SetAPIKeyID(
+	contextValue context.Context,
+	requestIdentifier primitive.ObjectID,
+	apiKeyIdentifier primitive.ObjectID,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("key_request.id", requestIdentifier.Hex()))
+
+	_, returnedError = repository.collection.UpdateOne(
+		contextValue,
+		bson.M{"_id": requestIdentifier},
+		bson.M{"$set": bson.M{"apiKeyID": apiKeyIdentifier, "updatedAt": time.Now().UTC()}},
+	)
+	return returnedError
+}
+
+// Reopen：核准後核發失敗時退回待審核
+func (repository *KeyRequestRepository) Reopen(
+	contextValue context.Context,
+	requestIdentifier primitive.ObjectID,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("key_request.id", requestIdentifier.Hex()))
+
+	_, returnedError = repository.collection.UpdateOne(
+		contextValue,
+		bson.M{"_id": requestIdentifier, "status": core.StatusApproved, "apiKeyID": bson.M{"$exists": false}},
+		bson.M{
+			"$set":   bson.M{"status": core.StatusPending, "updatedAt": time.Now().UTC()},
+			"$unset": bson.M{"reviewedBy": "", "reviewNote": "", "reviewedAt": "", "approvedAccess": ""},
+		},
+	)
+	return returnedError
+}
@@ -0,0 +1,424 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type NotificationRuleRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewNotificationRuleRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *NotificationRuleRepository {
+	repository := &NotificationRuleRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionNotificationRules)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：scope + scopeID、enabled
+func (repository *NotificationRuleRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "scopeID", Value: 1}},
+			Options: options.Index().SetName("idx_scope_scopeID"),
+		},
+		{
+			Keys:    bson.D{{Key: "enabled", Value: 1}},
+			Options: options.Index().SetName("idx_enabled"),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Create：單文件插入
+func (repository *NotificationRuleRepository) Create(
+	contextValue context.Context,
+	rule *model.NotificationRule,
+) (_ *model.NotificationRule, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	nowUTC := time.Now().UTC()
+	if rule.ID.IsZero() {
+		rule.ID = primitive.NewObjectID()
+	}
+	rule.CreatedAt = nowUTC
+	rule.UpdatedAt = nowUTC
+
+	insertResult, insertError := repository.collection.InsertOne(contextValue, rule)
+	if insertError != nil {
+		return nil, insertError
+	}
+	objectID, ok := insertResult.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("unexpected InsertedID type: %T", insertResult.InsertedID)
+	}
+	rule.ID = objectID
+	return rule, nil
+}
+
+// GetByID：單文件讀取
+func (repository *NotificationRuleRepository) GetByID(
+	contextValue context.Context,
+	ruleIdentifier primitive.ObjectID,
+) (_ *model.NotificationRule, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("notification.rule_id", ruleIdentifier.Hex()))
+
+	var rule model.NotificationRule
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": ruleIdentifier}).Decode(&rule); returnedError != nil {
+		return nil, returnedError
+	}
+	return &rule, nil
+}
+
+// List：分頁查詢（page 為 0 起算）
+func (repository *NotificationRuleRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.NotificationRule, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.M{"createdAt": -1})
+
+	cursor, findError := repository.collection.Find(contextValue, listOptions.Filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var rules []*model.NotificationRule
+	if returnedError = cursor.All(contextValue, &rules); returnedError != nil {
+		return nil, returnedError
+	}
+	return rules, nil
+}
+
+// ListEnabled：所有啟用中的規則（供通知服務快取比對）
+func (repository *NotificationRuleRepository) ListEnabled(
+	contextValue context.Context,
+) (_ []*model.NotificationRule, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	cursor, findError := repository.collection.Find(contextValue, bson.M{"enabled": true})
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var rules []*model.NotificationRule
+	if returnedError = cursor.All(contextValue, &rules); returnedError != nil {
+		return nil, returnedError
+	}
+	return rules, nil
+}
+
+// UpdateByID：將呼叫端給的欄位寫入 $set / $unset
+func (repository *NotificationRuleRepository) UpdateByID(
+	contextValue context.Context,
+	ruleIdentifier primitive.ObjectID,
+	setFields bson.M,
+	unsetFields bson.M,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("notification.rule_id", ruleIdentifier.Hex()))
+
+	update := bson.M{}
+	if len(setFields) > 0 {
+		update["$set"] = setFields
+	}
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
+	result, updateError := repository.collection.UpdateOne(contextValue, bson.M{"_id": ruleIdentifier}, withUpdatedAt(update))
+	if updateError != nil {
+		return 0, updateError
+	}
+	return result.MatchedCount, nil
+}
+
+// DeleteByID：單文件刪除，回傳刪除筆數
+func (repository *NotificationRuleRepository) DeleteByID(
+	contextValue context.Context,
+	ruleIdentifier primitive.ObjectID,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("notification.rule_id", ruleIdentifier.Hex()))
+	result, deleteError := repository.collection.DeleteOne(contextValue, bson.M{"_id": ruleIdentifier})
+	if deleteError != nil {
+		return 0, deleteError
+	}
+	return result.DeletedCount, nil
+}
+
+type NotificationDeliveryRepository struct {
+	trace      *telemetry.Trace
+	config     *config.Configuration
+	collection *mongo.Collection
+}
+
+func NewNotificationDeliveryRepository(config *config.Configuration, trace *telemetry.Trace, mongoClient *client.MongoClient) *NotificationDeliveryRepository {
+	repository := &NotificationDeliveryRepository{
+		trace:      trace,
+		config:     config,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionNotificationDeliveries)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：status + nextAttemptAt（worker 取件）、ruleID + createdAt；設定保留天數時以 TTL 索引自動刪除
+func (repository *NotificationDeliveryRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			Options: options.Index().SetName("idx_status_nextAttemptAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "ruleID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_ruleID_createdAt"),
+		},
+	}
+	if days := repository.config.Notification.DeliveryRetentionDays; days > 0 {
+		indexModels = append(indexModels, mongo.IndexModel{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("ttl_createdAt").SetExpireAfterSeconds(int32(days * 24 * 60 * 60)),
+		})
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// InsertMany：批次新增待投遞紀錄
+func (repository *NotificationDeliveryRepository) InsertMany(
+	contextValue context.Context,
+	deliveries []*model.NotificationDelivery,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.Int("notification.deliveries", len(deliveries)))
+	if len(deliveries) == 0 {
+		return nil
+	}
+	nowUTC := time.Now().UTC()
+	documents := make([]interface{}, len(deliveries))
+	for i, delivery := range deliveries {
+		if delivery.ID.IsZero() {
+			delivery.ID = primitive.NewObjectID()
+		}
+		delivery.CreatedAt = nowUTC
+		delivery.UpdatedAt = nowUTC
+		documents[i] = delivery
+	}
+	_, returnedError = repository.collection.InsertMany(contextValue, documents)
+	return returnedError
+}
+
+// ClaimDue：取出一筆到期的待投遞紀錄並累加嘗試次數，同時將下次嘗試時間延後 lease
+// （投遞中程序中斷時，lease 到期後由其他 replica 重試）；無待投遞時回傳 mongo.ErrNoDocuments
+func (repository *NotificationDeliveryRepository) ClaimDue(
+	contextValue context.Context,
+	now time.Time,
+	lease time.Duration,
+) (_ *model.NotificationDelivery, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		if returnedError == mongo.ErrNoDocuments {
+			endSpan(nil)
+			return
+		}
+		endSpan(returnedError)
+	}()
+
+	update := withUpdatedAt(bson.M{
+		"$set": bson.M{"nextAttemptAt": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	})
+	updateOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery model.NotificationDelivery
+	if returnedError = repository.collection.FindOneAndUpdate(
+		contextValue,
+		bson.M{"status": core.NotificationDeliveryPending, "nextAttemptAt": bson.M{"$lte": now}},
+		update,
+		updateOptions,
+	).Decode(&delivery); returnedError != nil {
+		return nil, returnedError
+	}
+	return &delivery, nil
+}
+
+// MarkDelivered：投遞成功
+func (repository *NotificationDeliveryRepository) MarkDelivered(
+	contextValue context.Context,
+	deliveryIdentifier primitive.ObjectID,
+	deliveredAt time.Time,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("notification.delivery_id", deliveryIdentifier.Hex()))
+	update := withUpdatedAt(bson.M{
+		"$set":   bson.M{"status": core.NotificationDeliveryDelivered, "deliveredAt": deliveredAt},
+		"$unset": bson.M{"lastError": ""},
+	})
+	_, returnedError = repository.collection.UpdateOne(contextValue, bson.M{"_id": deliveryIdentifier}, update)
+	return returnedError
+}
+
+// MarkFailed：投遞失敗；dead 為 true 時不再重試
+func (repository *NotificationDeliveryRepository) MarkFailed(
+	contextValue context.Context,
+	deliveryIdentifier primitive.ObjectID,
+	lastError string,
+	nextAttemptAt time.Time,
+	dead bool,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("notification.delivery_id", deliveryIdentifier.Hex()),
+		attribute.Bool("notification.dead", dead),
+	)
+	status := core.NotificationDeliveryPending
+	if dead {
+		status = core.NotificationDeliveryDead
+	}
+	update := withUpdatedAt(bson.M{
+		"$set": bson.M{"status": status, "lastError": lastError, "nextAttemptAt": nextAttemptAt},
+	})
+	_, returnedError = repository.collection.UpdateOne(contextValue, bson.M{"_id": deliveryIdentifier}, update)
+	return returnedError
+}
+
+// Requeue：將 dead-letter 重新排入投遞（嘗試次數歸零），回傳符合筆數
+func (repository *NotificationDeliveryRepository) Requeue(
+	contextValue context.Context,
+	deliveryIdentifier primitive.ObjectID,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("notification.delivery_id", deliveryIdentifier.Hex()))
+	update := withUpdatedAt(bson.M{
+		"$set": bson.M{"status": core.NotificationDeliveryPending, "attempts": 0, "nextAttemptAt": time.Now().UTC()},
+	})
+	result, updateError := repository.collection.UpdateOne(
+		contextValue,
+		bson.M{"_id": deliveryIdentifier, "status": core.NotificationDeliveryDead, "secret": bson.M{"$ne": true}},
+		update,
+	)
+	if updateError != nil {
+		return 0, updateError
+	}
+	return result.MatchedCount, nil
+}
+
+// GetByID：單文件讀取
+func (repository *NotificationDeliveryRepository) GetByID(
+	contextValue context.Context,
+	deliveryIdentifier primitive.ObjectID,
+) (_ *model.NotificationDelivery, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("notification.delivery_id", deliveryIdentifier.Hex()))
+
+	var delivery model.NotificationDelivery
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": deliveryIdentifier}).Decode(&delivery); returnedError != nil {
+		return nil, returnedError
+	}
+	return &delivery, nil
+}
+
+// List：投遞紀錄（新到舊，page 為 0 起算）；filter 可帶 status、ruleID、event
+func (repository *NotificationDeliveryRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.NotificationDelivery, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(listOptions.Page * listOptions.Size).
+		SetLimit(listOptions.Size).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, findError := repository.collection.Find(contextValue, listOptions.Filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var deliveries []*model.NotificationDelivery
+	if returnedError = cursor.All(contextValue, &deliveries); returnedError != nil {
+		return nil, returnedError
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type OrganizationRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewOrganizationRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *OrganizationRepository {
+	repository := &OrganizationRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionOrganizations)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：組織名稱唯一
+func (repository *OrganizationRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetName("uniq_name").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_createdAt_desc"),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Create：單文件插入
+func (repository *OrganizationRepository) Create(
+	contextValue context.Context,
+	organization *model.Organization,
+) (_ *model.Organization, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	nowUTC := time.Now().UTC()
+	if organization.ID.IsZero() {
+		organization.ID = primitive.NewObjectID()
+	}
+	organization.CreatedAt = nowUTC
+	organization.UpdatedAt = nowUTC
+
+	insertResult, insertError := repository.collection.InsertOne(contextValue, organization)
+	if insertError != nil {
+		return nil, insertError
+	}
+	objectID, ok := insertResult.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("unexpected InsertedID type: %T", insertResult.InsertedID)
+	}
+	organization.ID = objectID
+	return organization, nil
+}
+
+// GetByID：單文件讀取
+func (repository *OrganizationRepository) GetByID(
+	contextValue context.Context,
+	organizationIdentifier primitive.ObjectID,
+) (_ *model.Organization, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("org.id", organizationIdentifier.Hex()))
+
+	var organization model.Organization
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"_id": organizationIdentifier}).Decode(&organization); returnedError != nil {
+		return nil, returnedError
+	}
+	return &organization, nil
+}
+
+// List：分頁查詢（page 為 0 起算）
+func (repository *OrganizationRepository) List(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.Organization, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	findOptions := options.Find().
+		SetSkip(int64(listOptions.Page) * int64(listOptions.Size)).
+		SetLimit(int64(listOptions.Size)).
+		SetSort(bson.M{"createdAt": -1})
+
+	cursor, findError := repository.collection.Find(contextValue, listOptions.Filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var organizations []*model.Organization
+	if returnedError = cursor.All(contextValue, &organizations); returnedError != nil {
+		return nil, returnedError
+	}
+	return organizations, nil
+}
+
+// UpdateByID：將呼叫端給的欄位寫入 $set
+func (repository *OrganizationRepository) UpdateByID(
+	contextValue context.Context,
+	organizationIdentifier primitive.ObjectID,
+	setFields bson.M,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("org.id", organizationIdentifier.Hex()))
+
+	update := bson.M{"$set": setFields}
+	result, updateError := repository.collection.UpdateOne(contextValue, bson.M{"_id": organizationIdentifier}, withUpdatedAt(update))
+	if updateError != nil {
+		return 0, updateError
+	}
+	return result.MatchedCount, nil
+}
+
+// UpdateQuotaPolicy：覆寫組織配額；policy 為 nil 時移除配額
+func (repository *OrganizationRepository) UpdateQuotaPolicy(
+	contextValue context.Context,
+	organizationIdentifier primitive.ObjectID,
+	policy *model.QuotaPolicy,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("org.id", organizationIdentifier.Hex()))
+
+	update := bson.M{"$set": bson.M{"quotaPolicy": policy}}
+	if policy == nil {
+		update = bson.M{"$unset": bson.M{"quotaPolicy": ""}}
+	}
+	result, updateError := repository.collection.UpdateOne(contextValue, bson.M{"_id": organizationIdentifier}, withUpdatedAt(update))
+	if updateError != nil {
+		return 0, updateError
+	}
+	return result.MatchedCount, nil
+}
+
+// DeleteByID：單文件刪除
+func (repository *OrganizationRepository) DeleteByID(
+	contextValue context.Context,
+	organizationIdentifier primitive.ObjectID,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("org.id", organizationIdentifier.Hex()))
+	_, returnedError = repository.collection.DeleteOne(contextValue, bson.M{"_id": organizationIdentifier})
+	return returnedError
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type PromptTemplateRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewPromptTemplateRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *PromptTemplateRepository {
+	repository := &PromptTemplateRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionPromptTemplates)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：name + version 唯一
+func (repository *PromptTemplateRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetName("uniq_name_version").SetUnique(true),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// CreateVersion：以目前最新版本 +1 新增一版；同時寫入衝突時重試
+func (repository *PromptTemplateRepository) CreateVersion(
+	contextValue context.Context,
+	template *model.PromptTemplate,
+) (_ *model.PromptTemplate, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("template.name", template.Name))
+
+	for attempt := 0; attempt < 3; attempt++ {
+		latest, latestError := repository.GetLatest(contextValue, template.Name)
+		if latestError != nil && !errors.Is(latestError, mongo.ErrNoDocuments) {
+			returnedError = latestError
+			return nil, returnedError
+		}
+		template.Version = 1
+		if latest != nil {
+			template.Version = latest.Version + 1
+		}
+
+		nowUTC := time.Now().UTC()
+		template.ID = primitive.NewObjectID()
+		template.CreatedAt = nowUTC
+		template.UpdatedAt = nowUTC
+
+		_, insertError := repository.collection.InsertOne(contextValue, template)
+		if insertError == nil {
+			span.SetAttributes(attribute.Int("template.version", template.Version))
+			return template, nil
+		}
+		if !mongo.IsDuplicateKeyError(insertError) {
+			returnedError = insertError
+			return nil, returnedError
+		}
+	}
+	returnedError = fmt.Errorf("create prompt template %s: version conflict", template.Name)
+	return nil, returnedError
+}
+
+// GetVersion：取得指定版本
+func (repository *PromptTemplateRepository) GetVersion(
+	contextValue context.Context,
+	name string,
+	version int,
+) (_ *model.PromptTemplate, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("template.name", name),
+		attribute.Int("template.version", version),
+	)
+
+	var template model.PromptTemplate
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"name": name, "version": version}).Decode(&template); returnedError != nil {
+		return nil, returnedError
+	}
+	return &template, nil
+}
+
+// GetLatest：取得最新版本
+func (repository *PromptTemplateRepository) GetLatest(
+	contextValue context.Context,
+	name string,
+) (_ *model.PromptTemplate, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("template.name", name))
+
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	var template model.PromptTemplate
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"name": name}, findOptions).Decode(&template); returnedError != nil {
+		return nil, returnedError
+	}
+	return &template, nil
+}
+
+// ListVersions：列出樣板所有版本（新到舊）
+func (repository *PromptTemplateRepository) ListVersions(
+	contextValue context.Context,
+	name string,
+) (_ []*model.PromptTemplate, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("template.name", name))
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, findError := repository.collection.Find(contextValue, bson.M{"name": name}, findOptions)
+	if findError != nil {
+		returnedError = findError
+		return nil, returnedError
+	}
+	defer cursor.Close(contextValue)
+
+	var templates []*model.PromptTemplate
+	if returnedError = cursor.All(contextValue, &templates); returnedError != nil {
+		return nil, returnedError
+	}
+	return templates, nil
+}
+
+// ListLatest：分頁列出各樣板的最新版本（page 為 0 起算）
+func (repository *PromptTemplateRepository) ListLatest(
+	contextValue context.Context,
+	listOptions core.ListOptions,
+) (_ []*model.PromptTemplate, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() {
+		repository.trace.ApplyTraceAttributes(span, map[string]any{
+			"filter": listOptions.Filter,
+			"page":   listOptions.Page,
+			"size":   listOptions.Size,
+		})
+		endSpan(returnedError)
+	}()
+
+	filter := listOptions.Filter
+	if filter == nil {
+		filter = bson.M{}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}, {Key: "version", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$name", "doc": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$doc"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}}}},
+		{{Key: "$skip", Value: listOptions.Page * listOptions.Size}},
+		{{Key: "$limit", Value: listOptions.Size}},
+	}
+	cursor, aggregateError := repository.collection.Aggregate(contextValue, pipeline)
+	if aggregateError != nil {
+		returnedError = aggregateError
+		return nil, returnedError
+	}
+	defer cursor.Close(contextValue)
+
+	var templates []*model.PromptTemplate
+	if returnedError = cursor.All(contextValue, &templates); returnedError != nil {
+		return nil, returnedError
+	}
+	return templates, nil
+}
+
+// DeleteByName：刪除樣板所有版本
+func (repository *PromptTemplateRepository) DeleteByName(
+	contextValue context.Context,
+	name string,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("template.name", name))
+	result, deleteError := repository.collection.DeleteMany(contextValue, bson.M{"name": name})
+	if deleteError != nil {
+		returnedError = deleteError
+		return 0, returnedError
+	}
+	return result.DeletedCount, nil
+}
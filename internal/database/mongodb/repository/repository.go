@@ -9,16 +9,19 @@ import (
 type MongoDBRepository struct {
 	userRepo             *UserRepository
 	userAPIKeyRepository *UserAPIKeyRepository
+	organizationRepo     *OrganizationRepository
 }
 
 // 建立 MySQL repository 物件
 func NewMongoDBRepository(
 	userRepo *UserRepository,
 	userAPIKeyRepository *UserAPIKeyRepository,
+	organizationRepo *OrganizationRepository,
 ) *MongoDBRepository {
 	return &MongoDBRepository{
 		userRepo:             userRepo,
 		userAPIKeyRepository: userAPIKeyRepository,
+		organizationRepo:     organizationRepo,
 	}
 }
 
@@ -26,6 +29,21 @@ func NewMongoDBRepository(
 var ProviderSet = wire.NewSet(
 	NewUserRepository,
 	NewUserAPIKeyRepository,
+	NewOrganizationRepository,
+	NewPromptTemplateRepository,
+	NewSemanticCacheRepository,
+	NewUsageRecordRepository,
+	NewCreditAccountRepository,
+	NewCreditLedgerRepository,
+	NewInvoiceRepository,
+	NewNotificationRuleRepository,
+	NewNotificationDeliveryRepository,
+	NewJobRepository,
+	NewJobRunRepository,
+	NewUsageRollupRepository,
+	NewAuditLogRepository,
+	NewKeyRequestRepository,
+	NewAnomalyEventRepository,
 	NewMongoDBRepository)
 
 func withUpdatedAt(update bson.M) bson.M {
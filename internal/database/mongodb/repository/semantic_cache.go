@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SemanticCacheNamespaceCount 命名空間項目數
+type SemanticCacheNamespaceCount struct {
+	Namespace string `bson:"_id"`
+	Count     int64  `bson:"count"`
+}
+
+type SemanticCacheRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewSemanticCacheRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *SemanticCacheRepository {
+	repository := &SemanticCacheRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionSemanticCache)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：namespace + createdAt、expiresAt TTL
+func (repository *SemanticCacheRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "namespace", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("idx_namespace_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("ttl_expiresAt").SetExpireAfterSeconds(0),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// Insert：新增快取項目
+func (repository *SemanticCacheRepository) Insert(
+	contextValue context.Context,
+	entry *model.SemanticCacheEntry,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("semantic_cache.namespace", entry.Namespace))
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	_, returnedError = repository.collection.InsertOne(contextValue, entry)
+	return returnedError
+}
+
+// ListByNamespace：取得命名空間內未過期的項目（新到舊，最多 limit 筆）
+func (repository *SemanticCacheRepository) ListByNamespace(
+	contextValue context.Context,
+	namespace string,
+	limit int64,
+) (_ []*model.SemanticCacheEntry, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("semantic_cache.namespace", namespace))
+
+	filter := bson.M{"namespace": namespace, "expiresAt": bson.M{"$gt": time.Now().UTC()}}
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit)
+	cursor, findError := repository.collection.Find(contextValue, filter, findOptions)
+	if findError != nil {
+		returnedError = findError
+		return nil, returnedError
+	}
+	defer cursor.Close(contextValue)
+
+	var entries []*model.SemanticCacheEntry
+	if returnedError = cursor.All(contextValue, &entries); returnedError != nil {
+		return nil, returnedError
+	}
+	return entries, nil
+}
+
+// TrimNamespace：只保留命名空間內最新的 keep 筆
+func (repository *SemanticCacheRepository) TrimNamespace(
+	contextValue context.Context,
+	namespace string,
+	keep int64,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("semantic_cache.namespace", namespace))
+
+	findOptions := options.FindOne().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(keep).
+		SetProjection(bson.M{"createdAt": 1})
+	var boundary model.SemanticCacheEntry
+	findError := repository.collection.FindOne(contextValue, bson.M{"namespace": namespace}, findOptions).Decode(&boundary)
+	if findError == mongo.ErrNoDocuments {
+		return nil
+	}
+	if findError != nil {
+		returnedError = findError
+		return returnedError
+	}
+	_, returnedError = repository.collection.DeleteMany(contextValue, bson.M{
+		"namespace": namespace,
+		"createdAt": bson.M{"$lte": boundary.CreatedAt},
+	})
+	return returnedError
+}
+
+// CountByNamespace：各命名空間未過期項目數
+func (repository *SemanticCacheRepository) CountByNamespace(
+	contextValue context.Context,
+) (_ []SemanticCacheNamespaceCount, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"expiresAt": bson.M{"$gt": time.Now().UTC()}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$namespace", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, aggregateError := repository.collection.Aggregate(contextValue, pipeline)
+	if aggregateError != nil {
+		returnedError = aggregateError
+		return nil, returnedError
+	}
+	defer cursor.Close(contextValue)
+
+	var counts []SemanticCacheNamespaceCount
+	if returnedError = cursor.All(contextValue, &counts); returnedError != nil {
+		return nil, returnedError
+	}
+	return counts, nil
+}
+
+// DeleteByNamespace：清除命名空間（namespace 為空字串時清除全部）
+func (repository *SemanticCacheRepository) DeleteByNamespace(
+	contextValue context.Context,
+	namespace string,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	filter := bson.M{}
+	if namespace != "" {
+		filter["namespace"] = namespace
+	}
+	span.SetAttributes(attribute.String("semantic_cache.namespace", namespace))
+	result, deleteError := repository.collection.DeleteMany(contextValue, filter)
+	if deleteError != nil {
+		returnedError = deleteError
+		return 0, returnedError
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const defaultUsageRetentionDays = 90
+
+// UsageGroupFields 可分組的維度與對應欄位
+var UsageGroupFields = map[string]string{
+	"user":     "meta.userID",
+	"apiKey":   "meta.apiKeyID",
+	"project":  "meta.projectName",
+	"provider": "meta.provider",
+	"model":    "meta.model",
+	"endpoint": "meta.endpoint",
+}
+
+// UsageRecordFilter 用量查詢條件（空字串 / 零值表示不限制）
+type UsageRecordFilter struct {
+	From        time.Time
+	To          time.Time
+	UserID      string
+	APIKeyID    string
+	ProjectName string
+	Provider    string
+	Model       string
+	Endpoint    string
+}
+
+// UsageAggregateGroup 分組鍵（只會填入有指定的維度）
+type UsageAggregateGroup struct {
+	Bucket      *time.Time `bson:"bucket,omitempty"`
+	UserID      string     `bson:"user,omitempty"`
+	APIKeyID    string     `bson:"apiKey,omitempty"`
+	ProjectName string     `bson:"project,omitempty"`
+	Provider    string     `bson:"provider,omitempty"`
+	Model       string     `bson:"model,omitempty"`
+	Endpoint    string     `bson:"endpoint,omitempty"`
+}
+
+// UsageAggregate 分組後的用量合計
+type UsageAggregate struct {
+	Group            UsageAggregateGroup `bson:"_id"`
+	Requests         int64               `bson:"requests"`
+	CachedRequests   int64               `bson:"cachedRequests"`
+	TokensPrompt     int64               `bson:"tokensPrompt"`
+	TokensCompletion int64               `bson:"tokensCompletion"`
+	InputTokens      int64               `bson:"inputTokens"`
+	OutputTokens     int64               `bson:"outputTokens"`
+	TokensTotal      int64               `bson:"tokensTotal"`
+	Cost             float64             `bson:"cost"`
+}
+
+type UsageRecordRepository struct {
+	trace      *telemetry.Trace
+	config     *config.Configuration
+	database   *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewUsageRecordRepository(config *config.Configuration, trace *telemetry.Trace, mongoClient *client.MongoClient) *UsageRecordRepository {
+	database := mongoClient.Client().Database(string(core.MongoDBInterchange))
+	repository := &UsageRecordRepository{
+		trace:      trace,
+		config:     config,
+		database:   database,
+		collection: database.Collection(string(core.MongoCollectionUsageRecords)),
+	}
+	_ = repository.ensureCollection(context.Background())
+	return repository
+}
+
+// 建立 time-series collection；已存在時以 collMod 同步保留期限
+func (repository *UsageRecordRepository) ensureCollection(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	retentionDays := repository.config.UsageRecord.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = defaultUsageRetentionDays
+	}
+	expireAfterSeconds := int64(retentionDays) * 24 * 60 * 60
+
+	timeSeries := options.TimeSeries().
+		SetTimeField("timestamp").
+		SetMetaField("meta").
+		SetGranularity("minutes")
+	createOptions := options.CreateCollection().
+		SetTimeSeriesOptions(timeSeries).
+		SetExpireAfterSeconds(expireAfterSeconds)
+	createError := repository.database.CreateCollection(ctx, string(core.MongoCollectionUsageRecords), createOptions)
+	var commandError mongo.CommandError
+	if createError == nil || !errors.As(createError, &commandError) || commandError.Name != "NamespaceExists" {
+		return createError
+	}
+	return repository.database.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: string(core.MongoCollectionUsageRecords)},
+		{Key: "expireAfterSeconds", Value: expireAfterSeconds},
+	}).Err()
+}
+
+// Insert：新增用量紀錄
+func (repository *UsageRecordRepository) Insert(
+	contextValue context.Context,
+	record *model.UsageRecord,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("usage.provider", record.Meta.Provider),
+		attribute.String("usage.model", record.Meta.Model),
+	)
+	_, returnedError = repository.collection.InsertOne(contextValue, record)
+	return returnedError
+}
+
+// Aggregate：依維度與時間區間（hour / day / month，空字串不分區間）彙總，依區間、總 tokens 排序
+func (repository *UsageRecordRepository) Aggregate(
+	contextValue context.Context,
+	filter UsageRecordFilter,
+	groupBy []string,
+	bucket string,
+	timezone string,
+	limit int64,
+) (_ []UsageAggregate, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.StringSlice("usage.group_by", groupBy),
+		attribute.String("usage.bucket", bucket),
+	)
+
+	groupKey := bson.M{}
+	if bucket != "" {
+		dateTrunc := bson.M{"date": "$timestamp", "unit": bucket}
+		if timezone != "" {
+			dateTrunc["timezone"] = timezone
+		}
+		groupKey["bucket"] = bson.M{"$dateTrunc": dateTrunc}
+	}
+	for _, dimension := range groupBy {
+		if field, ok := UsageGroupFields[dimension]; ok {
+			groupKey[dimension] = "$" + field
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: usageFilter(filter)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":              groupKey,
+			"requests":         bson.M{"$sum": 1},
+			"cachedRequests":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$cached", true}}, 1, 0}}},
+			"tokensPrompt":     bson.M{"$sum": "$tokensPrompt"},
+			"tokensCompletion": bson.M{"$sum": "$tokensCompletion"},
+			"inputTokens":      bson.M{"$sum": "$inputTokens"},
+			"outputTokens":     bson.M{"$sum": "$outputTokens"},
+			"tokensTotal":      bson.M{"$sum": "$tokensTotal"},
+			"cost":             bson.M{"$sum": "$cost"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.bucket", Value: 1}, {Key: "tokensTotal", Value: -1}}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	cursor, aggregateError := repository.collection.Aggregate(contextValue, pipeline)
+	if aggregateError != nil {
+		returnedError = aggregateError
+		return nil, returnedError
+	}
+	defer cursor.Close(contextValue)
+
+	var aggregates []UsageAggregate
+	if returnedError = cursor.All(contextValue, &aggregates); returnedError != nil {
+		return nil, returnedError
+	}
+	return aggregates, nil
+}
+
+func usageFilter(filter UsageRecordFilter) bson.M {
+	match := bson.M{}
+	timestamp := bson.M{}
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timestamp["$lt"] = filter.To
+	}
+	if len(timestamp) > 0 {
+		match["timestamp"] = timestamp
+	}
+	for field, value := range map[string]string{
+		"meta.userID":      filter.UserID,
+		"meta.apiKeyID":    filter.APIKeyID,
+		"meta.projectName": filter.ProjectName,
+		"meta.provider":    filter.Provider,
+		"meta.model":       filter.Model,
+		"meta.endpoint":    filter.Endpoint,
+	} {
+		if value != "" {
+			match[field] = value
+		}
+	}
+	return match
+}
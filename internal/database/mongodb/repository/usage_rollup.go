@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type UsageRollupRepository struct {
+	trace      *telemetry.Trace
+	collection *mongo.Collection
+}
+
+func NewUsageRollupRepository(trace *telemetry.Trace, mongoClient *client.MongoClient) *UsageRollupRepository {
+	repository := &UsageRollupRepository{
+		trace:      trace,
+		collection: mongoClient.Client().Database(string(core.MongoDBInterchange)).Collection(string(core.MongoCollectionUsageRollups)),
+	}
+	_ = repository.ensureIndexes(context.Background())
+	return repository
+}
+
+// 建索引：day + userID + apiKeyID + provider + model 唯一（upsert 依此比對）、userID + day
+func (repository *UsageRollupRepository) ensureIndexes(contextValue context.Context) error {
+	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer endSpan(nil)
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "day", Value: 1},
+				{Key: "userID", Value: 1},
+				{Key: "apiKeyID", Value: 1},
+				{Key: "provider", Value: 1},
+				{Key: "model", Value: 1},
+			},
+			Options: options.Index().SetName("uniq_day_dimensions").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "day", Value: -1}},
+			Options: options.Index().SetName("idx_userID_day"),
+		},
+	}
+	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
+	return nil
+}
+
+// UpsertMany：以日期與維度為鍵覆寫彙總值（重算同一天結果相同，可重複執行）
+func (repository *UsageRollupRepository) UpsertMany(
+	contextValue context.Context,
+	rollups []*model.UsageRollup,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.Int("usage.rollups", len(rollups)))
+	if len(rollups) == 0 {
+		return 0, nil
+	}
+
+	nowUTC := time.Now().UTC()
+	writeModels := make([]mongo.WriteModel, 0, len(rollups))
+	for _, rollup := range rollups {
+		filter := bson.M{
+			"day":      rollup.Day,
+			"userID":   rollup.UserID,
+			"apiKeyID": rollup.APIKeyID,
+			"provider": rollup.Provider,
+			"model":    rollup.Model,
+		}
+		update := bson.M{"$set": bson.M{
+			"requests":         rollup.Requests,
+			"cachedRequests":   rollup.CachedRequests,
+			"tokensPrompt":     rollup.TokensPrompt,
+			"tokensCompletion": rollup.TokensCompletion,
+			"inputTokens":      rollup.InputTokens,
+			"outputTokens":     rollup.OutputTokens,
+			"tokensTotal":      rollup.TokensTotal,
+			"cost":             rollup.Cost,
+			"updatedAt":        nowUTC,
+		}}
+		writeModels = append(writeModels, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	result, writeError := repository.collection.BulkWrite(contextValue, writeModels, options.BulkWrite().SetOrdered(false))
+	if writeError != nil {
+		return 0, writeError
+	}
+	return result.UpsertedCount + result.ModifiedCount, nil
+}
@@ -46,6 +46,14 @@ func (repository *UserRepository) ensureIndexes(contextValue context.Context) er
 			Keys:    bson.D{{Key: "status", Value: 1}},
 			Options: options.Index().SetName("idx_status"),
 		},
+		{ // 閒置掃描
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "lastSeen", Value: 1}},
+			Options: options.Index().SetName("idx_status_lastSeen"),
+		},
+		{ // session 以外部登入平台的使用者 ID 查詢
+			Keys:    bson.D{{Key: "externalID", Value: 1}},
+			Options: options.Index().SetName("idx_externalID"),
+		},
 	}
 	_, _ = repository.collection.Indexes().CreateMany(ctx, indexModels)
 	return nil
@@ -98,6 +106,22 @@ func (repository *UserRepository) GetByID(
 	return &user, nil
 }
 
+// GetByExternalID：以外部登入平台的使用者 ID 讀取
+func (repository *UserRepository) GetByExternalID(
+	contextValue context.Context,
+	externalID string,
+) (_ *model.User, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	var user model.User
+	if returnedError = repository.collection.FindOne(contextValue, bson.M{"externalID": externalID}).Decode(&user); returnedError != nil {
+		return nil, returnedError
+	}
+	return &user, nil
+}
+
 // UpdateStatus：單文件部分更新
 func (repository *UserRepository) UpdateStatus(
 	contextValue context.Context,
@@ -110,7 +134,11 @@ func (repository *UserRepository) UpdateStatus(
 
 	span.SetAttributes(attribute.String("user.id", userIdentifier.Hex()))
 
-	update := bson.M{"$set": bson.M{"status": status}}
+	// 手動改狀態時一併清除自動停用的紀錄，避免排程沿用舊的通知時間
+	update := bson.M{
+		"$set":   bson.M{"status": status},
+		"$unset": bson.M{"inactivityWarnedAt": "", "suspendReason": "", "suspendedAt": ""},
+	}
 	result, updateError := repository.collection.UpdateOne(contextValue, bson.M{"_id": userIdentifier}, withUpdatedAt(update))
 	if updateError != nil {
 		return 0, updateError
@@ -158,6 +186,52 @@ func (repository *UserRepository) UpdateLastSeen(
 	return result.MatchedCount, nil
 }
 
+// UpdateQuotaPolicy：覆寫使用者配額；policy 為 nil 時移除配額
+func (repository *UserRepository) UpdateQuotaPolicy(
+	contextValue context.Context,
+	userIdentifier primitive.ObjectID,
+	policy *model.QuotaPolicy,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("user.id", userIdentifier.Hex()))
+
+	update := bson.M{"$set": bson.M{"quotaPolicy": policy}}
+	if policy == nil {
+		update = bson.M{"$unset": bson.M{"quotaPolicy": ""}}
+	}
+	result, updateError := repository.collection.UpdateOne(contextValue, bson.M{"_id": userIdentifier}, withUpdatedAt(update))
+	if updateError != nil {
+		return 0, updateError
+	}
+	return result.MatchedCount, nil
+}
+
+// UpdateOrgID：指派使用者所屬組織；orgIdentifier 為 nil 時移出組織
+func (repository *UserRepository) UpdateOrgID(
+	contextValue context.Context,
+	userIdentifier primitive.ObjectID,
+	orgIdentifier *primitive.ObjectID,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("user.id", userIdentifier.Hex()))
+
+	update := bson.M{"$set": bson.M{"orgID": orgIdentifier}}
+	if orgIdentifier == nil {
+		update = bson.M{"$unset": bson.M{"orgID": ""}}
+	}
+	result, updateError := repository.collection.UpdateOne(contextValue, bson.M{"_id": userIdentifier}, withUpdatedAt(update))
+	if updateError != nil {
+		return 0, updateError
+	}
+	return result.MatchedCount, nil
+}
+
 // UpdateByID：將呼叫端給的欄位寫入 $set（請確認呼叫端只傳「欄位值」，不要傳 $inc 之類 operator）
 func (repository *UserRepository) UpdateByID(
 	contextValue context.Context,
@@ -254,3 +328,140 @@ func (repository *UserRepository) ListAll(
 	}
 	return users, nil
 }
+
+// BulkUpdateLastSeen：批次更新最後使用時間（$max，避免較舊的時間覆蓋較新的）
+func (repository *UserRepository) BulkUpdateLastSeen(
+	contextValue context.Context,
+	lastSeen map[primitive.ObjectID]time.Time,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.Int("bulk.size", len(lastSeen)))
+	if len(lastSeen) == 0 {
+		return nil
+	}
+
+	writeModels := make([]mongo.WriteModel, 0, len(lastSeen))
+	for userIdentifier, seenAt := range lastSeen {
+		writeModels = append(writeModels, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": userIdentifier}).
+			SetUpdate(withUpdatedAt(bson.M{"$max": bson.M{"lastSeen": seenAt.UTC()}})))
+	}
+	_, returnedError = repository.collection.BulkWrite(contextValue, writeModels, options.BulkWrite().SetOrdered(false))
+	return returnedError
+}
+
+// ListInactivityCandidates：閒置掃描候選（active 且未通知過，lastSeen 早於 before 或從未使用且建立早於 before；
+// 或通知時間早於 warnedBefore，即寬限期已過）。寬限期內的已通知者不列入，避免每輪佔滿批次；
+// 依 lastSeen 由舊到新，最多 limit 筆
+func (repository *UserRepository) ListInactivityCandidates(
+	contextValue context.Context,
+	before time.Time,
+	warnedBefore time.Time,
+	limit int64,
+) (_ []*model.User, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	filter := bson.M{
+		"status": core.StatusActive,
+		"$or": bson.A{
+			bson.M{"inactivityWarnedAt": nil, "lastSeen": bson.M{"$lt": before.UTC()}},
+			bson.M{"inactivityWarnedAt": nil, "lastSeen": nil, "createdAt": bson.M{"$lt": before.UTC()}},
+			bson.M{"inactivityWarnedAt": bson.M{"$lte": warnedBefore.UTC()}},
+		},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "lastSeen", Value: 1}, {Key: "_id", Value: 1}})
+	if limit > 0 {
+		findOptions.SetLimit(limit)
+	}
+	cursor, findError := repository.collection.Find(contextValue, filter, findOptions)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var users []*model.User
+	if returnedError = cursor.All(contextValue, &users); returnedError != nil {
+		return nil, returnedError
+	}
+	span.SetAttributes(attribute.Int("count", len(users)))
+	return users, nil
+}
+
+// SetInactivityWarning：記錄 / 清除停用前通知時間（warnedAt 為 nil 時清除）
+func (repository *UserRepository) SetInactivityWarning(
+	contextValue context.Context,
+	userIdentifier primitive.ObjectID,
+	warnedAt *time.Time,
+) (_ int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("user.id", userIdentifier.Hex()))
+
+	update := bson.M{"$set": bson.M{"inactivityWarnedAt": warnedAt}}
+	if warnedAt == nil {
+		update = bson.M{"$unset": bson.M{"inactivityWarnedAt": ""}}
+	}
+	result, updateError := repository.collection.UpdateOne(contextValue, bson.M{"_id": userIdentifier}, withUpdatedAt(update))
+	if updateError != nil {
+		return 0, updateError
+	}
+	return result.MatchedCount, nil
+}
+
+// SuspendActive：將 active 使用者改為 suspended 並記錄原因；已不是 active 時回傳 false
+func (repository *UserRepository) SuspendActive(
+	contextValue context.Context,
+	userIdentifier primitive.ObjectID,
+	reason core.SuspendReason,
+	now time.Time,
+) (_ bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("user.id", userIdentifier.Hex()))
+
+	filter := bson.M{"_id": userIdentifier, "status": core.StatusActive}
+	update := bson.M{"$set": bson.M{
+		"status":        core.StatusSuspended,
+		"suspendReason": reason,
+		"suspendedAt":   now.UTC(),
+	}}
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update))
+	if updateError != nil {
+		return false, updateError
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Reactivate：將 suspended 使用者恢復為 active，最後使用時間設為 now（重新起算閒置天數）；
+// 已不是 suspended 時回傳 false
+func (repository *UserRepository) Reactivate(
+	contextValue context.Context,
+	userIdentifier primitive.ObjectID,
+	now time.Time,
+) (_ bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("user.id", userIdentifier.Hex()))
+
+	filter := bson.M{"_id": userIdentifier, "status": core.StatusSuspended}
+	update := bson.M{
+		"$set":   bson.M{"status": core.StatusActive, "lastSeen": now.UTC()},
+		"$unset": bson.M{"inactivityWarnedAt": "", "suspendReason": "", "suspendedAt": ""},
+	}
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update))
+	if updateError != nil {
+		return false, updateError
+	}
+	return result.MatchedCount > 0, nil
+}
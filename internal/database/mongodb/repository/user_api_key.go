@@ -34,7 +34,7 @@ func NewUserAPIKeyRepository(trace *telemetry.Trace, mongoClient *client.MongoCl
 
 // 建索引：
 // 1) userID+keyName 唯一（避免同用戶同名重覆建立）
-// 2) 常用查詢加速：userID、createdAt、providerAccess.provider
+// 2) 常用查詢加速：userID、createdAt、providerAccess.provider、providerAccess.expireTime（過期掃描）、suspendReason（停用標記）
 func (repository *UserAPIKeyRepository) ensureIndexes(contextValue context.Context) error {
 	ctx, _, endSpan := repository.trace.WithSpan(contextValue)
 	defer endSpan(nil)
@@ -59,6 +59,14 @@ func (repository *UserAPIKeyRepository) ensureIndexes(contextValue context.Conte
 			Keys:    bson.D{{Key: "providerAccess.provider", Value: 1}},
 			Options: options.Index().SetName("idx_providerAccess_provider"),
 		},
+		{
+			Keys:    bson.D{{Key: "providerAccess.expireTime", Value: 1}},
+			Options: options.Index().SetName("idx_providerAccess_expireTime").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "suspendReason", Value: 1}},
+			Options: options.Index().SetName("idx_suspendReason").SetSparse(true),
+		},
 	}
 
 	_, returnedError := repository.collection.Indexes().CreateMany(ctx, models)
@@ -339,17 +347,45 @@ func (repository *UserAPIKeyRepository) UpdateProviderUsedCount(contextValue con
 	return nil
 }
 
-// UpdateExpireTime 更新 API Key 的到期時間
-func (repository *UserAPIKeyRepository) UpdateExpireTime(contextValue context.Context, apiKeyIdentifier primitive.ObjectID, expireTime time.Time) (returnedError error) {
+// UpdateProviderExpireTime 更新單一 provider 的到期時間（expireTime 為 nil 表示移除期限）；status 非空時一併覆寫狀態（續期時把 expired 恢復為 active）
+func (repository *UserAPIKeyRepository) UpdateProviderExpireTime(
+	contextValue context.Context,
+	apiKeyIdentifier primitive.ObjectID,
+	providerName core.ProviderName,
+	expireTime *time.Time,
+	status core.Status,
+) (returnedError error) {
 	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
 	defer func() { endSpan(returnedError) }()
 
-	span.SetAttributes(attribute.String("api_key.id", apiKeyIdentifier.Hex()))
+	span.SetAttributes(
+		attribute.String("api_key.id", apiKeyIdentifier.Hex()),
+		attribute.String("provider", string(providerName)),
+	)
 
-	filter := bson.M{"_id": apiKeyIdentifier}
-	update := bson.M{"$set": bson.M{"expireTime": expireTime.UTC()}}
+	filter := bson.M{
+		"_id":                     apiKeyIdentifier,
+		"providerAccess.provider": providerName,
+	}
+	set := bson.M{}
+	update := bson.M{}
+	if expireTime != nil {
+		set["providerAccess.$[target].expireTime"] = expireTime.UTC()
+	} else {
+		update["$unset"] = bson.M{"providerAccess.$[target].expireTime": ""}
+	}
+	if status != "" {
+		set["providerAccess.$[target].status"] = status
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{bson.M{"target.provider": providerName}},
+	}
+	opts := options.Update().SetArrayFilters(arrayFilters)
 
-	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update))
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update), opts)
 	if updateError != nil {
 		return updateError
 	}
@@ -359,6 +395,124 @@ func (repository *UserAPIKeyRepository) UpdateExpireTime(contextValue context.Co
 	return nil
 }
 
+// ListExpiredActive 取得仍為 active 但 expireTime 已到的 API Key（依 _id 排序，最多 limit 筆）
+func (repository *UserAPIKeyRepository) ListExpiredActive(contextValue context.Context, now time.Time, limit int64) (_ []*model.UserAPIKey, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	filter := bson.M{
+		"providerAccess": bson.M{"$elemMatch": bson.M{
+			"status":     core.StatusActive,
+			"expireTime": bson.M{"$lte": now.UTC()},
+		}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cursor, findError := repository.collection.Find(contextValue, filter, opts)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var results []*model.UserAPIKey
+	if returnedError = cursor.All(contextValue, &results); returnedError != nil {
+		return nil, returnedError
+	}
+	span.SetAttributes(attribute.Int("count", len(results)))
+	return results, nil
+}
+
+// ExpireProviderAccess 將已到期且仍為 active 的 provider 標記為 expired；
+// 已續期或已被其他流程標記時不會比對到文件（回傳 false），避免重複通知
+func (repository *UserAPIKeyRepository) ExpireProviderAccess(
+	contextValue context.Context,
+	apiKeyIdentifier primitive.ObjectID,
+	providerName core.ProviderName,
+	now time.Time,
+) (_ bool, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("api_key.id", apiKeyIdentifier.Hex()),
+		attribute.String("provider", string(providerName)),
+	)
+
+	filter := bson.M{
+		"_id": apiKeyIdentifier,
+		"providerAccess": bson.M{"$elemMatch": bson.M{
+			"provider":   providerName,
+			"status":     core.StatusActive,
+			"expireTime": bson.M{"$lte": now.UTC()},
+		}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"providerAccess.$[target].status": core.StatusExpired,
+		},
+	}
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{bson.M{"target.provider": providerName}},
+	}
+	opts := options.Update().SetArrayFilters(arrayFilters)
+
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update), opts)
+	if updateError != nil {
+		return false, updateError
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ResetElapsedProviderWindows 將限流週期為 period 且上次重置早於 windowStart（或從未重置）的 provider
+// 已用次數歸零、重置時間設為 now；回傳受影響的 API Key 數
+func (repository *UserAPIKeyRepository) ResetElapsedProviderWindows(
+	contextValue context.Context,
+	period core.LimitPeriod,
+	windowStart time.Time,
+	now time.Time,
+) (_ int64, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("limit_period", string(period)))
+
+	elapsed := bson.A{
+		bson.M{"lastResetAt": nil},
+		bson.M{"lastResetAt": bson.M{"$lte": windowStart.UTC()}},
+	}
+	filter := bson.M{
+		"providerAccess": bson.M{"$elemMatch": bson.M{
+			"limitPeriod": period,
+			"$or":         elapsed,
+		}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"providerAccess.$[target].usedCount":   0,
+			"providerAccess.$[target].lastResetAt": now.UTC(),
+		},
+	}
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{bson.M{
+			"target.limitPeriod": period,
+			"$or": bson.A{
+				bson.M{"target.lastResetAt": nil},
+				bson.M{"target.lastResetAt": bson.M{"$lte": windowStart.UTC()}},
+			},
+		}},
+	}
+	opts := options.Update().SetArrayFilters(arrayFilters)
+
+	result, updateError := repository.collection.UpdateMany(contextValue, filter, withUpdatedAt(update), opts)
+	if updateError != nil {
+		return 0, updateError
+	}
+	span.SetAttributes(attribute.Int64("modified", result.ModifiedCount))
+	return result.ModifiedCount, nil
+}
+
 // IncrProviderUsedCount 累加已用次數
 func (repository *UserAPIKeyRepository) IncrProviderUsedCount(
 	contextValue context.Context,
@@ -515,3 +669,267 @@ func (repository *UserAPIKeyRepository) UpdateProviderFields(contextValue contex
 	}
 	return nil
 }
+
+// ProviderUsageDelta 單一 API Key / provider 的累計用量
+type ProviderUsageDelta struct {
+	APIKeyID  primitive.ObjectID
+	Provider  core.ProviderName
+	Increment int
+	LastSeen  time.Time
+}
+
+// BulkIncrProviderUsage 批次累加已用次數並更新最後使用時間
+func (repository *UserAPIKeyRepository) BulkIncrProviderUsage(
+	contextValue context.Context,
+	deltas []ProviderUsageDelta,
+) (returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.Int("bulk.size", len(deltas)))
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	writeModels := make([]mongo.WriteModel, 0, len(deltas))
+	for _, delta := range deltas {
+		update := bson.M{
+			"$inc": bson.M{"providerAccess.$[target].usedCount": delta.Increment},
+			"$max": bson.M{"providerAccess.$[target].lastSeen": delta.LastSeen.UTC()},
+		}
+		writeModels = append(writeModels, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": delta.APIKeyID, "providerAccess.provider": delta.Provider}).
+			SetUpdate(withUpdatedAt(update)).
+			SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"target.provider": delta.Provider}}}))
+	}
+	_, returnedError = repository.collection.BulkWrite(contextValue, writeModels, options.BulkWrite().SetOrdered(false))
+	return returnedError
+}
+
+// ListInactivityCandidates 閒置掃描候選：建立早於 before，且有 active provider 未通知過而 lastSeen 早於 before 或從未使用，
+// 或通知時間早於 warnedBefore（寬限期已過）；寬限期內的已通知者不列入，避免每輪佔滿批次
+// （依 _id 排序，最多 limit 筆；逐項判斷交給呼叫端）
+func (repository *UserAPIKeyRepository) ListInactivityCandidates(
+	contextValue context.Context,
+	before time.Time,
+	warnedBefore time.Time,
+	limit int64,
+) (_ []*model.UserAPIKey, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	filter := bson.M{
+		"createdAt": bson.M{"$lt": before.UTC()},
+		"providerAccess": bson.M{"$elemMatch": bson.M{
+			"status": core.StatusActive,
+			"$or": bson.A{
+				bson.M{"inactivityWarnedAt": nil, "lastSeen": bson.M{"$lt": before.UTC()}},
+				bson.M{"inactivityWarnedAt": nil, "lastSeen": nil},
+				bson.M{"inactivityWarnedAt": bson.M{"$lte": warnedBefore.UTC()}},
+			},
+		}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cursor, findError := repository.collection.Find(contextValue, filter, opts)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var results []*model.UserAPIKey
+	if returnedError = cursor.All(contextValue, &results); returnedError != nil {
+		return nil, returnedError
+	}
+	span.SetAttributes(attribute.Int("count", len(results)))
+	return results, nil
+}
+
+// ListBySuspendReason 取得帶有指定停用標記的 API Key（依 _id 排序，最多 limit 筆）
+func (repository *UserAPIKeyRepository) ListBySuspendReason(contextValue context.Context, reason core.SuspendReason, limit int64) (_ []*model.UserAPIKey, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cursor, findError := repository.collection.Find(contextValue, bson.M{"suspendReason": reason}, opts)
+	if findError != nil {
+		return nil, findError
+	}
+	defer cursor.Close(contextValue)
+
+	var results []*model.UserAPIKey
+	if returnedError = cursor.All(contextValue, &results); returnedError != nil {
+		return nil, returnedError
+	}
+	span.SetAttributes(attribute.Int("count", len(results)))
+	return results, nil
+}
+
+// SetSuspendFlag 標記整把 key 停用；已有標記時不覆寫（回傳 false）
+func (repository *UserAPIKeyRepository) SetSuspendFlag(
+	contextValue context.Context,
+	apiKeyIdentifier primitive.ObjectID,
+	reason core.SuspendReason,
+	now time.Time,
+) (_ bool, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("api_key.id", apiKeyIdentifier.Hex()))
+
+	filter := bson.M{"_id": apiKeyIdentifier, "suspendReason": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"suspendReason": reason, "suspendedAt": now.UTC()}}
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update))
+	if updateError != nil {
+		return false, updateError
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ClearSuspendFlag 移除指定原因的停用標記；標記原因不同或已移除時回傳 false
+func (repository *UserAPIKeyRepository) ClearSuspendFlag(
+	contextValue context.Context,
+	apiKeyIdentifier primitive.ObjectID,
+	reason core.SuspendReason,
+) (_ bool, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("api_key.id", apiKeyIdentifier.Hex()))
+
+	filter := bson.M{"_id": apiKeyIdentifier, "suspendReason": reason}
+	update := bson.M{"$unset": bson.M{"suspendReason": "", "suspendedAt": ""}}
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update))
+	if updateError != nil {
+		return false, updateError
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// SetProviderInactivityWarning 記錄 / 清除 provider 停用前通知時間（warnedAt 為 nil 時清除）
+func (repository *UserAPIKeyRepository) SetProviderInactivityWarning(
+	contextValue context.Context,
+	apiKeyIdentifier primitive.ObjectID,
+	providerName core.ProviderName,
+	warnedAt *time.Time,
+) (returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("api_key.id", apiKeyIdentifier.Hex()),
+		attribute.String("provider", string(providerName)),
+	)
+
+	filter := bson.M{
+		"_id":                     apiKeyIdentifier,
+		"providerAccess.provider": providerName,
+	}
+	update := bson.M{"$set": bson.M{"providerAccess.$[target].inactivityWarnedAt": warnedAt}}
+	if warnedAt == nil {
+		update = bson.M{"$unset": bson.M{"providerAccess.$[target].inactivityWarnedAt": ""}}
+	}
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{bson.M{"target.provider": providerName}},
+	}
+	opts := options.Update().SetArrayFilters(arrayFilters)
+
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update), opts)
+	if updateError != nil {
+		return updateError
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SuspendProviderAccess 將 active 的 provider 改為 suspended 並記錄原因；已不是 active 時回傳 false
+func (repository *UserAPIKeyRepository) SuspendProviderAccess(
+	contextValue context.Context,
+	apiKeyIdentifier primitive.ObjectID,
+	providerName core.ProviderName,
+	reason core.SuspendReason,
+) (_ bool, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("api_key.id", apiKeyIdentifier.Hex()),
+		attribute.String("provider", string(providerName)),
+	)
+
+	filter := bson.M{
+		"_id": apiKeyIdentifier,
+		"providerAccess": bson.M{"$elemMatch": bson.M{
+			"provider": providerName,
+			"status":   core.StatusActive,
+		}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"providerAccess.$[target].status":        core.StatusSuspended,
+			"providerAccess.$[target].suspendReason": reason,
+		},
+	}
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{bson.M{"target.provider": providerName}},
+	}
+	opts := options.Update().SetArrayFilters(arrayFilters)
+
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update), opts)
+	if updateError != nil {
+		return false, updateError
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ReactivateProviderAccess 將 suspended 的 provider 恢復為 active，最後使用時間設為 now（重新起算閒置天數）；
+// 已不是 suspended 時回傳 false
+func (repository *UserAPIKeyRepository) ReactivateProviderAccess(
+	contextValue context.Context,
+	apiKeyIdentifier primitive.ObjectID,
+	providerName core.ProviderName,
+	now time.Time,
+) (_ bool, returnedError error) {
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("api_key.id", apiKeyIdentifier.Hex()),
+		attribute.String("provider", string(providerName)),
+	)
+
+	filter := bson.M{
+		"_id": apiKeyIdentifier,
+		"providerAccess": bson.M{"$elemMatch": bson.M{
+			"provider": providerName,
+			"status":   core.StatusSuspended,
+		}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"providerAccess.$[target].status":   core.StatusActive,
+			"providerAccess.$[target].lastSeen": now.UTC(),
+		},
+		"$unset": bson.M{
+			"providerAccess.$[target].suspendReason":      "",
+			"providerAccess.$[target].inactivityWarnedAt": "",
+		},
+	}
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{bson.M{"target.provider": providerName}},
+	}
+	opts := options.Update().SetArrayFilters(arrayFilters)
+
+	result, updateError := repository.collection.UpdateOne(contextValue, filter, withUpdatedAt(update), opts)
+	if updateError != nil {
+		return false, updateError
+	}
+	return result.MatchedCount > 0, nil
+}
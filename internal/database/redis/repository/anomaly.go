@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// AnomalyRepository 每把 API Key 的異常偵測狀態：
+// 速率（hash：目前視窗、請求數、EWMA 基準）、已見過的 IP / User-Agent / 模型（SET）、
+// 每個視窗的請求 / 錯誤數、限流標記與計數、處置冷卻與重新驗證碼
+type AnomalyRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewAnomalyRepository(trace *telemetry.Trace, client *client.RedisClient) *AnomalyRepository {
+	return &AnomalyRepository{trace: trace, client: client.Client()}
+}
+
+// 以 Redis 伺服器時間切視窗，避免各實例時鐘誤差；換視窗時將上一個視窗的請求數併入 EWMA，中間閒置的視窗視為 0。
+// 基準以千分之一為單位回傳
+// KEYS[1]=rate hash、KEYS[2..4]=ip / user agent / model SET
+// ARGV[1]=視窗秒數、ARGV[2]=alpha、ARGV[3]=rate TTL 秒、ARGV[4]=seen TTL 秒、ARGV[5]=每個 SET 上限、ARGV[6..8]=對應值（空字串略過）
+var anomalyObserveScript = redis.NewScript(`
+local t = redis.call('TIME')
+local bucket = math.floor(tonumber(t[1]) / tonumber(ARGV[1]))
+local alpha = tonumber(ARGV[2])
+local state = redis.call('HMGET', KEYS[1], 'bucket', 'count', 'baseline', 'windows')
+local current = tonumber(state[1] or '-1')
+local baseline = tonumber(state[3] or '0')
+local windows = tonumber(state[4] or '0')
+if current ~= bucket then
+	if current >= 0 and bucket > current then
+		baseline = baseline * (1 - alpha) + tonumber(state[2] or '0') * alpha
+		local idle = math.min(bucket - current - 1, 10000)
+		if idle > 0 then
+			baseline = baseline * math.pow(1 - alpha, idle)
+		end
+		windows = windows + (bucket - current)
+	end
+	redis.call('HSET', KEYS[1], 'bucket', bucket, 'count', 0, 'baseline', tostring(baseline), 'windows', windows)
+end
+local count = redis.call('HINCRBY', KEYS[1], 'count', 1)
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+
+local result = {count, math.floor(baseline * 1000), windows}
+for i = 2, 4 do
+	local value = ARGV[i + 4]
+	local fresh = 0
+	if value ~= '' then
+		local size = redis.call('SCARD', KEYS[i])
+		if redis.call('SISMEMBER', KEYS[i], value) == 0 and size < tonumber(ARGV[5]) then
+			redis.call('SADD', KEYS[i], value)
+			if size > 0 then
+				fresh = 1
+			end
+		end
+		redis.call('EXPIRE', KEYS[i], ARGV[4])
+	end
+	table.insert(result, fresh)
+end
+return result
+`)
+
+// KEYS[1]=限流標記、KEYS[2]=本視窗限流計數；ARGV[1]=計數 TTL 秒
+var anomalyThrottleScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	return {0, 0, 0}
+end
+local limit = tonumber(redis.call('GET', KEYS[1]) or '0')
+local count = redis.call('INCR', KEYS[2])
+if count == 1 then
+	redis.call('EXPIRE', KEYS[2], ARGV[1])
+end
+return {1, count, limit, ttl}
+`)
+
+// Observe 計入一次請求並回傳速率與新來源判斷
+func (repository *AnomalyRepository) Observe(
+	contextValue context.Context,
+	apiKeyID string,
+	window time.Duration,
+	alpha float64,
+	rateTTL time.Duration,
+	seenTTL time.Duration,
+	maxTracked int64,
+	clientIP string,
+	userAgent string,
+	model string,
+) (_ *core.AnomalyObservation, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(attribute.String("api_key.id", apiKeyID))
+
+	result, scriptError := anomalyObserveScript.Run(
+		contextValue,
+		repository.client,
+		[]string{
+			repository.buildKey("rate", apiKeyID),
+			repository.buildKey("seen:ip", apiKeyID),
+			repository.buildKey("seen:ua", apiKeyID),
+			repository.buildKey("seen:model", apiKeyID),
+		},
+		int64(window.Seconds()),
+		alpha,
+		int64(rateTTL.Seconds()),
+		int64(seenTTL.Seconds()),
+		maxTracked,
+		clientIP,
+		userAgent,
+		model,
+	).Int64Slice()
+	if scriptError != nil {
+		returnedError = scriptError
+		return nil, returnedError
+	}
+	if len(result) != 6 {
+		returnedError = fmt.Errorf("unexpected anomaly observe result: %v", result)
+		return nil, returnedError
+	}
+	observation := &core.AnomalyObservation{
+		Requests:     result[0],
+		Baseline:     float64(result[1]) / 1000,
+		Windows:      result[2],
+		NewClientIP:  result[3] == 1,
+		NewUserAgent: result[4] == 1,
+		NewModel:     result[5] == 1,
+	}
+	span.SetAttributes(
+		attribute.Int64("anomaly.requests", observation.Requests),
+		attribute.Float64("anomaly.baseline", observation.Baseline),
+	)
+	return observation, nil
+}
+
+// ObserveResult 計入一次請求結果，回傳本視窗的請求數與錯誤數
+func (repository *AnomalyRepository) ObserveResult(
+	contextValue context.Context,
+	apiKeyID string,
+	bucket int64,
+	failed bool,
+	ttl time.Duration,
+) (total int64, errors int64, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	redisKey := repository.buildKey(fmt.Sprintf("result:%d", bucket), apiKeyID)
+	pipe := repository.client.TxPipeline()
+	totalCmd := pipe.HIncrBy(contextValue, redisKey, "total", 1)
+	var errorsCmd *redis.IntCmd
+	if failed {
+		errorsCmd = pipe.HIncrBy(contextValue, redisKey, "errors", 1)
+	} else {
+		errorsCmd = pipe.HIncrBy(contextValue, redisKey, "errors", 0)
+	}
+	pipe.Expire(contextValue, redisKey, ttl)
+	if _, returnedError = pipe.Exec(contextValue); returnedError != nil {
+		return 0, 0, returnedError
+	}
+	return totalCmd.Val(), errorsCmd.Val(), nil
+}
+
+// SetThrottle 開始限流：期間內每個視窗最多放行 limit 次
+func (repository *AnomalyRepository) SetThrottle(
+	contextValue context.Context,
+	apiKeyID string,
+	limit int64,
+	ttl time.Duration,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Set(contextValue, repository.buildKey("throttle", apiKeyID), limit, ttl).Err()
+	return returnedError
+}
+
+// Throttle 限流中時計入一次請求；回傳是否限流中、本視窗計數、上限與限流剩餘時間
+func (repository *AnomalyRepository) Throttle(
+	contextValue context.Context,
+	apiKeyID string,
+	bucket int64,
+	window time.Duration,
+) (throttled bool, count int64, limit int64, remaining time.Duration, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	result, scriptError := anomalyThrottleScript.Run(
+		contextValue,
+		repository.client,
+		[]string{
+			repository.buildKey("throttle", apiKeyID),
+			repository.buildKey(fmt.Sprintf("throttle:%d", bucket), apiKeyID),
+		},
+		int64(window.Seconds()),
+	).Int64Slice()
+	if scriptError != nil {
+		returnedError = scriptError
+		return false, 0, 0, 0, returnedError
+	}
+	if len(result) < 1 || result[0] == 0 {
+		return false, 0, 0, 0, nil
+	}
+	if len(result) != 4 {
+		returnedError = fmt.Errorf("unexpected anomaly throttle result: %v", result)
+		return false, 0, 0, 0, returnedError
+	}
+	return true, result[1], result[2], time.Duration(result[3]) * time.Millisecond, nil
+}
+
+// ClearThrottle 解除限流；回傳是否限流中
+func (repository *AnomalyRepository) ClearThrottle(
+	contextValue context.Context,
+	apiKeyID string,
+) (_ bool, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	deleted, err := repository.client.Del(contextValue, repository.buildKey("throttle", apiKeyID)).Result()
+	if err != nil {
+		returnedError = err
+		return false, returnedError
+	}
+	return deleted > 0, nil
+}
+
+// MarkOnce 同一把 key 同一事項（訊號處置、重寄驗證碼）在冷卻期間只做一次；回傳 true 表示可執行
+func (repository *AnomalyRepository) MarkOnce(
+	contextValue context.Context,
+	apiKeyID string,
+	name string,
+	ttl time.Duration,
+) (_ bool, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	first, err := repository.client.SetNX(contextValue, repository.buildKey("cooldown:"+name, apiKeyID), 1, ttl).Result()
+	if err != nil {
+		returnedError = err
+		return false, returnedError
+	}
+	return first, nil
+}
+
+// SetReverifyCode 保存重新驗證碼的雜湊（覆寫舊的）
+func (repository *AnomalyRepository) SetReverifyCode(
+	contextValue context.Context,
+	apiKeyID string,
+	codeHash string,
+	ttl time.Duration,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Set(contextValue, repository.buildKey("reverify", apiKeyID), codeHash, ttl).Err()
+	return returnedError
+}
+
+// TakeReverifyCode 取出並刪除重新驗證碼雜湊；不存在（過期）時回傳空字串
+func (repository *AnomalyRepository) TakeReverifyCode(
+	contextValue context.Context,
+	apiKeyID string,
+) (_ string, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	codeHash, err := repository.client.GetDel(contextValue, repository.buildKey("reverify", apiKeyID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		returnedError = err
+		return "", returnedError
+	}
+	return codeHash, nil
+}
+
+// buildKey 建構異常偵測用的 Redis key
+func (repository *AnomalyRepository) buildKey(kind string, apiKeyID string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", core.RedisKeyServerName, core.RedisKeyAnomaly, kind, apiKeyID)
+}
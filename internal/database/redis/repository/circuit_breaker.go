@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CircuitBreakerRepository 多個 replica 共享的熔斷狀態（key 存在即代表熔斷中，TTL 為剩餘熔斷時間）
+type CircuitBreakerRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewCircuitBreakerRepository(trace *telemetry.Trace, client *client.RedisClient) *CircuitBreakerRepository {
+	return &CircuitBreakerRepository{trace: trace, client: client.Client()}
+}
+
+// Open 標記熔斷，持續 openDuration
+func (repository *CircuitBreakerRepository) Open(
+	contextValue context.Context,
+	provider core.ProviderName,
+	endpoint string,
+	openDuration time.Duration,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("circuit.provider", string(provider)),
+		attribute.String("circuit.endpoint", endpoint),
+	)
+
+	redisKey := repository.buildKey(provider, endpoint)
+	returnedError = repository.client.Set(contextValue, redisKey, time.Now().UTC().Unix(), openDuration).Err()
+	return returnedError
+}
+
+// GetOpen 查詢剩餘熔斷時間；未熔斷回傳 0
+func (repository *CircuitBreakerRepository) GetOpen(
+	contextValue context.Context,
+	provider core.ProviderName,
+	endpoint string,
+) (_ time.Duration, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	redisKey := repository.buildKey(provider, endpoint)
+	ttl, err := repository.client.PTTL(contextValue, redisKey).Result()
+	if err != nil && err != redis.Nil {
+		returnedError = err
+		return 0, returnedError
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Clear 解除熔斷
+func (repository *CircuitBreakerRepository) Clear(
+	contextValue context.Context,
+	provider core.ProviderName,
+	endpoint string,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Del(contextValue, repository.buildKey(provider, endpoint)).Err()
+	return returnedError
+}
+
+// buildKey 建構熔斷狀態用的 Redis key
+func (repository *CircuitBreakerRepository) buildKey(provider core.ProviderName, endpoint string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", core.RedisKeyServerName, core.RedisKeyCircuit, provider, endpoint)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CoalesceRepository 跨 replica 請求合併：leader 以 SET NX 取得鎖，完成後寫入結果供其他 replica 讀取
+type CoalesceRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewCoalesceRepository(trace *telemetry.Trace, client *client.RedisClient) *CoalesceRepository {
+	return &CoalesceRepository{trace: trace, client: client.Client()}
+}
+
+// 只釋放自己持有的鎖
+// KEYS[1]=lock key；ARGV[1]=token
+var coalesceReleaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Acquire 嘗試成為 leader
+func (repository *CoalesceRepository) Acquire(
+	contextValue context.Context,
+	coalesceKey string,
+	token string,
+	lease time.Duration,
+) (acquired bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	acquired, returnedError = repository.client.SetNX(contextValue, repository.buildKey("lock", coalesceKey), token, lease).Result()
+	span.SetAttributes(attribute.Bool("coalesce.leader", acquired))
+	return acquired, returnedError
+}
+
+// Release 釋放 leader 鎖
+func (repository *CoalesceRepository) Release(
+	contextValue context.Context,
+	coalesceKey string,
+	token string,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = coalesceReleaseScript.Run(contextValue, repository.client, []string{repository.buildKey("lock", coalesceKey)}, token).Err()
+	return returnedError
+}
+
+// Locked 是否仍有 leader 執行中
+func (repository *CoalesceRepository) Locked(
+	contextValue context.Context,
+	coalesceKey string,
+) (_ bool, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	count, existsError := repository.client.Exists(contextValue, repository.buildKey("lock", coalesceKey)).Result()
+	if existsError != nil {
+		returnedError = existsError
+		return false, returnedError
+	}
+	return count > 0, nil
+}
+
+// SetResult 寫入 leader 結果
+func (repository *CoalesceRepository) SetResult(
+	contextValue context.Context,
+	coalesceKey string,
+	value []byte,
+	ttl time.Duration,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Set(contextValue, repository.buildKey("result", coalesceKey), value, ttl).Err()
+	return returnedError
+}
+
+// GetResult 讀取 leader 結果；尚未完成回傳 nil
+func (repository *CoalesceRepository) GetResult(
+	contextValue context.Context,
+	coalesceKey string,
+) (_ []byte, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	value, getError := repository.client.Get(contextValue, repository.buildKey("result", coalesceKey)).Bytes()
+	if getError == redis.Nil {
+		return nil, nil
+	}
+	if getError != nil {
+		returnedError = getError
+		return nil, returnedError
+	}
+	return value, nil
+}
+
+// buildKey 建構請求合併用的 Redis key
+func (repository *CoalesceRepository) buildKey(kind string, coalesceKey string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", core.RedisKeyServerName, core.RedisKeyCoalesce, kind, coalesceKey)
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// EntityCacheRepository API Key / 使用者文件快取（value 為 BSON）與跨 replica 失效通知。
+// 每份文件另有世代值（generation）：失效時遞增，寫入時須與讀取 Mongo 前的世代值相同，
+// 避免失效前讀到的舊文件在失效後寫回快取。
+type EntityCacheRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewEntityCacheRepository(trace *telemetry.Trace, client *client.RedisClient) *EntityCacheRepository {
+	return &EntityCacheRepository{trace: trace, client: client.Client()}
+}
+
+// KEYS[1]=文件 key、KEYS[2]=世代 key；ARGV[1]=讀取 Mongo 前的世代值、ARGV[2]=value、ARGV[3]=ttl ms
+var entityCacheSetScript = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '0') ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// Get 取得快取；未命中回傳 nil
+func (repository *EntityCacheRepository) Get(
+	contextValue context.Context,
+	entity core.CacheEntity,
+	identifier string,
+) (_ []byte, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	value, err := repository.client.Get(contextValue, repository.buildKey(entity, identifier)).Bytes()
+	if err == redis.Nil {
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		return nil, nil
+	}
+	if err != nil {
+		returnedError = err
+		return nil, returnedError
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", true))
+	return value, nil
+}
+
+// Generation 取得文件目前的世代值（從未失效時為 "0"）；須在讀取 Mongo 前呼叫
+func (repository *EntityCacheRepository) Generation(
+	contextValue context.Context,
+	entity core.CacheEntity,
+	identifier string,
+) (_ string, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	generation, err := repository.client.Get(contextValue, repository.generationKey(entity, identifier)).Result()
+	if err == redis.Nil {
+		return "0", nil
+	}
+	if err != nil {
+		returnedError = err
+		return "", returnedError
+	}
+	return generation, nil
+}
+
+// Set 世代值未變時才寫入快取；stored=false 代表期間已失效，不寫入
+func (repository *EntityCacheRepository) Set(
+	contextValue context.Context,
+	entity core.CacheEntity,
+	identifier string,
+	generation string,
+	value []byte,
+	ttl time.Duration,
+) (stored bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	result, err := entityCacheSetScript.Run(
+		contextValue,
+		repository.client,
+		[]string{repository.buildKey(entity, identifier), repository.generationKey(entity, identifier)},
+		generation,
+		value,
+		ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		returnedError = err
+		return false, returnedError
+	}
+	span.SetAttributes(attribute.Bool("cache.stored", result == 1))
+	return result == 1, nil
+}
+
+// Invalidate 遞增世代值並刪除快取（世代值保留 ttl，涵蓋進行中的讀取）
+func (repository *EntityCacheRepository) Invalidate(
+	contextValue context.Context,
+	entity core.CacheEntity,
+	identifier string,
+	ttl time.Duration,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	generationKey := repository.generationKey(entity, identifier)
+	pipeline := repository.client.TxPipeline()
+	pipeline.Incr(contextValue, generationKey)
+	pipeline.PExpire(contextValue, generationKey, ttl)
+	pipeline.Del(contextValue, repository.buildKey(entity, identifier))
+	_, returnedError = pipeline.Exec(contextValue)
+	return returnedError
+}
+
+// Publish 廣播失效通知（message 為 "<entity>:<id>"）
+func (repository *EntityCacheRepository) Publish(
+	contextValue context.Context,
+	message string,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Publish(contextValue, repository.channel(), message).Err()
+	return returnedError
+}
+
+// Subscribe 訂閱失效通知；呼叫端負責 Close
+func (repository *EntityCacheRepository) Subscribe(contextValue context.Context) *redis.PubSub {
+	return repository.client.Subscribe(contextValue, repository.channel())
+}
+
+// buildKey 建構文件快取用的 Redis key
+func (repository *EntityCacheRepository) buildKey(entity core.CacheEntity, identifier string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", core.RedisKeyServerName, core.RedisKeyEntity, entity, identifier)
+}
+
+// generationKey 建構文件世代值用的 Redis key
+func (repository *EntityCacheRepository) generationKey(entity core.CacheEntity, identifier string) string {
+	return fmt.Sprintf("%s:%s:%s:%s:generation", core.RedisKeyServerName, core.RedisKeyEntity, entity, identifier)
+}
+
+func (repository *EntityCacheRepository) channel() string {
+	return fmt.Sprintf("%s:%s", core.RedisKeyServerName, core.RedisKeyEntityChannel)
+}
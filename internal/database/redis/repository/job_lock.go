@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// JobLockRepository 排程工作的租約鎖：同一工作同時只會由持有 token 的 replica 執行；
+// 持有者崩潰未釋放時，租約到期後自動失效
+type JobLockRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewJobLockRepository(trace *telemetry.Trace, client *client.RedisClient) *JobLockRepository {
+	return &JobLockRepository{trace: trace, client: client.Client()}
+}
+
+// 只有持有者能續約 / 釋放
+// KEYS[1]=lock key；ARGV[1]=token、ARGV[2]=lease ms
+var jobLockRenewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// KEYS[1]=lock key；ARGV[1]=token
+var jobLockReleaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Acquire 以 SET NX PX 取得租約；acquired=false 表示其他 replica 正在執行
+func (repository *JobLockRepository) Acquire(
+	contextValue context.Context,
+	job string,
+	token string,
+	lease time.Duration,
+) (acquired bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	acquired, returnedError = repository.client.SetNX(contextValue, repository.buildKey(job), token, lease).Result()
+	span.SetAttributes(attribute.String("job.name", job), attribute.Bool("job.lock_acquired", acquired))
+	return acquired, returnedError
+}
+
+// Renew 延長租約；回傳 false 表示鎖已不屬於此 token（租約過期被他人取得）
+func (repository *JobLockRepository) Renew(
+	contextValue context.Context,
+	job string,
+	token string,
+	lease time.Duration,
+) (_ bool, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	renewed, returnedError := jobLockRenewScript.Run(contextValue, repository.client, []string{repository.buildKey(job)}, token, lease.Milliseconds()).Int()
+	return renewed == 1, returnedError
+}
+
+// Release 釋放租約（只刪除自己持有的鎖）
+func (repository *JobLockRepository) Release(
+	contextValue context.Context,
+	job string,
+	token string,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	return jobLockReleaseScript.Run(contextValue, repository.client, []string{repository.buildKey(job)}, token).Err()
+}
+
+// Holder 目前持有者 token（未鎖定時回傳空字串）
+func (repository *JobLockRepository) Holder(
+	contextValue context.Context,
+	job string,
+) (_ string, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	holder, err := repository.client.Get(contextValue, repository.buildKey(job)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return holder, err
+}
+
+// ClaimSlot 認領一個排程時段（SET NX，到期自動刪除）；claimed=false 表示其他 replica 已執行該時段。
+// 與租約鎖分開：租約鎖於執行結束即釋放，時鐘稍慢的 replica 仍會在同一時段觸發
+func (repository *JobLockRepository) ClaimSlot(
+	contextValue context.Context,
+	job string,
+	slot int64,
+	instance string,
+	ttl time.Duration,
+) (claimed bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	key := fmt.Sprintf("%s:%s:%s:%d", core.RedisKeyServerName, core.RedisKeyJobSlot, job, slot)
+	claimed, returnedError = repository.client.SetNX(contextValue, key, instance, ttl).Result()
+	span.SetAttributes(attribute.String("job.name", job), attribute.Int64("job.slot", slot), attribute.Bool("job.slot_claimed", claimed))
+	return claimed, returnedError
+}
+
+// buildKey 建構工作鎖的 Redis key
+func (repository *JobLockRepository) buildKey(job string) string {
+	return fmt.Sprintf("%s:%s:%s", core.RedisKeyServerName, core.RedisKeyJobLock, job)
+}
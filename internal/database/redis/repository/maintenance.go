@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MaintenanceRepository 維護模式開關（單一 hash，value 為 JSON）與跨 replica 異動通知
+type MaintenanceRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewMaintenanceRepository(trace *telemetry.Trace, client *client.RedisClient) *MaintenanceRepository {
+	return &MaintenanceRepository{trace: trace, client: client.Client()}
+}
+
+// GetAll 取得所有開關（field → JSON）
+func (repository *MaintenanceRepository) GetAll(
+	contextValue context.Context,
+) (_ map[string]string, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	values, err := repository.client.HGetAll(contextValue, repository.buildKey()).Result()
+	if err != nil {
+		returnedError = err
+		return nil, returnedError
+	}
+	return values, nil
+}
+
+// Set 寫入單一開關
+func (repository *MaintenanceRepository) Set(
+	contextValue context.Context,
+	field string,
+	value []byte,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.HSet(contextValue, repository.buildKey(), field, value).Err()
+	return returnedError
+}
+
+// Delete 刪除單一開關；回傳是否存在
+func (repository *MaintenanceRepository) Delete(
+	contextValue context.Context,
+	field string,
+) (_ bool, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	deleted, err := repository.client.HDel(contextValue, repository.buildKey(), field).Result()
+	if err != nil {
+		returnedError = err
+		return false, returnedError
+	}
+	return deleted > 0, nil
+}
+
+// Publish 廣播異動通知（message 為異動的 field）
+func (repository *MaintenanceRepository) Publish(
+	contextValue context.Context,
+	message string,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Publish(contextValue, repository.channel(), message).Err()
+	return returnedError
+}
+
+// Subscribe 訂閱異動通知；呼叫端負責 Close
+func (repository *MaintenanceRepository) Subscribe(contextValue context.Context) *redis.PubSub {
+	return repository.client.Subscribe(contextValue, repository.channel())
+}
+
+func (repository *MaintenanceRepository) buildKey() string {
+	return fmt.Sprintf("%s:%s", core.RedisKeyServerName, core.RedisKeyMaintenance)
+}
+
+func (repository *MaintenanceRepository) channel() string {
+	return fmt.Sprintf("%s:%s", core.RedisKeyServerName, core.RedisKeyMaintenanceChannel)
+}
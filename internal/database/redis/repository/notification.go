@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// NotificationRepository 通知事件去重：同一事件在 TTL 內只會由一個 replica 送出
+type NotificationRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewNotificationRepository(trace *telemetry.Trace, client *client.RedisClient) *NotificationRepository {
+	return &NotificationRepository{trace: trace, client: client.Client()}
+}
+
+// MarkOnce 以 SET NX 標記事件；回傳 true 表示第一次出現
+func (repository *NotificationRepository) MarkOnce(
+	contextValue context.Context,
+	dedupKey string,
+	ttl time.Duration,
+) (first bool, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	first, returnedError = repository.client.SetNX(contextValue, repository.buildKey(dedupKey), 1, ttl).Result()
+	span.SetAttributes(attribute.Bool("notification.first", first))
+	return first, returnedError
+}
+
+// Unmark 移除標記（事件未成功寫入時釋放，讓之後的相同事件可再送出）
+func (repository *NotificationRepository) Unmark(
+	contextValue context.Context,
+	dedupKey string,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Del(contextValue, repository.buildKey(dedupKey)).Err()
+	return returnedError
+}
+
+// buildKey 建構通知去重用的 Redis key
+func (repository *NotificationRepository) buildKey(dedupKey string) string {
+	return fmt.Sprintf("%s:%s:%s", core.RedisKeyServerName, core.RedisKeyNotify, dedupKey)
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// QuotaRepository user/org 彙總配額計數（Redis Hash：requests / tokens / cost）
+type QuotaRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewQuotaRepository(trace *telemetry.Trace, client *client.RedisClient) *QuotaRepository {
+	return &QuotaRepository{trace: trace, client: client.Client()}
+}
+
+const (
+	quotaFieldRequests = "requests"
+	quotaFieldTokens   = "tokens"
+	quotaFieldCost     = "cost"
+)
+
+// QuotaHold 預留時檢查的單一層級與其上限（上限 < 0 代表該計量單位不限制）
+type QuotaHold struct {
+	Scope           core.QuotaScope
+	ScopeIdentifier string
+	Period          core.LimitPeriod
+	WindowSeconds   int64
+	RequestLimit    int64
+	TokenLimit      int64
+	CostLimit       float64
+}
+
+// 先檢查所有層級，全部有額度才一併累加，避免併發請求超出上限
+// KEYS[i]=各層級配額 key；ARGV[4(i-1)+1..4i]=request / token / cost 上限與視窗秒數；
+// ARGV[4n+1..4n+3]=預留的 requests / tokens / cost
+// 回傳 {0} 代表成功，{i, metric} 代表第 i 個層級的 metric 已用盡
+var quotaReserveScript = redis.NewScript(`
+local n = #KEYS
+local requests = tonumber(ARGV[4 * n + 1])
+local tokens = tonumber(ARGV[4 * n + 2])
+local cost = tonumber(ARGV[4 * n + 3])
+for i = 1, n do
+	local base = 4 * (i - 1)
+	local used = redis.call('HMGET', KEYS[i], 'requests', 'tokens', 'cost')
+	local usedRequests = tonumber(used[1]) or 0
+	local usedTokens = tonumber(used[2]) or 0
+	local usedCost = tonumber(used[3]) or 0
+	local requestLimit = tonumber(ARGV[base + 1])
+	local tokenLimit = tonumber(ARGV[base + 2])
+	local costLimit = tonumber(ARGV[base + 3])
+	if requestLimit >= 0 and usedRequests + requests > requestLimit then
+		return {i, 'requests'}
+	end
+	if tokenLimit >= 0 and (usedTokens >= tokenLimit or usedTokens + tokens > tokenLimit) then
+		return {i, 'tokens'}
+	end
+	if costLimit >= 0 and (usedCost >= costLimit or usedCost + cost > costLimit) then
+		return {i, 'cost'}
+	end
+end
+for i = 1, n do
+	local window = tonumber(ARGV[4 * (i - 1) + 4])
+	redis.call('HINCRBY', KEYS[i], 'requests', requests)
+	redis.call('HINCRBY', KEYS[i], 'tokens', tokens)
+	if cost ~= 0 then
+		redis.call('HINCRBYFLOAT', KEYS[i], 'cost', cost)
+	end
+	if window > 0 and redis.call('TTL', KEYS[i]) < 0 then
+		redis.call('EXPIRE', KEYS[i], window)
+	end
+end
+return {0}
+`)
+
+// Reserve 原子地檢查各層級剩餘額度並預留 usage；exhausted 為用盡的層級索引（-1 代表預留成功）
+func (repository *QuotaRepository) Reserve(
+	contextValue context.Context,
+	holds []QuotaHold,
+	usage core.QuotaUsage,
+) (exhausted int, metric core.QuotaMetric, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.Int("quota.hold_count", len(holds)),
+		attribute.Int64("quota.requests", usage.Requests),
+		attribute.Int64("quota.tokens", usage.Tokens),
+		attribute.Float64("quota.cost", usage.Cost),
+	)
+	if len(holds) == 0 {
+		return -1, "", nil
+	}
+
+	keys := make([]string, 0, len(holds))
+	args := make([]interface{}, 0, 4*len(holds)+3)
+	for _, hold := range holds {
+		keys = append(keys, repository.buildKey(hold.Scope, hold.ScopeIdentifier, hold.Period))
+		args = append(args, hold.RequestLimit, hold.TokenLimit, hold.CostLimit, hold.WindowSeconds)
+	}
+	args = append(args, usage.Requests, usage.Tokens, usage.Cost)
+
+	result, scriptError := quotaReserveScript.Run(contextValue, repository.client, keys, args...).Slice()
+	if scriptError != nil {
+		returnedError = scriptError
+		return -1, "", returnedError
+	}
+	if len(result) == 2 {
+		index, _ := result[0].(int64)
+		name, _ := result[1].(string)
+		span.SetAttributes(attribute.String("quota.exhausted_metric", name))
+		return int(index) - 1, core.QuotaMetric(name), nil
+	}
+	return -1, "", nil
+}
+
+// GetUsage 查詢目前視窗內的累計用量與剩餘 TTL（秒）。若無紀錄回傳零值。
+func (repository *QuotaRepository) GetUsage(
+	contextValue context.Context,
+	scope core.QuotaScope,
+	scopeIdentifier string,
+	period core.LimitPeriod,
+) (_ core.QuotaUsage, timeToLiveSeconds int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("quota.scope", string(scope)),
+		attribute.String("quota.scope_id", scopeIdentifier),
+		attribute.String("quota.period", string(period)),
+	)
+
+	redisKey := repository.buildKey(scope, scopeIdentifier, period)
+
+	pipeline := repository.client.Pipeline()
+	getCommand := pipeline.HMGet(contextValue, redisKey, quotaFieldRequests, quotaFieldTokens, quotaFieldCost)
+	ttlCommand := pipeline.TTL(contextValue, redisKey)
+	if _, execError := pipeline.Exec(contextValue); execError != nil && execError != redis.Nil {
+		returnedError = execError
+		return core.QuotaUsage{}, 0, returnedError
+	}
+
+	var usage core.QuotaUsage
+	values := getCommand.Val()
+	if len(values) == 3 {
+		usage.Requests = parseInt64(values[0])
+		usage.Tokens = parseInt64(values[1])
+		usage.Cost = parseFloat64(values[2])
+	}
+	if ttlDuration := ttlCommand.Val(); ttlDuration > 0 {
+		timeToLiveSeconds = int64(ttlDuration.Seconds())
+	}
+	return usage, timeToLiveSeconds, nil
+}
+
+// Add 累加用量；視窗第一次寫入時設定 TTL（windowSeconds 為 0 代表永不過期）。
+func (repository *QuotaRepository) Add(
+	contextValue context.Context,
+	scope core.QuotaScope,
+	scopeIdentifier string,
+	period core.LimitPeriod,
+	windowSeconds int64,
+	usage core.QuotaUsage,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("quota.scope", string(scope)),
+		attribute.String("quota.scope_id", scopeIdentifier),
+		attribute.String("quota.period", string(period)),
+		attribute.Int64("quota.requests", usage.Requests),
+		attribute.Int64("quota.tokens", usage.Tokens),
+		attribute.Float64("quota.cost", usage.Cost),
+	)
+
+	redisKey := repository.buildKey(scope, scopeIdentifier, period)
+
+	pipeline := repository.client.TxPipeline()
+	pipeline.HIncrBy(contextValue, redisKey, quotaFieldRequests, usage.Requests)
+	pipeline.HIncrBy(contextValue, redisKey, quotaFieldTokens, usage.Tokens)
+	if usage.Cost != 0 {
+		pipeline.HIncrByFloat(contextValue, redisKey, quotaFieldCost, usage.Cost)
+	}
+	ttlCommand := pipeline.TTL(contextValue, redisKey)
+	if _, returnedError = pipeline.Exec(contextValue); returnedError != nil {
+		return returnedError
+	}
+
+	// 新視窗（尚未設定 TTL）才設定過期時間，避免每次寫入延長視窗
+	if windowSeconds > 0 && ttlCommand.Val() < 0 {
+		returnedError = repository.client.Expire(contextValue, redisKey, time.Duration(windowSeconds)*time.Second).Err()
+	}
+	return returnedError
+}
+
+// Reset 清除目前視窗的累計用量（管理用）
+func (repository *QuotaRepository) Reset(
+	contextValue context.Context,
+	scope core.QuotaScope,
+	scopeIdentifier string,
+	period core.LimitPeriod,
+) (returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("quota.scope", string(scope)),
+		attribute.String("quota.scope_id", scopeIdentifier),
+		attribute.String("quota.period", string(period)),
+	)
+
+	redisKey := repository.buildKey(scope, scopeIdentifier, period)
+	returnedError = repository.client.Del(contextValue, redisKey).Err()
+	return returnedError
+}
+
+// buildKey 建構配額用的 Redis key
+func (repository *QuotaRepository) buildKey(scope core.QuotaScope, scopeIdentifier string, period core.LimitPeriod) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%s", core.RedisKeyServerName, core.RedisKeyQuota, scope, scopeIdentifier, period)
+}
+
+func parseInt64(value any) int64 {
+	text, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	parsed, _ := strconv.ParseInt(text, 10, 64)
+	return parsed
+}
+
+func parseFloat64(value any) float64 {
+	text, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	parsed, _ := strconv.ParseFloat(text, 64)
+	return parsed
+}
@@ -6,19 +6,59 @@ import (
 
 // 統一管理所有 Redis repository
 type RedisRepository struct {
-	rateLimitRepo *RateLimiterRepository
+	rateLimitRepo   *RateLimiterRepository
+	quotaRepo       *QuotaRepository
+	semaphoreRepo   *SemaphoreRepository
+	circuitRepo     *CircuitBreakerRepository
+	cacheRepo       *ResponseCacheRepository
+	coalesceRepo    *CoalesceRepository
+	entityRepo      *EntityCacheRepository
+	notifyRepo      *NotificationRepository
+	jobLockRepo     *JobLockRepository
+	maintenanceRepo *MaintenanceRepository
+	anomalyRepo     *AnomalyRepository
 }
 
 // 建立 Redis repository 物件
 func NewRedisRepository(
 	rateLimitRepo *RateLimiterRepository,
+	quotaRepo *QuotaRepository,
+	semaphoreRepo *SemaphoreRepository,
+	circuitRepo *CircuitBreakerRepository,
+	cacheRepo *ResponseCacheRepository,
+	coalesceRepo *CoalesceRepository,
+	entityRepo *EntityCacheRepository,
+	notifyRepo *NotificationRepository,
+	jobLockRepo *JobLockRepository,
+	maintenanceRepo *MaintenanceRepository,
+	anomalyRepo *AnomalyRepository,
 ) *RedisRepository {
 	return &RedisRepository{
-		rateLimitRepo: rateLimitRepo,
+		rateLimitRepo:   rateLimitRepo,
+		quotaRepo:       quotaRepo,
+		semaphoreRepo:   semaphoreRepo,
+		circuitRepo:     circuitRepo,
+		cacheRepo:       cacheRepo,
+		coalesceRepo:    coalesceRepo,
+		entityRepo:      entityRepo,
+		notifyRepo:      notifyRepo,
+		jobLockRepo:     jobLockRepo,
+		maintenanceRepo: maintenanceRepo,
+		anomalyRepo:     anomalyRepo,
 	}
 }
 
 // Wire 依賴提供
 var ProviderSet = wire.NewSet(
 	NewRateLimiterRepository,
+	NewQuotaRepository,
+	NewSemaphoreRepository,
+	NewCircuitBreakerRepository,
+	NewResponseCacheRepository,
+	NewCoalesceRepository,
+	NewEntityCacheRepository,
+	NewNotificationRepository,
+	NewJobLockRepository,
+	NewMaintenanceRepository,
+	NewAnomalyRepository,
 	NewRedisRepository)
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ResponseCacheRepository 回應快取（value 為序列化後的回應，TTL 即快取時間）
+type ResponseCacheRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewResponseCacheRepository(trace *telemetry.Trace, client *client.RedisClient) *ResponseCacheRepository {
+	return &ResponseCacheRepository{trace: trace, client: client.Client()}
+}
+
+// Get 取得快取；未命中回傳 nil
+func (repository *ResponseCacheRepository) Get(
+	contextValue context.Context,
+	cacheKey string,
+) (_ []byte, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	value, err := repository.client.Get(contextValue, repository.buildKey(cacheKey)).Bytes()
+	if err == redis.Nil {
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		return nil, nil
+	}
+	if err != nil {
+		returnedError = err
+		return nil, returnedError
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", true))
+	return value, nil
+}
+
+// Set 寫入快取
+func (repository *ResponseCacheRepository) Set(
+	contextValue context.Context,
+	cacheKey string,
+	value []byte,
+	ttl time.Duration,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	returnedError = repository.client.Set(contextValue, repository.buildKey(cacheKey), value, ttl).Err()
+	return returnedError
+}
+
+// buildKey 建構回應快取用的 Redis key
+func (repository *ResponseCacheRepository) buildKey(cacheKey string) string {
+	return fmt.Sprintf("%s:%s:%s", core.RedisKeyServerName, core.RedisKeyResponseCache, cacheKey)
+}
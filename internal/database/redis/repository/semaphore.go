@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"interchange/internal/core"
+	client "interchange/internal/database/client"
+	"interchange/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SemaphoreRepository 分散式並行槽位（Redis ZSET：member 為 holder token，score 為租約到期毫秒）。
+// 實例崩潰未釋放時，租約到期後由下一次 Acquire 清除。
+type SemaphoreRepository struct {
+	trace  *telemetry.Trace
+	client *redis.Client
+}
+
+func NewSemaphoreRepository(trace *telemetry.Trace, client *client.RedisClient) *SemaphoreRepository {
+	return &SemaphoreRepository{trace: trace, client: client.Client()}
+}
+
+// 以 Redis 伺服器時間判斷租約，避免各實例時鐘誤差
+// KEYS[1]=semaphore key；ARGV[1]=token、ARGV[2]=limit、ARGV[3]=lease ms
+var semaphoreAcquireScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+if redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	redis.call('ZADD', KEYS[1], now + tonumber(ARGV[3]), ARGV[1])
+	return {1, redis.call('ZCARD', KEYS[1])}
+end
+local count = redis.call('ZCARD', KEYS[1])
+if count >= tonumber(ARGV[2]) then
+	return {0, count}
+end
+redis.call('ZADD', KEYS[1], now + tonumber(ARGV[3]), ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1, count + 1}
+`)
+
+// KEYS[1]=semaphore key；ARGV[1]=token、ARGV[2]=lease ms
+var semaphoreRefreshScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local updated = redis.call('ZADD', KEYS[1], 'XX', 'CH', now + tonumber(ARGV[2]), ARGV[1])
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[2]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return updated
+`)
+
+// Acquire 嘗試取得一個槽位；acquired=false 代表已達上限。inFlight 為目前占用數。
+func (repository *SemaphoreRepository) Acquire(
+	contextValue context.Context,
+	scope core.SemaphoreScope,
+	scopeIdentifier string,
+	token string,
+	limit int,
+	lease time.Duration,
+) (acquired bool, inFlight int64, returnedError error) {
+
+	contextValue, span, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	span.SetAttributes(
+		attribute.String("semaphore.scope", string(scope)),
+		attribute.String("semaphore.scope_id", scopeIdentifier),
+		attribute.Int("semaphore.limit", limit),
+	)
+
+	redisKey := repository.buildKey(scope, scopeIdentifier)
+	result, scriptError := semaphoreAcquireScript.Run(
+		contextValue,
+		repository.client,
+		[]string{redisKey},
+		token,
+		limit,
+		lease.Milliseconds(),
+	).Int64Slice()
+	if scriptError != nil {
+		returnedError = scriptError
+		return false, 0, returnedError
+	}
+	if len(result) != 2 {
+		returnedError = fmt.Errorf("unexpected semaphore acquire result: %v", result)
+		return false, 0, returnedError
+	}
+
+	acquired, inFlight = result[0] == 1, result[1]
+	span.SetAttributes(
+		attribute.Bool("semaphore.acquired", acquired),
+		attribute.Int64("semaphore.in_flight", inFlight),
+	)
+	return acquired, inFlight, nil
+}
+
+// Refresh 延長持有中槽位的租約（長時間串流用）；槽位已過期被清除時回傳 false。
+func (repository *SemaphoreRepository) Refresh(
+	contextValue context.Context,
+	scope core.SemaphoreScope,
+	scopeIdentifier string,
+	token string,
+	lease time.Duration,
+) (_ bool, returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	redisKey := repository.buildKey(scope, scopeIdentifier)
+	updated, scriptError := semaphoreRefreshScript.Run(
+		contextValue,
+		repository.client,
+		[]string{redisKey},
+		token,
+		lease.Milliseconds(),
+	).Int64()
+	if scriptError != nil {
+		returnedError = scriptError
+		return false, returnedError
+	}
+	return updated == 1, nil
+}
+
+// Release 釋放槽位
+func (repository *SemaphoreRepository) Release(
+	contextValue context.Context,
+	scope core.SemaphoreScope,
+	scopeIdentifier string,
+	token string,
+) (returnedError error) {
+
+	contextValue, _, endSpan := repository.trace.WithSpan(contextValue)
+	defer func() { endSpan(returnedError) }()
+
+	redisKey := repository.buildKey(scope, scopeIdentifier)
+	returnedError = repository.client.ZRem(contextValue, redisKey, token).Err()
+	return returnedError
+}
+
+// buildKey 建構 semaphore 用的 Redis key
+func (repository *SemaphoreRepository) buildKey(scope core.SemaphoreScope, scopeIdentifier string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", core.RedisKeyServerName, core.RedisKeySemaphore, scope, scopeIdentifier)
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 異常事件查詢條件（from / to 為 RFC3339 或 YYYY-MM-DD）
+type AnomalyQueryDto struct {
+	APIKeyID string `json:"apiKeyID"`
+	UserID   string `json:"userID"`
+	Provider string `json:"provider"`
+	Signal   string `json:"signal"`
+	Action   string `json:"action"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Page     int64  `json:"page"`
+	Size     int64  `json:"size"`
+}
+
+type AnomalyEventDto struct {
+	ID        string                 `json:"id"`
+	APIKeyID  string                 `json:"apiKeyID"`
+	UserID    string                 `json:"userID"`
+	Provider  core.ProviderName      `json:"provider"`
+	Endpoint  string                 `json:"endpoint"`
+	Signal    core.AnomalySignal     `json:"signal"`
+	Action    core.AnomalyAction     `json:"action"`
+	Applied   bool                   `json:"applied"`
+	Detail    map[string]interface{} `json:"detail,omitempty"`
+	ClientIP  string                 `json:"clientIP,omitempty"`
+	UserAgent string                 `json:"userAgent,omitempty"`
+	Model     string                 `json:"model,omitempty"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// 管理端解除異常處置（限流與重新驗證；停用的 provider 存取以重新啟用端點恢復）
+type AnomalyClearResultDto struct {
+	ThrottleCleared bool `json:"throttleCleared"`
+	ReverifyCleared bool `json:"reverifyCleared"`
+}
+
+// 擁有者以通知收到的驗證碼重新驗證 API Key
+type ReverifyAPIKeyDto struct {
+	Code string `json:"code" binding:"required,len=8,numeric"`
+}
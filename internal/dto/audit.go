@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 稽核紀錄查詢條件（from / to 為 RFC3339 或 YYYY-MM-DD）
+type AuditQueryDto struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	ActorType  string `json:"actorType"`
+	Actor      string `json:"actor"`
+	Action     string `json:"action"`
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetID"`
+	Provider   string `json:"provider"`
+	RequestID  string `json:"requestID"`
+	Page       int64  `json:"page"`
+	Size       int64  `json:"size"`
+}
+
+type AuditLogDto struct {
+	ID         string               `json:"id"`
+	ActorType  core.AuditActorType  `json:"actorType"`
+	Actor      string               `json:"actor,omitempty"`
+	Action     core.AuditAction     `json:"action"`
+	TargetType core.AuditTargetType `json:"targetType"`
+	TargetID   string               `json:"targetID"`
+	Provider   core.ProviderName    `json:"provider,omitempty"`
+	Changes    []AuditChangeDto     `json:"changes,omitempty"`
+	Reason     string               `json:"reason,omitempty"`
+	ClientIP   string               `json:"clientIP,omitempty"`
+	UserAgent  string               `json:"userAgent,omitempty"`
+	RequestID  string               `json:"requestID,omitempty"` // trace ID
+	CreatedAt  time.Time            `json:"createdAt"`
+}
+
+// AuditChangeDto 欄位差異（巢狀欄位以 "." 串接）
+type AuditChangeDto struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 熔斷器狀態與統計視窗內的近期錯誤率
+type CircuitBreakerStateDto struct {
+	Provider        core.ProviderName `json:"provider"`
+	Endpoint        string            `json:"endpoint"`
+	State           core.CircuitState `json:"state"`
+	Requests        int               `json:"requests"`
+	Failures        int               `json:"failures"`
+	SlowCalls       int               `json:"slowCalls"`
+	ErrorRate       float64           `json:"errorRate"`
+	AvgLatencyMs    float64           `json:"avgLatencyMs"`
+	OpenedAt        *time.Time        `json:"openedAt,omitempty"`
+	RetryAfterSec   int64             `json:"retryAfterSec,omitempty"`
+	SharedOpen      bool              `json:"sharedOpen"`
+	LastFailureAt   *time.Time        `json:"lastFailureAt,omitempty"`
+	LastFailureText string            `json:"lastFailure,omitempty"`
+}
+
+// 手動重置熔斷器
+type ResetCircuitBreakerDto struct {
+	Provider core.ProviderName `json:"provider" binding:"required"`
+	Endpoint string            `json:"endpoint" binding:"required"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 儲值 / 調整 / 退款（儲值與退款金額須為正數，調整可正可負）
+type CreditOperationDto struct {
+	Amount         float64 `json:"amount" binding:"required"`
+	Note           string  `json:"note,omitempty"`
+	IdempotencyKey string  `json:"idempotencyKey,omitempty"` // 重送時帶相同值，避免重複入帳
+}
+
+// 帳戶餘額
+type CreditBalanceDto struct {
+	Scope     core.CreditScope `json:"scope"`
+	ScopeID   string           `json:"scopeID"`
+	Balance   float64          `json:"balance"`
+	Currency  string           `json:"currency"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// 帳本分錄
+type CreditLedgerEntryDto struct {
+	ID           string               `json:"id"`
+	Type         core.CreditEntryType `json:"type"`
+	Amount       float64              `json:"amount"`
+	UserID       string               `json:"userID,omitempty"`
+	Model        string               `json:"model,omitempty"`
+	InputTokens  int                  `json:"inputTokens,omitempty"`
+	OutputTokens int                  `json:"outputTokens,omitempty"`
+	Note         string               `json:"note,omitempty"`
+	CreatedAt    time.Time            `json:"createdAt"`
+}
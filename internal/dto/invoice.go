@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 產生月結帳單（scopeID 為空時產生帳期內所有對象）
+type GenerateInvoiceDto struct {
+	Scope   core.InvoiceScope `json:"scope" binding:"required,oneof=project user"`
+	ScopeID string            `json:"scopeID,omitempty"`
+	Period  string            `json:"period" binding:"required"` // YYYY-MM
+}
+
+type InvoiceDto struct {
+	ID          string               `json:"id"`
+	Number      string               `json:"number"`
+	Scope       core.InvoiceScope    `json:"scope"`
+	ScopeID     string               `json:"scopeID"`
+	Period      string               `json:"period"`
+	PeriodStart time.Time            `json:"periodStart"`
+	PeriodEnd   time.Time            `json:"periodEnd"`
+	Currency    string               `json:"currency"`
+	LineItems   []InvoiceLineItemDto `json:"lineItems,omitempty"`
+	Requests    int64                `json:"requests"`
+	TokensTotal int64                `json:"tokensTotal"`
+	Subtotal    float64              `json:"subtotal"`
+	MarkupRate  float64              `json:"markupRate"`
+	Markup      float64              `json:"markup"`
+	TaxRate     float64              `json:"taxRate"`
+	Tax         float64              `json:"tax"`
+	Total       float64              `json:"total"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+}
+
+type InvoiceLineItemDto struct {
+	Provider       string  `json:"provider"`
+	Model          string  `json:"model"`
+	Endpoint       string  `json:"endpoint"`
+	Requests       int64   `json:"requests"`
+	CachedRequests int64   `json:"cachedRequests"`
+	InputTokens    int64   `json:"inputTokens"`
+	OutputTokens   int64   `json:"outputTokens"`
+	TokensTotal    int64   `json:"tokensTotal"`
+	Cost           float64 `json:"cost"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+type JobDto struct {
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	Spec           string            `json:"spec"`
+	Local          bool              `json:"local"` // 每個 replica 各自執行（不取分散式鎖）
+	Paused         bool              `json:"paused"`
+	PausedBy       string            `json:"pausedBy,omitempty"`
+	PausedAt       *time.Time        `json:"pausedAt,omitempty"`
+	Running        bool              `json:"running"` // 是否有 replica 持有租約鎖
+	LastRunAt      *time.Time        `json:"lastRunAt,omitempty"`
+	LastStatus     core.JobRunStatus `json:"lastStatus,omitempty"`
+	LastDurationMs int64             `json:"lastDurationMs,omitempty"`
+}
+
+type JobRunDto struct {
+	ID          string            `json:"id"`
+	Job         string            `json:"job"`
+	Trigger     core.JobTrigger   `json:"trigger"`
+	TriggeredBy string            `json:"triggeredBy,omitempty"`
+	Instance    string            `json:"instance"`
+	Status      core.JobRunStatus `json:"status"`
+	Message     string            `json:"message,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	StartedAt   time.Time         `json:"startedAt"`
+	FinishedAt  *time.Time        `json:"finishedAt,omitempty"`
+	DurationMs  int64             `json:"durationMs"`
+}
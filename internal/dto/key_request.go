@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 單一 provider 的申請內容（不含 provider key 與進階 policy，核准時可由審核者補上）
+type KeyRequestAccessDto struct {
+	Provider    core.ProviderName `json:"provider" binding:"required"`
+	ApiScopes   []core.ApiScope   `json:"apiScopes" binding:"required,min=1"`
+	LimitPeriod *core.LimitPeriod `json:"limitPeriod" binding:"omitempty,oneof=daily weekly monthly yearly none"`
+	LimitCount  *int              `json:"limitCount" binding:"omitempty,min=1"`
+	ExpireTime  *time.Time        `json:"expireTime" binding:"omitempty"`
+}
+
+// 送出 API Key 申請
+type CreateKeyRequestDto struct {
+	KeyName        string                `json:"keyName" binding:"required,max=100"`
+	ProviderAccess []KeyRequestAccessDto `json:"providerAccess" binding:"required,min=1,dive"`
+	Justification  string                `json:"justification" binding:"required,min=10,max=2000"`
+}
+
+// 核准申請；未帶 keyName / providerAccess 時依申請內容核發，帶入時以審核者修改後的內容核發。
+// 每個 provider 都需要上游金鑰：providerAccess 未帶 providerKey 時以 providerKeys 對應的值補上，仍缺少時拒絕核准
+type ApproveKeyRequestDto struct {
+	KeyName        *string                      `json:"keyName" binding:"omitempty,max=100"`
+	ProviderAccess *[]ProviderAccessDto         `json:"providerAccess" binding:"omitempty,min=1,dive"`
+	ProviderKeys   map[core.ProviderName]string `json:"providerKeys" binding:"omitempty"` // provider -> 上游金鑰
+	Note           string                       `json:"note" binding:"omitempty,max=2000"`
+}
+
+// 駁回申請
+type RejectKeyRequestDto struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+type KeyRequestDto struct {
+	ID             string                 `json:"id"`
+	UserID         string                 `json:"userID"`
+	KeyName        string                 `json:"keyName"`
+	ProviderAccess []KeyRequestAccessDto  `json:"providerAccess"`
+	Justification  string                 `json:"justification"`
+	Status         core.Status            `json:"status"` // pending / approved / rejected
+	ReviewedBy     string                 `json:"reviewedBy,omitempty"`
+	ReviewNote     string                 `json:"reviewNote,omitempty"`
+	ReviewedAt     *time.Time             `json:"reviewedAt,omitempty"`
+	ApprovedAccess []KeyRequestAccessDto  `json:"approvedAccess,omitempty"`
+	APIKeyID       string                 `json:"apiKeyID,omitempty"`
+	APIKey         *UserAPIKeyResponseDto `json:"apiKey,omitempty"` // 核准時回傳核發的 API Key
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "interchange/internal/core"
+
+// 自助重新啟用結果
+type ReactivationResultDto struct {
+	UserReactivated bool                `json:"userReactivated"` // 帳號是否由閒置停用恢復
+	Providers       []core.ProviderName `json:"providers"`       // 這把 key 恢復的 provider
+}
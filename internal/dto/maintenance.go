@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 新增 / 覆寫維護模式開關（以 scope + provider + endpoint 為識別）
+type UpsertMaintenanceDto struct {
+	Scope             core.MaintenanceScope `json:"scope" binding:"required,oneof=global provider endpoint"`
+	Provider          core.ProviderName     `json:"provider"`
+	Endpoint          string                `json:"endpoint"` // 例如 "/chat/completions"、"/images/*"、"/mcp-server/*"
+	Enabled           *bool                 `json:"enabled" binding:"required"`
+	StartsAt          *time.Time            `json:"startsAt"`
+	EndsAt            *time.Time            `json:"endsAt"`
+	Message           string                `json:"message" binding:"max=500"`
+	RetryAfterSeconds int64                 `json:"retryAfterSeconds" binding:"min=0,max=86400"`
+	AllowKeys         []string              `json:"allowKeys" binding:"omitempty,max=50,dive,len=24,hexadecimal"` // 可略過維護的 API Key ID
+}
+
+// 刪除維護模式開關的識別（query string）
+type MaintenanceTargetDto struct {
+	Scope    core.MaintenanceScope `json:"scope"`
+	Provider core.ProviderName     `json:"provider"`
+	Endpoint string                `json:"endpoint"`
+}
+
+type MaintenanceDto struct {
+	Scope             core.MaintenanceScope `json:"scope"`
+	Provider          core.ProviderName     `json:"provider,omitempty"`
+	Endpoint          string                `json:"endpoint,omitempty"`
+	Enabled           bool                  `json:"enabled"`
+	Active            bool                  `json:"active"` // 目前是否生效（已啟用且在時間窗內）
+	StartsAt          *time.Time            `json:"startsAt,omitempty"`
+	EndsAt            *time.Time            `json:"endsAt,omitempty"`
+	Message           string                `json:"message,omitempty"`
+	RetryAfterSeconds int64                 `json:"retryAfterSeconds,omitempty"`
+	AllowKeys         []string              `json:"allowKeys"`
+	UpdatedBy         string                `json:"updatedBy,omitempty"`
+	UpdatedAt         time.Time             `json:"updatedAt"`
+}
+
+// 維護中回給 client 的內容（503 回應的 data）
+type MaintenanceNoticeDto struct {
+	Scope      core.MaintenanceScope `json:"scope"`
+	Provider   core.ProviderName     `json:"provider,omitempty"`
+	Endpoint   string                `json:"endpoint,omitempty"`
+	Message    string                `json:"message"`
+	RetryAfter int64                 `json:"retryAfter"` // 秒
+	EndsAt     *time.Time            `json:"endsAt,omitempty"`
+}
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 新增通知規則（webhookURL 與 emails 至少擇一；未帶 webhookSecret 時自動產生，只在建立時回傳一次）
+type CreateNotificationRuleDto struct {
+	Name          string                   `json:"name" binding:"required,max=64"`
+	Scope         core.NotificationScope   `json:"scope" binding:"required,oneof=key user org global"`
+	ScopeID       string                   `json:"scopeID,omitempty"`
+	Events        []core.NotificationEvent `json:"events" binding:"required,min=1,dive,oneof=quota.threshold key.expiring key.expired user.inactive user.suspended key.inactive key.suspended key_request.submitted key_request.approved key_request.rejected spend.anomaly key.anomaly upstream.failure"`
+	Thresholds    []int                    `json:"thresholds,omitempty" binding:"omitempty,dive,min=1,max=100"`
+	WebhookURL    string                   `json:"webhookURL,omitempty" binding:"omitempty,url"`
+	WebhookSecret string                   `json:"webhookSecret,omitempty" binding:"omitempty,min=16"`
+	Emails        []string                 `json:"emails,omitempty" binding:"omitempty,dive,email"`
+	Enabled       *bool                    `json:"enabled,omitempty"` // 未帶時預設啟用
+}
+
+// 更新通知規則（只更新有帶的欄位；webhookURL 帶空字串表示移除）
+type UpdateNotificationRuleDto struct {
+	Name          *string                   `json:"name,omitempty" binding:"omitempty,max=64"`
+	Events        *[]core.NotificationEvent `json:"events,omitempty" binding:"omitempty,min=1,dive,oneof=quota.threshold key.expiring key.expired user.inactive user.suspended key.inactive key.suspended key_request.submitted key_request.approved key_request.rejected spend.anomaly key.anomaly upstream.failure"`
+	Thresholds    *[]int                    `json:"thresholds,omitempty" binding:"omitempty,dive,min=1,max=100"`
+	WebhookURL    *string                   `json:"webhookURL,omitempty"`
+	WebhookSecret *string                   `json:"webhookSecret,omitempty" binding:"omitempty,min=16"`
+	Emails        *[]string                 `json:"emails,omitempty" binding:"omitempty,dive,email"`
+	Enabled       *bool                     `json:"enabled,omitempty"`
+}
+
+type NotificationRuleDto struct {
+	ID            string                   `json:"id"`
+	Name          string                   `json:"name"`
+	Scope         core.NotificationScope   `json:"scope"`
+	ScopeID       string                   `json:"scopeID,omitempty"`
+	Events        []core.NotificationEvent `json:"events"`
+	Thresholds    []int                    `json:"thresholds,omitempty"`
+	WebhookURL    string                   `json:"webhookURL,omitempty"`
+	WebhookSecret string                   `json:"webhookSecret,omitempty"` // 只在建立時回傳
+	Emails        []string                 `json:"emails,omitempty"`
+	Enabled       bool                     `json:"enabled"`
+	CreatedBy     string                   `json:"createdBy,omitempty"`
+	CreatedAt     time.Time                `json:"createdAt"`
+	UpdatedAt     time.Time                `json:"updatedAt"`
+}
+
+type NotificationDeliveryDto struct {
+	ID            string                          `json:"id"`
+	RuleID        string                          `json:"ruleID"`
+	EventID       string                          `json:"eventID"`
+	Event         core.NotificationEvent          `json:"event"`
+	Channel       core.NotificationChannel        `json:"channel"`
+	Target        string                          `json:"target"`
+	Subject       string                          `json:"subject"`
+	Payload       string                          `json:"payload"`
+	Status        core.NotificationDeliveryStatus `json:"status"`
+	Attempts      int                             `json:"attempts"`
+	LastError     string                          `json:"lastError,omitempty"`
+	NextAttemptAt time.Time                       `json:"nextAttemptAt"`
+	DeliveredAt   *time.Time                      `json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time                       `json:"createdAt"`
+}
+
+// webhook body（HMAC-SHA256 簽章於 X-Interchange-Signature 標頭：sha256=hex(HMAC(secret, timestamp + "." + body))）
+type NotificationPayloadDto struct {
+	ID         string                 `json:"id"`
+	Event      core.NotificationEvent `json:"event"`
+	Subject    string                 `json:"subject"`
+	OccurredAt time.Time              `json:"occurredAt"`
+	KeyID      string                 `json:"keyID,omitempty"`
+	UserID     string                 `json:"userID,omitempty"`
+	OrgID      string                 `json:"orgID,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+}
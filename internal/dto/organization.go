@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"interchange/internal/core"
+	"time"
+)
+
+// 建立組織
+type CreateOrganizationDto struct {
+	Name        string          `json:"name" binding:"required"`
+	Description string          `json:"description,omitempty"`
+	Status      core.Status     `json:"status" binding:"required"`
+	QuotaPolicy *QuotaPolicyDto `json:"quotaPolicy,omitempty"`
+}
+
+// 更新組織
+type UpdateOrganizationDto struct {
+	Name        *string      `json:"name,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Status      *core.Status `json:"status,omitempty"`
+}
+
+type OrganizationResponseDto struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Status      core.Status     `json:"status"`
+	QuotaPolicy *QuotaPolicyDto `json:"quotaPolicy,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
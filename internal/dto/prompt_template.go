@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// 新增樣板版本（同名樣板自動遞增版本）
+type CreatePromptTemplateDto struct {
+	Name        string             `json:"name" binding:"required,max=64"`
+	Description string             `json:"description,omitempty"`
+	Messages    []PromptMessageDto `json:"messages" binding:"required,min=1,dive"`
+	Variables   []string           `json:"variables,omitempty" binding:"omitempty,dive,required"`
+}
+
+// 樣板訊息（content 使用 text/template 語法，例如 {{.customerName}}）
+type PromptMessageDto struct {
+	Role    string `json:"role" binding:"required,oneof=system developer user assistant"`
+	Content string `json:"content" binding:"required"`
+}
+
+type PromptTemplateResponseDto struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Version     int                `json:"version"`
+	Description string             `json:"description,omitempty"`
+	Messages    []PromptMessageDto `json:"messages"`
+	Variables   []string           `json:"variables,omitempty"`
+	CreatedBy   string             `json:"createdBy,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt"`
+}
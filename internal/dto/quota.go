@@ -0,0 +1,26 @@
+package dto
+
+import "interchange/internal/core"
+
+// 設定 user / org 配額
+type QuotaPolicyDto struct {
+	LimitPeriod  core.LimitPeriod `json:"limitPeriod" binding:"required"`   // daily, weekly, monthly...
+	RequestLimit *int64           `json:"requestLimit" binding:"omitempty"` // 請求次數上限
+	TokenLimit   *int64           `json:"tokenLimit" binding:"omitempty"`   // token 上限
+	CostLimit    *float64         `json:"costLimit" binding:"omitempty"`    // 成本上限
+}
+
+// 配額狀態（政策 + 目前視窗用量 + 各項剩餘額度）
+type QuotaStatusDto struct {
+	Scope        core.QuotaScope    `json:"scope"`
+	ScopeID      string             `json:"scopeID"`
+	Policy       *QuotaPolicyDto    `json:"policy,omitempty"`
+	Usage        core.QuotaUsage    `json:"usage"`
+	ResetSeconds int64              `json:"resetSeconds"`
+	Budgets      []core.QuotaBudget `json:"budgets"`
+}
+
+// 指派使用者所屬組織（orgID 為空代表移出組織）
+type UpdateUserOrganizationDto struct {
+	OrgID string `json:"orgID" binding:"omitempty"`
+}
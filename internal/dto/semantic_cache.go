@@ -0,0 +1,16 @@
+package dto
+
+// 語意快取命名空間統計（hits / misses 為目前 replica 自啟動以來的統計）
+type SemanticCacheStatsDto struct {
+	Namespace string  `json:"namespace"`
+	Entries   int64   `json:"entries"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	HitRate   float64 `json:"hitRate"`
+}
+
+// 清除語意快取結果
+type SemanticCacheInvalidateResponseDto struct {
+	Namespace string `json:"namespace,omitempty"`
+	Deleted   int64  `json:"deleted"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// 用量查詢條件（from / to 為 RFC3339 或 YYYY-MM-DD，to 不含；groupBy：user, apiKey, project, provider, model, endpoint）
+type UsageQueryDto struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	UserID   string   `json:"userID"`
+	APIKeyID string   `json:"apiKeyID"`
+	Project  string   `json:"project"`
+	Provider string   `json:"provider"`
+	Model    string   `json:"model"`
+	Endpoint string   `json:"endpoint"`
+	GroupBy  []string `json:"groupBy"`
+	Bucket   string   `json:"bucket"`   // hour, day, month；空字串不分區間
+	Timezone string   `json:"timezone"` // IANA 時區，決定區間切點（預設 UTC）
+}
+
+// 用量彙總（只會填入有指定的分組欄位）
+type UsageAggregateDto struct {
+	Bucket           *time.Time `json:"bucket,omitempty"`
+	UserID           string     `json:"userID,omitempty"`
+	APIKeyID         string     `json:"apiKeyID,omitempty"`
+	Project          string     `json:"project,omitempty"`
+	Provider         string     `json:"provider,omitempty"`
+	Model            string     `json:"model,omitempty"`
+	Endpoint         string     `json:"endpoint,omitempty"`
+	Requests         int64      `json:"requests"`
+	CachedRequests   int64      `json:"cachedRequests"`
+	TokensPrompt     int64      `json:"tokensPrompt"`
+	TokensCompletion int64      `json:"tokensCompletion"`
+	InputTokens      int64      `json:"inputTokens"`
+	OutputTokens     int64      `json:"outputTokens"`
+	TokensTotal      int64      `json:"tokensTotal"`
+	Cost             float64    `json:"cost"`
+}
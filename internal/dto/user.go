@@ -35,14 +35,20 @@ type UpdateUserRoleDto struct {
 	Role core.Role `json:"role" binding:"required"`
 }
 type UserResponseDto struct {
-	ID          string      `json:"id"`
-	ExternalID  string      `json:"externalID,omitempty"`
-	DisplayName string      `json:"displayName"`
-	Email       string      `json:"email,omitempty"`
-	Lang        string      `json:"lang,omitempty"`
-	Role        core.Role   `json:"role"`
-	Status      core.Status `json:"status"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updatedAt" `
-	LastSeen    *time.Time  `json:"last_seen,omitempty"`
+	ID          string          `json:"id"`
+	ExternalID  string          `json:"externalID,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Email       string          `json:"email,omitempty"`
+	Lang        string          `json:"lang,omitempty"`
+	Role        core.Role       `json:"role"`
+	Status      core.Status     `json:"status"`
+	OrgID       string          `json:"orgID,omitempty"`
+	QuotaPolicy *QuotaPolicyDto `json:"quotaPolicy,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updatedAt" `
+	LastSeen    *time.Time      `json:"last_seen,omitempty"`
+	// 閒置生命週期（唯讀）
+	InactivityWarnedAt *time.Time         `json:"inactivityWarnedAt,omitempty"`
+	SuspendReason      core.SuspendReason `json:"suspendReason,omitempty"`
+	SuspendedAt        *time.Time         `json:"suspendedAt,omitempty"`
 }
@@ -6,16 +6,77 @@ import (
 )
 
 type ProviderAccessDto struct {
-	Provider    core.ProviderName `json:"provider" binding:"required"`     // openai, gemini...
-	ProviderKey string            `json:"providerKey" binding:"omitempty"` // 可選，對應 Provider 的 API Key
-	Status      core.Status       `json:"status" binding:"required"`       // active, blocked...
-	LimitPeriod *core.LimitPeriod `json:"limitPeriod" binding:"omitempty"` // daily, weekly, monthly...
-	LimitCount  *int              `json:"limitCount" binding:"omitempty"`  // 限制次數
-	UsedCount   int               `json:"usedCount" binding:"omitempty"`   // 已使用次數
-	LastResetAt *time.Time        `json:"lastResetAt" binding:"omitempty"` // 最後重置時間
-	ApiScopes   []core.ApiScope   `json:"apiScopes" binding:"omitempty"`   // 可選，API 權限範圍
-	ExpireTime  *time.Time        `json:"expireTime" binding:"omitempty"`  // API Key 過期時間
-	LastSeen    *time.Time        `json:"lastSeen" binding:"omitempty"`    // 最後使用時間
+	Provider    core.ProviderName       `json:"provider" binding:"required"`     // openai, gemini...
+	ProviderKey string                  `json:"providerKey" binding:"omitempty"` // 可選，對應 Provider 的 API Key
+	Status      core.Status             `json:"status" binding:"required"`       // active, blocked...
+	LimitPeriod *core.LimitPeriod       `json:"limitPeriod" binding:"omitempty"` // daily, weekly, monthly...
+	LimitCount  *int                    `json:"limitCount" binding:"omitempty"`  // 限制次數
+	UsedCount   int                     `json:"usedCount" binding:"omitempty"`   // 已使用次數
+	LastResetAt *time.Time              `json:"lastResetAt" binding:"omitempty"` // 最後重置時間
+	ApiScopes   []core.ApiScope         `json:"apiScopes" binding:"omitempty"`   // 可選，API 權限範圍
+	ExpireTime  *time.Time              `json:"expireTime" binding:"omitempty"`  // API Key 過期時間
+	LastSeen    *time.Time              `json:"lastSeen" binding:"omitempty"`    // 最後使用時間
+	Concurrency *ConcurrencyPolicyDto   `json:"concurrency" binding:"omitempty"` // 可選，並行上限設定
+	Models      *ModelPolicyDto         `json:"models" binding:"omitempty"`      // 可選，可用模型與別名
+	Request     *RequestPolicyDto       `json:"request" binding:"omitempty"`     // 可選，請求參數防護
+	Prompt      *PromptPolicyDto        `json:"prompt" binding:"omitempty"`      // 可選，強制 system prompt
+	Cache       *CachePolicyDto         `json:"cache" binding:"omitempty"`       // 可選，回應快取
+	Semantic    *SemanticCachePolicyDto `json:"semantic" binding:"omitempty"`    // 可選，語意快取
+	// 閒置生命週期（唯讀，寫入時忽略）
+	InactivityWarnedAt *time.Time         `json:"inactivityWarnedAt,omitempty"`
+	SuspendReason      core.SuspendReason `json:"suspendReason,omitempty"`
+}
+
+// 語意快取（threshold 為 cosine 相似度 0~1；namespace 未設定時為 API Key ID）
+type SemanticCachePolicyDto struct {
+	Enabled   bool     `json:"enabled"`
+	Threshold *float64 `json:"threshold" binding:"omitempty,gt=0,lte=1"`
+	Namespace string   `json:"namespace" binding:"omitempty,max=64"`
+}
+
+// 回應快取（僅 temperature=0 的 chat 與 embedding；namespace 未設定時為 API Key ID）
+type CachePolicyDto struct {
+	Enabled    bool   `json:"enabled"`
+	TTLSeconds *int   `json:"ttlSeconds" binding:"omitempty,min=1"`
+	Namespace  string `json:"namespace" binding:"omitempty,max=64"`
+}
+
+// 強制 system prompt（prepend 置於最前、append 置於最後）
+type PromptPolicyDto struct {
+	Prepend string `json:"prepend" binding:"omitempty"`
+	Append  string `json:"append" binding:"omitempty"`
+}
+
+// 請求參數防護（key 為請求欄位名稱；force 可用 {{userID}}、{{externalID}}）
+type RequestPolicyDto struct {
+	Limits  map[string]NumericLimitDto `json:"limits" binding:"omitempty,dive"`
+	Allowed map[string][]string        `json:"allowed" binding:"omitempty"`
+	Force   map[string]interface{}     `json:"force" binding:"omitempty"`
+	Strip   []string                   `json:"strip" binding:"omitempty,dive,required"`
+}
+
+// 數值上下限（action: clamp 夾到範圍內 / reject 回 400）
+type NumericLimitDto struct {
+	Min    *float64         `json:"min" binding:"omitempty"`
+	Max    *float64         `json:"max" binding:"omitempty"`
+	Action core.LimitAction `json:"action" binding:"omitempty,oneof=clamp reject"`
+}
+
+// 可用模型限制（glob；deny 優先於 allow，allow 為空代表不限制；aliases 為別名 → 實際模型）
+type ModelPolicyDto struct {
+	Allow   []string          `json:"allow" binding:"omitempty,dive,required"`
+	Deny    []string          `json:"deny" binding:"omitempty,dive,required"`
+	Aliases map[string]string `json:"aliases" binding:"omitempty"`
+}
+
+// 並行上限設定（mode: reject 直接回 429 / queue 排隊等待）
+type ConcurrencyPolicyDto struct {
+	MaxInFlight         *int                 `json:"maxInFlight" binding:"omitempty,min=1"`
+	UpstreamMaxInFlight *int                 `json:"upstreamMaxInFlight" binding:"omitempty,min=1"`
+	Mode                core.ConcurrencyMode `json:"mode" binding:"omitempty,oneof=reject queue"`
+	QueueTimeoutMs      *int                 `json:"queueTimeoutMs" binding:"omitempty,min=0"`
+	Priority            core.PriorityTier    `json:"priority" binding:"omitempty,oneof=production standard batch"`
+	Weight              *int                 `json:"weight" binding:"omitempty,min=1"`
 }
 
 // 建立 API Key
@@ -37,6 +98,8 @@ type UserAPIKeyResponseDto struct {
 	KeyName        string              `json:"keyName"`
 	KeyValue       string              `json:"keyValue"`
 	ProviderAccess []ProviderAccessDto `json:"providerAccess"`
+	SuspendReason  core.SuspendReason  `json:"suspendReason,omitempty"` // 停用標記（擁有者停用時）
+	SuspendedAt    *time.Time          `json:"suspendedAt,omitempty"`
 	CreatedAt      time.Time           `json:"createdAt"`
 	UpdatedAt      time.Time           `json:"updatedAt"`
 }
@@ -44,3 +107,11 @@ type UserAPIKeyResponseDto struct {
 type UpdateProviderAccessAllDto struct {
 	ProviderAccess []ProviderAccessDto `json:"providerAccess" binding:"required"`
 }
+
+// 延長 / 續期 provider 到期時間（expireTime、extendDays、noExpiry 三擇一）；
+// extendDays 自目前到期時間與現在兩者較晚者起算，已過期的權限會一併恢復為 active
+type RenewProviderExpiryDto struct {
+	ExpireTime *time.Time `json:"expireTime,omitempty"`
+	ExtendDays *int       `json:"extendDays,omitempty" binding:"omitempty,min=1,max=3650"`
+	NoExpiry   bool       `json:"noExpiry,omitempty"`
+}
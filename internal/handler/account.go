@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccountHandler 使用者以自己的 API Key 呼叫的 self-service 端點
+type AccountHandler struct {
+	trace             *telemetry.Trace
+	lifecycleService  *service.LifecycleService
+	keyRequestService *service.KeyRequestService
+	anomalyService    *service.AnomalyService
+}
+
+func NewAccountHandler(
+	trace *telemetry.Trace,
+	lifecycleService *service.LifecycleService,
+	keyRequestService *service.KeyRequestService,
+	anomalyService *service.AnomalyService,
+) *AccountHandler {
+	return &AccountHandler{
+		trace:             trace,
+		lifecycleService:  lifecycleService,
+		keyRequestService: keyRequestService,
+		anomalyService:    anomalyService,
+	}
+}
+
+// Reactivate 自助重新啟用
+// @Summary 恢復因閒置停用的帳號，以及這把 API Key 因閒置停用的 provider 存取（需啟用 LIFECYCLE.SELF_SERVICE_REACTIVATION）
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ReactivationResultDto
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /account/reactivate [post]
+func (h *AccountHandler) Reactivate(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	apiKeyID, err := primitive.ObjectIDFromHex(c.GetString("apiKeyID"))
+	if err != nil {
+		cause := cErr.UnauthorizedApiKey("missing api key context")
+		end(cause)
+		response.AbortWithError(c, cause)
+		return
+	}
+	result, err := h.lifecycleService.SelfReactivate(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// SubmitKeyRequest 申請新的 API Key
+// @Summary 送出 API Key 申請（provider、scope、配額與申請理由），待 editor / admin 審核
+// @Description 以登入平台簽發的 session（Authorization: Bearer <JWT>，sub 為使用者 externalID）驗證，不需已持有 API Key
+// @Tags Account
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateKeyRequestDto true "申請內容"
+// @Success 201 {object} dto.KeyRequestDto
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /account/key-requests [post]
+func (h *AccountHandler) SubmitKeyRequest(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		cause := cErr.InvalidSession("missing user context")
+		end(cause)
+		response.AbortWithError(c, cause)
+		return
+	}
+	var req dto.CreateKeyRequestDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	request, err := h.keyRequestService.Submit(ctx, userID, &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Create(c, request)
+}
+
+// ListKeyRequests 自己的 API Key 申請
+// @Summary 查詢自己送出的 API Key 申請與審核結果（新到舊）
+// @Description 以登入平台簽發的 session 驗證（同送出申請）
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.KeyRequestDto
+// @Failure 401 {object} map[string]string
+// @Router /account/key-requests [get]
+func (h *AccountHandler) ListKeyRequests(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		cause := cErr.InvalidSession("missing user context")
+		end(cause)
+		response.AbortWithError(c, cause)
+		return
+	}
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	requests, err := h.keyRequestService.ListByUser(ctx, userID, page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, requests)
+}
+
+// Reverify 異常用量後重新驗證 API Key
+// @Summary 以通知送給擁有者的驗證碼重新驗證這把 API Key，解除異常偵測要求的重新驗證（驗證碼僅能使用一次）
+// @Tags Account
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.ReverifyAPIKeyDto true "驗證碼"
+// @Success 200 {string} string "api key re-verified successfully"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /account/reverify [post]
+func (h *AccountHandler) Reverify(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	apiKeyID, err := primitive.ObjectIDFromHex(c.GetString("apiKeyID"))
+	if err != nil {
+		cause := cErr.UnauthorizedApiKey("missing api key context")
+		end(cause)
+		response.AbortWithError(c, cause)
+		return
+	}
+	var req dto.ReverifyAPIKeyDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	if err := h.anomalyService.Reverify(ctx, apiKeyID, req.Code); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "api key re-verified successfully")
+}
+
+// ResendReverifyCode 重新寄送驗證碼
+// @Summary 重新產生並寄送重新驗證用的驗證碼給 API Key 擁有者（舊驗證碼失效，每分鐘最多一次）
+// @Description 驗證碼只送到明確指定擁有者或這把 key 的通知規則（不含套用所有對象的規則、不留在投遞紀錄）；沒有這類規則時改寄到使用者信箱，
+// @Description 兩者皆無或通知未啟用時回 409，需由管理端解除重新驗證
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {string} string "verification code sent successfully"
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /account/reverify/resend [post]
+func (h *AccountHandler) ResendReverifyCode(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	apiKeyID, err := primitive.ObjectIDFromHex(c.GetString("apiKeyID"))
+	if err != nil {
+		cause := cErr.UnauthorizedApiKey("missing api key context")
+		end(cause)
+		response.AbortWithError(c, cause)
+		return
+	}
+	if err := h.anomalyService.ResendReverifyCode(ctx, apiKeyID); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "verification code sent successfully")
+}
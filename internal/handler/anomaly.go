@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminAnomalyHandler struct {
+	trace          *telemetry.Trace
+	anomalyService *service.AnomalyService
+}
+
+func NewAdminAnomalyHandler(
+	trace *telemetry.Trace,
+	anomalyService *service.AnomalyService,
+) *AdminAnomalyHandler {
+	return &AdminAnomalyHandler{trace: trace, anomalyService: anomalyService}
+}
+
+// List 異常事件查詢
+// @Summary 查詢 API Key 異常偵測事件（新到舊；訊號、處置與是否實際套用）
+// @Tags Admin-Anomaly
+// @Security BearerAuth
+// @Produce json
+// @Param apiKeyID query string false "API Key ID"
+// @Param userID query string false "使用者 ID"
+// @Param provider query string false "模型提供者"
+// @Param signal query string false "訊號（rate_spike / new_client_ip / new_user_agent / model_change / error_burst）"
+// @Param action query string false "處置（log / reverify / throttle / suspend）"
+// @Param from query string false "起始時間（含），RFC3339 或 YYYY-MM-DD"
+// @Param to query string false "結束時間（不含），RFC3339 或 YYYY-MM-DD"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.AnomalyEventDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/anomalies [get]
+func (h *AdminAnomalyHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	query := &dto.AnomalyQueryDto{
+		APIKeyID: c.Query("apiKeyID"),
+		UserID:   c.Query("userID"),
+		Provider: c.Query("provider"),
+		Signal:   c.Query("signal"),
+		Action:   c.Query("action"),
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Page:     getInt64Query(c, "page", 0),
+		Size:     getInt64Query(c, "size", 20),
+	}
+	events, err := h.anomalyService.List(ctx, query)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, events)
+}
+
+// Clear 解除異常處置
+// @Summary 解除 API Key 的異常限流與重新驗證要求（因異常停用的 provider 存取請用 provider 重新啟用端點）
+// @Tags Admin-Anomaly
+// @Security BearerAuth
+// @Produce json
+// @Param apiKeyID path string true "API Key ID"
+// @Success 200 {object} dto.AnomalyClearResultDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/anomalies/keys/{apiKeyID}/clear [post]
+func (h *AdminAnomalyHandler) Clear(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	apiKeyID, cause, respErr := validate.ParseObjectID(c, "apiKeyID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	result, err := h.anomalyService.Clear(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, result)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminAuditHandler struct {
+	trace        *telemetry.Trace
+	auditService *service.AuditService
+}
+
+func NewAdminAuditHandler(
+	trace *telemetry.Trace,
+	auditService *service.AuditService,
+) *AdminAuditHandler {
+	return &AdminAuditHandler{trace: trace, auditService: auditService}
+}
+
+// List 稽核紀錄查詢
+// @Summary 查詢稽核紀錄（新到舊；使用者、API Key、provider 存取異動與其他管理端操作）
+// @Tags Admin-Audit
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "起始時間（含），RFC3339 或 YYYY-MM-DD"
+// @Param to query string false "結束時間（不含），RFC3339 或 YYYY-MM-DD"
+// @Param actorType query string false "操作者類型（admin / user / system）"
+// @Param actor query string false "操作者 ID"
+// @Param action query string false "動作，例如 user.updated、api_key.rotated"
+// @Param targetType query string false "對象類型（user / api_key / provider_access / endpoint）"
+// @Param targetID query string false "對象 ID"
+// @Param provider query string false "模型提供者"
+// @Param requestID query string false "request ID（trace ID）"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.AuditLogDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/audit-logs [get]
+func (h *AdminAuditHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	query := &dto.AuditQueryDto{
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		ActorType:  c.Query("actorType"),
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("targetType"),
+		TargetID:   c.Query("targetID"),
+		Provider:   c.Query("provider"),
+		RequestID:  c.Query("requestID"),
+		Page:       getInt64Query(c, "page", 0),
+		Size:       getInt64Query(c, "size", 20),
+	}
+	entries, err := h.auditService.List(ctx, query)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, entries)
+}
+
+// Get 單筆稽核紀錄
+// @Summary 取得單筆稽核紀錄（含欄位差異）
+// @Tags Admin-Audit
+// @Security BearerAuth
+// @Produce json
+// @Param auditID path string true "稽核紀錄 ID"
+// @Success 200 {object} dto.AuditLogDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/audit-logs/{auditID} [get]
+func (h *AdminAuditHandler) Get(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	auditID, cause, respErr := validate.ParseObjectID(c, "auditID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	entry, err := h.auditService.GetByID(ctx, auditID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, entry)
+}
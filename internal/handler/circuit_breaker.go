@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminCircuitBreakerHandler struct {
+	trace                 *telemetry.Trace
+	circuitBreakerService *service.CircuitBreakerService
+}
+
+func NewAdminCircuitBreakerHandler(
+	trace *telemetry.Trace,
+	circuitBreakerService *service.CircuitBreakerService,
+) *AdminCircuitBreakerHandler {
+	return &AdminCircuitBreakerHandler{trace: trace, circuitBreakerService: circuitBreakerService}
+}
+
+// List 熔斷器狀態
+// @Summary 取得各 provider / endpoint 熔斷器狀態與近期錯誤率（本 replica 統計）
+// @Tags Admin-CircuitBreaker
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.CircuitBreakerStateDto
+// @Router /admin/circuit-breakers [get]
+func (h *AdminCircuitBreakerHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	response.Success(c, h.circuitBreakerService.States(ctx))
+}
+
+// Reset 重置熔斷器
+// @Summary 手動關閉熔斷器並清除統計
+// @Tags Admin-CircuitBreaker
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.ResetCircuitBreakerDto true "provider 與 endpoint"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/circuit-breakers/reset [post]
+func (h *AdminCircuitBreakerHandler) Reset(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	var req dto.ResetCircuitBreakerDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	if err := h.circuitBreakerService.Reset(ctx, req.Provider, req.Endpoint); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "circuit breaker reset successfully")
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+
+	"interchange/internal/core"
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminCreditHandler struct {
+	trace         *telemetry.Trace
+	creditService *service.CreditService
+}
+
+func NewAdminCreditHandler(
+	trace *telemetry.Trace,
+	creditService *service.CreditService,
+) *AdminCreditHandler {
+	return &AdminCreditHandler{trace: trace, creditService: creditService}
+}
+
+// GetBalance 取得預付餘額
+// @Summary 取得使用者 / 組織的預付額度餘額
+// @Tags Admin-Credit
+// @Security BearerAuth
+// @Produce json
+// @Param scope path string true "帳戶層級（user / org）"
+// @Param scopeID path string true "使用者或組織 ID"
+// @Success 200 {object} dto.CreditBalanceDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/credits/{scope}/{scopeID} [get]
+func (h *AdminCreditHandler) GetBalance(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	balance, err := h.creditService.GetBalance(ctx, core.CreditScope(c.Param("scope")), c.Param("scopeID"))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, balance)
+}
+
+// ListLedger 餘額異動紀錄
+// @Summary 查詢預付額度帳本分錄（新到舊）
+// @Tags Admin-Credit
+// @Security BearerAuth
+// @Produce json
+// @Param scope path string true "帳戶層級（user / org）"
+// @Param scopeID path string true "使用者或組織 ID"
+// @Param type query string false "分錄類型（top_up / adjust / refund / debit / reversal）"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.CreditLedgerEntryDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/credits/{scope}/{scopeID}/ledger [get]
+func (h *AdminCreditHandler) ListLedger(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	entries, err := h.creditService.ListLedger(ctx, core.CreditScope(c.Param("scope")), c.Param("scopeID"), core.CreditEntryType(c.Query("type")), page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, entries)
+}
+
+// TopUp 儲值
+// @Summary 預付額度儲值
+// @Tags Admin-Credit
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param scope path string true "帳戶層級（user / org）"
+// @Param scopeID path string true "使用者或組織 ID"
+// @Param body body dto.CreditOperationDto true "儲值金額（正數）"
+// @Success 200 {object} dto.CreditBalanceDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/credits/{scope}/{scopeID}/top-up [post]
+func (h *AdminCreditHandler) TopUp(c *gin.Context) {
+	h.operate(c, h.creditService.TopUp)
+}
+
+// Adjust 人工調整
+// @Summary 預付額度人工調整（正數加值、負數扣減）
+// @Tags Admin-Credit
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param scope path string true "帳戶層級（user / org）"
+// @Param scopeID path string true "使用者或組織 ID"
+// @Param body body dto.CreditOperationDto true "調整金額"
+// @Success 200 {object} dto.CreditBalanceDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/credits/{scope}/{scopeID}/adjust [post]
+func (h *AdminCreditHandler) Adjust(c *gin.Context) {
+	h.operate(c, h.creditService.Adjust)
+}
+
+// Refund 退款
+// @Summary 預付額度退款
+// @Tags Admin-Credit
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param scope path string true "帳戶層級（user / org）"
+// @Param scopeID path string true "使用者或組織 ID"
+// @Param body body dto.CreditOperationDto true "退款金額（正數）"
+// @Success 200 {object} dto.CreditBalanceDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/credits/{scope}/{scopeID}/refund [post]
+func (h *AdminCreditHandler) Refund(c *gin.Context) {
+	h.operate(c, h.creditService.Refund)
+}
+
+func (h *AdminCreditHandler) operate(
+	c *gin.Context,
+	operateFn func(ctx context.Context, scope core.CreditScope, scopeID string, req *dto.CreditOperationDto) (*dto.CreditBalanceDto, error),
+) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	var req dto.CreditOperationDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	balance, err := operateFn(ctx, core.CreditScope(c.Param("scope")), c.Param("scopeID"), &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, balance)
+}
@@ -10,6 +10,22 @@ import (
 var ProviderSet = wire.NewSet(
 	NewAdminUserHandler,
 	NewAdminUserAPIKeyHandler,
+	NewAdminUserQuotaHandler,
+	NewAdminOrganizationHandler,
+	NewAdminCircuitBreakerHandler,
+	NewAdminPromptTemplateHandler,
+	NewAdminSemanticCacheHandler,
+	NewAdminUsageHandler,
+	NewAdminCreditHandler,
+	NewAdminInvoiceHandler,
+	NewAdminNotificationHandler,
+	NewAdminJobHandler,
+	NewAdminLifecycleHandler,
+	NewAccountHandler,
+	NewAdminAuditHandler,
+	NewAdminKeyRequestHandler,
+	NewAdminMaintenanceHandler,
+	NewAdminAnomalyHandler,
 	proxy.NewChatHandler,
 	proxy.NewAudioHandler,
 	proxy.NewImageHandler,
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+
+	"interchange/internal/core"
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type AdminInvoiceHandler struct {
+	trace          *telemetry.Trace
+	invoiceService *service.InvoiceService
+}
+
+func NewAdminInvoiceHandler(
+	trace *telemetry.Trace,
+	invoiceService *service.InvoiceService,
+) *AdminInvoiceHandler {
+	return &AdminInvoiceHandler{trace: trace, invoiceService: invoiceService}
+}
+
+// Generate 產生月結帳單
+// @Summary 產生專案 / 使用者月結帳單（已存在時回傳既有帳單；未啟用用量紀錄時拒絕，指定對象無用量時不建立）
+// @Tags Admin-Invoice
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.GenerateInvoiceDto true "帳單對象與帳期"
+// @Success 201 {array} dto.InvoiceDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/invoices [post]
+func (h *AdminInvoiceHandler) Generate(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	var req dto.GenerateInvoiceDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	invoices, err := h.invoiceService.Generate(ctx, &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Create(c, invoices)
+}
+
+// List 帳單列表
+// @Summary 查詢月結帳單（不含明細）
+// @Tags Admin-Invoice
+// @Security BearerAuth
+// @Produce json
+// @Param scope query string false "帳單對象（project / user）"
+// @Param scopeID query string false "專案名稱或使用者 ID"
+// @Param period query string false "帳期（YYYY-MM）"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.InvoiceDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/invoices [get]
+func (h *AdminInvoiceHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	filter := bson.M{}
+	for _, key := range []string{"scope", "scopeID", "period"} {
+		if value := c.Query(key); value != "" {
+			filter[key] = value
+		}
+	}
+
+	invoices, err := h.invoiceService.ListInvoices(ctx, filter, page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, invoices)
+}
+
+// Get 取得帳單
+// @Summary 取得月結帳單（json / csv / 可列印 html）
+// @Tags Admin-Invoice
+// @Security BearerAuth
+// @Produce json,text/csv,text/html
+// @Param invoiceID path string true "Invoice ID"
+// @Param format query string false "輸出格式：json（預設）、csv、html"
+// @Success 200 {object} dto.InvoiceDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/invoices/{invoiceID} [get]
+func (h *AdminInvoiceHandler) Get(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	invoiceID, cause, respErr := validate.ParseObjectID(c, "invoiceID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	invoice, err := h.invoiceService.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+
+	format := core.InvoiceFormat(c.DefaultQuery("format", string(core.InvoiceFormatJSON)))
+	if format == core.InvoiceFormatJSON {
+		response.Success(c, invoice)
+		return
+	}
+	body, contentType, err := h.invoiceService.Render(invoice, format)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	if format == core.InvoiceFormatCSV {
+		c.Header("Content-Disposition", `attachment; filename="`+invoice.Number+`.csv"`)
+	}
+	c.Data(http.StatusOK, contentType, body)
+	c.Abort()
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"interchange/internal/core"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminJobHandler struct {
+	trace      *telemetry.Trace
+	jobService *service.JobService
+}
+
+func NewAdminJobHandler(
+	trace *telemetry.Trace,
+	jobService *service.JobService,
+) *AdminJobHandler {
+	return &AdminJobHandler{trace: trace, jobService: jobService}
+}
+
+// ListJobs 排程工作列表
+// @Summary 取得排程工作列表（含暫停狀態、是否執行中與最後一次結果）
+// @Tags Admin-Job
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.JobDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs [get]
+func (h *AdminJobHandler) ListJobs(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	jobs, err := h.jobService.ListJobs(ctx)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, jobs)
+}
+
+// PauseJob 暫停排程
+// @Summary 暫停工作的排程（執行中的不受影響，仍可手動觸發）
+// @Tags Admin-Job
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "工作名稱"
+// @Success 200 {object} dto.JobDto
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs/{name}/pause [post]
+func (h *AdminJobHandler) PauseJob(c *gin.Context) {
+	h.setPaused(c, true)
+}
+
+// ResumeJob 恢復排程
+// @Summary 恢復工作的排程
+// @Tags Admin-Job
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "工作名稱"
+// @Success 200 {object} dto.JobDto
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs/{name}/resume [post]
+func (h *AdminJobHandler) ResumeJob(c *gin.Context) {
+	h.setPaused(c, false)
+}
+
+func (h *AdminJobHandler) setPaused(c *gin.Context, paused bool) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	job, err := h.jobService.SetPaused(ctx, c.Param("name"), paused, c.GetString("userID"))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, job)
+}
+
+// TriggerJob 手動觸發
+// @Summary 立即執行工作（背景執行，回傳執行紀錄；其他 replica 執行中時回傳 409）
+// @Tags Admin-Job
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "工作名稱"
+// @Success 200 {object} dto.JobRunDto
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/jobs/{name}/trigger [post]
+func (h *AdminJobHandler) TriggerJob(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	run, err := h.jobService.Trigger(ctx, c.Param("name"), c.GetString("userID"))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, run)
+}
+
+// ListRuns 執行紀錄
+// @Summary 查詢排程工作的執行紀錄（新到舊）
+// @Tags Admin-Job
+// @Security BearerAuth
+// @Produce json
+// @Param job query string false "工作名稱"
+// @Param status query string false "執行結果（running / succeeded / failed）"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.JobRunDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs/runs [get]
+func (h *AdminJobHandler) ListRuns(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	runs, err := h.jobService.ListRuns(ctx, c.Query("job"), core.JobRunStatus(c.Query("status")), page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, runs)
+}
+
+// GetRun 單筆執行紀錄
+// @Summary 取得單筆執行紀錄（手動觸發後可用來查詢結果）
+// @Tags Admin-Job
+// @Security BearerAuth
+// @Produce json
+// @Param runID path string true "執行紀錄 ID"
+// @Success 200 {object} dto.JobRunDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/jobs/runs/{runID} [get]
+func (h *AdminJobHandler) GetRun(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	runID, cause, respErr := validate.ParseObjectID(c, "runID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	run, err := h.jobService.GetRun(ctx, runID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, run)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AdminKeyRequestHandler struct {
+	trace             *telemetry.Trace
+	keyRequestService *service.KeyRequestService
+}
+
+func NewAdminKeyRequestHandler(
+	trace *telemetry.Trace,
+	keyRequestService *service.KeyRequestService,
+) *AdminKeyRequestHandler {
+	return &AdminKeyRequestHandler{trace: trace, keyRequestService: keyRequestService}
+}
+
+// List API Key 申請列表
+// @Summary 查詢 API Key 申請（新到舊；預設列出全部狀態）
+// @Tags Admin-KeyRequest
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "狀態（pending / approved / rejected）"
+// @Param userID query string false "申請者 ID"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.KeyRequestDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/key-requests [get]
+func (h *AdminKeyRequestHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if userID := c.Query("userID"); userID != "" {
+		oid, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			cause := cErr.BadRequestParams("invalid userID")
+			end(cause)
+			response.AbortWithError(c, cause)
+			return
+		}
+		filter["userID"] = oid
+	}
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	requests, err := h.keyRequestService.List(ctx, filter, page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, requests)
+}
+
+// Get 單筆 API Key 申請
+// @Summary 取得單筆 API Key 申請（含審核結果）
+// @Tags Admin-KeyRequest
+// @Security BearerAuth
+// @Produce json
+// @Param requestID path string true "申請 ID"
+// @Success 200 {object} dto.KeyRequestDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/key-requests/{requestID} [get]
+func (h *AdminKeyRequestHandler) Get(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	requestID, cause, respErr := validate.ParseObjectID(c, "requestID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	request, err := h.keyRequestService.GetByID(ctx, requestID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, request)
+}
+
+// Approve 核准 API Key 申請
+// @Summary 核准申請並核發 API Key（可修改名稱與 provider 權限；限 editor / admin，不可審核自己的申請）
+// @Description 每個 provider 都需要上游金鑰（providerKeys 或 providerAccess[].providerKey），缺少時回 400 且申請維持待審核
+// @Tags Admin-KeyRequest
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param requestID path string true "申請 ID"
+// @Param body body dto.ApproveKeyRequestDto true "核准內容（未帶 providerAccess 時依申請內容核發）"
+// @Success 200 {object} dto.KeyRequestDto
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/key-requests/{requestID}/approve [post]
+func (h *AdminKeyRequestHandler) Approve(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	requestID, cause, respErr := validate.ParseObjectID(c, "requestID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	var req dto.ApproveKeyRequestDto
+	if c.Request.ContentLength != 0 {
+		if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+			end(cause)
+			response.AbortWithError(c, respErr)
+			return
+		}
+	}
+	request, err := h.keyRequestService.Approve(ctx, requestID, c.GetString("userID"), &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, request)
+}
+
+// Reject 駁回 API Key 申請
+// @Summary 駁回申請（需填寫原因；限 editor / admin，不可審核自己的申請）
+// @Tags Admin-KeyRequest
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param requestID path string true "申請 ID"
+// @Param body body dto.RejectKeyRequestDto true "駁回原因"
+// @Success 200 {object} dto.KeyRequestDto
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/key-requests/{requestID}/reject [post]
+func (h *AdminKeyRequestHandler) Reject(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	requestID, cause, respErr := validate.ParseObjectID(c, "requestID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	var req dto.RejectKeyRequestDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	request, err := h.keyRequestService.Reject(ctx, requestID, c.GetString("userID"), &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, request)
+}
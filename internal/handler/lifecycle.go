@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"strconv"
+
+	"interchange/internal/core"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminLifecycleHandler struct {
+	trace            *telemetry.Trace
+	userService      *service.UserService
+	lifecycleService *service.LifecycleService
+}
+
+func NewAdminLifecycleHandler(
+	trace *telemetry.Trace,
+	userService *service.UserService,
+	lifecycleService *service.LifecycleService,
+) *AdminLifecycleHandler {
+	return &AdminLifecycleHandler{trace: trace, userService: userService, lifecycleService: lifecycleService}
+}
+
+// ListInactive 閒置用戶列表
+// @Summary 取得超過指定天數未使用的用戶
+// @Tags Admin-User
+// @Security BearerAuth
+// @Produce json
+// @Param days query int true "未使用天數"
+// @Success 200 {array} dto.UserResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/users/inactive [get]
+func (h *AdminLifecycleHandler) ListInactive(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	days, err := strconv.Atoi(c.Query("days"))
+	if err != nil || days <= 0 {
+		cause := cErr.BadRequestParams("days must be a positive integer")
+		end(cause)
+		response.AbortWithError(c, cause)
+		return
+	}
+	users, err := h.userService.ListInactiveUsers(ctx, days)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, users)
+}
+
+// ReactivateUser 重新啟用用戶
+// @Summary 重新啟用 suspended 用戶（重新起算閒置天數，並移除其 API Key 的停用標記）
+// @Tags Admin-User
+// @Security BearerAuth
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} dto.UserResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/users/{userID}/reactivate [post]
+func (h *AdminLifecycleHandler) ReactivateUser(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	user, err := h.lifecycleService.ReactivateUser(ctx, userID, adminActor(c))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, user)
+}
+
+// ReactivateProvider 重新啟用 provider 存取
+// @Summary 將 suspended 的 provider 存取恢復為 active（重新起算閒置天數）
+// @Tags Admin-APIKey
+// @Security BearerAuth
+// @Produce json
+// @Param userID path string true "User ID"
+// @Param apiKeyID path string true "API Key ID"
+// @Param provider path string true "Provider"
+// @Success 200 {object} dto.UserAPIKeyResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/users/{userID}/api-keys/{apiKeyID}/providers/{provider}/reactivate [post]
+func (h *AdminLifecycleHandler) ReactivateProvider(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	apiKeyID, cause, respErr := validate.ParseObjectID(c, "apiKeyID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	key, err := h.lifecycleService.ReactivateProviderAccess(ctx, userID, apiKeyID, core.ProviderName(c.Param("provider")), adminActor(c))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, key)
+}
+
+// adminActor 管理端操作者
+func adminActor(c *gin.Context) service.AuditActor {
+	return service.AuditActor{Type: core.AuditActorAdmin, ID: c.GetString("userID")}
+}
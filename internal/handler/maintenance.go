@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"interchange/internal/core"
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminMaintenanceHandler struct {
+	trace              *telemetry.Trace
+	maintenanceService *service.MaintenanceService
+}
+
+func NewAdminMaintenanceHandler(
+	trace *telemetry.Trace,
+	maintenanceService *service.MaintenanceService,
+) *AdminMaintenanceHandler {
+	return &AdminMaintenanceHandler{trace: trace, maintenanceService: maintenanceService}
+}
+
+// List 維護模式開關
+// @Summary 取得所有維護模式開關（global / provider / endpoint，含排程中與未啟用）
+// @Tags Admin-Maintenance
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.MaintenanceDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/maintenance [get]
+func (h *AdminMaintenanceHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	switches, err := h.maintenanceService.List(ctx)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, switches)
+}
+
+// Upsert 設定維護模式
+// @Summary 新增或覆寫維護模式開關；可設定生效時間窗、訊息、Retry-After 與可略過的 API Key，所有 replica 即時生效
+// @Tags Admin-Maintenance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.UpsertMaintenanceDto true "維護模式設定"
+// @Success 200 {object} dto.MaintenanceDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/maintenance [put]
+func (h *AdminMaintenanceHandler) Upsert(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	var req dto.UpsertMaintenanceDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	sw, err := h.maintenanceService.Upsert(ctx, c.GetString("userID"), &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, sw)
+}
+
+// Delete 移除維護模式
+// @Summary 移除維護模式開關
+// @Tags Admin-Maintenance
+// @Security BearerAuth
+// @Produce json
+// @Param scope query string true "範圍（global / provider / endpoint）"
+// @Param provider query string false "模型提供者（provider 範圍必填）"
+// @Param endpoint query string false "端點，例如 /chat/completions、/images/*（endpoint 範圍必填）"
+// @Success 200 {string} string "maintenance switch removed successfully"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/maintenance [delete]
+func (h *AdminMaintenanceHandler) Delete(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	target := &dto.MaintenanceTargetDto{
+		Scope:    core.MaintenanceScope(c.Query("scope")),
+		Provider: core.ProviderName(c.Query("provider")),
+		Endpoint: c.Query("endpoint"),
+	}
+	if err := h.maintenanceService.Delete(ctx, c.GetString("userID"), target); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "maintenance switch removed successfully")
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"interchange/internal/core"
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminNotificationHandler struct {
+	trace               *telemetry.Trace
+	notificationService *service.NotificationService
+}
+
+func NewAdminNotificationHandler(
+	trace *telemetry.Trace,
+	notificationService *service.NotificationService,
+) *AdminNotificationHandler {
+	return &AdminNotificationHandler{trace: trace, notificationService: notificationService}
+}
+
+// ListRules 通知規則列表
+// @Summary 取得通知規則列表
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Produce json
+// @Param scope query string false "規則層級（key / user / org / global）"
+// @Param scopeID query string false "API Key / 使用者 / 組織 ID"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.NotificationRuleDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/notifications/rules [get]
+func (h *AdminNotificationHandler) ListRules(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	rules, err := h.notificationService.ListRules(ctx, core.NotificationScope(c.Query("scope")), c.Query("scopeID"), page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, rules)
+}
+
+// CreateRule 新增通知規則
+// @Summary 新增通知規則（未帶 webhookSecret 時自動產生，只在此回應中出現一次）
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateNotificationRuleDto true "規則內容"
+// @Success 201 {object} dto.NotificationRuleDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/notifications/rules [post]
+func (h *AdminNotificationHandler) CreateRule(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	var req dto.CreateNotificationRuleDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	created, err := h.notificationService.CreateRule(ctx, &req, c.GetString("userID"))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Create(c, created)
+}
+
+// GetRule 取得通知規則
+// @Summary 取得通知規則
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Produce json
+// @Param ruleID path string true "規則 ID"
+// @Success 200 {object} dto.NotificationRuleDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/notifications/rules/{ruleID} [get]
+func (h *AdminNotificationHandler) GetRule(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	ruleID, cause, respErr := validate.ParseObjectID(c, "ruleID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	rule, err := h.notificationService.GetRule(ctx, ruleID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, rule)
+}
+
+// UpdateRule 更新通知規則
+// @Summary 更新通知規則（只更新有帶的欄位；webhookURL 帶空字串表示移除）
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param ruleID path string true "規則 ID"
+// @Param body body dto.UpdateNotificationRuleDto true "更新內容"
+// @Success 200 {object} dto.NotificationRuleDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/notifications/rules/{ruleID} [put]
+func (h *AdminNotificationHandler) UpdateRule(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	ruleID, cause, respErr := validate.ParseObjectID(c, "ruleID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	var req dto.UpdateNotificationRuleDto
+	if cause, respErr = validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	rule, err := h.notificationService.UpdateRule(ctx, ruleID, &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, rule)
+}
+
+// DeleteRule 刪除通知規則
+// @Summary 刪除通知規則（投遞紀錄保留）
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Produce json
+// @Param ruleID path string true "規則 ID"
+// @Success 200 {string} string "notification rule deleted successfully"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/notifications/rules/{ruleID} [delete]
+func (h *AdminNotificationHandler) DeleteRule(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	ruleID, cause, respErr := validate.ParseObjectID(c, "ruleID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	if err := h.notificationService.DeleteRule(ctx, ruleID); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "notification rule deleted successfully")
+}
+
+// TestRule 送出測試通知
+// @Summary 對規則的所有管道送出測試事件（notification.test）
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Produce json
+// @Param ruleID path string true "規則 ID"
+// @Success 200 {array} dto.NotificationDeliveryDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/notifications/rules/{ruleID}/test [post]
+func (h *AdminNotificationHandler) TestRule(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	ruleID, cause, respErr := validate.ParseObjectID(c, "ruleID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	deliveries, err := h.notificationService.TestRule(ctx, ruleID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, deliveries)
+}
+
+// ListDeliveries 投遞紀錄
+// @Summary 查詢通知投遞紀錄（新到舊；status=dead 為 dead-letter）
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "投遞狀態（pending / delivered / dead）"
+// @Param ruleID query string false "規則 ID"
+// @Param event query string false "事件類型"
+// @Param page query int false "頁碼（0 起算）"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.NotificationDeliveryDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/notifications/deliveries [get]
+func (h *AdminNotificationHandler) ListDeliveries(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	deliveries, err := h.notificationService.ListDeliveries(
+		ctx,
+		core.NotificationDeliveryStatus(c.Query("status")),
+		c.Query("ruleID"),
+		core.NotificationEvent(c.Query("event")),
+		page,
+		size,
+	)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, deliveries)
+}
+
+// RetryDelivery 重送 dead-letter
+// @Summary 將 dead 狀態的投遞重新排入佇列（嘗試次數歸零）
+// @Tags Admin-Notification
+// @Security BearerAuth
+// @Produce json
+// @Param deliveryID path string true "投遞紀錄 ID"
+// @Success 200 {object} dto.NotificationDeliveryDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/notifications/deliveries/{deliveryID}/retry [post]
+func (h *AdminNotificationHandler) RetryDelivery(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	deliveryID, cause, respErr := validate.ParseObjectID(c, "deliveryID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	delivery, err := h.notificationService.RetryDelivery(ctx, deliveryID)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, delivery)
+}
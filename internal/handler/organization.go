@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminOrganizationHandler struct {
+	trace               *telemetry.Trace
+	organizationService *service.OrganizationService
+	quotaService        *service.QuotaService
+}
+
+func NewAdminOrganizationHandler(
+	trace *telemetry.Trace,
+	organizationService *service.OrganizationService,
+	quotaService *service.QuotaService,
+) *AdminOrganizationHandler {
+	return &AdminOrganizationHandler{trace: trace, organizationService: organizationService, quotaService: quotaService}
+}
+
+// List 組織列表
+// @Summary 取得組織列表
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "頁碼"
+// @Param size query int false "每頁筆數"
+// @Param status query string false "狀態"
+// @Success 200 {array} dto.OrganizationResponseDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/organizations [get]
+func (h *AdminOrganizationHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+	filter := map[string]any{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+
+	organizations, err := h.organizationService.ListOrganizations(ctx, filter, page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, organizations)
+}
+
+// Get 取得組織
+// @Summary 取得單一組織資訊
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {object} dto.OrganizationResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{orgID} [get]
+func (h *AdminOrganizationHandler) Get(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	orgID, cause, respErr := validate.ParseObjectID(c, "orgID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	organization, err := h.organizationService.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, organization)
+}
+
+// Create 新增組織
+// @Summary 新增組織
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateOrganizationDto true "組織資訊"
+// @Success 201 {object} dto.OrganizationResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/organizations [post]
+func (h *AdminOrganizationHandler) Create(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	var req dto.CreateOrganizationDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	created, err := h.organizationService.CreateOrganization(ctx, &req)
+	if err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Create(c, created)
+}
+
+// Update 更新組織
+// @Summary 更新組織
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param body body dto.UpdateOrganizationDto true "組織更新資訊"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{orgID} [put]
+func (h *AdminOrganizationHandler) Update(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	orgID, cause, respErr := validate.ParseObjectID(c, "orgID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	var req dto.UpdateOrganizationDto
+	if cause, respErr = validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.organizationService.UpdateOrganizationByID(ctx, orgID, &req); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "organization updated successfully")
+}
+
+// Delete 刪除組織
+// @Summary 刪除組織
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/organizations/{orgID} [delete]
+func (h *AdminOrganizationHandler) Delete(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	orgID, cause, respErr := validate.ParseObjectID(c, "orgID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.organizationService.DeleteOrganization(ctx, orgID); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "organization deleted successfully")
+}
+
+// GetQuota 取得組織配額
+// @Summary 取得組織配額政策與目前用量
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {object} dto.QuotaStatusDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{orgID}/quota [get]
+func (h *AdminOrganizationHandler) GetQuota(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	orgID, cause, respErr := validate.ParseObjectID(c, "orgID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	status, err := h.quotaService.GetOrganizationQuota(ctx, orgID)
+	if err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, status)
+}
+
+// UpdateQuota 設定組織配額
+// @Summary 設定組織配額政策
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param body body dto.QuotaPolicyDto true "配額政策"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{orgID}/quota [put]
+func (h *AdminOrganizationHandler) UpdateQuota(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	orgID, cause, respErr := validate.ParseObjectID(c, "orgID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	var req dto.QuotaPolicyDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.quotaService.UpdateOrganizationQuota(ctx, orgID, &req); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "organization quota updated successfully")
+}
+
+// DeleteQuota 移除組織配額
+// @Summary 移除組織配額政策
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{orgID}/quota [delete]
+func (h *AdminOrganizationHandler) DeleteQuota(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	orgID, cause, respErr := validate.ParseObjectID(c, "orgID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.quotaService.UpdateOrganizationQuota(ctx, orgID, nil); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "organization quota removed successfully")
+}
+
+// ResetQuotaUsage 重置組織配額用量
+// @Summary 重置組織目前週期的配額用量
+// @Tags Admin-Organization
+// @Security BearerAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{orgID}/quota/reset [post]
+func (h *AdminOrganizationHandler) ResetQuotaUsage(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	orgID, cause, respErr := validate.ParseObjectID(c, "orgID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.quotaService.ResetOrganizationQuotaUsage(ctx, orgID); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "organization quota usage reset successfully")
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"strconv"
+
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminPromptTemplateHandler struct {
+	trace                 *telemetry.Trace
+	promptTemplateService *service.PromptTemplateService
+}
+
+func NewAdminPromptTemplateHandler(
+	trace *telemetry.Trace,
+	promptTemplateService *service.PromptTemplateService,
+) *AdminPromptTemplateHandler {
+	return &AdminPromptTemplateHandler{trace: trace, promptTemplateService: promptTemplateService}
+}
+
+// List 樣板列表（各樣板最新版本）
+// @Summary 取得提示詞樣板列表（各樣板最新版本）
+// @Tags Admin-PromptTemplate
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "頁碼"
+// @Param size query int false "每頁筆數"
+// @Success 200 {array} dto.PromptTemplateResponseDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/prompt-templates [get]
+func (h *AdminPromptTemplateHandler) List(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	page := getInt64Query(c, "page", 0)
+	size := getInt64Query(c, "size", 20)
+
+	templates, err := h.promptTemplateService.ListTemplates(ctx, map[string]any{}, page, size)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, templates)
+}
+
+// ListVersions 樣板版本歷史
+// @Summary 取得提示詞樣板所有版本（新到舊）
+// @Tags Admin-PromptTemplate
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "樣板名稱"
+// @Success 200 {array} dto.PromptTemplateResponseDto
+// @Failure 404 {object} map[string]string
+// @Router /admin/prompt-templates/{name} [get]
+func (h *AdminPromptTemplateHandler) ListVersions(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	templates, err := h.promptTemplateService.ListVersions(ctx, c.Param("name"))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, templates)
+}
+
+// GetVersion 取得指定版本
+// @Summary 取得提示詞樣板指定版本
+// @Tags Admin-PromptTemplate
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "樣板名稱"
+// @Param version path int true "版本"
+// @Success 200 {object} dto.PromptTemplateResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/prompt-templates/{name}/versions/{version} [get]
+func (h *AdminPromptTemplateHandler) GetVersion(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		cause := cErr.BadRequestParams("invalid version")
+		end(cause)
+		response.AbortWithError(c, cause)
+		return
+	}
+
+	template, err := h.promptTemplateService.GetVersion(ctx, c.Param("name"), version)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, template)
+}
+
+// Create 新增樣板版本
+// @Summary 新增提示詞樣板（同名樣板自動建立新版本）
+// @Tags Admin-PromptTemplate
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.CreatePromptTemplateDto true "樣板內容"
+// @Success 201 {object} dto.PromptTemplateResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/prompt-templates [post]
+func (h *AdminPromptTemplateHandler) Create(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	var req dto.CreatePromptTemplateDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	created, err := h.promptTemplateService.CreateVersion(ctx, &req, c.GetString("userID"))
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Create(c, created)
+}
+
+// Delete 刪除樣板
+// @Summary 刪除提示詞樣板（所有版本）
+// @Tags Admin-PromptTemplate
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "樣板名稱"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/prompt-templates/{name} [delete]
+func (h *AdminPromptTemplateHandler) Delete(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	if err := h.promptTemplateService.DeleteTemplate(ctx, c.Param("name")); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "prompt template deleted successfully")
+}
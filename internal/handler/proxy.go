@@ -14,10 +14,10 @@ import (
 	"interchange/config"
 	"interchange/internal/core"
 	fluentdModel "interchange/internal/database/fluentd/model"
-	fluentd "interchange/internal/database/fluentd/repository"
 	"interchange/internal/database/mongodb/model"
 	"interchange/internal/database/redis/repository"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/pkg/response"
 	"interchange/internal/service"
 	"interchange/internal/telemetry"
@@ -97,7 +97,8 @@ type ProxyHandler struct {
 	logger                *zap.Logger
 	config                *config.Configuration
 	rateLimiterRepository *repository.RateLimiterRepository
-	logRepository         *fluentd.LogRepository
+	usageService          *service.UsageService
+	quotaService          *service.QuotaService
 }
 
 func NewProxyHandler(
@@ -107,7 +108,8 @@ func NewProxyHandler(
 	logger *zap.Logger,
 	config *config.Configuration,
 	rateLimiterRepository *repository.RateLimiterRepository,
-	logRepository *fluentd.LogRepository,
+	usageService *service.UsageService,
+	quotaService *service.QuotaService,
 ) *ProxyHandler {
 	return &ProxyHandler{
 		trace:                 trace,
@@ -116,7 +118,8 @@ func NewProxyHandler(
 		logger:                logger,
 		config:                config,
 		rateLimiterRepository: rateLimiterRepository,
-		logRepository:         logRepository,
+		usageService:          usageService,
+		quotaService:          quotaService,
 	}
 }
 
@@ -201,11 +204,40 @@ func (h *ProxyHandler) Passthrough(c *gin.Context) {
 		fail(cErr.InternalServer("invalid provider access data"))
 		return
 	}
-	base, ok := providerBase(provider)
+	base, ok := h.providerBase(provider)
 	if !ok {
 		fail(cErr.Forbidden("provider not supported: " + string(provider)))
 		return
 	}
+	if !outbound.SupportsVersion(h.config, provider, version) {
+		fail(cErr.Forbidden("api version not supported: " + version))
+		return
+	}
+
+	// ---- 模型限制（路徑中的 models/{model} 與 JSON body 的 model；別名改寫後轉發）----
+	action, modelErr := service.ResolvePathModel(providerAccess, action)
+	if modelErr != nil {
+		fail(modelErr)
+		return
+	}
+	var requestBody io.Reader = c.Request.Body
+	if providerAccess.Models != nil && isJSONBody(c.Request) {
+		raw, readErr := io.ReadAll(io.LimitReader(c.Request.Body, core.MaxModelInspectBytes+1))
+		if readErr != nil {
+			fail(cErr.BadRequestBody("read request body failed"))
+			return
+		}
+		if len(raw) > core.MaxModelInspectBytes {
+			fail(cErr.BadRequestBody("request body too large"))
+			return
+		}
+		rewritten, _, modelErr := service.ResolveBodyModel(providerAccess, raw)
+		if modelErr != nil {
+			fail(modelErr)
+			return
+		}
+		requestBody = bytes.NewReader(rewritten)
+	}
 
 	c.Set("passthrough_raw", true)
 	c.Writer.Header().Set("X-Proxy-Passthrough", "true")
@@ -222,7 +254,7 @@ func (h *ProxyHandler) Passthrough(c *gin.Context) {
 		Version:     version,
 		RawQuery:    c.Request.URL.RawQuery,
 		Header:      c.Request.Header,
-		Body:        c.Request.Body,
+		Body:        requestBody,
 	})
 	if fwdErr != nil {
 		fail(fwdErr)
@@ -275,9 +307,13 @@ func (h *ProxyHandler) Passthrough(c *gin.Context) {
 	if result.Usage != nil { // 若你的型別是大寫 Usage，請改成 result.Usage
 		usage = *result.Usage
 	}
-	_ = h.logRepository.LogUsage(ctx, fluentdModel.AIUsageLog{
+	_ = h.usageService.Record(ctx, fluentdModel.AIUsageLog{
 		RequestID:        fmt.Sprintf("%x", traceID[:]),
+		GatewayRequestID: c.GetString("gatewayRequestID"),
+		IdempotencyKey:   c.GetString("idempotencyKey"),
 		ExternalID:       userID,
+		UserID:           userID,
+		APIKeyID:         apiKeyID,
 		DisplayName:      displayName,
 		ProjectName:      "mcp-server",
 		Provider:         string(provider),
@@ -294,6 +330,11 @@ func (h *ProxyHandler) Passthrough(c *gin.Context) {
 		Version:          h.config.App.Version,
 		LoggedAt:         time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC"),
 	})
+	// user / org 彙總配額結算預留（失敗不影響主流程）
+	if err := h.quotaService.Settle(ctx, userID, result.Model,
+		usage.PromptTokens+usage.InputTokens, usage.CompletionTokens+usage.OutputTokens); err != nil {
+		h.logger.Warn("settle quota failed", zap.Error(err))
+	}
 
 	// 一律把下游的原始 body 寫回（避免某些分支沒寫回）
 	if _, werr := c.Writer.Write(body); werr != nil {
@@ -556,6 +597,11 @@ func isStream(r *http.Request) bool {
 	return stringsContainsFold(r.URL.Query().Get("stream"), "true") // ?stream=true
 }
 
+// isJSONBody 是否為帶 JSON body 的請求
+func isJSONBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && stringsContainsFold(r.Header.Get("Content-Type"), "application/json")
+}
+
 func stringsContainsFold(s, sub string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
 }
@@ -619,14 +665,10 @@ func safeTruncateRunes(s string, n int) string {
 	return s
 }
 
-// providerBase 轉成小函式，集中管理
-func providerBase(p core.ProviderName) (string, bool) {
-	switch p {
-	case core.ProviderOpenAI:
-		return string(core.OpenAIAPIBaseURL), true
-	case core.ProviderGemini:
-		return string(core.GeminiAPIBaseURL), true
-	default:
+// providerBase 由 PROVIDERS 設定取得 Base URL（須啟用 passthrough 功能）
+func (h *ProxyHandler) providerBase(p core.ProviderName) (string, bool) {
+	if !outbound.Supports(h.config, p, core.ProviderCapabilityPassthrough) {
 		return "", false
 	}
+	return outbound.BaseURL(h.config, p)
 }
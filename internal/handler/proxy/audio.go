@@ -9,7 +9,6 @@ import (
 	"interchange/config"
 	"interchange/internal/core"
 	fluentdModel "interchange/internal/database/fluentd/model"
-	"interchange/internal/database/fluentd/repository"
 	"interchange/internal/database/mongodb/model"
 	cErr "interchange/internal/pkg/error"
 	"interchange/internal/pkg/response"
@@ -28,7 +27,8 @@ type AudioHandler struct {
 	logger            *zap.Logger
 	config            *config.Configuration
 	userAPIKeyService *service.UserAPIKeyService
-	logRepository     *repository.LogRepository
+	usageService      *service.UsageService
+	quotaService      *service.QuotaService
 }
 
 func NewAudioHandler(
@@ -37,7 +37,8 @@ func NewAudioHandler(
 	logger *zap.Logger,
 	config *config.Configuration,
 	userAPIKeyService *service.UserAPIKeyService,
-	logRepository *repository.LogRepository,
+	usageService *service.UsageService,
+	quotaService *service.QuotaService,
 ) *AudioHandler {
 	return &AudioHandler{
 		trace:             trace,
@@ -45,7 +46,8 @@ func NewAudioHandler(
 		logger:            logger,
 		config:            config,
 		userAPIKeyService: userAPIKeyService,
-		logRepository:     logRepository,
+		usageService:      usageService,
+		quotaService:      quotaService,
 	}
 }
 
@@ -97,7 +99,7 @@ func (handler *AudioHandler) AudioSpeech(c *gin.Context) {
 		return
 	}
 
-	service, ok := handler.registry.GetAudio(provider)
+	audioService, ok := handler.registry.GetAudio(provider)
 	if !ok {
 		cause = cErr.NotFound("audio provider not supported: " + string(provider))
 		end(cause)
@@ -126,11 +128,28 @@ func (handler *AudioHandler) AudioSpeech(c *gin.Context) {
 		return
 	}
 
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(providerAccess, payload.Model)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = resolvedModel
+
+	// 請求參數防護：移除 / 強制 / 允許值 / 數值範圍
+	policyVars := service.PolicyVars{UserID: c.GetString("userID"), ExternalID: c.Query("userID")}
+	if err := service.ApplyRequestPolicy(providerAccess, &payload, policyVars); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+
 	switch version {
 	case "v1":
-		audioData, err := service.AudioSpeechV1(ctx, &payload, providerAccess.ProviderKey)
+		audioData, err := audioService.AudioSpeechV1(ctx, &payload, providerAccess.ProviderKey)
 		if err != nil {
-			cause = cErr.ExternalRequestError(err.Error())
+			cause = upstreamError(err)
 			response.AbortWithError(c, cause)
 			return
 		}
@@ -140,6 +159,10 @@ func (handler *AudioHandler) AudioSpeech(c *gin.Context) {
 			cause = cErr.ExternalRequestError(err.Error())
 			response.AbortWithError(c, cause)
 		}
+		// user / org 彙總配額結算預留（失敗不影響主流程）
+		if err := handler.quotaService.Settle(ctx, c.GetString("userID"), payload.Model, 0, 0); err != nil {
+			handler.logger.Warn("settle quota failed", zap.Error(err))
+		}
 
 		ext := strings.TrimPrefix(audioData.ContentType, "audio/")
 		if ext == "" {
@@ -233,11 +256,28 @@ func (handler *AudioHandler) AudioTranscriptions(c *gin.Context) {
 		return
 	}
 
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(providerAccess, payload.Model)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = resolvedModel
+
+	// 請求參數防護：移除 / 強制 / 允許值 / 數值範圍
+	policyVars := service.PolicyVars{UserID: c.GetString("userID"), ExternalID: c.Query("userID")}
+	if err := service.ApplyRequestPolicy(providerAccess, &payload, policyVars); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+
 	switch version {
 	case "v1":
 		result, err := audioService.AudioTranscriptionsV1(ctx, &payload, providerAccess.ProviderKey)
 		if err != nil {
-			response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+			response.AbortWithError(c, upstreamError(err))
 			return
 		}
 		if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, providerAccess); err != nil {
@@ -256,10 +296,18 @@ func (handler *AudioHandler) AudioTranscriptions(c *gin.Context) {
 			outputToken = result.Usage.OutputTokens
 			tokensTotal = result.Usage.TotalTokens
 		}
+		// user / org 彙總配額結算預留（失敗不影響主流程）
+		if err := handler.quotaService.Settle(ctx, c.GetString("userID"), payload.Model, inputToken, outputToken); err != nil {
+			handler.logger.Warn("settle quota failed", zap.Error(err))
+		}
 		//fluentd 紀錄
 		log := fluentdModel.AIUsageLog{
 			RequestID:        fmt.Sprintf("%x", traceID[:]),
+			GatewayRequestID: c.GetString("gatewayRequestID"),
+			IdempotencyKey:   c.GetString("idempotencyKey"),
 			ExternalID:       userID,
+			UserID:           c.GetString("userID"),
+			APIKeyID:         apiKeyID,
 			DisplayName:      displayName,
 			ProjectName:      projectName,
 			Provider:         string(provider),
@@ -276,7 +324,7 @@ func (handler *AudioHandler) AudioTranscriptions(c *gin.Context) {
 			Version:          handler.config.App.Version,
 			LoggedAt:         time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC"),
 		}
-		handler.logRepository.LogUsage(ctx, log)
+		handler.usageService.Record(ctx, log)
 		response.Success(c, result)
 
 	default:
@@ -359,17 +407,38 @@ func (handler *AudioHandler) AudioTranslations(c *gin.Context) {
 		return
 	}
 
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(providerAccess, payload.Model)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = resolvedModel
+
+	// 請求參數防護：移除 / 強制 / 允許值 / 數值範圍
+	policyVars := service.PolicyVars{UserID: c.GetString("userID"), ExternalID: c.Query("userID")}
+	if err := service.ApplyRequestPolicy(providerAccess, &payload, policyVars); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+
 	switch version {
 	case "v1":
 		result, err := audioService.AudioTranslationsV1(ctx, &payload, providerAccess.ProviderKey)
 		if err != nil {
-			response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+			response.AbortWithError(c, upstreamError(err))
 			return
 		}
 		if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, providerAccess); err != nil {
 			response.AbortWithError(c, err)
 			return
 		}
+		// user / org 彙總配額結算預留（失敗不影響主流程）
+		if err := handler.quotaService.Settle(ctx, c.GetString("userID"), payload.Model, 0, 0); err != nil {
+			handler.logger.Warn("settle quota failed", zap.Error(err))
+		}
 		response.Success(c, result)
 
 	default:
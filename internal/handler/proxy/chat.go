@@ -1,11 +1,11 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"interchange/config"
 	"interchange/internal/core"
 	fluentdModel "interchange/internal/database/fluentd/model"
-	"interchange/internal/database/fluentd/repository"
 	"interchange/internal/database/mongodb/model"
 	cErr "interchange/internal/pkg/error"
 	"interchange/internal/pkg/response"
@@ -25,7 +25,12 @@ type ChatHandler struct {
 	logger            *zap.Logger
 	config            *config.Configuration
 	userAPIKeyService *service.UserAPIKeyService
-	logRepository     *repository.LogRepository
+	usageService      *service.UsageService
+	quotaService      *service.QuotaService
+	templateService   *service.PromptTemplateService
+	responseCache     *service.ResponseCacheService
+	coalescing        *service.CoalescingService
+	semanticCache     *service.SemanticCacheService
 }
 
 func NewChatHandler(
@@ -34,7 +39,12 @@ func NewChatHandler(
 	logger *zap.Logger,
 	config *config.Configuration,
 	userAPIKeyService *service.UserAPIKeyService,
-	logRepository *repository.LogRepository,
+	usageService *service.UsageService,
+	quotaService *service.QuotaService,
+	templateService *service.PromptTemplateService,
+	responseCache *service.ResponseCacheService,
+	coalescing *service.CoalescingService,
+	semanticCache *service.SemanticCacheService,
 ) *ChatHandler {
 	return &ChatHandler{
 		trace:             trace,
@@ -42,7 +52,12 @@ func NewChatHandler(
 		logger:            logger,
 		config:            config,
 		userAPIKeyService: userAPIKeyService,
-		logRepository:     logRepository,
+		usageService:      usageService,
+		quotaService:      quotaService,
+		templateService:   templateService,
+		responseCache:     responseCache,
+		coalescing:        coalescing,
+		semanticCache:     semanticCache,
 	}
 }
 
@@ -125,22 +140,130 @@ func (handler *ChatHandler) ChatCompletions(c *gin.Context) {
 		return
 	}
 
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(providerAccess, payload.Model)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = resolvedModel
+
+	// 請求參數防護：移除 / 強制 / 允許值 / 數值範圍
+	policyVars := service.PolicyVars{UserID: c.GetString("userID"), ExternalID: c.Query("userID")}
+	if err := service.ApplyRequestPolicy(providerAccess, &payload, policyVars); err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+
+	// 提示詞樣板與強制 system message
+	templateUsage, err := handler.templateService.ApplyToChat(ctx, providerAccess, &payload)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	templateName, templateVersion := "", 0
+	if templateUsage != nil {
+		templateName, templateVersion = templateUsage.Name, templateUsage.Version
+	}
+
 	// 依版本路由（目前支援 v1）
 	switch version {
 	case "v1":
-		result, err := chatService.ChatCompletionsV1(ctx, &payload, providerAccess.ProviderKey)
+		// 命中快取：不呼叫上游，以零成本記錄；user / org 配額計入 1 次請求，不計 tokens 與成本
+		serveCached := func(cached *chat.ChatResult, cacheType string) {
+			if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, providerAccess); err != nil {
+				response.AbortWithError(c, err)
+				return
+			}
+			if err := handler.quotaService.Settle(ctx, c.GetString("userID"), payload.Model, 0, 0); err != nil {
+				handler.logger.Warn("settle quota failed", zap.Error(err))
+			}
+			handler.usageService.Record(ctx, fluentdModel.AIUsageLog{
+				RequestID:        fmt.Sprintf("%x", traceID[:]),
+				GatewayRequestID: c.GetString("gatewayRequestID"),
+				IdempotencyKey:   c.GetString("idempotencyKey"),
+				ExternalID:       userID,
+				UserID:           c.GetString("userID"),
+				APIKeyID:         apiKeyID,
+				DisplayName:      displayName,
+				ProjectName:      projectName,
+				Provider:         string(provider),
+				Model:            payload.Model,
+				Endpoint:         c.Request.URL.Path,
+				TemplateName:     templateName,
+				TemplateVersion:  templateVersion,
+				Cached:           true,
+				CacheType:        cacheType,
+				Version:          handler.config.App.Version,
+				LoggedAt:         time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC"),
+			})
+			response.Success(c, cached)
+		}
+		bypassCache := service.CacheBypass(c.GetHeader("Cache-Control"))
+
+		// 回應快取（僅確定性請求）
+		cacheKey := ""
+		if handler.responseCache.Enabled(providerAccess) {
+			c.Header("X-Cache", "MISS")
+			cacheKey, _ = handler.responseCache.ChatKey(apiKeyID, provider, providerAccess, &payload)
+		}
+		if cacheKey != "" && !bypassCache {
+			var cached chat.ChatResult
+			if handler.responseCache.Get(ctx, cacheKey, &cached) {
+				c.Header("X-Cache", "HIT")
+				serveCached(&cached, "exact")
+				return
+			}
+		}
+
+		// 語意快取（最後一則 user 訊息相似度比對）
+		var semanticQuery *service.SemanticQuery
+		if !bypassCache && handler.semanticCache.Enabled(providerAccess) {
+			c.Header("X-Semantic-Cache", "MISS")
+			var cached *chat.ChatResult
+			cached, semanticQuery = handler.semanticCache.Lookup(ctx, apiKeyID, provider, providerAccess, &payload)
+			if cached != nil {
+				c.Header("X-Semantic-Cache", "HIT")
+				serveCached(cached, "semantic")
+				return
+			}
+		}
+
+		// 相同的確定性請求並發時合併為一次上游呼叫；扣額與記帳仍逐筆進行
+		result := &chat.ChatResult{}
+		coalesceKey := handler.coalescing.ChatKey(provider, providerAccess.ProviderKey, &payload)
+		coalesced, err := handler.coalescing.Do(ctx, provider, coalesceKey, result, func(ctx context.Context) (interface{}, error) {
+			return chatService.ChatCompletionsV1(ctx, &payload, providerAccess.ProviderKey)
+		})
 		if err != nil {
-			response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+			response.AbortWithError(c, upstreamError(err))
 			return
 		}
+		if cacheKey != "" {
+			handler.responseCache.Set(ctx, providerAccess, cacheKey, result)
+		}
+		if semanticQuery != nil {
+			handler.semanticCache.Store(ctx, semanticQuery, result)
+		}
 		if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, providerAccess); err != nil {
 			response.AbortWithError(c, err)
 			return
 		}
+		// user / org 彙總配額結算預留（失敗不影響主流程）
+		if err := handler.quotaService.Settle(ctx, c.GetString("userID"), payload.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens); err != nil {
+			handler.logger.Warn("settle quota failed", zap.Error(err))
+		}
 		//fluentd 紀錄
 		log := fluentdModel.AIUsageLog{
 			RequestID:        fmt.Sprintf("%x", traceID[:]),
+			GatewayRequestID: c.GetString("gatewayRequestID"),
+			IdempotencyKey:   c.GetString("idempotencyKey"),
 			ExternalID:       userID,
+			UserID:           c.GetString("userID"),
+			APIKeyID:         apiKeyID,
 			DisplayName:      displayName,
 			ProjectName:      projectName,
 			Provider:         string(provider),
@@ -154,10 +277,13 @@ func (handler *ChatHandler) ChatCompletions(c *gin.Context) {
 			InputToken:       0,
 			OutputToken:      0,
 			TokensTotal:      result.Usage.TotalTokens,
+			Coalesced:        coalesced,
+			TemplateName:     templateName,
+			TemplateVersion:  templateVersion,
 			Version:          handler.config.App.Version,
 			LoggedAt:         time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC"),
 		}
-		handler.logRepository.LogUsage(ctx, log)
+		handler.usageService.Record(ctx, log)
 		response.Success(c, result)
 
 	default:
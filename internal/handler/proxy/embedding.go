@@ -1,11 +1,11 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"interchange/config"
 	"interchange/internal/core"
 	fluentdModel "interchange/internal/database/fluentd/model"
-	fluentd "interchange/internal/database/fluentd/repository"
 	"interchange/internal/database/mongodb/model"
 
 	"interchange/internal/database/redis/repository"
@@ -28,7 +28,10 @@ type EmbeddingHandler struct {
 	userAPIKeyService     *service.UserAPIKeyService
 	logger                *zap.Logger
 	config                *config.Configuration
-	logRepository         *fluentd.LogRepository
+	usageService          *service.UsageService
+	quotaService          *service.QuotaService
+	responseCache         *service.ResponseCacheService
+	coalescing            *service.CoalescingService
 }
 
 func NewEmbeddingHandler(
@@ -38,7 +41,10 @@ func NewEmbeddingHandler(
 	userAPIKeyService *service.UserAPIKeyService,
 	logger *zap.Logger,
 	config *config.Configuration,
-	logRepository *fluentd.LogRepository,
+	usageService *service.UsageService,
+	quotaService *service.QuotaService,
+	responseCache *service.ResponseCacheService,
+	coalescing *service.CoalescingService,
 ) *EmbeddingHandler {
 	return &EmbeddingHandler{
 		trace:                 trace,
@@ -47,7 +53,10 @@ func NewEmbeddingHandler(
 		userAPIKeyService:     userAPIKeyService,
 		logger:                logger,
 		config:                config,
-		logRepository:         logRepository,
+		usageService:          usageService,
+		quotaService:          quotaService,
+		responseCache:         responseCache,
+		coalescing:            coalescing,
 	}
 }
 
@@ -133,23 +142,88 @@ func (handler *EmbeddingHandler) GenerateEmbedding(c *gin.Context) {
 		return
 	}
 
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(providerAccess, payload.Model)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = resolvedModel
+
 	// 依版本路由（目前支援 v1）
 	switch version {
 	case "v1":
-		result, err := embService.GenerateEmbedding(ctx, &payload, providerAccess.ProviderKey)
+		// 回應快取
+		cacheKey := ""
+		if handler.responseCache.Enabled(providerAccess) {
+			c.Header("X-Cache", "MISS")
+			cacheKey, _ = handler.responseCache.EmbeddingKey(apiKeyID, provider, providerAccess, &payload)
+		}
+		if cacheKey != "" && !service.CacheBypass(c.GetHeader("Cache-Control")) {
+			var cached embedding.EmbeddingResponse
+			if handler.responseCache.Get(ctx, cacheKey, &cached) {
+				c.Header("X-Cache", "HIT")
+				if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, providerAccess); err != nil {
+					response.AbortWithError(c, err)
+					return
+				}
+				// 命中快取：計入 1 次請求，不計 tokens 與成本
+				if err := handler.quotaService.Settle(ctx, c.GetString("userID"), payload.Model, 0, 0); err != nil {
+					handler.logger.Warn("settle quota failed", zap.Error(err))
+				}
+				// 命中快取：不呼叫上游，以零成本記錄
+				handler.usageService.Record(ctx, fluentdModel.AIUsageLog{
+					RequestID:        fmt.Sprintf("%x", traceID[:]),
+					GatewayRequestID: c.GetString("gatewayRequestID"),
+					IdempotencyKey:   c.GetString("idempotencyKey"),
+					ExternalID:       userID,
+					UserID:           c.GetString("userID"),
+					APIKeyID:         apiKeyID,
+					DisplayName:      displayName,
+					ProjectName:      projectName,
+					Provider:         string(provider),
+					Model:            payload.Model,
+					Endpoint:         c.Request.URL.Path,
+					Cached:           true,
+					Version:          handler.config.App.Version,
+					LoggedAt:         time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC"),
+				})
+				response.Success(c, &cached)
+				return
+			}
+		}
+
+		// 相同的確定性請求並發時合併為一次上游呼叫；扣額與記帳仍逐筆進行
+		result := &embedding.EmbeddingResponse{}
+		coalesceKey := handler.coalescing.EmbeddingKey(provider, providerAccess.ProviderKey, &payload)
+		coalesced, err := handler.coalescing.Do(ctx, provider, coalesceKey, result, func(ctx context.Context) (interface{}, error) {
+			return embService.GenerateEmbedding(ctx, &payload, providerAccess.ProviderKey)
+		})
 		if err != nil {
-			response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+			response.AbortWithError(c, upstreamError(err))
 			return
 		}
+		if cacheKey != "" {
+			handler.responseCache.Set(ctx, providerAccess, cacheKey, result)
+		}
 		// 同步記帳（失敗不影響主流程）
 		if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, providerAccess); err != nil {
 			response.AbortWithError(c, err)
 			return
 		}
+		// user / org 彙總配額結算預留（失敗不影響主流程）
+		if err := handler.quotaService.Settle(ctx, c.GetString("userID"), payload.Model, result.Usage.PromptTokens, 0); err != nil {
+			handler.logger.Warn("settle quota failed", zap.Error(err))
+		}
 		//fluentd 紀錄
 		log := fluentdModel.AIUsageLog{
 			RequestID:        fmt.Sprintf("%x", traceID[:]),
+			GatewayRequestID: c.GetString("gatewayRequestID"),
+			IdempotencyKey:   c.GetString("idempotencyKey"),
 			ExternalID:       userID,
+			UserID:           c.GetString("userID"),
+			APIKeyID:         apiKeyID,
 			DisplayName:      displayName,
 			ProjectName:      projectName,
 			Provider:         string(provider),
@@ -163,10 +237,11 @@ func (handler *EmbeddingHandler) GenerateEmbedding(c *gin.Context) {
 			InputToken:       0,
 			OutputToken:      0,
 			TokensTotal:      result.Usage.TotalTokens,
+			Coalesced:        coalesced,
 			Version:          handler.config.App.Version,
 			LoggedAt:         time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC"),
 		}
-		handler.logRepository.LogUsage(ctx, log)
+		handler.usageService.Record(ctx, log)
 		response.Success(c, result)
 
 	default:
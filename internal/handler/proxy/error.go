@@ -0,0 +1,17 @@
+package proxy
+
+import (
+	"errors"
+
+	cErr "interchange/internal/pkg/error"
+)
+
+// upstreamError 上游呼叫失敗時回給呼叫端的錯誤：service 已分類的錯誤（例如熔斷中的 ServiceUnavailable）原樣回傳，
+// 其餘視為 ExternalRequestError
+func upstreamError(err error) *cErr.Error {
+	var appErr *cErr.Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return cErr.ExternalRequestError(err.Error())
+}
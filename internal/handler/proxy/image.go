@@ -6,7 +6,6 @@ import (
 	"interchange/config"
 	"interchange/internal/core"
 	fluentdModel "interchange/internal/database/fluentd/model"
-	"interchange/internal/database/fluentd/repository"
 	mongoModel "interchange/internal/database/mongodb/model"
 	cErr "interchange/internal/pkg/error"
 	"interchange/internal/pkg/response"
@@ -21,15 +20,18 @@ import (
 )
 
 type LogArgs struct {
-	traceID      string
-	userID       string
-	apiKeyID     string
-	displayName  string
-	projectName  string
-	model        string
-	endpoint     string
-	provider     core.ProviderName
-	responseBody *images.ImageGenerationResponse
+	traceID        string
+	requestID      string // 閘道產生的請求 ID（未帶 Idempotency-Key 時的扣款冪等鍵）
+	idempotencyKey string // 呼叫端帶入的 Idempotency-Key
+	userID         string
+	ownerID        string
+	apiKeyID       string
+	displayName    string
+	projectName    string
+	model          string
+	endpoint       string
+	provider       core.ProviderName
+	responseBody   *images.ImageGenerationResponse
 }
 type ImageHandler struct {
 	trace             *telemetry.Trace
@@ -37,7 +39,8 @@ type ImageHandler struct {
 	userAPIKeyService *service.UserAPIKeyService
 	logger            *zap.Logger
 	config            *config.Configuration
-	logRepository     *repository.LogRepository
+	usageService      *service.UsageService
+	quotaService      *service.QuotaService
 }
 
 func NewImageHandler(
@@ -46,7 +49,8 @@ func NewImageHandler(
 	userAPIKeyService *service.UserAPIKeyService,
 	logger *zap.Logger,
 	config *config.Configuration,
-	logRepository *repository.LogRepository,
+	usageService *service.UsageService,
+	quotaService *service.QuotaService,
 ) *ImageHandler {
 	return &ImageHandler{
 		trace:             trace,
@@ -54,7 +58,8 @@ func NewImageHandler(
 		userAPIKeyService: userAPIKeyService,
 		logger:            logger,
 		config:            config,
-		logRepository:     logRepository,
+		usageService:      usageService,
+		quotaService:      quotaService,
 	}
 }
 
@@ -174,9 +179,24 @@ func (handler *ImageHandler) ImagesGenerations(c *gin.Context) {
 		return
 	}
 
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(access, string(payload.Model))
+	if err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = images.ImageModel(resolvedModel)
+
+	// 請求參數防護：移除 / 強制 / 允許值 / 數值範圍
+	policyVars := service.PolicyVars{UserID: c.GetString("userID"), ExternalID: c.Query("userID")}
+	if err := service.ApplyRequestPolicy(access, &payload, policyVars); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+
 	result, err := imgSvc.GenerateV1(ctx, &payload, access.ProviderKey)
 	if err != nil {
-		response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+		response.AbortWithError(c, upstreamError(err))
 		return
 	}
 	if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, access); err != nil {
@@ -185,15 +205,18 @@ func (handler *ImageHandler) ImagesGenerations(c *gin.Context) {
 	}
 
 	handler.logImageUsage(ctx, LogArgs{
-		displayName:  displayName,
-		projectName:  projectName,
-		traceID:      traceID,
-		userID:       userID,
-		apiKeyID:     apiKeyID,
-		provider:     provider,
-		model:        string(payload.Model),
-		endpoint:     c.Request.URL.Path,
-		responseBody: result,
+		displayName:    displayName,
+		projectName:    projectName,
+		traceID:        traceID,
+		requestID:      c.GetString("gatewayRequestID"),
+		idempotencyKey: c.GetString("idempotencyKey"),
+		userID:         userID,
+		ownerID:        c.GetString("userID"),
+		apiKeyID:       apiKeyID,
+		provider:       provider,
+		model:          string(payload.Model),
+		endpoint:       c.Request.URL.Path,
+		responseBody:   result,
 	})
 	response.Success(c, result)
 }
@@ -236,9 +259,24 @@ func (handler *ImageHandler) ImagesVariations(c *gin.Context) {
 		response.AbortWithError(c, cErr.BadRequestBody("invalid image variation payload"))
 		return
 	}
+
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(access, payload.Model)
+	if err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = resolvedModel
+
+	// 請求參數防護：移除 / 強制 / 允許值 / 數值範圍
+	policyVars := service.PolicyVars{UserID: c.GetString("userID"), ExternalID: c.Query("userID")}
+	if err := service.ApplyRequestPolicy(access, &payload, policyVars); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
 	result, err := imgSvc.VariationV1(ctx, &payload, access.ProviderKey)
 	if err != nil {
-		response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+		response.AbortWithError(c, upstreamError(err))
 		return
 	}
 	if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, access); err != nil {
@@ -247,15 +285,18 @@ func (handler *ImageHandler) ImagesVariations(c *gin.Context) {
 	}
 
 	handler.logImageUsage(ctx, LogArgs{
-		displayName:  displayName,
-		projectName:  projectName,
-		traceID:      traceID,
-		userID:       userID,
-		apiKeyID:     apiKeyID,
-		provider:     provider,
-		model:        string(payload.Model),
-		endpoint:     c.Request.URL.Path,
-		responseBody: result,
+		displayName:    displayName,
+		projectName:    projectName,
+		traceID:        traceID,
+		requestID:      c.GetString("gatewayRequestID"),
+		idempotencyKey: c.GetString("idempotencyKey"),
+		userID:         userID,
+		ownerID:        c.GetString("userID"),
+		apiKeyID:       apiKeyID,
+		provider:       provider,
+		model:          string(payload.Model),
+		endpoint:       c.Request.URL.Path,
+		responseBody:   result,
 	})
 	response.Success(c, result)
 }
@@ -299,9 +340,24 @@ func (handler *ImageHandler) ImagesEdits(c *gin.Context) {
 		return
 	}
 
+	// 模型限制：別名改寫並檢查是否允許
+	resolvedModel, err := service.ResolveModel(access, string(payload.Model))
+	if err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	payload.Model = images.ImageModel(resolvedModel)
+
+	// 請求參數防護：移除 / 強制 / 允許值 / 數值範圍
+	policyVars := service.PolicyVars{UserID: c.GetString("userID"), ExternalID: c.Query("userID")}
+	if err := service.ApplyRequestPolicy(access, &payload, policyVars); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+
 	result, err := imgSvc.EditV1(ctx, &payload, access.ProviderKey)
 	if err != nil {
-		response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+		response.AbortWithError(c, upstreamError(err))
 		return
 	}
 	if _, err := handler.userAPIKeyService.Consume(ctx, apiKeyID, access); err != nil {
@@ -310,20 +366,23 @@ func (handler *ImageHandler) ImagesEdits(c *gin.Context) {
 	}
 
 	handler.logImageUsage(ctx, LogArgs{
-		displayName:  displayName,
-		projectName:  projectName,
-		traceID:      traceID,
-		userID:       userID,
-		apiKeyID:     apiKeyID,
-		provider:     provider,
-		model:        string(payload.Model),
-		endpoint:     c.Request.URL.Path,
-		responseBody: result,
+		displayName:    displayName,
+		projectName:    projectName,
+		traceID:        traceID,
+		requestID:      c.GetString("gatewayRequestID"),
+		idempotencyKey: c.GetString("idempotencyKey"),
+		userID:         userID,
+		ownerID:        c.GetString("userID"),
+		apiKeyID:       apiKeyID,
+		provider:       provider,
+		model:          string(payload.Model),
+		endpoint:       c.Request.URL.Path,
+		responseBody:   result,
 	})
 	response.Success(c, result)
 }
 
-// ===== 共用記錄：把 token / req / resp 與基礎欄位打包，統一寫 Fluentd
+// ===== 共用記錄：把 token / req / resp 與基礎欄位打包，統一寫 Fluentd，並記入 user / org 彙總配額
 func (h *ImageHandler) logImageUsage(ctx context.Context, args LogArgs) {
 	var (
 		textToken, imageToken, inputToken, outputToken, total int
@@ -338,7 +397,11 @@ func (h *ImageHandler) logImageUsage(ctx context.Context, args LogArgs) {
 
 	log := fluentdModel.AIUsageLog{
 		RequestID:        args.traceID,
+		GatewayRequestID: args.requestID,
+		IdempotencyKey:   args.idempotencyKey,
 		ExternalID:       args.userID,
+		UserID:           args.ownerID,
+		APIKeyID:         args.apiKeyID,
 		DisplayName:      args.displayName,
 		ProjectName:      args.projectName,
 		Provider:         string(args.provider),
@@ -355,5 +418,9 @@ func (h *ImageHandler) logImageUsage(ctx context.Context, args LogArgs) {
 		Version:          h.config.App.Version,
 		LoggedAt:         time.Now().UTC().Format("2006-01-02 15:04:05.999999 UTC"),
 	}
-	h.logRepository.LogUsage(ctx, log)
+	h.usageService.Record(ctx, log)
+	// user / org 彙總配額結算預留（失敗不影響主流程）
+	if err := h.quotaService.Settle(ctx, args.ownerID, args.model, inputToken, outputToken); err != nil {
+		h.logger.Warn("settle quota failed", zap.Error(err))
+	}
 }
@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"context"
 	"interchange/internal/core"
 	"interchange/internal/database/mongodb/model"
 	"interchange/internal/database/redis/repository"
@@ -21,6 +22,7 @@ type ModelsHandler struct {
 	rateLimiterRepository *repository.RateLimiterRepository
 	userAPIKeyService     *service.UserAPIKeyService
 	logger                *zap.Logger
+	coalescing            *service.CoalescingService
 }
 
 func NewModelsHandler(
@@ -29,6 +31,7 @@ func NewModelsHandler(
 	rateLimiterRepository *repository.RateLimiterRepository,
 	userAPIKeyService *service.UserAPIKeyService,
 	logger *zap.Logger,
+	coalescing *service.CoalescingService,
 ) *ModelsHandler {
 	return &ModelsHandler{
 		trace:                 trace,
@@ -36,6 +39,7 @@ func NewModelsHandler(
 		rateLimiterRepository: rateLimiterRepository,
 		userAPIKeyService:     userAPIKeyService,
 		logger:                logger,
+		coalescing:            coalescing,
 	}
 }
 
@@ -109,15 +113,20 @@ func (handler *ModelsHandler) ListModels(c *gin.Context) {
 	// 依版本路由（目前支援 v1）
 	switch version {
 	case "v1":
-		var result *models.ListResponse
-		result, err := modelsService.List(ctx, providerAccess.ProviderKey)
+		// 相同上游金鑰的並發列表請求合併為一次呼叫
+		var result models.ListResponse
+		coalesceKey := handler.coalescing.ModelsKey(provider, providerAccess.ProviderKey)
+		_, err := handler.coalescing.Do(ctx, provider, coalesceKey, &result, func(ctx context.Context) (interface{}, error) {
+			return modelsService.List(ctx, providerAccess.ProviderKey)
+		})
 		if err != nil {
 			end(err)
-			response.AbortWithError(c, cErr.ExternalRequestError(err.Error()))
+			response.AbortWithError(c, upstreamError(err))
 			return
 		}
 
-		response.Success(c, result)
+		// 只列出此 key 可用的模型（含別名）
+		response.Success(c, service.FilterModels(providerAccess, &result))
 
 	default:
 		err := cErr.UnsupportedVersion("unsupported version")
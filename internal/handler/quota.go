@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AdminUserQuotaHandler struct {
+	trace               *telemetry.Trace
+	quotaService        *service.QuotaService
+	organizationService *service.OrganizationService
+}
+
+func NewAdminUserQuotaHandler(
+	trace *telemetry.Trace,
+	quotaService *service.QuotaService,
+	organizationService *service.OrganizationService,
+) *AdminUserQuotaHandler {
+	return &AdminUserQuotaHandler{trace: trace, quotaService: quotaService, organizationService: organizationService}
+}
+
+// Get 取得用戶配額
+// @Summary 取得用戶配額政策與目前用量
+// @Tags Admin-Quota
+// @Security BearerAuth
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} dto.QuotaStatusDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{userID}/quota [get]
+func (h *AdminUserQuotaHandler) Get(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	status, err := h.quotaService.GetUserQuota(ctx, userID)
+	if err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, status)
+}
+
+// Update 設定用戶配額
+// @Summary 設定用戶配額政策
+// @Tags Admin-Quota
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param userID path string true "User ID"
+// @Param body body dto.QuotaPolicyDto true "配額政策"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{userID}/quota [put]
+func (h *AdminUserQuotaHandler) Update(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	var req dto.QuotaPolicyDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.quotaService.UpdateUserQuota(ctx, userID, &req); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "user quota updated successfully")
+}
+
+// Delete 移除用戶配額
+// @Summary 移除用戶配額政策
+// @Tags Admin-Quota
+// @Security BearerAuth
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{userID}/quota [delete]
+func (h *AdminUserQuotaHandler) Delete(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.quotaService.UpdateUserQuota(ctx, userID, nil); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "user quota removed successfully")
+}
+
+// ResetUsage 重置用戶配額用量
+// @Summary 重置用戶目前週期的配額用量
+// @Tags Admin-Quota
+// @Security BearerAuth
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{userID}/quota/reset [post]
+func (h *AdminUserQuotaHandler) ResetUsage(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if err := h.quotaService.ResetUserQuotaUsage(ctx, userID); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "user quota usage reset successfully")
+}
+
+// UpdateOrganization 指派用戶組織
+// @Summary 指派用戶所屬組織（orgID 為空代表移出組織）
+// @Tags Admin-Quota
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param userID path string true "User ID"
+// @Param body body dto.UpdateUserOrganizationDto true "組織資訊"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{userID}/organization [patch]
+func (h *AdminUserQuotaHandler) UpdateOrganization(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+	var req dto.UpdateUserOrganizationDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	var orgID *primitive.ObjectID
+	if req.OrgID != "" {
+		parsed, err := primitive.ObjectIDFromHex(req.OrgID)
+		if err != nil {
+			end(err)
+			response.AbortWithError(c, cErr.BadRequestBody("invalid orgID"))
+			return
+		}
+		orgID = &parsed
+	}
+
+	if err := h.organizationService.AssignUser(ctx, userID, orgID); err != nil {
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, "user organization updated successfully")
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"interchange/internal/dto"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminSemanticCacheHandler struct {
+	trace                *telemetry.Trace
+	semanticCacheService *service.SemanticCacheService
+}
+
+func NewAdminSemanticCacheHandler(
+	trace *telemetry.Trace,
+	semanticCacheService *service.SemanticCacheService,
+) *AdminSemanticCacheHandler {
+	return &AdminSemanticCacheHandler{trace: trace, semanticCacheService: semanticCacheService}
+}
+
+// Stats 各命名空間統計
+// @Summary 取得語意快取各命名空間項目數與命中率
+// @Tags Admin-SemanticCache
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.SemanticCacheStatsDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/semantic-cache [get]
+func (h *AdminSemanticCacheHandler) Stats(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	stats, err := h.semanticCacheService.Stats(ctx)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, stats)
+}
+
+// Invalidate 清除指定命名空間
+// @Summary 清除語意快取命名空間
+// @Tags Admin-SemanticCache
+// @Security BearerAuth
+// @Produce json
+// @Param namespace path string true "命名空間（預設為 API Key ID）"
+// @Success 200 {object} dto.SemanticCacheInvalidateResponseDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/semantic-cache/{namespace} [delete]
+func (h *AdminSemanticCacheHandler) Invalidate(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	namespace := c.Param("namespace")
+	deleted, err := h.semanticCacheService.Invalidate(ctx, namespace)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, &dto.SemanticCacheInvalidateResponseDto{Namespace: namespace, Deleted: deleted})
+}
+
+// InvalidateAll 清除全部
+// @Summary 清除全部語意快取
+// @Tags Admin-SemanticCache
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.SemanticCacheInvalidateResponseDto
+// @Failure 500 {object} map[string]string
+// @Router /admin/semantic-cache [delete]
+func (h *AdminSemanticCacheHandler) InvalidateAll(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	deleted, err := h.semanticCacheService.Invalidate(ctx, "")
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, &dto.SemanticCacheInvalidateResponseDto{Deleted: deleted})
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminUsageHandler struct {
+	trace        *telemetry.Trace
+	usageService *service.UsageService
+}
+
+func NewAdminUsageHandler(
+	trace *telemetry.Trace,
+	usageService *service.UsageService,
+) *AdminUsageHandler {
+	return &AdminUsageHandler{trace: trace, usageService: usageService}
+}
+
+// Aggregate 用量彙總
+// @Summary 依維度與時間區間彙總用量（支援 CSV / JSON 匯出）
+// @Tags Admin-Usage
+// @Security BearerAuth
+// @Produce json,text/csv
+// @Param from query string false "起始時間（含），RFC3339 或 YYYY-MM-DD"
+// @Param to query string false "結束時間（不含），RFC3339 或 YYYY-MM-DD"
+// @Param userID query string false "使用者 ID"
+// @Param apiKeyID query string false "API Key ID"
+// @Param project query string false "專案名稱"
+// @Param provider query string false "模型提供者"
+// @Param model query string false "模型"
+// @Param endpoint query string false "請求路徑"
+// @Param groupBy query string false "分組維度，逗號分隔：user, apiKey, project, provider, model, endpoint"
+// @Param bucket query string false "時間區間：hour, day, month"
+// @Param timezone query string false "IANA 時區（預設 UTC）"
+// @Param format query string false "回傳格式：json（預設）、csv"
+// @Success 200 {array} dto.UsageAggregateDto
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/usage [get]
+func (h *AdminUsageHandler) Aggregate(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		err := cErr.BadRequestParams("format must be json or csv")
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+
+	query := &dto.UsageQueryDto{
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		UserID:   c.Query("userID"),
+		APIKeyID: c.Query("apiKeyID"),
+		Project:  c.Query("project"),
+		Provider: c.Query("provider"),
+		Model:    c.Query("model"),
+		Endpoint: c.Query("endpoint"),
+		Bucket:   c.Query("bucket"),
+		Timezone: c.Query("timezone"),
+	}
+	for _, dimension := range strings.Split(c.Query("groupBy"), ",") {
+		if dimension = strings.TrimSpace(dimension); dimension != "" {
+			query.GroupBy = append(query.GroupBy, dimension)
+		}
+	}
+
+	results, err := h.usageService.Aggregate(ctx, query)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	if format == "json" {
+		response.Success(c, results)
+		return
+	}
+
+	body, err := usageCSV(query, results)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, cErr.InternalServer(err.Error()))
+		return
+	}
+	filename := "usage-" + time.Now().UTC().Format("20060102150405") + ".csv"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", body)
+	c.Abort()
+}
+
+// usageCSV 欄位依序為時間區間、分組維度（依 groupBy 順序）與各項合計
+func usageCSV(query *dto.UsageQueryDto, results []*dto.UsageAggregateDto) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{}
+	if query.Bucket != "" {
+		header = append(header, "bucket")
+	}
+	header = append(header, query.GroupBy...)
+	header = append(header, "requests", "cachedRequests", "tokensPrompt", "tokensCompletion", "inputTokens", "outputTokens", "tokensTotal", "cost")
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		row := make([]string, 0, len(header))
+		if query.Bucket != "" {
+			bucket := ""
+			if result.Bucket != nil {
+				bucket = result.Bucket.UTC().Format(time.RFC3339)
+			}
+			row = append(row, bucket)
+		}
+		for _, dimension := range query.GroupBy {
+			row = append(row, usageDimension(result, dimension))
+		}
+		for _, value := range []int64{
+			result.Requests, result.CachedRequests, result.TokensPrompt, result.TokensCompletion,
+			result.InputTokens, result.OutputTokens, result.TokensTotal,
+		} {
+			row = append(row, strconv.FormatInt(value, 10))
+		}
+		row = append(row, strconv.FormatFloat(result.Cost, 'f', -1, 64))
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+func usageDimension(result *dto.UsageAggregateDto, dimension string) string {
+	switch dimension {
+	case "user":
+		return result.UserID
+	case "apiKey":
+		return result.APIKeyID
+	case "project":
+		return result.Project
+	case "provider":
+		return result.Provider
+	case "model":
+		return result.Model
+	case "endpoint":
+		return result.Endpoint
+	}
+	return ""
+}
@@ -208,6 +208,60 @@ func (h *AdminUserAPIKeyHandler) PatchProviderField(c *gin.Context) {
 	response.Success(c, "provider field updated successfully")
 }
 
+// RenewProviderExpiry 延長 / 續期 provider 到期時間
+// @Summary 延長或續期 provider 到期時間（expireTime、extendDays、noExpiry 三擇一；已過期的權限恢復為 active）
+// @Tags Admin-APIKey
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param userID path string true "User ID"
+// @Param apiKeyID path string true "API Key ID"
+// @Param provider path string true "Provider"
+// @Param body body dto.RenewProviderExpiryDto true "到期設定"
+// @Success 200 {object} dto.UserAPIKeyResponseDto
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/users/{userID}/api-keys/{apiKeyID}/providers/{provider}/expiry [put]
+func (h *AdminUserAPIKeyHandler) RenewProviderExpiry(c *gin.Context) {
+	ctx, _, end := h.trace.WithSpan(c)
+	defer end(nil)
+
+	userID, cause, respErr := validate.ParseObjectID(c, "userID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	if _, err := h.userService.GetUserByID(ctx, userID); err != nil {
+		response.AbortWithError(c, cErr.NotFound(fmt.Sprintf("user with id %s not found", userID.Hex())))
+		return
+	}
+
+	apiKeyID, cause, respErr := validate.ParseObjectID(c, "apiKeyID")
+	if cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	var req dto.RenewProviderExpiryDto
+	if cause, respErr := validate.BindAndValidate(c, &req); cause != nil {
+		end(cause)
+		response.AbortWithError(c, respErr)
+		return
+	}
+
+	key, err := h.userAPIKeyService.RenewProviderExpiry(ctx, apiKeyID, core.ProviderName(c.Param("provider")), &req)
+	if err != nil {
+		end(err)
+		response.AbortWithError(c, err)
+		return
+	}
+	response.Success(c, key)
+}
+
 // DeleteByID 刪除 API Key
 // @Summary 刪除 API Key
 // @Tags Admin-APIKey
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"interchange/internal/core"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Admin 管理端身分：接在 APIKey.KeyOnly 之後，確認 key 擁有者為啟用中的 admin / editor，
+// 並把角色放入 gin.Context（userRole）；管理端的操作者（稽核、審核者）一律取自這裡驗證過的 userID
+type Admin struct {
+	logger      *zap.Logger
+	trace       *telemetry.Trace
+	userService *service.UserService
+}
+
+func NewAdmin(
+	logger *zap.Logger,
+	trace *telemetry.Trace,
+	userService *service.UserService,
+) *Admin {
+	return &Admin{
+		logger:      logger,
+		trace:       trace,
+		userService: userService,
+	}
+}
+
+func (m *Admin) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span, end := m.trace.WithSpan(c.Request.Context(), string(core.SpanAdminMiddleware))
+		userID := c.GetString("userID")
+		span.SetAttributes(attribute.String("auth.user_id", userID))
+
+		oid, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			span.SetAttributes(attribute.String("auth.status", "missing_admin_identity"))
+			cause := cErr.Unauthorized("admin identity required")
+			response.AbortWithError(c, cause)
+			end(cause)
+			return
+		}
+		user, err := m.userService.GetUserByID(ctx, oid)
+		if err != nil {
+			span.SetAttributes(attribute.String("auth.status", "user_check_failed"))
+			response.AbortWithError(c, err)
+			end(err)
+			return
+		}
+		if user.Status != core.StatusActive || (user.Role != core.RoleAdmin && user.Role != core.RoleEditor) {
+			span.SetAttributes(
+				attribute.String("auth.status", "not_admin"),
+				attribute.String("auth.user_role", string(user.Role)),
+				attribute.String("auth.user_status", string(user.Status)),
+			)
+			m.logger.Warn("[Admin] access denied",
+				zap.String("userID", userID),
+				zap.String("role", string(user.Role)),
+				zap.String("status", string(user.Status)),
+				zap.String("path", c.Request.URL.Path),
+			)
+			cause := cErr.Forbidden("admin or editor role required")
+			response.AbortWithError(c, cause)
+			end(cause)
+			return
+		}
+		span.SetAttributes(
+			attribute.String("auth.status", "success"),
+			attribute.String("auth.user_role", string(user.Role)),
+		)
+		end(nil)
+
+		c.Set("userRole", string(user.Role))
+		c.Set("displayName", user.DisplayName)
+		c.Next()
+	}
+}
+
+// Require 限定角色（需先經過 Handler）
+func (m *Admin) Require(roles ...core.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := core.Role(c.GetString("userRole"))
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		response.AbortWithError(c, cErr.Forbidden("insufficient role for this operation"))
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"interchange/internal/core"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Anomaly 每把 API Key 的異常偵測：放在 User 之後、RateLimit 之前，
+// 先檢查限流處置，再計入速率與來源；回應後依狀態碼計入錯誤率
+type Anomaly struct {
+	logger         *zap.Logger
+	trace          *telemetry.Trace
+	anomalyService *service.AnomalyService
+}
+
+func NewAnomaly(
+	logger *zap.Logger,
+	trace *telemetry.Trace,
+	anomalyService *service.AnomalyService,
+) *Anomaly {
+	return &Anomaly{
+		logger:         logger,
+		trace:          trace,
+		anomalyService: anomalyService,
+	}
+}
+
+func (middleware *Anomaly) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !middleware.anomalyService.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx, span, end := middleware.trace.WithSpan(c.Request.Context(), string(core.SpanAnomalyMiddleware))
+		request := &service.AnomalyRequest{
+			APIKeyID:  c.GetString("apiKeyID"),
+			UserID:    c.GetString("userID"),
+			Provider:  core.ProviderName(c.Param("provider")),
+			Endpoint:  routeEndpoint(c.Request.URL.Path),
+			ClientIP:  c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			Model:     peekRequestModel(c),
+		}
+		span.SetAttributes(
+			attribute.String("api_key.id", request.APIKeyID),
+			attribute.String("provider", string(request.Provider)),
+			attribute.String("endpoint", request.Endpoint),
+		)
+
+		retryAfter, err := middleware.anomalyService.Guard(ctx, request)
+		if err != nil {
+			c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+			response.AbortWithError(c, err)
+			end(err)
+			return
+		}
+		if err := middleware.anomalyService.Observe(ctx, request); err != nil {
+			middleware.logger.Info("[Anomaly] request blocked",
+				zap.String("apiKeyID", request.APIKeyID),
+				zap.String("provider", string(request.Provider)),
+				zap.String("endpoint", request.Endpoint),
+				zap.Error(err),
+			)
+			response.AbortWithError(c, err)
+			end(err)
+			return
+		}
+		end(nil)
+
+		c.Next()
+
+		// 只計上游或伺服器錯誤；呼叫端自身造成的 429 / 4xx（限流、參數錯誤）不計入，避免因限流被停用
+		failed := c.Writer.Status() >= http.StatusInternalServerError
+		middleware.anomalyService.ObserveResult(c.Request.Context(), request, failed)
+	}
+}
+
+// peekRequestModel 從 JSON 請求內容取出 model 欄位；最多暫存 core.MaxModelInspectBytes，
+// 讀過的前段與未讀的剩餘部分接回 Body 供後續 handler 使用（不整包緩衝）；
+// 非 JSON（multipart 等）、超過上限或解析失敗時回傳空字串（不計入模型變化）
+func peekRequestModel(c *gin.Context) string {
+	if c.Request.Body == nil || c.Request.ContentLength == 0 ||
+		!strings.Contains(c.GetHeader("Content-Type"), "application/json") {
+		return ""
+	}
+	body := c.Request.Body
+	prefix, err := io.ReadAll(io.LimitReader(body, core.MaxModelInspectBytes+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), body), body}
+	if err != nil || len(prefix) > core.MaxModelInspectBytes {
+		return ""
+	}
+	var payload struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(prefix, &payload); err != nil {
+		return ""
+	}
+	return payload.Model
+}
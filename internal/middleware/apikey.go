@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -122,6 +123,54 @@ func (middleware *APIKey) Handler() gin.HandlerFunc {
 		c.Set("apiKeyID", apiKeyID)
 		c.Set("keyName", payload.KeyName)
 		c.Set("providerAccess", providerAccess)
+		// 扣款冪等鍵：呼叫端重試時帶相同的 Idempotency-Key（以 apiKeyID 區隔），
+		// 未帶時以閘道自行產生的請求 ID 代替（trace ID 可由呼叫端的 traceparent 指定，不可使用）
+		c.Set("gatewayRequestID", uuid.NewString())
+		if idempotencyKey := c.GetHeader(core.HeaderIdempotencyKey); idempotencyKey != "" && len(idempotencyKey) <= core.MaxIdempotencyKeyLength {
+			c.Set("idempotencyKey", idempotencyKey)
+		}
+		c.Next()
+	}
+}
+
+// KeyOnly 只驗證平台 key 本身（不檢查 provider 權限與使用者狀態），供 self-service 端點使用，
+// 例如因閒置停用的使用者以自己的 key 重新啟用
+func (middleware *APIKey) KeyOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span, end := middleware.trace.WithSpan(c.Request.Context(), string(core.SpanAPIKeyMiddleware))
+		apiKey, from := middleware.readPlatformKey(c)
+		meta := core.TraceAPIKeyMiddlewareMeta{
+			Where:    from,
+			ClientIP: c.ClientIP(),
+		}
+
+		if apiKey == "" {
+			meta.Status = "missing_api_key"
+			middleware.trace.ApplyTraceAttributes(span, meta)
+			cause := cErr.UnauthorizedApiKey("Missing API Key")
+			response.AbortWithError(c, cause)
+			end(cause)
+			return
+		}
+		payload, err := middleware.userAPIKeyService.ValidateKey(ctx, apiKey)
+		if err != nil {
+			meta.Status = "invalid_api_key"
+			middleware.trace.ApplyTraceAttributes(span, meta)
+			response.AbortWithError(c, cErr.UnauthorizedApiKey("Invalid API Key"))
+			end(err)
+			return
+		}
+
+		meta.UserID = payload.UserID.Hex()
+		meta.APIKeyID = payload.ID.Hex()
+		meta.KeyName = payload.KeyName
+		meta.Status = "success"
+		middleware.trace.ApplyTraceAttributes(span, meta)
+		end(nil)
+
+		c.Set("userID", payload.UserID.Hex())
+		c.Set("apiKeyID", payload.ID.Hex())
+		c.Set("keyName", payload.KeyName)
 		c.Next()
 	}
 }
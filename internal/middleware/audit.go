@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"interchange/config"
+	"interchange/internal/core"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit 管理端稽核（接在 Admin 之後）：將操作者、來源 IP 與 User-Agent 放入 request context，供 service 寫稽核紀錄時帶入；
+// 異動請求成功但 service 沒有寫任何紀錄時（例如配額、組織、通知規則等設定），補一筆 admin.request
+type Audit struct {
+	config       *config.Configuration
+	auditService *service.AuditService
+}
+
+func NewAudit(
+	config *config.Configuration,
+	auditService *service.AuditService,
+) *Audit {
+	return &Audit{config: config, auditService: auditService}
+}
+
+func (m *Audit) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 操作者取自 Admin middleware 驗證過的身分；沒有身分的請求不放行，避免寫入匿名稽核紀錄
+		actorID := c.GetString("userID")
+		if actorID == "" {
+			response.AbortWithError(c, cErr.Unauthorized("admin identity required"))
+			return
+		}
+		request := &service.AuditRequest{
+			Actor:     service.AuditActor{Type: core.AuditActorAdmin, ID: actorID},
+			ClientIP:  c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		c.Request = c.Request.WithContext(service.WithAuditRequest(c.Request.Context(), request))
+		c.Next()
+
+		if !m.config.Audit.RecordAdminRequests || !isMutatingMethod(c.Request.Method) {
+			return
+		}
+		if status := c.Writer.Status(); status >= http.StatusBadRequest || request.Recorded() {
+			return
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		m.auditService.Record(c.Request.Context(), service.AuditEntry{
+			Action:     core.AuditActionAdminRequest,
+			TargetType: core.AuditTargetEndpoint,
+			TargetID:   c.Request.URL.Path,
+			Provider:   core.ProviderName(c.Param("provider")),
+			After: map[string]interface{}{
+				"method": c.Request.Method,
+				"route":  route,
+				"status": strconv.Itoa(c.Writer.Status()),
+			},
+		})
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"interchange/internal/core"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Maintenance 維護模式：依 provider / endpoint 比對生效中的開關，放在 APIKey 之後以便放行白名單 key
+type Maintenance struct {
+	logger             *zap.Logger
+	trace              *telemetry.Trace
+	maintenanceService *service.MaintenanceService
+}
+
+func NewMaintenance(
+	logger *zap.Logger,
+	trace *telemetry.Trace,
+	maintenanceService *service.MaintenanceService,
+) *Maintenance {
+	return &Maintenance{
+		logger:             logger,
+		trace:              trace,
+		maintenanceService: maintenanceService,
+	}
+}
+
+func (middleware *Maintenance) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provider := core.ProviderName(c.Param("provider"))
+		endpoint := routeEndpoint(c.Request.URL.Path)
+		apiKeyID := c.GetString("apiKeyID")
+
+		sw := middleware.maintenanceService.Check(provider, endpoint, apiKeyID)
+		if sw == nil {
+			c.Next()
+			return
+		}
+
+		_, span, end := middleware.trace.WithSpan(c.Request.Context(), string(core.SpanMaintenanceMiddleware))
+		notice := middleware.maintenanceService.Notice(sw)
+		span.SetAttributes(
+			attribute.String("provider", string(provider)),
+			attribute.String("endpoint", endpoint),
+			attribute.String("maintenance.scope", string(sw.Scope)),
+			attribute.Int64("maintenance.retry_after", notice.RetryAfter),
+		)
+		cause := cErr.ServiceUnavailable(notice.Message)
+		end(cause)
+
+		requestID, err := uuid.NewV7()
+		if err != nil {
+			requestID = uuid.New()
+		}
+		middleware.logger.Info("[Maintenance] request rejected",
+			zap.String("provider", string(provider)),
+			zap.String("endpoint", endpoint),
+			zap.String("scope", string(sw.Scope)),
+			zap.String("apiKeyID", apiKeyID),
+			zap.String("requestId", fmt.Sprintf("%x", requestID.String())),
+		)
+		// 直接輸出帶維護資訊的回應（FormatHandler / Recovery 會略過已寫出的回應）
+		c.Header("Retry-After", strconv.FormatInt(notice.RetryAfter, 10))
+		c.JSON(http.StatusServiceUnavailable, response.Response{
+			RequestID:   fmt.Sprintf("%x", requestID.String()),
+			Code:        cause.ErrorCode(),
+			Data:        notice,
+			Message:     cause.Error(),
+			Description: cause.ErrorDesc(),
+		})
+		c.Abort()
+	}
+}
+
+// routeEndpoint 取出 /proxy/:version/:provider 之後的路徑；MCP 路由沿用 ApiScope 的 "/mcp-server/..." 形式
+func routeEndpoint(urlPath string) string {
+	cleanPath := path.Clean(urlPath)
+	parts := strings.SplitN(strings.TrimPrefix(cleanPath, "/"), "/", 4)
+	if len(parts) < 4 {
+		return "/"
+	}
+	if parts[0] == "mcp-server" {
+		return "/mcp-server/" + parts[3]
+	}
+	return "/" + parts[3]
+}
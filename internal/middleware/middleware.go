@@ -11,4 +11,9 @@ var ProviderSet = wire.NewSet(
 	// NewRateLimit,
 	NewResponse,
 	// NewUser,
+	// NewAudit,
+	// NewMaintenance,
+	// NewAnomaly,
+	// NewAdmin,
+	// NewSession,
 )
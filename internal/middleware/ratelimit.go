@@ -1,15 +1,20 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"interchange/internal/core"
 	"interchange/internal/database/mongodb/model"
 	"interchange/internal/database/redis/repository"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/pkg/response"
 	"interchange/internal/service"
 	"interchange/internal/telemetry"
 	"interchange/utils/validate"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,17 +26,35 @@ type RateLimit struct {
 	trace                 *telemetry.Trace
 	rateLimiterRepository *repository.RateLimiterRepository
 	userAPIKeyService     *service.UserAPIKeyService
+	quotaService          *service.QuotaService
+	concurrencyService    *service.ConcurrencyService
+	upstreamQueueService  *service.UpstreamQueueService
+	circuitBreakerService *service.CircuitBreakerService
+	creditService         *service.CreditService
+	notificationService   *service.NotificationService
 }
 
 func NewRateLimit(
 	trace *telemetry.Trace,
 	rateLimiterRepository *repository.RateLimiterRepository,
 	userAPIKeyService *service.UserAPIKeyService,
+	quotaService *service.QuotaService,
+	concurrencyService *service.ConcurrencyService,
+	upstreamQueueService *service.UpstreamQueueService,
+	circuitBreakerService *service.CircuitBreakerService,
+	creditService *service.CreditService,
+	notificationService *service.NotificationService,
 ) *RateLimit {
 	return &RateLimit{
 		trace:                 trace,
 		rateLimiterRepository: rateLimiterRepository,
 		userAPIKeyService:     userAPIKeyService,
+		quotaService:          quotaService,
+		concurrencyService:    concurrencyService,
+		upstreamQueueService:  upstreamQueueService,
+		circuitBreakerService: circuitBreakerService,
+		creditService:         creditService,
+		notificationService:   notificationService,
 	}
 }
 
@@ -81,8 +104,15 @@ func (middleware *RateLimit) Guard() gin.HandlerFunc {
 			end(err)
 			return
 		}
-		// 若未設定 period 或 limit，next()
+		// 若未設定 period 或 limit，只檢查 user / org 彙總配額
 		if providerAccess.LimitPeriod == nil || providerAccess.LimitCount == nil || *providerAccess.LimitCount <= 0 {
+			release, err := middleware.admit(ctx, c, apiKeyID, providerAccess, nil)
+			if err != nil {
+				response.AbortWithError(c, err)
+				end(err)
+				return
+			}
+			defer release()
 			end(nil)
 			c.Next()
 			return
@@ -97,8 +127,15 @@ func (middleware *RateLimit) Guard() gin.HandlerFunc {
 			*providerAccess.LimitCount,
 		)
 		if e != nil {
+			// 風險控制：讀取錯誤不阻斷主流程（可改為嚴格阻斷），但仍檢查 user / org 彙總配額
+			release, err := middleware.admit(ctx, c, apiKeyID, providerAccess, nil)
+			if err != nil {
+				response.AbortWithError(c, err)
+				end(err)
+				return
+			}
+			defer release()
 			end(nil)
-			// 風險控制：讀取錯誤不阻斷主流程（可改為嚴格阻斷）
 			c.Next()
 			return
 		}
@@ -165,7 +202,157 @@ func (middleware *RateLimit) Guard() gin.HandlerFunc {
 			end(err)
 			return
 		}
+
+		// 階層式檢查：key 通過後再檢查 user → org
+		keyBudget := core.QuotaBudget{
+			Scope:        core.QuotaScopeKey,
+			ScopeID:      apiKeyID,
+			Metric:       core.QuotaMetricRequests,
+			Limit:        float64(*providerAccess.LimitCount),
+			Remaining:    float64(effectiveRemaining),
+			ResetSeconds: ttlSec,
+		}
+		release, err := middleware.admit(ctx, c, apiKeyID, providerAccess, &keyBudget)
+		if err != nil {
+			response.AbortWithError(c, err)
+			end(err)
+			return
+		}
+		defer release()
 		end(nil)
 		c.Next()
 	}
 }
+
+// admit 通過次數限制後的放行檢查：先確認上游未熔斷（不佔半開探測名額，探測於實際送出上游時才取得）、
+// 檢查並原子預留 user / org 彙總配額、檢查預付餘額，再於上游飽和時公平排隊，最後取得並行槽位。
+// 回傳的 release 須於後續 handler 結束後呼叫以釋放佇列與槽位，並退回 handler 未結算的配額預留。
+func (middleware *RateLimit) admit(
+	ctx context.Context,
+	c *gin.Context,
+	apiKeyID string,
+	providerAccess *model.ProviderAccess,
+	keyBudget *core.QuotaBudget,
+) (func(), error) {
+	retryAfter, err := middleware.circuitBreakerService.Allow(ctx, providerAccess.Provider, upstreamEndpoint(c))
+	if err != nil {
+		c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+		return nil, err
+	}
+	if err := middleware.guardQuota(ctx, c, keyBudget); err != nil {
+		return nil, err
+	}
+	reservation, err := middleware.quotaService.Reserve(ctx, c.GetString("userID"))
+	if err != nil {
+		return nil, err
+	}
+	releaseReservation := func() {
+		_ = middleware.quotaService.Release(context.WithoutCancel(ctx), reservation)
+	}
+	account, err := middleware.creditService.Guard(ctx, c.GetString("userID"))
+	if account != nil {
+		c.Header("X-Credit-Scope", string(account.Scope))
+		c.Header("X-Credit-Balance", strconv.FormatFloat(account.Balance, 'f', -1, 64))
+	}
+	if err != nil {
+		releaseReservation()
+		return nil, err
+	}
+	releaseQueue, err := middleware.upstreamQueueService.Wait(ctx, apiKeyID, providerAccess)
+	if err != nil {
+		releaseReservation()
+		return nil, err
+	}
+	releaseSlots, err := middleware.concurrencyService.Acquire(ctx, apiKeyID, providerAccess)
+	if err != nil {
+		releaseQueue()
+		releaseReservation()
+		return nil, err
+	}
+	if reservation != nil {
+		// handler 以 trace.WithSpan(c) 取得的 context 結算，兩者都需帶上預留
+		c.Set(core.ContextTraceKey, service.WithQuotaReservation(middleware.trace.GetTraceContext(c), reservation))
+		c.Request = c.Request.WithContext(service.WithQuotaReservation(c.Request.Context(), reservation))
+	}
+	return func() {
+		releaseSlots()
+		releaseQueue()
+		releaseReservation()
+	}, nil
+}
+
+// guardQuota 檢查 user / org 彙總配額，並將最緊的剩餘額度寫入 X-Quota-* 標頭；用量跨過通知門檻時送出通知。
+// 任一層級額度用盡時回傳 RateLimitExceeded；讀取錯誤不阻斷主流程。
+func (middleware *RateLimit) guardQuota(ctx context.Context, c *gin.Context, keyBudget *core.QuotaBudget) (returnedError error) {
+	ctx, span, end := middleware.trace.WithSpan(ctx, "quota_guard")
+	defer func() { end(returnedError) }()
+
+	budgets := make([]core.QuotaBudget, 0, 7)
+	if keyBudget != nil {
+		budgets = append(budgets, *keyBudget)
+	}
+	userID := c.GetString("userID")
+	if userObjectID, err := primitive.ObjectIDFromHex(userID); err == nil {
+		ownerBudgets, checkErr := middleware.quotaService.Check(ctx, userObjectID)
+		if checkErr == nil {
+			budgets = append(budgets, ownerBudgets...)
+		} else {
+			span.SetAttributes(attribute.String("quota.check_error", checkErr.Error()))
+		}
+	}
+
+	// 用量跨過通知門檻時非同步送出
+	middleware.notificationService.ObserveBudgets(ctx, c.Param("provider"), budgets)
+
+	// 已用盡的 user / org 額度優先回報，否則回報剩餘比例最低者
+	var exhausted *core.QuotaBudget
+	for i := range budgets {
+		if budgets[i].Scope != core.QuotaScopeKey && budgets[i].Exhausted() {
+			exhausted = &budgets[i]
+			break
+		}
+	}
+	reported := exhausted
+	if reported == nil {
+		reported = core.TightestBudget(budgets)
+	}
+	if reported == nil {
+		return nil
+	}
+
+	remaining := reported.Remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	c.Header("X-Quota-Scope", string(reported.Scope))
+	c.Header("X-Quota-Metric", string(reported.Metric))
+	c.Header("X-Quota-Limit", strconv.FormatFloat(reported.Limit, 'f', -1, 64))
+	c.Header("X-Quota-Remaining", strconv.FormatFloat(remaining, 'f', -1, 64))
+	if reported.ResetSeconds > 0 {
+		c.Header("X-Quota-Reset", strconv.FormatInt(reported.ResetSeconds, 10))
+	}
+	span.SetAttributes(
+		attribute.String("quota.scope", string(reported.Scope)),
+		attribute.String("quota.metric", string(reported.Metric)),
+		attribute.Float64("quota.remaining", remaining),
+		attribute.Bool("quota.blocked", exhausted != nil),
+	)
+
+	if exhausted != nil {
+		if exhausted.ResetSeconds > 0 {
+			c.Header("Retry-After", strconv.FormatInt(exhausted.ResetSeconds, 10))
+		}
+		return cErr.RateLimitExceeded(fmt.Sprintf("%s quota exceeded: %s", exhausted.Scope, exhausted.Metric))
+	}
+	return nil
+}
+
+// upstreamEndpoint 由閘道路徑推導上游 endpoint（與熔斷器 Transport 端相同的 outbound.EndpointKey）：
+// /proxy/{version}/{provider}/<rest>、/mcp-server/{version}/{provider}/<rest> → /{version}/<rest>
+func upstreamEndpoint(c *gin.Context) string {
+	parts := strings.SplitN(strings.TrimPrefix(path.Clean(c.Request.URL.Path), "/"), "/", 4)
+	if len(parts) < 4 {
+		return outbound.EndpointKey(c.Param("version"), "")
+	}
+	return outbound.EndpointKey(parts[1], parts[3])
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"strings"
+
+	"interchange/config"
+	"interchange/internal/core"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/response"
+	"interchange/internal/service"
+	"interchange/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Session 以外部登入平台簽發的 session（Authorization: Bearer <JWT>）驗證使用者身分，
+// 供尚未持有 API Key 的 self-service 端點使用；通過後將 userID 放入 gin.Context
+type Session struct {
+	logger      *zap.Logger
+	trace       *telemetry.Trace
+	config      *config.Configuration
+	userService *service.UserService
+}
+
+func NewSession(
+	logger *zap.Logger,
+	trace *telemetry.Trace,
+	config *config.Configuration,
+	userService *service.UserService,
+) *Session {
+	return &Session{
+		logger:      logger,
+		trace:       trace,
+		config:      config,
+		userService: userService,
+	}
+}
+
+func (m *Session) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span, end := m.trace.WithSpan(c.Request.Context(), string(core.SpanSessionMiddleware))
+
+		if m.config.Session.JWTSecret == "" {
+			span.SetAttributes(attribute.String("auth.status", "session_disabled"))
+			cause := cErr.InvalidSession("session authentication is not configured")
+			response.AbortWithError(c, cause)
+			end(cause)
+			return
+		}
+		claims, err := m.parse(readBearer(c))
+		if err != nil {
+			span.SetAttributes(attribute.String("auth.status", "invalid_session"))
+			cause := cErr.InvalidSession("invalid or expired session")
+			response.AbortWithError(c, cause)
+			end(err)
+			return
+		}
+
+		user, err := m.userService.GetUserByExternalID(ctx, claims.Subject)
+		if err != nil {
+			span.SetAttributes(attribute.String("auth.status", "user_not_found"))
+			m.logger.Info("[Session] no user for session subject", zap.String("subject", claims.Subject), zap.Error(err))
+			cause := cErr.InvalidSession("no user registered for this session")
+			response.AbortWithError(c, cause)
+			end(cause)
+			return
+		}
+		span.SetAttributes(
+			attribute.String("auth.status", "success"),
+			attribute.String("auth.user_id", user.ID),
+		)
+		end(nil)
+
+		c.Set("userID", user.ID)
+		c.Set("displayName", user.DisplayName)
+		c.Next()
+	}
+}
+
+// parse 驗證簽章（只接受 HS256）、到期時間（必填）與簽發者
+func (m *Session) parse(token string) (*core.Claims, error) {
+	if token == "" {
+		return nil, jwt.NewValidationError("missing session token", jwt.ValidationErrorMalformed)
+	}
+	claims := &core.Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, jwt.NewValidationError("unexpected signing method", jwt.ValidationErrorSignatureInvalid)
+		}
+		return []byte(m.config.Session.JWTSecret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" || claims.ExpiresAt == nil {
+		return nil, jwt.NewValidationError("session requires sub and exp", jwt.ValidationErrorClaimsInvalid)
+	}
+	if issuer := m.config.Session.Issuer; issuer != "" && !claims.VerifyIssuer(issuer, true) {
+		return nil, jwt.NewValidationError("unexpected issuer", jwt.ValidationErrorIssuer)
+	}
+	return claims, nil
+}
+
+func readBearer(c *gin.Context) string {
+	auth := strings.TrimSpace(c.GetHeader("Authorization"))
+	if len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "bearer ") {
+		return strings.TrimSpace(auth[len("Bearer "):])
+	}
+	return ""
+}
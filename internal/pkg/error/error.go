@@ -119,6 +119,10 @@ func UnauthorizedApiKey(errorDesc string) *Error {
 	return New(http.StatusForbidden, UNAUTHORIZED_API_KEY, "unauthorized-api-key", errorDesc)
 }
 
+func APIKeyExpired(errorDesc string) *Error {
+	return New(http.StatusForbidden, API_KEY_EXPIRED, "api-key-expired", errorDesc)
+}
+
 func RateLimitExceeded(errorDesc string) *Error {
 	return New(http.StatusTooManyRequests, RATE_LIMIT_EXCEEDED, "rate-limit-exceeded", errorDesc)
 }
@@ -131,6 +135,11 @@ func Forbidden(errorDesc string, errorCode ...int) *Error {
 	return New(http.StatusForbidden, errCode, "forbidden", errorDesc)
 }
 
+// ✅ 付費錯誤 (402)
+func InsufficientCredit(errorDesc string) *Error {
+	return New(http.StatusPaymentRequired, INSUFFICIENT_CREDIT, "insufficient-credit", errorDesc)
+}
+
 // ✅ 資源找不到 (404)
 func NotFound(errorDesc string, errorCode ...int) *Error {
 	errCode := NOT_FOUND
@@ -139,6 +148,12 @@ func NotFound(errorDesc string, errorCode ...int) *Error {
 	}
 	return New(http.StatusNotFound, errCode, "not-found", errorDesc)
 }
+
+// ✅ 狀態衝突 (409)
+func Conflict(errorDesc string) *Error {
+	return New(http.StatusConflict, CONFLICT, "conflict", errorDesc)
+}
+
 func (e *Error) HttpCode() int {
 	return e.httpCode
 }
@@ -17,10 +17,17 @@ const (
 	INVALID_SESSION      = 40101 // 401 - 會話失效
 	UNAUTHORIZED_API_KEY = 40300 // 403 - API Key 無權限
 	FORBIDDEN            = 40301 // 403 - 禁止訪問
+	API_KEY_EXPIRED      = 40302 // 403 - API Key 已過期
+
+	// 40200 ~ 40299: 付費錯誤 (402 系列)
+	INSUFFICIENT_CREDIT = 40200 // 402 - 預付額度不足
 
 	// 40400 ~ 40499: 資源錯誤 (404 系列)
 	NOT_FOUND = 40400 // 404 - 資源未找到
 
+	// 40900 ~ 40999: 狀態衝突 (409 系列)
+	CONFLICT = 40900 // 409 - 資源狀態衝突
+
 	// 42900 ~ 42999: 流量限制錯誤 (429 系列)
 	RATE_LIMIT_EXCEEDED = 42900 // 429 - 速率限制超過
 
@@ -0,0 +1,243 @@
+package outbound
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+)
+
+const (
+	defaultConnectTimeoutMs        = 10000
+	defaultTLSHandshakeTimeoutMs   = 10000
+	defaultResponseHeaderTimeoutMs = 120000
+	defaultTotalTimeoutMs          = 300000
+	defaultStreamIdleTimeoutMs     = 60000
+	defaultMaxIdleConns            = 200
+	defaultMaxIdleConnsPerHost     = 64
+	defaultIdleConnTimeoutMs       = 90000
+)
+
+// Wrapper 包裝上游 RoundTripper（重試、熔斷、排隊等）
+type Wrapper func(http.RoundTripper) http.RoundTripper
+
+// Clients 依 provider 提供各自調校過的 *http.Client（連線池、逾時、代理、CA 各自獨立）
+type Clients struct {
+	config   *config.Configuration
+	wrappers []Wrapper
+
+	mu      sync.Mutex
+	clients map[core.ProviderName]*http.Client
+}
+
+// NewClients 建立 Clients；wrappers 由內而外套用在各 provider 的 transport 之上。
+// 設定錯誤（代理網址、CA 檔）於啟動時即回傳。
+func NewClients(config *config.Configuration, wrappers ...Wrapper) (*Clients, error) {
+	clients := &Clients{
+		config:   config,
+		wrappers: wrappers,
+		clients:  make(map[core.ProviderName]*http.Client),
+	}
+	providers := []core.ProviderName{core.ProviderCustom}
+	for provider := range config.ProviderDefinitions() {
+		providers = append(providers, core.ProviderName(provider))
+	}
+	for provider := range config.Outbound.Providers {
+		providers = append(providers, core.ProviderName(provider))
+	}
+	for _, provider := range providers {
+		if _, err := clients.build(provider); err != nil {
+			return nil, fmt.Errorf("outbound client %s: %w", provider, err)
+		}
+	}
+	return clients, nil
+}
+
+// For 取得 provider 專用的 client；未知 provider 使用 default 設定
+func (c *Clients) For(provider core.ProviderName) *http.Client {
+	c.mu.Lock()
+	client, ok := c.clients[provider]
+	c.mu.Unlock()
+	if ok {
+		return client
+	}
+	client, err := c.build(provider)
+	if err != nil {
+		// 設定已於啟動時驗證，此處僅作保險
+		return c.For(core.ProviderCustom)
+	}
+	return client
+}
+
+// build 建立並快取 provider 的 client
+func (c *Clients) build(provider core.ProviderName) (*http.Client, error) {
+	settings := Settings(c.config, provider)
+	transport, err := newTransport(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	var roundTripper http.RoundTripper = transport
+	for _, wrap := range c.wrappers {
+		roundTripper = wrap(roundTripper)
+	}
+	client := &http.Client{
+		// 總逾時由 timeoutTransport 控制，串流改以閒置逾時判斷，因此不設定 http.Client.Timeout
+		Transport: newTimeoutTransport(roundTripper, settings),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.clients[provider]; ok {
+		return existing, nil
+	}
+	c.clients[provider] = client
+	return client, nil
+}
+
+// NewClient 以 default 出口設定（代理、CA、連線逾時）建立不經上游 wrapper 的獨立 client，
+// 供 webhook 等非 provider 的對外呼叫使用；timeout 為整個請求的上限
+func NewClient(config *config.Configuration, timeout time.Duration) (*http.Client, error) {
+	transport, err := newTransport(Settings(config, ""))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// Settings 合併 default 與 provider 設定並補上預設值
+func Settings(config *config.Configuration, provider core.ProviderName) config.OutboundClient {
+	settings := config.Outbound.Default
+	if override, ok := config.Outbound.Providers[string(provider)]; ok {
+		settings = merge(settings, override)
+	}
+
+	setDefault := func(value *int, fallback int) {
+		if *value <= 0 {
+			*value = fallback
+		}
+	}
+	setDefault(&settings.ConnectTimeoutMs, defaultConnectTimeoutMs)
+	setDefault(&settings.TLSHandshakeTimeoutMs, defaultTLSHandshakeTimeoutMs)
+	setDefault(&settings.ResponseHeaderTimeoutMs, defaultResponseHeaderTimeoutMs)
+	setDefault(&settings.TotalTimeoutMs, defaultTotalTimeoutMs)
+	setDefault(&settings.StreamIdleTimeoutMs, defaultStreamIdleTimeoutMs)
+	setDefault(&settings.MaxIdleConns, defaultMaxIdleConns)
+	setDefault(&settings.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost)
+	setDefault(&settings.IdleConnTimeoutMs, defaultIdleConnTimeoutMs)
+	return settings
+}
+
+// merge 以 override 中有設定的欄位覆寫 base
+func merge(base, override config.OutboundClient) config.OutboundClient {
+	pick := func(value *int, candidate int) {
+		if candidate > 0 {
+			*value = candidate
+		}
+	}
+	pick(&base.ConnectTimeoutMs, override.ConnectTimeoutMs)
+	pick(&base.TLSHandshakeTimeoutMs, override.TLSHandshakeTimeoutMs)
+	pick(&base.ResponseHeaderTimeoutMs, override.ResponseHeaderTimeoutMs)
+	pick(&base.TotalTimeoutMs, override.TotalTimeoutMs)
+	pick(&base.StreamIdleTimeoutMs, override.StreamIdleTimeoutMs)
+	pick(&base.MaxIdleConns, override.MaxIdleConns)
+	pick(&base.MaxIdleConnsPerHost, override.MaxIdleConnsPerHost)
+	pick(&base.MaxConnsPerHost, override.MaxConnsPerHost)
+	pick(&base.IdleConnTimeoutMs, override.IdleConnTimeoutMs)
+	if override.HTTP2 != nil {
+		base.HTTP2 = override.HTTP2
+	}
+	if override.ProxyURL != "" {
+		base.ProxyURL = override.ProxyURL
+	}
+	if len(override.CABundles) > 0 {
+		base.CABundles = append(append([]string{}, base.CABundles...), override.CABundles...)
+	}
+	return base
+}
+
+// newTransport 依設定建立 http.Transport
+func newTransport(settings config.OutboundClient) (*http.Transport, error) {
+	proxy, err := proxyFunc(settings.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tlsConfig(settings.CABundles)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   milliseconds(settings.ConnectTimeoutMs),
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   milliseconds(settings.TLSHandshakeTimeoutMs),
+		ResponseHeaderTimeout: milliseconds(settings.ResponseHeaderTimeoutMs),
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          settings.MaxIdleConns,
+		MaxIdleConnsPerHost:   settings.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       settings.MaxConnsPerHost,
+		IdleConnTimeout:       milliseconds(settings.IdleConnTimeoutMs),
+		ForceAttemptHTTP2:     true,
+	}
+	if settings.HTTP2 != nil && !*settings.HTTP2 {
+		// 非 nil 的空 map 會停用 HTTP/2 協商
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport, nil
+}
+
+// proxyFunc 解析出口代理；未設定時沿用環境變數（HTTPS_PROXY / HTTP_PROXY / NO_PROXY）
+func proxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", parsed.Scheme)
+	}
+	return http.ProxyURL(parsed), nil
+}
+
+// tlsConfig 於系統憑證之外附加自訂 CA
+func tlsConfig(caBundles []string) (*tls.Config, error) {
+	if len(caBundles) == 0 {
+		return nil, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	for _, path := range caBundles {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read ca bundle %s: %w", path, err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca bundle %s", path)
+		}
+	}
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+func milliseconds(ms int) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,18 @@
+package outbound
+
+import (
+	"errors"
+
+	cErr "interchange/internal/pkg/error"
+)
+
+// ErrCircuitOpen 熔斷中，上游請求未送出
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// RequestError 上游請求送出失敗時回給呼叫端的錯誤：熔斷中為 ServiceUnavailable，其餘為 ExternalRequestError
+func RequestError(err error, desc string) *cErr.Error {
+	if errors.Is(err, ErrCircuitOpen) {
+		return cErr.ServiceUnavailable("upstream circuit breaker is open")
+	}
+	return cErr.ExternalRequestError(desc)
+}
@@ -0,0 +1,114 @@
+package outbound
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"interchange/config"
+	"interchange/internal/core"
+)
+
+// Provider 取得生效中的 provider 定義；未定義或已停用時回傳 false。
+// 每次呼叫都讀取目前設定，因此設定熱更新後立即生效。
+func Provider(conf *config.Configuration, provider core.ProviderName) (config.Provider, bool) {
+	definition, ok := conf.ProviderDefinitions()[string(provider)]
+	if !ok || (definition.Enabled != nil && !*definition.Enabled) {
+		return config.Provider{}, false
+	}
+	return definition, true
+}
+
+// Supports 判斷 provider 是否啟用指定功能（未設定 capabilities 代表全部啟用）
+func Supports(conf *config.Configuration, provider core.ProviderName, capability core.ProviderCapability) bool {
+	definition, ok := Provider(conf, provider)
+	if !ok {
+		return false
+	}
+	return len(definition.Capabilities) == 0 || slices.Contains(definition.Capabilities, string(capability))
+}
+
+// SupportsVersion 判斷 provider 是否支援指定 API 版本（未設定 versions 代表不限制）
+func SupportsVersion(conf *config.Configuration, provider core.ProviderName, version string) bool {
+	definition, ok := Provider(conf, provider)
+	if !ok {
+		return false
+	}
+	version = strings.Trim(version, "/")
+	return len(definition.Versions) == 0 || slices.Contains(definition.Versions, version)
+}
+
+// BaseURL 取得 provider 的 Base URL（不含結尾 /）
+func BaseURL(conf *config.Configuration, provider core.ProviderName) (string, bool) {
+	definition, ok := Provider(conf, provider)
+	if !ok {
+		return "", false
+	}
+	return strings.TrimRight(definition.BaseURL, "/"), true
+}
+
+// ProviderFromURL 依上游 URL 判斷 provider（比對最長的 Base URL）
+func ProviderFromURL(conf *config.Configuration, url string) core.ProviderName {
+	matched, matchedBase := core.ProviderCustom, ""
+	for name, definition := range conf.ProviderDefinitions() {
+		base := strings.TrimRight(definition.BaseURL, "/")
+		if base != "" && strings.HasPrefix(url, base) && len(base) > len(matchedBase) {
+			matched, matchedBase = core.ProviderName(name), base
+		}
+	}
+	return matched
+}
+
+// URL 組合上游網址：{base}/{version}{path}
+func (c *Clients) URL(provider core.ProviderName, version string, path string) (string, error) {
+	base, ok := BaseURL(c.config, provider)
+	if !ok {
+		return "", fmt.Errorf("provider %s is not configured or disabled", provider)
+	}
+	return base + "/" + strings.Trim(version, "/") + "/" + strings.TrimLeft(path, "/"), nil
+}
+
+// EndpointKey 上游端點分組 key（熔斷器等使用）：/{version}/{path}，不含 Base URL 的路徑前綴；
+// 進站的 {version} 與動作路徑與 URL 組出的上游網址經 EndpointKeyFromURL 會得到相同的 key
+func EndpointKey(version string, path string) string {
+	return "/" + strings.Trim(strings.Trim(version, "/")+"/"+strings.Trim(path, "/"), "/")
+}
+
+// EndpointKeyFromURL 由上游網址取得 EndpointKey（去掉 provider Base URL 的路徑前綴與 query）
+func EndpointKeyFromURL(conf *config.Configuration, provider core.ProviderName, upstream *url.URL) string {
+	path := upstream.Path
+	if base, ok := BaseURL(conf, provider); ok {
+		if parsed, err := url.Parse(base); err == nil {
+			if prefix := strings.TrimRight(parsed.Path, "/"); prefix != "" && strings.HasPrefix(path, prefix+"/") {
+				path = strings.TrimPrefix(path, prefix)
+			}
+		}
+	}
+	return EndpointKey("", path)
+}
+
+// Authorize 依 provider 的授權方式注入金鑰，並附加預設標頭（不覆寫已存在者）
+func (c *Clients) Authorize(req *http.Request, provider core.ProviderName, apiKey string) error {
+	definition, ok := Provider(c.config, provider)
+	if !ok {
+		return fmt.Errorf("provider %s is not configured or disabled", provider)
+	}
+	for name, value := range definition.DefaultHeaders {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	switch strings.ToLower(definition.AuthScheme) {
+	case config.ProviderAuthHeader:
+		req.Header.Set(definition.AuthName, apiKey)
+	case config.ProviderAuthQuery:
+		query := req.URL.Query()
+		query.Set(definition.AuthName, apiKey)
+		req.URL.RawQuery = query.Encode()
+	default:
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return nil
+}
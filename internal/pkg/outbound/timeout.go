@@ -0,0 +1,95 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"interchange/config"
+)
+
+// ErrTimeout 上游請求超過總逾時或串流閒置逾時
+var ErrTimeout = errors.New("upstream request timed out")
+
+// timeoutTransport 控制整體逾時：
+// 一般回應從送出到 body 讀完須在 TotalTimeoutMs 內完成；
+// text/event-stream 回應在收到標頭後改以 StreamIdleTimeoutMs 判斷（每收到資料即重新計時）。
+type timeoutTransport struct {
+	next       http.RoundTripper
+	total      time.Duration
+	streamIdle time.Duration
+}
+
+func newTimeoutTransport(next http.RoundTripper, settings config.OutboundClient) http.RoundTripper {
+	return &timeoutTransport{
+		next:       next,
+		total:      milliseconds(settings.TotalTimeoutMs),
+		streamIdle: milliseconds(settings.StreamIdleTimeoutMs),
+	}
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancelCause(req.Context())
+	timer := time.AfterFunc(t.total, func() {
+		cancel(fmt.Errorf("%w: total timeout %s", ErrTimeout, t.total))
+	})
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		timer.Stop()
+		cause := context.Cause(ctx)
+		cancel(nil)
+		if errors.Is(cause, ErrTimeout) {
+			return nil, cause
+		}
+		return nil, err
+	}
+
+	body := &timeoutBody{ReadCloser: resp.Body, ctx: ctx, cancel: cancel, timer: timer}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		timer.Stop()
+		body.idle = t.streamIdle
+		body.timer = time.AfterFunc(t.streamIdle, func() {
+			cancel(fmt.Errorf("%w: stream idle for %s", ErrTimeout, t.streamIdle))
+		})
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+// timeoutBody 讀完或關閉時釋放計時器；串流模式下每次讀到資料即重設閒置計時
+type timeoutBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	timer  *time.Timer
+	idle   time.Duration
+	once   sync.Once
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && b.idle > 0 {
+		b.timer.Reset(b.idle)
+	}
+	if err != nil && err != io.EOF {
+		if cause := context.Cause(b.ctx); errors.Is(cause, ErrTimeout) {
+			return n, cause
+		}
+	}
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.timer.Stop()
+		b.cancel(nil)
+	})
+	return err
+}
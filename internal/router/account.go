@@ -0,0 +1,45 @@
+package router
+
+import (
+	"interchange/internal/handler"
+	"interchange/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountRouter 使用者 self-service 端點（只驗證 API Key，不檢查使用者狀態）；
+// API Key 申請以登入平台的 session 驗證，尚未持有 key 的使用者也能申請第一把
+type AccountRouter struct {
+	accountHandler    *handler.AccountHandler
+	apiKeyMiddleware  *middleware.APIKey
+	sessionMiddleware *middleware.Session
+}
+
+func NewAccountRouter(
+	accountHandler *handler.AccountHandler,
+	apiKeyMiddleware *middleware.APIKey,
+	sessionMiddleware *middleware.Session,
+) *AccountRouter {
+	return &AccountRouter{
+		accountHandler:    accountHandler,
+		apiKeyMiddleware:  apiKeyMiddleware,
+		sessionMiddleware: sessionMiddleware,
+	}
+}
+
+func (accountRouter *AccountRouter) RegisterRoutes(engine *gin.Engine) {
+	sessionGroup := engine.Group("/account/key-requests")
+	sessionGroup.Use(accountRouter.sessionMiddleware.Handler())
+	{
+		sessionGroup.POST("", accountRouter.accountHandler.SubmitKeyRequest)
+		sessionGroup.GET("", accountRouter.accountHandler.ListKeyRequests)
+	}
+
+	router := engine.Group("/account")
+	router.Use(accountRouter.apiKeyMiddleware.KeyOnly())
+	{
+		router.POST("/reactivate", accountRouter.accountHandler.Reactivate)
+		router.POST("/reverify", accountRouter.accountHandler.Reverify)
+		router.POST("/reverify/resend", accountRouter.accountHandler.ResendReverifyCode)
+	}
+}
@@ -1,28 +1,96 @@
 package router
 
 import (
+	"interchange/internal/core"
 	"interchange/internal/handler"
+	"interchange/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AdminRouter struct {
-	userHandler           *handler.AdminUserHandler
-	adminUserAPIKeyRouter *AdminUserAPIKeyRouter
+	userHandler             *handler.AdminUserHandler
+	adminUserAPIKeyRouter   *AdminUserAPIKeyRouter
+	adminUserQuotaRouter    *AdminUserQuotaRouter
+	adminOrganizationRouter *AdminOrganizationRouter
+	adminCircuitRouter      *AdminCircuitBreakerRouter
+	adminTemplateRouter     *AdminPromptTemplateRouter
+	adminSemanticRouter     *AdminSemanticCacheRouter
+	adminUsageRouter        *AdminUsageRouter
+	adminCreditRouter       *AdminCreditRouter
+	adminInvoiceRouter      *AdminInvoiceRouter
+	adminNotifyRouter       *AdminNotificationRouter
+	adminJobRouter          *AdminJobRouter
+	adminLifecycleRouter    *AdminLifecycleRouter
+	adminAuditRouter        *AdminAuditRouter
+	adminKeyRequestRouter   *AdminKeyRequestRouter
+	adminMaintenanceRouter  *AdminMaintenanceRouter
+	adminAnomalyRouter      *AdminAnomalyRouter
+	apiKeyMiddleware        *middleware.APIKey
+	adminMiddleware         *middleware.Admin
+	auditMiddleware         *middleware.Audit
 }
 
 func NewAdminRouter(
 	userHandler *handler.AdminUserHandler,
 	adminUserAPIKeyRouter *AdminUserAPIKeyRouter,
+	adminUserQuotaRouter *AdminUserQuotaRouter,
+	adminOrganizationRouter *AdminOrganizationRouter,
+	adminCircuitRouter *AdminCircuitBreakerRouter,
+	adminTemplateRouter *AdminPromptTemplateRouter,
+	adminSemanticRouter *AdminSemanticCacheRouter,
+	adminUsageRouter *AdminUsageRouter,
+	adminCreditRouter *AdminCreditRouter,
+	adminInvoiceRouter *AdminInvoiceRouter,
+	adminNotifyRouter *AdminNotificationRouter,
+	adminJobRouter *AdminJobRouter,
+	adminLifecycleRouter *AdminLifecycleRouter,
+	adminAuditRouter *AdminAuditRouter,
+	adminKeyRequestRouter *AdminKeyRequestRouter,
+	adminMaintenanceRouter *AdminMaintenanceRouter,
+	adminAnomalyRouter *AdminAnomalyRouter,
+	apiKeyMiddleware *middleware.APIKey,
+	adminMiddleware *middleware.Admin,
+	auditMiddleware *middleware.Audit,
 ) *AdminRouter {
 	return &AdminRouter{
-		userHandler:           userHandler,
-		adminUserAPIKeyRouter: adminUserAPIKeyRouter,
+		userHandler:             userHandler,
+		adminUserAPIKeyRouter:   adminUserAPIKeyRouter,
+		adminUserQuotaRouter:    adminUserQuotaRouter,
+		adminOrganizationRouter: adminOrganizationRouter,
+		adminCircuitRouter:      adminCircuitRouter,
+		adminTemplateRouter:     adminTemplateRouter,
+		adminSemanticRouter:     adminSemanticRouter,
+		adminUsageRouter:        adminUsageRouter,
+		adminCreditRouter:       adminCreditRouter,
+		adminInvoiceRouter:      adminInvoiceRouter,
+		adminNotifyRouter:       adminNotifyRouter,
+		adminJobRouter:          adminJobRouter,
+		adminLifecycleRouter:    adminLifecycleRouter,
+		adminAuditRouter:        adminAuditRouter,
+		adminKeyRequestRouter:   adminKeyRequestRouter,
+		adminMaintenanceRouter:  adminMaintenanceRouter,
+		adminAnomalyRouter:      adminAnomalyRouter,
+		apiKeyMiddleware:        apiKeyMiddleware,
+		adminMiddleware:         adminMiddleware,
+		auditMiddleware:         auditMiddleware,
 	}
 }
 
 func (ar *AdminRouter) RegisterRoutes(r *gin.Engine) {
-	admin := r.Group("/admin/users")
+	// 所有管理端路由：以平台 API Key 驗證管理者身分（操作者即 key 擁有者），異動寫入稽核紀錄
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(ar.apiKeyMiddleware.KeyOnly())
+	adminGroup.Use(ar.adminMiddleware.Handler())
+	adminGroup.Use(ar.auditMiddleware.Handler())
+
+	// API Key 申請審核（editor / admin）
+	ar.adminKeyRequestRouter.Register(adminGroup)
+
+	// 其餘管理端操作限 admin
+	adminOnly := adminGroup.Group("", ar.adminMiddleware.Require(core.RoleAdmin))
+
+	admin := adminOnly.Group("/users")
 	{
 		admin.GET("", ar.userHandler.List)
 		admin.GET("/:userID", ar.userHandler.Get)
@@ -34,5 +102,34 @@ func (ar *AdminRouter) RegisterRoutes(r *gin.Engine) {
 
 		// user_api_key 子路由直接獨立管理
 		ar.adminUserAPIKeyRouter.Register(admin)
+		// user 配額 / 組織指派子路由
+		ar.adminUserQuotaRouter.Register(admin)
+		// 閒置用戶列表與重新啟用
+		ar.adminLifecycleRouter.Register(admin)
 	}
+
+	// 組織（團隊）與組織配額
+	ar.adminOrganizationRouter.Register(adminOnly)
+	// 上游熔斷器狀態
+	ar.adminCircuitRouter.Register(adminOnly)
+	// 提示詞樣板
+	ar.adminTemplateRouter.Register(adminOnly)
+	// 語意快取
+	ar.adminSemanticRouter.Register(adminOnly)
+	// 用量統計
+	ar.adminUsageRouter.Register(adminOnly)
+	// 預付額度
+	ar.adminCreditRouter.Register(adminOnly)
+	// 月結帳單
+	ar.adminInvoiceRouter.Register(adminOnly)
+	// 事件通知（規則與投遞紀錄）
+	ar.adminNotifyRouter.Register(adminOnly)
+	// 排程工作（列表、暫停、手動觸發與執行紀錄）
+	ar.adminJobRouter.Register(adminOnly)
+	// 稽核紀錄查詢
+	ar.adminAuditRouter.Register(adminOnly)
+	// 維護模式開關
+	ar.adminMaintenanceRouter.Register(adminOnly)
+	// 異常偵測事件與解除處置
+	ar.adminAnomalyRouter.Register(adminOnly)
 }
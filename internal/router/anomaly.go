@@ -0,0 +1,26 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminAnomalyRouter struct {
+	handler *handler.AdminAnomalyHandler
+}
+
+func NewAdminAnomalyRouter(
+	handler *handler.AdminAnomalyHandler,
+) *AdminAnomalyRouter {
+	return &AdminAnomalyRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminAnomalyRouter) Register(group *gin.RouterGroup) {
+	anomalies := group.Group("/anomalies")
+	{
+		anomalies.GET("", ar.handler.List)
+		anomalies.POST("/keys/:apiKeyID/clear", ar.handler.Clear)
+	}
+}
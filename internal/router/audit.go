@@ -0,0 +1,26 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminAuditRouter struct {
+	handler *handler.AdminAuditHandler
+}
+
+func NewAdminAuditRouter(
+	handler *handler.AdminAuditHandler,
+) *AdminAuditRouter {
+	return &AdminAuditRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminAuditRouter) Register(group *gin.RouterGroup) {
+	audit := group.Group("/audit-logs")
+	{
+		audit.GET("", ar.handler.List)
+		audit.GET("/:auditID", ar.handler.Get)
+	}
+}
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminCircuitBreakerRouter struct {
+	handler *handler.AdminCircuitBreakerHandler
+}
+
+func NewAdminCircuitBreakerRouter(
+	handler *handler.AdminCircuitBreakerHandler,
+) *AdminCircuitBreakerRouter {
+	return &AdminCircuitBreakerRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminCircuitBreakerRouter) Register(group *gin.RouterGroup) {
+	breakers := group.Group("/circuit-breakers")
+	{
+		breakers.GET("", ar.handler.List)
+		breakers.POST("/reset", ar.handler.Reset)
+	}
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminCreditRouter struct {
+	handler *handler.AdminCreditHandler
+}
+
+func NewAdminCreditRouter(
+	handler *handler.AdminCreditHandler,
+) *AdminCreditRouter {
+	return &AdminCreditRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminCreditRouter) Register(group *gin.RouterGroup) {
+	credits := group.Group("/credits/:scope/:scopeID")
+	{
+		credits.GET("", ar.handler.GetBalance)
+		credits.GET("/ledger", ar.handler.ListLedger)
+		credits.POST("/top-up", ar.handler.TopUp)
+		credits.POST("/adjust", ar.handler.Adjust)
+		credits.POST("/refund", ar.handler.Refund)
+	}
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminInvoiceRouter struct {
+	handler *handler.AdminInvoiceHandler
+}
+
+func NewAdminInvoiceRouter(
+	handler *handler.AdminInvoiceHandler,
+) *AdminInvoiceRouter {
+	return &AdminInvoiceRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminInvoiceRouter) Register(group *gin.RouterGroup) {
+	invoices := group.Group("/invoices")
+	{
+		invoices.GET("", ar.handler.List)
+		invoices.POST("", ar.handler.Generate)
+		invoices.GET("/:invoiceID", ar.handler.Get)
+	}
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminJobRouter struct {
+	handler *handler.AdminJobHandler
+}
+
+func NewAdminJobRouter(
+	handler *handler.AdminJobHandler,
+) *AdminJobRouter {
+	return &AdminJobRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminJobRouter) Register(group *gin.RouterGroup) {
+	jobs := group.Group("/jobs")
+	{
+		jobs.GET("", ar.handler.ListJobs)
+		jobs.GET("/runs", ar.handler.ListRuns)
+		jobs.GET("/runs/:runID", ar.handler.GetRun)
+		jobs.POST("/:name/pause", ar.handler.PauseJob)
+		jobs.POST("/:name/resume", ar.handler.ResumeJob)
+		jobs.POST("/:name/trigger", ar.handler.TriggerJob)
+	}
+}
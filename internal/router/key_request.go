@@ -0,0 +1,28 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminKeyRequestRouter struct {
+	handler *handler.AdminKeyRequestHandler
+}
+
+func NewAdminKeyRequestRouter(
+	handler *handler.AdminKeyRequestHandler,
+) *AdminKeyRequestRouter {
+	return &AdminKeyRequestRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminKeyRequestRouter) Register(group *gin.RouterGroup) {
+	requests := group.Group("/key-requests")
+	{
+		requests.GET("", ar.handler.List)
+		requests.GET("/:requestID", ar.handler.Get)
+		requests.POST("/:requestID/approve", ar.handler.Approve)
+		requests.POST("/:requestID/reject", ar.handler.Reject)
+	}
+}
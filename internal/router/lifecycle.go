@@ -0,0 +1,24 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminLifecycleRouter struct {
+	handler *handler.AdminLifecycleHandler
+}
+
+func NewAdminLifecycleRouter(
+	handler *handler.AdminLifecycleHandler,
+) *AdminLifecycleRouter {
+	return &AdminLifecycleRouter{handler: handler}
+}
+
+// 掛在 /admin/users group 底下
+func (ar *AdminLifecycleRouter) Register(group *gin.RouterGroup) {
+	group.GET("/inactive", ar.handler.ListInactive)
+	group.POST("/:userID/reactivate", ar.handler.ReactivateUser)
+	group.POST("/:userID/api-keys/:apiKeyID/providers/:provider/reactivate", ar.handler.ReactivateProvider)
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminMaintenanceRouter struct {
+	handler *handler.AdminMaintenanceHandler
+}
+
+func NewAdminMaintenanceRouter(
+	handler *handler.AdminMaintenanceHandler,
+) *AdminMaintenanceRouter {
+	return &AdminMaintenanceRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminMaintenanceRouter) Register(group *gin.RouterGroup) {
+	maintenance := group.Group("/maintenance")
+	{
+		maintenance.GET("", ar.handler.List)
+		maintenance.PUT("", ar.handler.Upsert)
+		maintenance.DELETE("", ar.handler.Delete)
+	}
+}
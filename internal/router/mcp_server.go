@@ -8,10 +8,12 @@ import (
 )
 
 type MCPRouter struct {
-	proxyHandler        *handler.ProxyHandler
-	apiKeyMiddleware    *middleware.APIKey
-	ratelimitMiddleware *middleware.RateLimit
-	userMiddleware      *middleware.User
+	proxyHandler          *handler.ProxyHandler
+	apiKeyMiddleware      *middleware.APIKey
+	ratelimitMiddleware   *middleware.RateLimit
+	userMiddleware        *middleware.User
+	maintenanceMiddleware *middleware.Maintenance
+	anomalyMiddleware     *middleware.Anomaly
 }
 
 func NewMCPRouter(
@@ -19,19 +21,25 @@ func NewMCPRouter(
 	apiKeyMiddleware *middleware.APIKey,
 	ratelimitMiddleware *middleware.RateLimit,
 	userMiddleware *middleware.User,
+	maintenanceMiddleware *middleware.Maintenance,
+	anomalyMiddleware *middleware.Anomaly,
 ) *MCPRouter {
 	return &MCPRouter{
-		proxyHandler:        proxyHandler,
-		apiKeyMiddleware:    apiKeyMiddleware,
-		ratelimitMiddleware: ratelimitMiddleware,
-		userMiddleware:      userMiddleware,
+		proxyHandler:          proxyHandler,
+		apiKeyMiddleware:      apiKeyMiddleware,
+		ratelimitMiddleware:   ratelimitMiddleware,
+		userMiddleware:        userMiddleware,
+		maintenanceMiddleware: maintenanceMiddleware,
+		anomalyMiddleware:     anomalyMiddleware,
 	}
 }
 
 func (MCPRouter *MCPRouter) RegisterRoutes(engine *gin.Engine) {
 	router := engine.Group("/mcp-server/:version/:provider")
 	router.Use(MCPRouter.apiKeyMiddleware.Handler())
+	router.Use(MCPRouter.maintenanceMiddleware.Handler())
 	router.Use(MCPRouter.userMiddleware.Handler())
+	router.Use(MCPRouter.anomalyMiddleware.Handler())
 	router.Use(MCPRouter.ratelimitMiddleware.Guard())
 	{
 		router.Any("/*action", MCPRouter.proxyHandler.Passthrough)
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminNotificationRouter struct {
+	handler *handler.AdminNotificationHandler
+}
+
+func NewAdminNotificationRouter(
+	handler *handler.AdminNotificationHandler,
+) *AdminNotificationRouter {
+	return &AdminNotificationRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminNotificationRouter) Register(group *gin.RouterGroup) {
+	notifications := group.Group("/notifications")
+	{
+		notifications.GET("/rules", ar.handler.ListRules)
+		notifications.POST("/rules", ar.handler.CreateRule)
+		notifications.GET("/rules/:ruleID", ar.handler.GetRule)
+		notifications.PUT("/rules/:ruleID", ar.handler.UpdateRule)
+		notifications.DELETE("/rules/:ruleID", ar.handler.DeleteRule)
+		notifications.POST("/rules/:ruleID/test", ar.handler.TestRule)
+		notifications.GET("/deliveries", ar.handler.ListDeliveries)
+		notifications.POST("/deliveries/:deliveryID/retry", ar.handler.RetryDelivery)
+	}
+}
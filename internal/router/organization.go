@@ -0,0 +1,33 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminOrganizationRouter struct {
+	handler *handler.AdminOrganizationHandler
+}
+
+func NewAdminOrganizationRouter(
+	handler *handler.AdminOrganizationHandler,
+) *AdminOrganizationRouter {
+	return &AdminOrganizationRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminOrganizationRouter) Register(group *gin.RouterGroup) {
+	organizations := group.Group("/organizations")
+	{
+		organizations.GET("", ar.handler.List)
+		organizations.POST("", ar.handler.Create)
+		organizations.GET("/:orgID", ar.handler.Get)
+		organizations.PUT("/:orgID", ar.handler.Update)
+		organizations.DELETE("/:orgID", ar.handler.Delete)
+		organizations.GET("/:orgID/quota", ar.handler.GetQuota)
+		organizations.PUT("/:orgID/quota", ar.handler.UpdateQuota)
+		organizations.DELETE("/:orgID/quota", ar.handler.DeleteQuota)
+		organizations.POST("/:orgID/quota/reset", ar.handler.ResetQuotaUsage)
+	}
+}
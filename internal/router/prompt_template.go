@@ -0,0 +1,29 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminPromptTemplateRouter struct {
+	handler *handler.AdminPromptTemplateHandler
+}
+
+func NewAdminPromptTemplateRouter(
+	handler *handler.AdminPromptTemplateHandler,
+) *AdminPromptTemplateRouter {
+	return &AdminPromptTemplateRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminPromptTemplateRouter) Register(group *gin.RouterGroup) {
+	templates := group.Group("/prompt-templates")
+	{
+		templates.GET("", ar.handler.List)
+		templates.POST("", ar.handler.Create)
+		templates.GET("/:name", ar.handler.ListVersions)
+		templates.DELETE("/:name", ar.handler.Delete)
+		templates.GET("/:name/versions/:version", ar.handler.GetVersion)
+	}
+}
@@ -9,15 +9,17 @@ import (
 )
 
 type ProxyRouter struct {
-	chatHandler         *proxy.ChatHandler
-	imageHandler        *proxy.ImageHandler
-	audioHandler        *proxy.AudioHandler
-	embeddingHandler    *proxy.EmbeddingHandler
-	modelHandler        *proxy.ModelsHandler
-	proxyHandler        *handler.ProxyHandler
-	apiKeyMiddleware    *middleware.APIKey
-	ratelimitMiddleware *middleware.RateLimit
-	userMiddleware      *middleware.User
+	chatHandler           *proxy.ChatHandler
+	imageHandler          *proxy.ImageHandler
+	audioHandler          *proxy.AudioHandler
+	embeddingHandler      *proxy.EmbeddingHandler
+	modelHandler          *proxy.ModelsHandler
+	proxyHandler          *handler.ProxyHandler
+	apiKeyMiddleware      *middleware.APIKey
+	ratelimitMiddleware   *middleware.RateLimit
+	userMiddleware        *middleware.User
+	maintenanceMiddleware *middleware.Maintenance
+	anomalyMiddleware     *middleware.Anomaly
 }
 
 func NewProxyRouter(
@@ -30,24 +32,30 @@ func NewProxyRouter(
 	apiKeyMiddleware *middleware.APIKey,
 	ratelimitMiddleware *middleware.RateLimit,
 	userMiddleware *middleware.User,
+	maintenanceMiddleware *middleware.Maintenance,
+	anomalyMiddleware *middleware.Anomaly,
 ) *ProxyRouter {
 	return &ProxyRouter{
-		chatHandler:         chatHandler,
-		imageHandler:        imageHandler,
-		audioHandler:        audioHandler,
-		embeddingHandler:    embeddingHandler,
-		modelHandler:        modelHandler,
-		proxyHandler:        proxyHandler,
-		apiKeyMiddleware:    apiKeyMiddleware,
-		ratelimitMiddleware: ratelimitMiddleware,
-		userMiddleware:      userMiddleware,
+		chatHandler:           chatHandler,
+		imageHandler:          imageHandler,
+		audioHandler:          audioHandler,
+		embeddingHandler:      embeddingHandler,
+		modelHandler:          modelHandler,
+		proxyHandler:          proxyHandler,
+		apiKeyMiddleware:      apiKeyMiddleware,
+		ratelimitMiddleware:   ratelimitMiddleware,
+		userMiddleware:        userMiddleware,
+		maintenanceMiddleware: maintenanceMiddleware,
+		anomalyMiddleware:     anomalyMiddleware,
 	}
 }
 
 func (proxyRouter *ProxyRouter) RegisterRoutes(engine *gin.Engine) {
 	router := engine.Group("/proxy/:version/:provider")
 	router.Use(proxyRouter.apiKeyMiddleware.Handler())
+	router.Use(proxyRouter.maintenanceMiddleware.Handler())
 	router.Use(proxyRouter.userMiddleware.Handler())
+	router.Use(proxyRouter.anomalyMiddleware.Handler())
 	router.Use(proxyRouter.ratelimitMiddleware.Guard())
 
 	chat := router.Group("/chat")
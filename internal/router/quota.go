@@ -0,0 +1,29 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminUserQuotaRouter struct {
+	handler *handler.AdminUserQuotaHandler
+}
+
+func NewAdminUserQuotaRouter(
+	handler *handler.AdminUserQuotaHandler,
+) *AdminUserQuotaRouter {
+	return &AdminUserQuotaRouter{handler: handler}
+}
+
+// 掛在 /admin/users group 底下
+func (ar *AdminUserQuotaRouter) Register(group *gin.RouterGroup) {
+	quota := group.Group("/:userID/quota")
+	{
+		quota.GET("", ar.handler.Get)
+		quota.PUT("", ar.handler.Update)
+		quota.DELETE("", ar.handler.Delete)
+		quota.POST("/reset", ar.handler.ResetUsage)
+	}
+	group.PATCH("/:userID/organization", ar.handler.UpdateOrganization)
+}
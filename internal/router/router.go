@@ -18,8 +18,24 @@ var ProviderSet = wire.NewSet(
 	NewRouter,
 	// NewAdminRouter,
 	// NewAdminUserAPIKeyRouter,
+	// NewAdminUserQuotaRouter,
+	// NewAdminOrganizationRouter,
+	// NewAdminCircuitBreakerRouter,
+	// NewAdminPromptTemplateRouter,
+	// NewAdminSemanticCacheRouter,
+	// NewAdminUsageRouter,
+	// NewAdminCreditRouter,
+	// NewAdminInvoiceRouter,
+	// NewAdminNotificationRouter,
+	// NewAdminJobRouter,
+	// NewAdminLifecycleRouter,
+	// NewAdminAuditRouter,
+	// NewAdminKeyRequestRouter,
+	// NewAdminMaintenanceRouter,
+	// NewAdminAnomalyRouter,
 	// NewProxyRouter,
 	// NewMCPRouter,
+	// NewAccountRouter,
 )
 
 // 透過依賴注入將
@@ -33,6 +49,7 @@ func NewRouter(
 	// adminRouter *AdminRouter,
 	// proxyRouter *ProxyRouter,
 	// mcpRouter *MCPRouter,
+	// accountRouter *AccountRouter,
 ) *gin.Engine {
 
 	switch config.App.Env {
@@ -74,6 +91,7 @@ func NewRouter(
 	// 註冊 AI Proxy 入口路由
 	// proxyRouter.RegisterRoutes(router)
 	// mcpRouter.RegisterRoutes(router)
+	// accountRouter.RegisterRoutes(router)
 	// adminRouter.RegisterRoutes(router)
 	pprof.Register(router)
 	return router
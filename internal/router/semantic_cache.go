@@ -0,0 +1,27 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminSemanticCacheRouter struct {
+	handler *handler.AdminSemanticCacheHandler
+}
+
+func NewAdminSemanticCacheRouter(
+	handler *handler.AdminSemanticCacheHandler,
+) *AdminSemanticCacheRouter {
+	return &AdminSemanticCacheRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminSemanticCacheRouter) Register(group *gin.RouterGroup) {
+	semanticCache := group.Group("/semantic-cache")
+	{
+		semanticCache.GET("", ar.handler.Stats)
+		semanticCache.DELETE("", ar.handler.InvalidateAll)
+		semanticCache.DELETE("/:namespace", ar.handler.Invalidate)
+	}
+}
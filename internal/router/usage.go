@@ -0,0 +1,25 @@
+package router
+
+import (
+	"interchange/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminUsageRouter struct {
+	handler *handler.AdminUsageHandler
+}
+
+func NewAdminUsageRouter(
+	handler *handler.AdminUsageHandler,
+) *AdminUsageRouter {
+	return &AdminUsageRouter{handler: handler}
+}
+
+// 掛在 /admin group 底下
+func (ar *AdminUsageRouter) Register(group *gin.RouterGroup) {
+	usage := group.Group("/usage")
+	{
+		usage.GET("", ar.handler.Aggregate)
+	}
+}
@@ -24,5 +24,6 @@ func (ar *AdminUserAPIKeyRouter) Register(group *gin.RouterGroup) {
 		apiKeys.POST("", ar.handler.CreateForUser)
 		apiKeys.GET("/:apiKeyID", ar.handler.GetByID)
 		apiKeys.DELETE("/:apiKeyID", ar.handler.DeleteByID)
+		apiKeys.PUT("/:apiKeyID/providers/:provider/expiry", ar.handler.RenewProviderExpiry)
 	}
 }
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const defaultActivityFlushIntervalMs = 5000
+
+// ActivityBufferService 合併請求熱路徑上的 lastSeen / usedCount 寫入，定期批次寫回 Mongo。
+// 啟用 ENTITY_CACHE 時生效；關閉時每次呼叫直接寫入。結束時會寫回尚未送出的資料。
+type ActivityBufferService struct {
+	trace          *telemetry.Trace
+	logger         *zap.Logger
+	config         *config.Configuration
+	userRepo       *mongoDb.UserRepository
+	userApiKeyRepo *mongoDb.UserAPIKeyRepository
+
+	mu       sync.Mutex
+	userSeen map[primitive.ObjectID]time.Time
+	keyUsage map[providerUsageKey]*mongoDb.ProviderUsageDelta
+}
+
+type providerUsageKey struct {
+	apiKeyID primitive.ObjectID
+	provider core.ProviderName
+}
+
+func NewActivityBufferService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	userRepo *mongoDb.UserRepository,
+	userApiKeyRepo *mongoDb.UserAPIKeyRepository,
+) (*ActivityBufferService, func()) {
+	s := &ActivityBufferService{
+		trace:          trace,
+		logger:         logger,
+		config:         config,
+		userRepo:       userRepo,
+		userApiKeyRepo: userApiKeyRepo,
+		userSeen:       make(map[primitive.ObjectID]time.Time),
+		keyUsage:       make(map[providerUsageKey]*mongoDb.ProviderUsageDelta),
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go s.run(stop, done)
+	cleanup := func() {
+		close(stop)
+		<-done
+	}
+	return s, cleanup
+}
+
+// TouchUser 記錄使用者最後使用時間
+func (s *ActivityBufferService) TouchUser(ctx context.Context, userID primitive.ObjectID) error {
+	now := time.Now().UTC()
+	if !s.config.EntityCache.Enabled {
+		_, err := s.userRepo.UpdateLastSeen(ctx, userID, now)
+		return err
+	}
+	s.mu.Lock()
+	s.userSeen[userID] = now
+	s.mu.Unlock()
+	return nil
+}
+
+// RecordKeyUsage 累加 API Key 在 provider 的已用次數與最後使用時間
+func (s *ActivityBufferService) RecordKeyUsage(ctx context.Context, apiKeyID primitive.ObjectID, provider core.ProviderName) error {
+	if !s.config.EntityCache.Enabled {
+		return s.userApiKeyRepo.IncrProviderUsedCount(ctx, apiKeyID, provider, 1)
+	}
+	now := time.Now().UTC()
+	key := providerUsageKey{apiKeyID: apiKeyID, provider: provider}
+	s.mu.Lock()
+	if delta, ok := s.keyUsage[key]; ok {
+		delta.Increment++
+		delta.LastSeen = now
+	} else {
+		s.keyUsage[key] = &mongoDb.ProviderUsageDelta{APIKeyID: apiKeyID, Provider: provider, Increment: 1, LastSeen: now}
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// run 依 FLUSH_INTERVAL_MS 定期寫回（間隔於啟動時決定）
+func (s *ActivityBufferService) run(stop, done chan struct{}) {
+	defer close(done)
+
+	interval := time.Duration(s.config.EntityCache.FlushIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultActivityFlushIntervalMs * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			s.flush()
+			return
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+// Flush 立即寫回本機累積的資料，回傳本次取出的使用者與 API Key 用量筆數
+func (s *ActivityBufferService) Flush() (users int, keys int) {
+	return s.flush()
+}
+
+// flush 取出目前累積的資料並批次寫入；整批失敗時併回下一輪（部分成功時不重送用量，避免重複累加）
+func (s *ActivityBufferService) flush() (int, int) {
+	s.mu.Lock()
+	userSeen, keyUsage := s.userSeen, s.keyUsage
+	if len(userSeen) == 0 && len(keyUsage) == 0 {
+		s.mu.Unlock()
+		return 0, 0
+	}
+	s.userSeen = make(map[primitive.ObjectID]time.Time)
+	s.keyUsage = make(map[providerUsageKey]*mongoDb.ProviderUsageDelta)
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if err := s.userRepo.BulkUpdateLastSeen(ctx, userSeen); err != nil {
+		end(err)
+		s.logger.Warn("flush user lastSeen failed", zap.Int("count", len(userSeen)), zap.Error(err))
+		s.mu.Lock()
+		for userID, seenAt := range userSeen {
+			if current, ok := s.userSeen[userID]; !ok || current.Before(seenAt) {
+				s.userSeen[userID] = seenAt
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	deltas := make([]mongoDb.ProviderUsageDelta, 0, len(keyUsage))
+	for _, delta := range keyUsage {
+		deltas = append(deltas, *delta)
+	}
+	if err := s.userApiKeyRepo.BulkIncrProviderUsage(ctx, deltas); err != nil {
+		end(err)
+		s.logger.Warn("flush api key usage failed", zap.Int("count", len(deltas)), zap.Error(err))
+		var partial mongo.BulkWriteException
+		if errors.As(err, &partial) {
+			return len(userSeen), len(keyUsage)
+		}
+		s.mu.Lock()
+		for key, delta := range keyUsage {
+			if current, ok := s.keyUsage[key]; ok {
+				current.Increment += delta.Increment
+				if current.LastSeen.Before(delta.LastSeen) {
+					current.LastSeen = delta.LastSeen
+				}
+			} else {
+				s.keyUsage[key] = delta
+			}
+		}
+		s.mu.Unlock()
+	}
+	return len(userSeen), len(keyUsage)
+}
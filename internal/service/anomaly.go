@@ -0,0 +1,666 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAnomalyWindowSeconds      = 60
+	defaultAnomalyBaselineAlpha      = 0.1
+	defaultAnomalyLearningWindows    = 60
+	defaultAnomalyRateMultiplier     = 5
+	defaultAnomalyRateMinRequests    = 30
+	defaultAnomalySeenTTLHours       = 168
+	defaultAnomalyMaxTrackedValues   = 50
+	defaultAnomalyErrorRateThreshold = 0.5
+	defaultAnomalyErrorMinRequests   = 20
+	defaultAnomalyThrottleSeconds    = 900
+	defaultAnomalyThrottleLimit      = 10
+	defaultAnomalyCooldownSeconds    = 900
+	defaultAnomalyReverifyTTLMinutes = 60
+	anomalyReverifyResendCooldown    = time.Minute
+	anomalyReverifyCodeDigits        = 8
+	anomalyActorID                   = "anomaly_detection"
+)
+
+// AnomalyRequest 一次 proxy / MCP 請求的來源資訊（由 middleware 組出）
+type AnomalyRequest struct {
+	APIKeyID  string
+	UserID    string
+	Provider  core.ProviderName
+	Endpoint  string
+	ClientIP  string
+	UserAgent string
+	Model     string
+}
+
+// AnomalyService 每把 API Key 的線上異常偵測：請求前計入速率與來源（IP / User-Agent / 模型），回應後計入錯誤率。
+// 學習期過後依訊號觸發設定的處置（log / reverify / throttle / suspend），同一訊號在冷卻時間內只處置一次；
+// 每次處置寫入異常事件，reverify / suspend 另寫稽核紀錄並通知。Redis 無法使用時不阻斷請求
+type AnomalyService struct {
+	trace        *telemetry.Trace
+	logger       *zap.Logger
+	config       *config.Configuration
+	anomalyRepo  *redisDb.AnomalyRepository
+	eventRepo    *mongoDb.AnomalyEventRepository
+	apiKeyRepo   *mongoDb.UserAPIKeyRepository
+	entityCache  *EntityCacheService
+	notification *NotificationService
+	audit        *AuditService
+}
+
+func NewAnomalyService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	anomalyRepo *redisDb.AnomalyRepository,
+	eventRepo *mongoDb.AnomalyEventRepository,
+	apiKeyRepo *mongoDb.UserAPIKeyRepository,
+	entityCache *EntityCacheService,
+	notification *NotificationService,
+	audit *AuditService,
+) *AnomalyService {
+	return &AnomalyService{
+		trace:        trace,
+		logger:       logger,
+		config:       config,
+		anomalyRepo:  anomalyRepo,
+		eventRepo:    eventRepo,
+		apiKeyRepo:   apiKeyRepo,
+		entityCache:  entityCache,
+		notification: notification,
+		audit:        audit,
+	}
+}
+
+// Enabled 是否啟用異常偵測
+func (s *AnomalyService) Enabled() bool {
+	return s.config.Anomaly.Enabled
+}
+
+// Guard 限流中的 key 超過每個視窗的上限時回傳 RateLimitExceeded 與建議的 Retry-After 秒數
+func (s *AnomalyService) Guard(ctx context.Context, request *AnomalyRequest) (int64, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	window := s.window()
+	bucket := time.Now().Unix() / int64(window.Seconds())
+	throttled, count, limit, remaining, err := s.anomalyRepo.Throttle(ctx, request.APIKeyID, bucket, window)
+	if err != nil {
+		s.logger.Warn("anomaly throttle check failed", zap.String("apiKeyID", request.APIKeyID), zap.Error(err))
+		return 0, nil
+	}
+	if !throttled || count <= limit {
+		return 0, nil
+	}
+	span.SetAttributes(attribute.Bool("anomaly.throttled", true), attribute.Int64("anomaly.throttle_count", count))
+
+	// 等到下個視窗或限流結束（取較早者）
+	retryAfter := (bucket+1)*int64(window.Seconds()) - time.Now().Unix()
+	if seconds := int64(remaining.Seconds()) + 1; seconds < retryAfter {
+		retryAfter = seconds
+	}
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return retryAfter, cErr.RateLimitExceeded(fmt.Sprintf("API key temporarily throttled due to unusual activity (%d requests per %d seconds)", limit, int64(window.Seconds())))
+}
+
+// Observe 請求前計入速率與來源並判斷異常；處置需擋下本次請求（reverify / suspend）時回傳錯誤
+func (s *AnomalyService) Observe(ctx context.Context, request *AnomalyRequest) error {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	cfg := s.config.Anomaly
+	seenTTL := time.Duration(positiveInt(cfg.SeenTTLHours, defaultAnomalySeenTTLHours)) * time.Hour
+	maxTracked := cfg.MaxTrackedValues
+	if maxTracked <= 0 {
+		maxTracked = defaultAnomalyMaxTrackedValues
+	}
+	// 速率基準與已見來源保留同樣久，長期閒置後重新進入學習期
+	rateTTL := seenTTL
+	window := s.window()
+	observation, err := s.anomalyRepo.Observe(ctx, request.APIKeyID, window, s.baselineAlpha(), rateTTL, seenTTL, maxTracked,
+		request.ClientIP, request.UserAgent, request.Model)
+	if err != nil {
+		s.logger.Warn("anomaly observe failed", zap.String("apiKeyID", request.APIKeyID), zap.Error(err))
+		return nil
+	}
+	learning := cfg.LearningWindows
+	if learning <= 0 {
+		learning = defaultAnomalyLearningWindows
+	}
+	if observation.Windows < learning {
+		return nil
+	}
+
+	type finding struct {
+		signal core.AnomalySignal
+		detail map[string]interface{}
+	}
+	findings := make([]finding, 0, 4)
+
+	minRequests := cfg.RateMinRequests
+	if minRequests <= 0 {
+		minRequests = defaultAnomalyRateMinRequests
+	}
+	multiplier := cfg.RateMultiplier
+	if multiplier <= 0 {
+		multiplier = defaultAnomalyRateMultiplier
+	}
+	if observation.Requests >= minRequests && float64(observation.Requests) > observation.Baseline*multiplier {
+		findings = append(findings, finding{core.AnomalySignalRateSpike, map[string]interface{}{
+			"requests":      observation.Requests,
+			"baseline":      observation.Baseline,
+			"multiplier":    multiplier,
+			"windowSeconds": int64(window.Seconds()),
+		}})
+	}
+	if observation.NewClientIP {
+		findings = append(findings, finding{core.AnomalySignalNewClientIP, map[string]interface{}{"clientIP": request.ClientIP}})
+	}
+	if observation.NewUserAgent {
+		findings = append(findings, finding{core.AnomalySignalNewUserAgent, map[string]interface{}{"userAgent": request.UserAgent}})
+	}
+	if observation.NewModel {
+		findings = append(findings, finding{core.AnomalySignalModelChange, map[string]interface{}{"model": request.Model}})
+	}
+
+	var blocked error
+	for _, f := range findings {
+		span.SetAttributes(attribute.String("anomaly.signal."+string(f.signal), "detected"))
+		if err := s.handle(ctx, request, f.signal, f.detail); err != nil && blocked == nil {
+			blocked = err
+		}
+	}
+	return blocked
+}
+
+// ObserveResult 回應後計入錯誤率（failed 為上游或 5xx 錯誤）；錯誤率暴增時處置（本次請求已完成，不回傳錯誤）
+func (s *AnomalyService) ObserveResult(ctx context.Context, request *AnomalyRequest, failed bool) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	cfg := s.config.Anomaly
+	window := s.window()
+	bucket := time.Now().Unix() / int64(window.Seconds())
+	total, failures, err := s.anomalyRepo.ObserveResult(ctx, request.APIKeyID, bucket, failed, 2*window)
+	if err != nil {
+		s.logger.Warn("anomaly observe result failed", zap.String("apiKeyID", request.APIKeyID), zap.Error(err))
+		return
+	}
+	if !failed {
+		return
+	}
+	minRequests := cfg.ErrorMinRequests
+	if minRequests <= 0 {
+		minRequests = defaultAnomalyErrorMinRequests
+	}
+	threshold := cfg.ErrorRateThreshold
+	if threshold <= 0 {
+		threshold = defaultAnomalyErrorRateThreshold
+	}
+	if total < minRequests || float64(failures)/float64(total) < threshold {
+		return
+	}
+	_ = s.handle(ctx, request, core.AnomalySignalErrorBurst, map[string]interface{}{
+		"requests":      total,
+		"errors":        failures,
+		"errorRate":     float64(failures) / float64(total),
+		"threshold":     threshold,
+		"windowSeconds": int64(window.Seconds()),
+	})
+}
+
+// Reverify 擁有者以驗證碼解除重新驗證標記；驗證碼只能嘗試一次，錯誤或過期需重新寄送
+func (s *AnomalyService) Reverify(ctx context.Context, apiKeyID primitive.ObjectID, code string) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	key, err := s.getAPIKey(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		return err
+	}
+	if key.SuspendReason != core.SuspendReasonReverify {
+		return cErr.Conflict("api key does not require re-verification")
+	}
+	stored, err := s.anomalyRepo.TakeReverifyCode(ctx, apiKeyID.Hex())
+	if err != nil {
+		end(err)
+		return cErr.DatabaseError("redis get reverify code failed")
+	}
+	if stored == "" || subtle.ConstantTimeCompare([]byte(stored), []byte(hashReverifyCode(code))) != 1 {
+		return cErr.Forbidden("invalid or expired verification code; request a new one")
+	}
+	return s.clearReverify(ctx, AuditActor{Type: core.AuditActorUser, ID: key.UserID.Hex()}, key, "verified by owner")
+}
+
+// ResendReverifyCode 重新產生驗證碼並通知擁有者（每分鐘最多一次）
+func (s *AnomalyService) ResendReverifyCode(ctx context.Context, apiKeyID primitive.ObjectID) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	key, err := s.getAPIKey(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		return err
+	}
+	if key.SuspendReason != core.SuspendReasonReverify {
+		return cErr.Conflict("api key does not require re-verification")
+	}
+	first, err := s.anomalyRepo.MarkOnce(ctx, apiKeyID.Hex(), "reverify_resend", anomalyReverifyResendCooldown)
+	if err != nil {
+		end(err)
+		return cErr.DatabaseError("redis mark reverify resend failed")
+	}
+	if !first {
+		return cErr.RateLimitExceeded("verification code was sent recently; please wait before requesting another")
+	}
+	if err := s.sendReverifyCode(ctx, key, "", ""); err != nil {
+		end(err)
+		return err
+	}
+	return nil
+}
+
+// Clear 管理端解除限流與重新驗證標記
+func (s *AnomalyService) Clear(ctx context.Context, apiKeyID primitive.ObjectID) (*dto.AnomalyClearResultDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	key, err := s.getAPIKey(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	result := &dto.AnomalyClearResultDto{}
+	result.ThrottleCleared, err = s.anomalyRepo.ClearThrottle(ctx, apiKeyID.Hex())
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("redis clear throttle failed")
+	}
+	if key.SuspendReason == core.SuspendReasonReverify {
+		if _, err := s.anomalyRepo.TakeReverifyCode(ctx, apiKeyID.Hex()); err != nil {
+			s.logger.Warn("drop reverify code failed", zap.String("apiKeyID", apiKeyID.Hex()), zap.Error(err))
+		}
+		if err := s.clearReverify(ctx, AuditActor{}, key, "cleared by administrator"); err != nil {
+			end(err)
+			return nil, err
+		}
+		result.ReverifyCleared = true
+	}
+	return result, nil
+}
+
+// List 查詢異常事件（新到舊，page 為 0 起算）
+func (s *AnomalyService) List(ctx context.Context, query *dto.AnomalyQueryDto) ([]*dto.AnomalyEventDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	from, err := parseUsageTime(query.From)
+	if err != nil {
+		return nil, cErr.BadRequestParams("invalid from: " + err.Error())
+	}
+	to, err := parseUsageTime(query.To)
+	if err != nil {
+		return nil, cErr.BadRequestParams("invalid to: " + err.Error())
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, cErr.BadRequestParams("from must be earlier than to")
+	}
+
+	filter := bson.M{}
+	for field, value := range map[string]string{"apiKeyID": query.APIKeyID, "userID": query.UserID} {
+		if value == "" {
+			continue
+		}
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, cErr.BadRequestParams("invalid " + field)
+		}
+		filter[field] = id
+	}
+	for field, value := range map[string]string{"provider": query.Provider, "signal": query.Signal, "action": query.Action} {
+		if value != "" {
+			filter[field] = value
+		}
+	}
+	if !from.IsZero() || !to.IsZero() {
+		createdAt := bson.M{}
+		if !from.IsZero() {
+			createdAt["$gte"] = from
+		}
+		if !to.IsZero() {
+			createdAt["$lt"] = to
+		}
+		filter["createdAt"] = createdAt
+	}
+	events, err := s.eventRepo.List(ctx, core.ListOptions{Filter: filter, Page: query.Page, Size: query.Size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListAnomalyEvents error")
+	}
+	results := make([]*dto.AnomalyEventDto, 0, len(events))
+	for _, event := range events {
+		results = append(results, modelToAnomalyEventDto(event))
+	}
+	return results, nil
+}
+
+// handle 依訊號設定的處置執行（冷卻時間內略過）；需擋下本次請求時回傳錯誤
+func (s *AnomalyService) handle(ctx context.Context, request *AnomalyRequest, signal core.AnomalySignal, detail map[string]interface{}) error {
+	cooldown := time.Duration(positiveInt(s.config.Anomaly.CooldownSeconds, defaultAnomalyCooldownSeconds)) * time.Second
+	first, err := s.anomalyRepo.MarkOnce(ctx, request.APIKeyID, string(signal), cooldown)
+	if err != nil {
+		s.logger.Warn("anomaly cooldown check failed", zap.String("apiKeyID", request.APIKeyID), zap.Error(err))
+		return nil
+	}
+	if !first {
+		return nil
+	}
+
+	action := s.action(signal)
+	fields := []zap.Field{
+		zap.String("apiKeyID", request.APIKeyID),
+		zap.String("userID", request.UserID),
+		zap.String("provider", string(request.Provider)),
+		zap.String("endpoint", request.Endpoint),
+		zap.String("signal", string(signal)),
+		zap.String("action", string(action)),
+		zap.String("clientIP", request.ClientIP),
+		zap.String("userAgent", request.UserAgent),
+		zap.String("model", request.Model),
+		zap.Any("detail", detail),
+	}
+	s.logger.Warn("[Anomaly] unusual api key activity detected", fields...)
+
+	apiKeyID, _ := primitive.ObjectIDFromHex(request.APIKeyID)
+	userID, _ := primitive.ObjectIDFromHex(request.UserID)
+	applied := true
+	var blocked error
+	switch action {
+	case core.AnomalyActionThrottle:
+		ttl := time.Duration(positiveInt(s.config.Anomaly.ThrottleSeconds, defaultAnomalyThrottleSeconds)) * time.Second
+		limit := s.config.Anomaly.ThrottleLimit
+		if limit <= 0 {
+			limit = defaultAnomalyThrottleLimit
+		}
+		if err := s.anomalyRepo.SetThrottle(ctx, request.APIKeyID, limit, ttl); err != nil {
+			s.logger.Warn("anomaly throttle failed", zap.String("apiKeyID", request.APIKeyID), zap.Error(err))
+			applied = false
+		}
+	case core.AnomalyActionSuspend:
+		if applied = s.suspendProvider(ctx, apiKeyID, request.Provider, signal); applied {
+			blocked = cErr.Forbidden("Provider access suspended due to unusual activity")
+		}
+	case core.AnomalyActionReverify:
+		if applied = s.requireReverify(ctx, apiKeyID, request, signal); applied {
+			blocked = cErr.Forbidden("API key requires re-verification after unusual activity")
+		}
+	}
+
+	if err := s.eventRepo.Insert(ctx, &model.AnomalyEvent{
+		APIKeyID:  apiKeyID,
+		UserID:    userID,
+		Provider:  request.Provider,
+		Endpoint:  request.Endpoint,
+		Signal:    signal,
+		Action:    action,
+		Applied:   applied,
+		Detail:    detail,
+		ClientIP:  request.ClientIP,
+		UserAgent: request.UserAgent,
+		Model:     request.Model,
+	}); err != nil {
+		s.logger.Warn("write anomaly event failed", zap.String("apiKeyID", request.APIKeyID), zap.Error(err))
+	}
+
+	// reverify 的通知帶驗證碼，已於 requireReverify 送出
+	if action == core.AnomalyActionThrottle || action == core.AnomalyActionSuspend {
+		s.emit(ctx, NotificationMessage{
+			Event:   core.NotificationEventKeyAnomaly,
+			Subject: fmt.Sprintf("API key %s: %s detected, action %s", request.APIKeyID, signal, action),
+			KeyID:   request.APIKeyID,
+			UserID:  request.UserID,
+			Data:    anomalyNotificationData(request, signal, action, detail),
+		})
+	}
+	return blocked
+}
+
+// suspendProvider 觸發請求的 provider 存取改為 suspended（原因 anomaly）
+func (s *AnomalyService) suspendProvider(ctx context.Context, apiKeyID primitive.ObjectID, provider core.ProviderName, signal core.AnomalySignal) bool {
+	suspended, err := s.apiKeyRepo.SuspendProviderAccess(ctx, apiKeyID, provider, core.SuspendReasonAnomaly)
+	if err != nil {
+		s.logger.Error("anomaly suspend provider access failed", zap.String("apiKeyID", apiKeyID.Hex()), zap.Error(err))
+		return false
+	}
+	if !suspended {
+		return false
+	}
+	s.entityCache.InvalidateAPIKey(ctx, apiKeyID)
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      AuditActor{Type: core.AuditActorSystem, ID: anomalyActorID},
+		Action:     core.AuditActionProviderAnomalySuspended,
+		TargetType: core.AuditTargetProviderAccess,
+		TargetID:   apiKeyID.Hex(),
+		Provider:   provider,
+		Before:     map[string]interface{}{"status": core.StatusActive},
+		After:      map[string]interface{}{"status": core.StatusSuspended, "suspendReason": core.SuspendReasonAnomaly},
+		Reason:     string(signal),
+	})
+	return true
+}
+
+// requireReverify 標記整把 key 需重新驗證並將驗證碼通知擁有者；已有其他停用標記時不覆寫
+func (s *AnomalyService) requireReverify(ctx context.Context, apiKeyID primitive.ObjectID, request *AnomalyRequest, signal core.AnomalySignal) bool {
+	now := time.Now().UTC()
+	flagged, err := s.apiKeyRepo.SetSuspendFlag(ctx, apiKeyID, core.SuspendReasonReverify, now)
+	if err != nil {
+		s.logger.Error("anomaly flag api key failed", zap.String("apiKeyID", apiKeyID.Hex()), zap.Error(err))
+		return false
+	}
+	if !flagged {
+		return false
+	}
+	s.entityCache.InvalidateAPIKey(ctx, apiKeyID)
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      AuditActor{Type: core.AuditActorSystem, ID: anomalyActorID},
+		Action:     core.AuditActionAPIKeyReverifyRequired,
+		TargetType: core.AuditTargetAPIKey,
+		TargetID:   apiKeyID.Hex(),
+		After:      map[string]interface{}{"suspendReason": core.SuspendReasonReverify, "suspendedAt": now},
+		Reason:     string(signal),
+	})
+
+	key, err := s.getAPIKey(ctx, apiKeyID)
+	if err != nil {
+		s.logger.Warn("load flagged api key failed", zap.String("apiKeyID", apiKeyID.Hex()), zap.Error(err))
+		return true
+	}
+	if err := s.sendReverifyCode(ctx, key, signal, request.Provider); err != nil {
+		s.logger.Warn("send reverify code failed", zap.String("apiKeyID", apiKeyID.Hex()), zap.Error(err))
+	}
+	return true
+}
+
+// sendReverifyCode 產生新驗證碼（只存雜湊）並只通知擁有者：驗證碼以 Secret 帶出，只送明確指定該使用者或該 key 的規則，
+// 也不留在投遞紀錄；沒有這類規則時改寄到使用者信箱，兩者皆無時回傳錯誤（需由管理端解除）
+func (s *AnomalyService) sendReverifyCode(ctx context.Context, key *model.UserAPIKey, signal core.AnomalySignal, provider core.ProviderName) error {
+	code, err := newReverifyCode()
+	if err != nil {
+		return cErr.InternalServer("generate verification code failed")
+	}
+	ttl := time.Duration(positiveInt(s.config.Anomaly.ReverifyCodeTTLMinutes, defaultAnomalyReverifyTTLMinutes)) * time.Minute
+	if err := s.anomalyRepo.SetReverifyCode(ctx, key.ID.Hex(), hashReverifyCode(code), ttl); err != nil {
+		return cErr.DatabaseError("redis set reverify code failed")
+	}
+	data := map[string]interface{}{
+		"keyName":   key.KeyName,
+		"action":    core.AnomalyActionReverify,
+		"expiresAt": time.Now().UTC().Add(ttl),
+	}
+	if signal != "" {
+		data["signal"] = signal
+		data["provider"] = provider
+	}
+	return s.notification.Emit(ctx, NotificationMessage{
+		Event:   core.NotificationEventKeyAnomaly,
+		Subject: fmt.Sprintf("API key %s requires re-verification after unusual activity", key.KeyName),
+		Scope:   core.NotificationScopeUser,
+		KeyID:   key.ID.Hex(),
+		UserID:  key.UserID.Hex(),
+		Data:    data,
+		Secret:  map[string]interface{}{"reverifyCode": code},
+	})
+}
+
+// clearReverify 移除重新驗證標記
+func (s *AnomalyService) clearReverify(ctx context.Context, actor AuditActor, key *model.UserAPIKey, reason string) error {
+	cleared, err := s.apiKeyRepo.ClearSuspendFlag(ctx, key.ID, core.SuspendReasonReverify)
+	if err != nil {
+		return cErr.DatabaseError("mongodb clear reverify flag failed")
+	}
+	if !cleared {
+		return cErr.Conflict("api key does not require re-verification")
+	}
+	s.entityCache.InvalidateAPIKey(ctx, key.ID)
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      actor,
+		Action:     core.AuditActionAPIKeyReverified,
+		TargetType: core.AuditTargetAPIKey,
+		TargetID:   key.ID.Hex(),
+		Before:     map[string]interface{}{"suspendReason": key.SuspendReason, "suspendedAt": key.SuspendedAt},
+		Reason:     reason,
+	})
+	return nil
+}
+
+func (s *AnomalyService) emit(ctx context.Context, message NotificationMessage) {
+	if err := s.notification.Emit(ctx, message); err != nil {
+		s.logger.Warn("emit anomaly notification failed", zap.String("apiKeyID", message.KeyID), zap.Error(err))
+	}
+}
+
+func (s *AnomalyService) getAPIKey(ctx context.Context, id primitive.ObjectID) (*model.UserAPIKey, error) {
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("api key with id %s not found", id.Hex()))
+		}
+		return nil, cErr.DatabaseError("database GetAPIKey error")
+	}
+	return key, nil
+}
+
+// action 訊號設定的處置；未設定或無法辨識時只記錄
+func (s *AnomalyService) action(signal core.AnomalySignal) core.AnomalyAction {
+	cfg := s.config.Anomaly
+	configured := map[core.AnomalySignal]string{
+		core.AnomalySignalRateSpike:    cfg.RateSpikeAction,
+		core.AnomalySignalNewClientIP:  cfg.NewClientIPAction,
+		core.AnomalySignalNewUserAgent: cfg.NewUserAgentAction,
+		core.AnomalySignalModelChange:  cfg.ModelChangeAction,
+		core.AnomalySignalErrorBurst:   cfg.ErrorBurstAction,
+	}[signal]
+	switch action := core.AnomalyAction(configured); action {
+	case core.AnomalyActionReverify, core.AnomalyActionThrottle, core.AnomalyActionSuspend:
+		return action
+	}
+	return core.AnomalyActionLog
+}
+
+func (s *AnomalyService) window() time.Duration {
+	return time.Duration(positiveInt(s.config.Anomaly.WindowSeconds, defaultAnomalyWindowSeconds)) * time.Second
+}
+
+func (s *AnomalyService) baselineAlpha() float64 {
+	if alpha := s.config.Anomaly.BaselineAlpha; alpha > 0 && alpha <= 1 {
+		return alpha
+	}
+	return defaultAnomalyBaselineAlpha
+}
+
+func positiveInt(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+func newReverifyCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < anomalyReverifyCodeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", anomalyReverifyCodeDigits, n), nil
+}
+
+func hashReverifyCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func anomalyNotificationData(request *AnomalyRequest, signal core.AnomalySignal, action core.AnomalyAction, detail map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		"signal":    signal,
+		"action":    action,
+		"provider":  request.Provider,
+		"endpoint":  request.Endpoint,
+		"clientIP":  request.ClientIP,
+		"userAgent": request.UserAgent,
+	}
+	if request.Model != "" {
+		data["model"] = request.Model
+	}
+	for key, value := range detail {
+		data[key] = value
+	}
+	return data
+}
+
+func modelToAnomalyEventDto(m *model.AnomalyEvent) *dto.AnomalyEventDto {
+	return &dto.AnomalyEventDto{
+		ID:        m.ID.Hex(),
+		APIKeyID:  m.APIKeyID.Hex(),
+		UserID:    m.UserID.Hex(),
+		Provider:  m.Provider,
+		Endpoint:  m.Endpoint,
+		Signal:    m.Signal,
+		Action:    m.Action,
+		Applied:   m.Applied,
+		Detail:    m.Detail,
+		ClientIP:  m.ClientIP,
+		UserAgent: m.UserAgent,
+		Model:     m.Model,
+		CreatedAt: m.CreatedAt,
+	}
+}
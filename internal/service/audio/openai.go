@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"interchange/internal/core"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/telemetry"
 	"io"
 	"mime/multipart"
@@ -18,11 +19,12 @@ import (
 
 type OpenAIService struct {
 	HTTPClient *http.Client
+	clients    *outbound.Clients
 	trace      *telemetry.Trace
 }
 
-func NewOpenAIService(trace *telemetry.Trace, client *http.Client) Service {
-	return &OpenAIService{HTTPClient: client, trace: trace}
+func NewOpenAIService(trace *telemetry.Trace, clients *outbound.Clients) Service {
+	return &OpenAIService{HTTPClient: clients.For(core.ProviderOpenAI), clients: clients, trace: trace}
 }
 
 // AudioTranscriptionsV1 呼叫 OpenAI /audio/transcriptions。
@@ -31,9 +33,13 @@ func NewOpenAIService(trace *telemetry.Trace, client *http.Client) Service {
 //   - 對外請求/非 2xx：ExternalRequestError
 //   - 回應解析失敗：ExternalResponseFormatError
 func (s *OpenAIService) AudioTranscriptionsV1(ctx context.Context, req *AudioTranscriptionRequestBody, apiKey string) (*AudioTranscriptionResponse, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1" + string(core.OpenAITranscriptionEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAITranscriptionEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.audio.transcriptions")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -123,13 +129,16 @@ func (s *OpenAIService) AudioTranscriptionsV1(ctx context.Context, req *AudioTra
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai api request failed")
+		return nil, outbound.RequestError(err, "openai api request failed")
 	}
 	defer resp.Body.Close()
 
@@ -155,9 +164,13 @@ func (s *OpenAIService) AudioTranscriptionsV1(ctx context.Context, req *AudioTra
 // AudioSpeechV1 呼叫 OpenAI /audio/speech。
 // 失敗分類同上；讀取音訊資料失敗 -> ExternalResponseFormatError。
 func (s *OpenAIService) AudioSpeechV1(ctx context.Context, req *AudioSpeechRequestBody, apiKey string) (*AudioSpeechResponse, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1" + string(core.OpenAISpeechEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAISpeechEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.audio.speech")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -215,14 +228,17 @@ func (s *OpenAIService) AudioSpeechV1(ctx context.Context, req *AudioSpeechReque
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// 3) 請求
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai api request failed")
+		return nil, outbound.RequestError(err, "openai api request failed")
 	}
 	defer resp.Body.Close()
 
@@ -253,9 +269,13 @@ func (s *OpenAIService) AudioSpeechV1(ctx context.Context, req *AudioSpeechReque
 // AudioTranslationsV1 呼叫 OpenAI /audio/translations。
 // 非 text 格式以 JSON 解碼 { "text": "..." }。
 func (s *OpenAIService) AudioTranslationsV1(ctx context.Context, req *AudioTranslationRequestBody, apiKey string) (string, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1" + string(core.OpenAITranslationEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAITranslationEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.audio.translations")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return "", cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -316,14 +336,17 @@ func (s *OpenAIService) AudioTranslationsV1(ctx context.Context, req *AudioTrans
 		end(err)
 		return "", cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return "", cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// 3) 請求
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return "", cErr.ExternalRequestError("openai api request failed")
+		return "", outbound.RequestError(err, "openai api request failed")
 	}
 	defer resp.Body.Close()
 
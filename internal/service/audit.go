@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync/atomic"
+
+	"interchange/config"
+	"interchange/internal/core"
+	fluentdModel "interchange/internal/database/fluentd/model"
+	fluentdDb "interchange/internal/database/fluentd/repository"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 管理端操作缺少操作者身分時寫入的標記
+const auditUnknownActor = "unknown"
+
+// 不比對的欄位（每次寫入都會變動）
+var auditIgnoredFields = map[string]struct{}{
+	"updatedAt": {},
+}
+
+// 需遮罩的欄位（API Key 與上游 key 不落地明碼）
+var auditMaskedFields = map[string]struct{}{
+	"keyValue":    {},
+	"providerKey": {},
+}
+
+type auditRequestKey struct{}
+
+// AuditRequest 由 HTTP middleware 放入 context 的來源資訊；service 寫稽核紀錄時自動帶入
+type AuditRequest struct {
+	Actor     AuditActor
+	ClientIP  string
+	UserAgent string
+	recorded  int32 // 此請求已寫入的稽核筆數
+}
+
+// WithAuditRequest 將來源資訊放入 context
+func WithAuditRequest(ctx context.Context, request *AuditRequest) context.Context {
+	return context.WithValue(ctx, auditRequestKey{}, request)
+}
+
+// AuditRequestFromContext 取出來源資訊（非 HTTP 觸發時為 nil）
+func AuditRequestFromContext(ctx context.Context) *AuditRequest {
+	request, _ := ctx.Value(auditRequestKey{}).(*AuditRequest)
+	return request
+}
+
+// Recorded 此請求是否已由 service 寫入稽核紀錄
+func (r *AuditRequest) Recorded() bool {
+	return atomic.LoadInt32(&r.recorded) > 0
+}
+
+// AuditActor 操作者（排程以工作名稱作為 ID）
+type AuditActor struct {
+	Type core.AuditActorType
+	ID   string
+}
+
+// AuditEntry 一筆待寫入的稽核紀錄；Before / After 可為 model、DTO 或 map，寫入時轉為欄位差異
+type AuditEntry struct {
+	Actor      AuditActor
+	Action     core.AuditAction
+	TargetType core.AuditTargetType
+	TargetID   string
+	Provider   core.ProviderName
+	Before     interface{}
+	After      interface{}
+	Reason     string
+}
+
+// AuditService 稽核紀錄：只新增不修改，寫入失敗只記 log，不影響原本的操作
+type AuditService struct {
+	trace         *telemetry.Trace
+	logger        *zap.Logger
+	config        *config.Configuration
+	auditRepo     *mongoDb.AuditLogRepository
+	logRepository *fluentdDb.LogRepository
+}
+
+func NewAuditService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	auditRepo *mongoDb.AuditLogRepository,
+	logRepository *fluentdDb.LogRepository,
+) *AuditService {
+	return &AuditService{
+		trace:         trace,
+		logger:        logger,
+		config:        config,
+		auditRepo:     auditRepo,
+		logRepository: logRepository,
+	}
+}
+
+// Record 寫入一筆稽核紀錄；未指定操作者時取 context 內的 HTTP 來源，都沒有則視為系統。
+// 有 Before / After 但比對後沒有差異時不寫入。
+func (s *AuditService) Record(ctx context.Context, entry AuditEntry) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	changes := diffAuditSnapshots(entry.Before, entry.After)
+	if (entry.Before != nil || entry.After != nil) && len(changes) == 0 {
+		return
+	}
+
+	request := AuditRequestFromContext(ctx)
+	actor := entry.Actor
+	if actor.Type == "" {
+		if request != nil {
+			actor = request.Actor
+		} else {
+			actor = AuditActor{Type: core.AuditActorSystem}
+		}
+	}
+	if actor.Type == core.AuditActorAdmin && actor.ID == "" {
+		// 管理端操作應已由 Admin middleware 驗證身分；缺少時仍寫入，但標示並告警以便追查
+		actor.ID = auditUnknownActor
+		s.logger.Warn("audit entry without admin identity", zap.String("action", string(entry.Action)))
+	}
+	log := &model.AuditLog{
+		ActorType:  actor.Type,
+		Actor:      actor.ID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Provider:   entry.Provider,
+		Changes:    changes,
+		Reason:     entry.Reason,
+	}
+	if request != nil {
+		log.ClientIP = request.ClientIP
+		log.UserAgent = request.UserAgent
+		atomic.AddInt32(&request.recorded, 1)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		traceID := spanContext.TraceID()
+		log.RequestID = fmt.Sprintf("%x", traceID[:])
+	}
+	if err := s.auditRepo.Insert(ctx, log); err != nil {
+		end(err)
+		s.logger.Error("write audit log failed",
+			zap.String("action", string(entry.Action)),
+			zap.String("targetType", string(entry.TargetType)),
+			zap.String("targetID", entry.TargetID),
+			zap.Error(err),
+		)
+		return
+	}
+	if s.config.Audit.ForwardFluentd {
+		if err := s.logRepository.LogAudit(ctx, toFluentdAuditLog(log)); err != nil {
+			s.logger.Warn("forward audit log to fluentd failed", zap.String("auditID", log.ID.Hex()), zap.Error(err))
+		}
+	}
+}
+
+// List 查詢稽核紀錄（新到舊，page 為 0 起算）
+func (s *AuditService) List(ctx context.Context, query *dto.AuditQueryDto) ([]*dto.AuditLogDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	from, err := parseUsageTime(query.From)
+	if err != nil {
+		return nil, cErr.BadRequestParams("invalid from: " + err.Error())
+	}
+	to, err := parseUsageTime(query.To)
+	if err != nil {
+		return nil, cErr.BadRequestParams("invalid to: " + err.Error())
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, cErr.BadRequestParams("from must be earlier than to")
+	}
+
+	filter := bson.M{}
+	for field, value := range map[string]string{
+		"actorType":  query.ActorType,
+		"actor":      query.Actor,
+		"action":     query.Action,
+		"targetType": query.TargetType,
+		"targetID":   query.TargetID,
+		"provider":   query.Provider,
+		"requestID":  query.RequestID,
+	} {
+		if value != "" {
+			filter[field] = value
+		}
+	}
+	if !from.IsZero() || !to.IsZero() {
+		createdAt := bson.M{}
+		if !from.IsZero() {
+			createdAt["$gte"] = from
+		}
+		if !to.IsZero() {
+			createdAt["$lt"] = to
+		}
+		filter["createdAt"] = createdAt
+	}
+	entries, err := s.auditRepo.List(ctx, core.ListOptions{Filter: filter, Page: query.Page, Size: query.Size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListAuditLogs error")
+	}
+	results := make([]*dto.AuditLogDto, 0, len(entries))
+	for _, entry := range entries {
+		results = append(results, modelToAuditLogDto(entry))
+	}
+	return results, nil
+}
+
+// GetByID 單筆稽核紀錄
+func (s *AuditService) GetByID(ctx context.Context, id primitive.ObjectID) (*dto.AuditLogDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	entry, err := s.auditRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("audit log with id %s not found", id.Hex()))
+		}
+		end(err)
+		return nil, cErr.DatabaseError("database GetAuditLog error")
+	}
+	return modelToAuditLogDto(entry), nil
+}
+
+// diffAuditSnapshots 將前後快照展平後逐欄比對
+func diffAuditSnapshots(before, after interface{}) []model.AuditChange {
+	beforeFields := flattenAuditSnapshot(before)
+	afterFields := flattenAuditSnapshot(after)
+
+	fields := make([]string, 0, len(beforeFields)+len(afterFields))
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]model.AuditChange, 0)
+	for _, field := range fields {
+		beforeValue, afterValue := beforeFields[field], afterFields[field]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, model.AuditChange{Field: field, Before: beforeValue, After: afterValue})
+	}
+	return changes
+}
+
+// flattenAuditSnapshot 以 JSON 欄位名稱展平：巢狀物件以 "." 串接，含 provider 欄位的陣列以 provider 名稱作為索引
+func flattenAuditSnapshot(snapshot interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if snapshot == nil || (reflect.ValueOf(snapshot).Kind() == reflect.Ptr && reflect.ValueOf(snapshot).IsNil()) {
+		return fields
+	}
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fields
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(b, &root); err != nil {
+		return fields
+	}
+	flattenAuditValue(fields, "", root)
+	return fields
+}
+
+func flattenAuditValue(fields map[string]interface{}, prefix string, value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if _, ignored := auditIgnoredFields[key]; ignored {
+				continue
+			}
+			field := key
+			if prefix != "" {
+				field = prefix + "." + key
+			}
+			if _, masked := auditMaskedFields[key]; masked {
+				if text, ok := child.(string); ok && text != "" {
+					child = maskKey(text)
+				}
+			}
+			flattenAuditValue(fields, field, child)
+		}
+	case []interface{}:
+		keyed := make(map[string]interface{}, len(typed))
+		for _, item := range typed {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				fields[prefix] = value
+				return
+			}
+			provider, ok := object["provider"].(string)
+			if !ok || provider == "" {
+				fields[prefix] = value
+				return
+			}
+			keyed[provider] = object
+		}
+		if len(keyed) == 0 {
+			fields[prefix] = value
+			return
+		}
+		flattenAuditValue(fields, prefix, keyed)
+	default:
+		fields[prefix] = value
+	}
+}
+
+func toFluentdAuditLog(m *model.AuditLog) fluentdModel.AuditLog {
+	changes := make([]fluentdModel.AuditChange, 0, len(m.Changes))
+	for _, change := range m.Changes {
+		changes = append(changes, fluentdModel.AuditChange{Field: change.Field, Before: change.Before, After: change.After})
+	}
+	return fluentdModel.AuditLog{
+		AuditID:    m.ID.Hex(),
+		RequestID:  m.RequestID,
+		ActorType:  string(m.ActorType),
+		Actor:      m.Actor,
+		Action:     string(m.Action),
+		TargetType: string(m.TargetType),
+		TargetID:   m.TargetID,
+		Provider:   string(m.Provider),
+		Changes:    changes,
+		Reason:     m.Reason,
+		ClientIP:   m.ClientIP,
+		UserAgent:  m.UserAgent,
+		CreatedAt:  m.CreatedAt.UTC().Format(usageLoggedAtLayout),
+	}
+}
+
+func modelToAuditLogDto(m *model.AuditLog) *dto.AuditLogDto {
+	changes := make([]dto.AuditChangeDto, 0, len(m.Changes))
+	for _, change := range m.Changes {
+		changes = append(changes, dto.AuditChangeDto{Field: change.Field, Before: change.Before, After: change.After})
+	}
+	return &dto.AuditLogDto{
+		ID:         m.ID.Hex(),
+		ActorType:  m.ActorType,
+		Actor:      m.Actor,
+		Action:     m.Action,
+		TargetType: m.TargetType,
+		TargetID:   m.TargetID,
+		Provider:   m.Provider,
+		Changes:    changes,
+		Reason:     m.Reason,
+		ClientIP:   m.ClientIP,
+		UserAgent:  m.UserAgent,
+		RequestID:  m.RequestID,
+		CreatedAt:  m.CreatedAt,
+	}
+}
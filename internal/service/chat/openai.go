@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"interchange/internal/core"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/telemetry"
 	"io"
 	"net/http"
@@ -17,15 +18,16 @@ import (
 
 type OpenAIService struct {
 	HTTPClient *http.Client
+	clients    *outbound.Clients
 	trace      *telemetry.Trace
 }
 
 // NewOpenAIService 建立 OpenAIService
 func NewOpenAIService(
 	trace *telemetry.Trace,
-	client *http.Client,
+	clients *outbound.Clients,
 ) Service {
-	return &OpenAIService{HTTPClient: client, trace: trace}
+	return &OpenAIService{HTTPClient: clients.For(core.ProviderOpenAI), clients: clients, trace: trace}
 }
 
 // ChatCompletionsV1 呼叫 OpenAI Chat Completions v1。
@@ -34,9 +36,13 @@ func NewOpenAIService(
 //   - 回應解碼失敗：ExternalResponseFormatError
 //   - 本地序列化/建請失敗：InternalServer
 func (s *OpenAIService) ChatCompletionsV1(ctx context.Context, req *ChatPayload, apiKey string) (*ChatResult, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1" + string(core.OpenAiChatEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAiChatEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.chat.completions")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -55,13 +61,16 @@ func (s *OpenAIService) ChatCompletionsV1(ctx context.Context, req *ChatPayload,
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai api request failed")
+		return nil, outbound.RequestError(err, "openai api request failed")
 	}
 	defer resp.Body.Close()
 
@@ -0,0 +1,527 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
+	"interchange/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCircuitWindowSeconds     = 60
+	defaultCircuitMinRequests       = 20
+	defaultCircuitFailureRate       = 0.5
+	defaultCircuitOpenSeconds       = 30
+	defaultCircuitHalfOpenMaxProbes = 3
+	circuitBucketCount              = 10
+	circuitSharedCacheTTL           = time.Second
+)
+
+// ErrCircuitOpen 熔斷中，上游請求未送出（上游 client 以 outbound.RequestError 轉為 ServiceUnavailable）
+var ErrCircuitOpen = outbound.ErrCircuitOpen
+
+// CircuitBreakerService 依 provider + endpoint 追蹤上游結果與延遲並熔斷。
+// RateLimit.Guard 以 Allow 預先拒絕熔斷中的請求（不佔探測名額）；半開探測名額由 Transport 在實際送出上游前取得，
+// 結果於每次上游嘗試後回報。兩者皆以 outbound.EndpointKey 分組。
+type CircuitBreakerService struct {
+	trace       *telemetry.Trace
+	logger      *zap.Logger
+	config      *config.Configuration
+	circuitRepo *redisDb.CircuitBreakerRepository
+	notifier    *NotificationService
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func NewCircuitBreakerService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	circuitRepo *redisDb.CircuitBreakerRepository,
+	notifier *NotificationService,
+) *CircuitBreakerService {
+	return &CircuitBreakerService{
+		trace:       trace,
+		logger:      logger,
+		config:      config,
+		circuitRepo: circuitRepo,
+		notifier:    notifier,
+		breakers:    make(map[string]*circuitBreaker),
+	}
+}
+
+// circuitBreaker 單一 provider + endpoint 的狀態
+type circuitBreaker struct {
+	mu              sync.Mutex
+	provider        core.ProviderName
+	endpoint        string
+	state           core.CircuitState
+	openedAt        time.Time
+	halfOpenAt      time.Time
+	probeAttempts   int    // 半開期間 Transport 實際送出的探測數
+	probeSuccesses  int    // 本輪探測的成功數
+	generation      uint64 // 每次轉為半開遞增；探測結果須帶相同值才計入
+	buckets         [circuitBucketCount]circuitBucket
+	lastFailureAt   time.Time
+	lastFailure     string
+	sharedOpenUntil time.Time
+	sharedCheckedAt time.Time
+}
+
+// circuitBucket 統計視窗中的一格
+type circuitBucket struct {
+	start     int64 // 該格起始時間（unix 秒，對齊格寬）
+	requests  int
+	failures  int
+	slowCalls int
+	latency   time.Duration
+}
+
+// Allow 預先判斷是否放行（不佔半開探測名額）；熔斷中或本輪探測名額已用完時回傳 ServiceUnavailable 與建議的 Retry-After 秒數
+func (s *CircuitBreakerService) Allow(ctx context.Context, provider core.ProviderName, endpoint string) (int64, error) {
+	if !s.config.CircuitBreaker.Enabled {
+		return 0, nil
+	}
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(
+		attribute.String("circuit.provider", string(provider)),
+		attribute.String("circuit.endpoint", endpoint),
+	)
+
+	breaker := s.breaker(provider, endpoint)
+	now := time.Now()
+
+	breaker.mu.Lock()
+	s.advance(breaker, now)
+	state := breaker.state
+	retryAfter := s.retryAfter(breaker, now)
+	switch state {
+	case core.CircuitStateOpen:
+		breaker.mu.Unlock()
+		return s.reject(span, end, provider, endpoint, state, retryAfter)
+	case core.CircuitStateHalfOpen:
+		if breaker.probeAttempts >= s.halfOpenMaxProbes() {
+			breaker.mu.Unlock()
+			return s.reject(span, end, provider, endpoint, state, retryAfter)
+		}
+		breaker.mu.Unlock()
+		span.SetAttributes(attribute.Bool("circuit.probe", true))
+		return 0, nil
+	}
+	breaker.mu.Unlock()
+
+	// 其他 replica 已熔斷
+	if remaining := s.sharedOpenRemaining(ctx, breaker, now); remaining > 0 {
+		return s.reject(span, end, provider, endpoint, core.CircuitStateOpen, ceilSeconds(remaining))
+	}
+	return 0, nil
+}
+
+// Record 回報一次上游結果（連線錯誤與 5xx 視為失敗，超過門檻的延遲視為慢請求）。
+// probe 為送出時取得的探測代號（0 代表非探測）；半開狀態只依本輪探測的結果轉換
+func (s *CircuitBreakerService) Record(provider core.ProviderName, endpoint string, probe uint64, statusCode int, latency time.Duration, err error) {
+	if !s.config.CircuitBreaker.Enabled {
+		return
+	}
+	failed := err != nil || statusCode >= http.StatusInternalServerError
+	slowThreshold := time.Duration(s.config.CircuitBreaker.SlowCallThresholdMs) * time.Millisecond
+	slow := slowThreshold > 0 && latency > slowThreshold
+
+	breaker := s.breaker(provider, endpoint)
+	now := time.Now()
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	s.advance(breaker, now)
+
+	bucket := s.bucket(breaker, now)
+	bucket.requests++
+	bucket.latency += latency
+	if failed {
+		bucket.failures++
+		breaker.lastFailureAt = now
+		if err != nil {
+			breaker.lastFailure = err.Error()
+		} else {
+			breaker.lastFailure = fmt.Sprintf("upstream status %d", statusCode)
+		}
+	}
+	if slow {
+		bucket.slowCalls++
+	}
+
+	switch breaker.state {
+	case core.CircuitStateHalfOpen:
+		if probe == 0 || probe != breaker.generation {
+			// 轉為半開前已送出或上一輪的請求，不影響半開判斷
+			return
+		}
+		if failed || slow {
+			s.trip(breaker, now)
+			return
+		}
+		breaker.probeSuccesses++
+		if breaker.probeSuccesses >= s.halfOpenMaxProbes() {
+			s.close(breaker)
+		}
+	case core.CircuitStateClosed:
+		requests, failures, slowCalls, _ := breaker.totals(now, s.windowSeconds())
+		if requests >= s.minRequests() && float64(failures+slowCalls)/float64(requests) >= s.failureRateThreshold() {
+			s.trip(breaker, now)
+		}
+	}
+}
+
+// Transport 包裝 RoundTripper：熔斷中直接拒絕，否則記錄每次上游嘗試的結果與延遲
+func (s *CircuitBreakerService) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if !s.config.CircuitBreaker.Enabled {
+			return next.RoundTrip(req)
+		}
+		provider := outbound.ProviderFromURL(s.config, req.URL.String())
+		endpoint := outbound.EndpointKeyFromURL(s.config, provider, req.URL)
+		probe, ok := s.admit(provider, endpoint)
+		if !ok {
+			return nil, ErrCircuitOpen
+		}
+
+		startedAt := time.Now()
+		resp, err := next.RoundTrip(req)
+		if err != nil && req.Context().Err() != nil && !errors.Is(context.Cause(req.Context()), outbound.ErrTimeout) {
+			// 呼叫端取消不代表上游異常；出站逾時則視為失敗
+			return resp, err
+		}
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		s.Record(provider, endpoint, probe, statusCode, time.Since(startedAt), err)
+		return resp, err
+	})
+}
+
+// States 列出所有熔斷器狀態（admin 用）
+func (s *CircuitBreakerService) States(ctx context.Context) []dto.CircuitBreakerStateDto {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	s.mu.Lock()
+	breakers := make([]*circuitBreaker, 0, len(s.breakers))
+	for _, breaker := range s.breakers {
+		breakers = append(breakers, breaker)
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+	states := make([]dto.CircuitBreakerStateDto, 0, len(breakers))
+	for _, breaker := range breakers {
+		sharedOpen := s.sharedOpenRemaining(ctx, breaker, now) > 0
+
+		breaker.mu.Lock()
+		s.advance(breaker, now)
+		requests, failures, slowCalls, latency := breaker.totals(now, s.windowSeconds())
+		state := dto.CircuitBreakerStateDto{
+			Provider:        breaker.provider,
+			Endpoint:        breaker.endpoint,
+			State:           breaker.state,
+			Requests:        requests,
+			Failures:        failures,
+			SlowCalls:       slowCalls,
+			SharedOpen:      sharedOpen,
+			LastFailureText: breaker.lastFailure,
+		}
+		if requests > 0 {
+			state.ErrorRate = float64(failures) / float64(requests)
+			state.AvgLatencyMs = float64(latency.Milliseconds()) / float64(requests)
+		}
+		if breaker.state != core.CircuitStateClosed {
+			openedAt := breaker.openedAt.UTC()
+			state.OpenedAt = &openedAt
+			state.RetryAfterSec = s.retryAfter(breaker, now)
+		}
+		if !breaker.lastFailureAt.IsZero() {
+			lastFailureAt := breaker.lastFailureAt.UTC()
+			state.LastFailureAt = &lastFailureAt
+		}
+		breaker.mu.Unlock()
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Provider != states[j].Provider {
+			return states[i].Provider < states[j].Provider
+		}
+		return states[i].Endpoint < states[j].Endpoint
+	})
+	return states
+}
+
+// Reset 手動關閉熔斷並清除統計（含共享狀態）
+func (s *CircuitBreakerService) Reset(ctx context.Context, provider core.ProviderName, endpoint string) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	s.mu.Lock()
+	breaker, ok := s.breakers[circuitKey(provider, endpoint)]
+	s.mu.Unlock()
+	if !ok {
+		return cErr.NotFound("circuit breaker not found")
+	}
+
+	breaker.mu.Lock()
+	s.close(breaker)
+	breaker.buckets = [circuitBucketCount]circuitBucket{}
+	breaker.sharedOpenUntil = time.Time{}
+	breaker.mu.Unlock()
+
+	if s.config.CircuitBreaker.SharedState {
+		if err := s.circuitRepo.Clear(ctx, provider, endpoint); err != nil {
+			end(err)
+			return cErr.DatabaseError("redis clear circuit state failed")
+		}
+	}
+	return nil
+}
+
+// admit 依本機狀態決定是否送出上游嘗試（Transport 用，不查 Redis）：熔斷中拒絕；
+// 半開時最多送出 halfOpenMaxProbes 次探測並回傳本輪探測代號，其餘拒絕
+func (s *CircuitBreakerService) admit(provider core.ProviderName, endpoint string) (uint64, bool) {
+	breaker := s.breaker(provider, endpoint)
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	s.advance(breaker, time.Now())
+	switch breaker.state {
+	case core.CircuitStateOpen:
+		return 0, false
+	case core.CircuitStateHalfOpen:
+		if breaker.probeAttempts >= s.halfOpenMaxProbes() {
+			return 0, false
+		}
+		breaker.probeAttempts++
+		return breaker.generation, true
+	}
+	return 0, true
+}
+
+func (s *CircuitBreakerService) reject(
+	span trace.Span,
+	end func(error),
+	provider core.ProviderName,
+	endpoint string,
+	state core.CircuitState,
+	retryAfter int64,
+) (int64, error) {
+	span.SetAttributes(attribute.String("circuit.state", string(state)))
+	cause := cErr.ServiceUnavailable(fmt.Sprintf("upstream %s %s is temporarily unavailable (circuit %s)", provider, endpoint, state))
+	end(cause)
+	return retryAfter, cause
+}
+
+// breaker 取得（或建立）熔斷器
+func (s *CircuitBreakerService) breaker(provider core.ProviderName, endpoint string) *circuitBreaker {
+	key := circuitKey(provider, endpoint)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	breaker, ok := s.breakers[key]
+	if !ok {
+		breaker = &circuitBreaker{provider: provider, endpoint: endpoint, state: core.CircuitStateClosed}
+		s.breakers[key] = breaker
+	}
+	return breaker
+}
+
+// advance 熔斷時間屆滿時轉為半開；半開過久仍無結果（探測請求未送達上游）則重新放行探測（需持有 breaker.mu）
+func (s *CircuitBreakerService) advance(breaker *circuitBreaker, now time.Time) {
+	switch breaker.state {
+	case core.CircuitStateOpen:
+		if now.Before(breaker.openedAt.Add(s.openDuration())) {
+			return
+		}
+	case core.CircuitStateHalfOpen:
+		if now.Before(breaker.halfOpenAt.Add(s.openDuration())) {
+			return
+		}
+	default:
+		return
+	}
+	breaker.state = core.CircuitStateHalfOpen
+	breaker.halfOpenAt = now
+	breaker.generation++
+	breaker.probeAttempts = 0
+	breaker.probeSuccesses = 0
+}
+
+// trip 轉為熔斷並同步至 Redis（需持有 breaker.mu）
+func (s *CircuitBreakerService) trip(breaker *circuitBreaker, now time.Time) {
+	breaker.state = core.CircuitStateOpen
+	breaker.openedAt = now
+	breaker.probeAttempts = 0
+	breaker.probeSuccesses = 0
+	s.logger.Warn("circuit breaker opened",
+		zap.String("provider", string(breaker.provider)),
+		zap.String("endpoint", breaker.endpoint),
+		zap.String("lastFailure", breaker.lastFailure),
+	)
+	s.notifier.NotifyUpstreamFailure(breaker.provider, breaker.endpoint, breaker.lastFailure)
+	if s.config.CircuitBreaker.SharedState {
+		provider, endpoint, openDuration := breaker.provider, breaker.endpoint, s.openDuration()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			if err := s.circuitRepo.Open(ctx, provider, endpoint, openDuration); err != nil {
+				s.logger.Warn("circuit breaker share state failed", zap.Error(err))
+			}
+		}()
+	}
+}
+
+// close 恢復正常並清除統計（需持有 breaker.mu）
+func (s *CircuitBreakerService) close(breaker *circuitBreaker) {
+	if breaker.state != core.CircuitStateClosed {
+		s.logger.Info("circuit breaker closed",
+			zap.String("provider", string(breaker.provider)),
+			zap.String("endpoint", breaker.endpoint),
+		)
+	}
+	breaker.state = core.CircuitStateClosed
+	breaker.probeAttempts = 0
+	breaker.probeSuccesses = 0
+	breaker.buckets = [circuitBucketCount]circuitBucket{}
+}
+
+// sharedOpenRemaining 查詢 Redis 共享熔斷狀態（本機快取 1 秒；Redis 異常視為未熔斷）
+func (s *CircuitBreakerService) sharedOpenRemaining(ctx context.Context, breaker *circuitBreaker, now time.Time) time.Duration {
+	if !s.config.CircuitBreaker.SharedState {
+		return 0
+	}
+	breaker.mu.Lock()
+	if now.Sub(breaker.sharedCheckedAt) < circuitSharedCacheTTL {
+		until := breaker.sharedOpenUntil
+		breaker.mu.Unlock()
+		return until.Sub(now)
+	}
+	breaker.mu.Unlock()
+
+	remaining, err := s.circuitRepo.GetOpen(ctx, breaker.provider, breaker.endpoint)
+	if err != nil {
+		s.logger.Warn("circuit breaker read shared state failed", zap.Error(err))
+		remaining = 0
+	}
+
+	breaker.mu.Lock()
+	breaker.sharedCheckedAt = now
+	breaker.sharedOpenUntil = now.Add(remaining)
+	breaker.mu.Unlock()
+	return remaining
+}
+
+// bucket 取得目前時間所屬的統計格（過期格會被重置；需持有 breaker.mu）
+func (s *CircuitBreakerService) bucket(breaker *circuitBreaker, now time.Time) *circuitBucket {
+	width := s.bucketWidth()
+	start := now.Unix() / width * width
+	bucket := &breaker.buckets[(now.Unix()/width)%circuitBucketCount]
+	if bucket.start != start {
+		*bucket = circuitBucket{start: start}
+	}
+	return bucket
+}
+
+// totals 加總視窗內的統計（需持有 breaker.mu）
+func (breaker *circuitBreaker) totals(now time.Time, windowSeconds int64) (requests, failures, slowCalls int, latency time.Duration) {
+	cutoff := now.Unix() - windowSeconds
+	for _, bucket := range breaker.buckets {
+		if bucket.start <= cutoff {
+			continue
+		}
+		requests += bucket.requests
+		failures += bucket.failures
+		slowCalls += bucket.slowCalls
+		latency += bucket.latency
+	}
+	return requests, failures, slowCalls, latency
+}
+
+// retryAfter 距離下一次半開試探的秒數（需持有 breaker.mu）
+func (s *CircuitBreakerService) retryAfter(breaker *circuitBreaker, now time.Time) int64 {
+	if breaker.state != core.CircuitStateOpen {
+		return 1
+	}
+	return ceilSeconds(breaker.openedAt.Add(s.openDuration()).Sub(now))
+}
+
+func (s *CircuitBreakerService) windowSeconds() int64 {
+	if seconds := s.config.CircuitBreaker.WindowSeconds; seconds > 0 {
+		return int64(seconds)
+	}
+	return defaultCircuitWindowSeconds
+}
+
+func (s *CircuitBreakerService) bucketWidth() int64 {
+	width := s.windowSeconds() / circuitBucketCount
+	if width < 1 {
+		width = 1
+	}
+	return width
+}
+
+func (s *CircuitBreakerService) minRequests() int {
+	if count := s.config.CircuitBreaker.MinRequests; count > 0 {
+		return count
+	}
+	return defaultCircuitMinRequests
+}
+
+func (s *CircuitBreakerService) failureRateThreshold() float64 {
+	if rate := s.config.CircuitBreaker.FailureRateThreshold; rate > 0 && rate <= 1 {
+		return rate
+	}
+	return defaultCircuitFailureRate
+}
+
+func (s *CircuitBreakerService) openDuration() time.Duration {
+	seconds := s.config.CircuitBreaker.OpenSeconds
+	if seconds <= 0 {
+		seconds = defaultCircuitOpenSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (s *CircuitBreakerService) halfOpenMaxProbes() int {
+	if probes := s.config.CircuitBreaker.HalfOpenMaxProbes; probes > 0 {
+		return probes
+	}
+	return defaultCircuitHalfOpenMaxProbes
+}
+
+func circuitKey(provider core.ProviderName, endpoint string) string {
+	return string(provider) + " " + endpoint
+}
+
+// ceilSeconds 無條件進位為秒（至少 1）
+func ceilSeconds(d time.Duration) int64 {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	redisDb "interchange/internal/database/redis/repository"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/service/chat"
+	"interchange/internal/service/embedding"
+	"interchange/internal/telemetry"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultCoalesceLockTTLMs      = 30000
+	defaultCoalesceResultTTLMs    = 5000
+	defaultCoalescePollIntervalMs = 50
+)
+
+// 合併角色（metric outcome）
+const (
+	coalesceLeader   = "leader"   // 實際呼叫上游
+	coalesceLocal    = "local"    // 共用同程序內 leader 的結果
+	coalesceRemote   = "remote"   // 共用其他 replica leader 的結果
+	coalesceFallback = "fallback" // 等不到其他 replica 的結果，自行呼叫
+)
+
+// CoalescingService 合併相同的冪等上游呼叫：同程序內以 singleflight 共用一次呼叫；
+// 啟用 Distributed 時由取得 Redis 鎖的 replica 呼叫上游，其他 replica 輪詢結果。
+// 只負責上游呼叫，各請求的扣額與記帳仍由呼叫端各自處理。
+type CoalescingService struct {
+	trace        *telemetry.Trace
+	logger       *zap.Logger
+	config       *config.Configuration
+	metric       *telemetry.Metric
+	coalesceRepo *redisDb.CoalesceRepository
+	group        singleflight.Group
+}
+
+func NewCoalescingService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	metric *telemetry.Metric,
+	coalesceRepo *redisDb.CoalesceRepository,
+) *CoalescingService {
+	return &CoalescingService{
+		trace:        trace,
+		logger:       logger,
+		config:       config,
+		metric:       metric,
+		coalesceRepo: coalesceRepo,
+	}
+}
+
+// ModelsKey 模型列表（依上游金鑰區分，不同帳號可見模型不同）
+func (s *CoalescingService) ModelsKey(provider core.ProviderName, providerKey string) string {
+	return "models:" + string(provider) + ":" + keyFingerprint(providerKey)
+}
+
+// ChatKey 只合併確定性 chat 請求；其他請求回傳空字串（不合併）
+func (s *CoalescingService) ChatKey(provider core.ProviderName, providerKey string, payload *chat.ChatPayload) string {
+	key, ok := deterministicChatKey(provider, payload)
+	if !ok {
+		return ""
+	}
+	return key + ":" + keyFingerprint(providerKey)
+}
+
+// EmbeddingKey embedding 請求
+func (s *CoalescingService) EmbeddingKey(provider core.ProviderName, providerKey string, payload *embedding.EmbeddingRequestBody) string {
+	key, ok := embeddingRequestKey(provider, payload)
+	if !ok {
+		return ""
+	}
+	return key + ":" + keyFingerprint(providerKey)
+}
+
+// Do 以 key 合併呼叫 fn，結果寫入 out（與 fn 回傳值同型別的指標；各請求取得獨立副本）。
+// 未啟用或 key 為空字串時直接呼叫；shared=true 表示結果來自其他請求的上游呼叫。
+// leader 的上游呼叫不隨發起請求取消，以免連帶中斷其他等待者。
+func (s *CoalescingService) Do(
+	ctx context.Context,
+	provider core.ProviderName,
+	key string,
+	out interface{},
+	fn func(context.Context) (interface{}, error),
+) (bool, error) {
+	if !s.config.Coalescing.Enabled || key == "" {
+		result, err := fn(ctx)
+		if err != nil {
+			return false, err
+		}
+		return false, assignResult(out, result)
+	}
+
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	leader := false
+	value, err, _ := s.group.Do(key, func() (interface{}, error) {
+		leader = true
+		return s.execute(context.WithoutCancel(ctx), provider, key, fn)
+	})
+	if err != nil {
+		if !leader {
+			s.record(provider, coalesceLocal)
+		}
+		return !leader, err
+	}
+
+	outcome := value.(*coalesceResult)
+	shared := !leader || outcome.role == coalesceRemote
+	if !leader {
+		s.record(provider, coalesceLocal)
+	}
+	span.SetAttributes(attribute.Bool("coalesce.shared", shared))
+	if err := json.Unmarshal(outcome.body, out); err != nil {
+		end(err)
+		return shared, cErr.InternalServer("decode coalesced result failed")
+	}
+	return shared, nil
+}
+
+// coalesceResult leader 的執行結果（序列化後共用）
+type coalesceResult struct {
+	body []byte
+	role string
+}
+
+// execute 由程序內 leader 執行：未啟用跨 replica 時直接呼叫；否則競爭 Redis 鎖，失敗者等待結果
+func (s *CoalescingService) execute(ctx context.Context, provider core.ProviderName, key string, fn func(context.Context) (interface{}, error)) (*coalesceResult, error) {
+	if !s.config.Coalescing.Distributed {
+		return s.call(ctx, provider, coalesceLeader, fn)
+	}
+
+	token := uuid.NewString()
+	acquired, err := s.coalesceRepo.Acquire(ctx, key, token, s.lockTTL())
+	if err != nil {
+		// Redis 異常時不合併
+		s.logger.Warn("coalesce lock failed", zap.String("key", key), zap.Error(err))
+		return s.call(ctx, provider, coalesceFallback, fn)
+	}
+	if acquired {
+		defer func() {
+			if err := s.coalesceRepo.Release(ctx, key, token); err != nil {
+				s.logger.Warn("coalesce unlock failed", zap.String("key", key), zap.Error(err))
+			}
+		}()
+		result, err := s.call(ctx, provider, coalesceLeader, fn)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.coalesceRepo.SetResult(ctx, key, result.body, s.resultTTL()); err != nil {
+			s.logger.Warn("coalesce publish failed", zap.String("key", key), zap.Error(err))
+		}
+		return result, nil
+	}
+
+	if body := s.wait(ctx, key); body != nil {
+		s.record(provider, coalesceRemote)
+		return &coalesceResult{body: body, role: coalesceRemote}, nil
+	}
+	return s.call(ctx, provider, coalesceFallback, fn)
+}
+
+// wait 輪詢其他 replica 的結果；leader 失敗（鎖釋放但無結果）或逾時回傳 nil
+func (s *CoalescingService) wait(ctx context.Context, key string) []byte {
+	deadline := time.Now().Add(s.lockTTL())
+	ticker := time.NewTicker(s.pollInterval())
+	defer ticker.Stop()
+
+	for time.Now().Before(deadline) {
+		<-ticker.C
+		body, err := s.coalesceRepo.GetResult(ctx, key)
+		if err != nil {
+			return nil
+		}
+		if body != nil {
+			return body
+		}
+		locked, err := s.coalesceRepo.Locked(ctx, key)
+		if err != nil || !locked {
+			// 鎖釋放與結果寫入之間可能有時間差，再確認一次
+			body, _ = s.coalesceRepo.GetResult(ctx, key)
+			return body
+		}
+	}
+	return nil
+}
+
+// call 呼叫上游並序列化結果
+func (s *CoalescingService) call(ctx context.Context, provider core.ProviderName, role string, fn func(context.Context) (interface{}, error)) (*coalesceResult, error) {
+	s.record(provider, role)
+	result, err := fn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, cErr.InternalServer("encode coalesced result failed")
+	}
+	return &coalesceResult{body: body, role: role}, nil
+}
+
+func (s *CoalescingService) record(provider core.ProviderName, outcome string) {
+	if s.metric == nil || s.metric.CoalescedRequests == nil {
+		return
+	}
+	s.metric.CoalescedRequests.WithLabelValues(string(provider), outcome).Inc()
+}
+
+func (s *CoalescingService) lockTTL() time.Duration {
+	ms := s.config.Coalescing.LockTTLMs
+	if ms <= 0 {
+		ms = defaultCoalesceLockTTLMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (s *CoalescingService) resultTTL() time.Duration {
+	ms := s.config.Coalescing.ResultTTLMs
+	if ms <= 0 {
+		ms = defaultCoalesceResultTTLMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (s *CoalescingService) pollInterval() time.Duration {
+	ms := s.config.Coalescing.PollIntervalMs
+	if ms <= 0 {
+		ms = defaultCoalescePollIntervalMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// assignResult 未合併時直接把 fn 回傳的指標內容寫入 out
+func assignResult(out interface{}, result interface{}) error {
+	target := reflect.ValueOf(out)
+	source := reflect.ValueOf(result)
+	if target.Kind() != reflect.Ptr || source.Kind() != reflect.Ptr || source.IsNil() || source.Type() != target.Type() {
+		return cErr.InternalServer("unexpected upstream result type")
+	}
+	target.Elem().Set(source.Elem())
+	return nil
+}
+
+// keyFingerprint 上游金鑰指紋（不落地明文）
+func keyFingerprint(providerKey string) string {
+	sum := sha256.Sum256([]byte(providerKey))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	redisDb "interchange/internal/database/redis/repository"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSemaphoreLeaseSeconds   = 30
+	defaultSemaphorePollIntervalMs = 100
+	defaultQueueTimeoutMs          = 10000
+)
+
+// ConcurrencyService 以 Redis semaphore 限制 API Key 與上游 provider key 的同時請求數
+type ConcurrencyService struct {
+	trace         *telemetry.Trace
+	logger        *zap.Logger
+	config        *config.Configuration
+	semaphoreRepo *redisDb.SemaphoreRepository
+}
+
+func NewConcurrencyService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	semaphoreRepo *redisDb.SemaphoreRepository,
+) *ConcurrencyService {
+	return &ConcurrencyService{
+		trace:         trace,
+		logger:        logger,
+		config:        config,
+		semaphoreRepo: semaphoreRepo,
+	}
+}
+
+// semaphoreSlot 單一層級的槽位
+type semaphoreSlot struct {
+	scope   core.SemaphoreScope
+	scopeID string
+	limit   int
+}
+
+// Acquire 依 ProviderAccess.Concurrency 取得所有層級（key → upstream）的槽位。
+// 回傳的 release 必須在請求結束時呼叫；未設定上限或 Redis 異常時回傳 no-op（不阻斷主流程）。
+func (s *ConcurrencyService) Acquire(ctx context.Context, apiKeyID string, providerAccess *model.ProviderAccess) (func(), error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	noop := func() {}
+	slots := concurrencySlots(apiKeyID, providerAccess)
+	if len(slots) == 0 {
+		return noop, nil
+	}
+	policy := providerAccess.Concurrency
+
+	token := uuid.NewString()
+	lease := s.leaseDuration()
+	var deadline time.Time
+	if policy.Mode == core.ConcurrencyModeQueue {
+		deadline = time.Now().Add(s.queueTimeout(policy))
+	}
+	span.SetAttributes(
+		attribute.String("concurrency.mode", string(policy.Mode)),
+		attribute.Int("concurrency.slots", len(slots)),
+	)
+
+	for {
+		blocked, err := s.tryAcquire(ctx, slots, token, lease)
+		if err != nil {
+			// 風險控制：Redis 異常不阻斷主流程
+			s.logger.Warn("semaphore acquire failed", zap.String("apiKeyID", apiKeyID), zap.Error(err))
+			return noop, nil
+		}
+		if blocked == nil {
+			return s.holdSlots(slots, token, lease), nil
+		}
+
+		if policy.Mode != core.ConcurrencyModeQueue || !time.Now().Before(deadline) {
+			span.SetAttributes(attribute.String("concurrency.blocked_scope", string(blocked.scope)))
+			cause := cErr.RateLimitExceeded(fmt.Sprintf("concurrency limit exceeded: %s", blocked.scope))
+			end(cause)
+			return noop, cause
+		}
+
+		select {
+		case <-ctx.Done():
+			cause := cErr.GatewayTimeout("request cancelled while waiting for concurrency slot")
+			end(cause)
+			return noop, cause
+		case <-time.After(s.pollInterval()):
+		}
+	}
+}
+
+// tryAcquire 依序取得所有槽位；任一層級已滿則釋放已取得者並回傳該層級
+func (s *ConcurrencyService) tryAcquire(ctx context.Context, slots []semaphoreSlot, token string, lease time.Duration) (*semaphoreSlot, error) {
+	for i := range slots {
+		acquired, _, err := s.semaphoreRepo.Acquire(ctx, slots[i].scope, slots[i].scopeID, token, slots[i].limit, lease)
+		if err != nil || !acquired {
+			s.releaseSlots(slots[:i], token)
+			if err != nil {
+				return nil, err
+			}
+			return &slots[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// holdSlots 於持有期間定期續約（租約的 1/3），並回傳只會執行一次的 release
+func (s *ConcurrencyService) holdSlots(slots []semaphoreSlot, token string, lease time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				for _, slot := range slots {
+					refreshCtx, cancel := context.WithTimeout(context.Background(), lease/3)
+					if _, err := s.semaphoreRepo.Refresh(refreshCtx, slot.scope, slot.scopeID, token, lease); err != nil {
+						s.logger.Warn("semaphore refresh failed", zap.String("scope", string(slot.scope)), zap.Error(err))
+					}
+					cancel()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			s.releaseSlots(slots, token)
+		})
+	}
+}
+
+// releaseSlots 釋放槽位；使用獨立 context，避免請求取消後無法釋放
+func (s *ConcurrencyService) releaseSlots(slots []semaphoreSlot, token string) {
+	for _, slot := range slots {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		if err := s.semaphoreRepo.Release(ctx, slot.scope, slot.scopeID, token); err != nil {
+			s.logger.Warn("semaphore release failed", zap.String("scope", string(slot.scope)), zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+func (s *ConcurrencyService) leaseDuration() time.Duration {
+	seconds := s.config.Concurrency.LeaseSeconds
+	if seconds <= 0 {
+		seconds = defaultSemaphoreLeaseSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (s *ConcurrencyService) pollInterval() time.Duration {
+	ms := s.config.Concurrency.PollIntervalMs
+	if ms <= 0 {
+		ms = defaultSemaphorePollIntervalMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (s *ConcurrencyService) queueTimeout(policy *model.ConcurrencyPolicy) time.Duration {
+	if policy.QueueTimeoutMs != nil && *policy.QueueTimeoutMs >= 0 {
+		return time.Duration(*policy.QueueTimeoutMs) * time.Millisecond
+	}
+	ms := s.config.Concurrency.DefaultQueueTimeoutMs
+	if ms <= 0 {
+		ms = defaultQueueTimeoutMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// concurrencySlots 依設定組出需取得的槽位（key 層級在前，upstream 在後）
+func concurrencySlots(apiKeyID string, providerAccess *model.ProviderAccess) []semaphoreSlot {
+	if providerAccess == nil || providerAccess.Concurrency == nil {
+		return nil
+	}
+	policy := providerAccess.Concurrency
+	slots := make([]semaphoreSlot, 0, 2)
+	if policy.MaxInFlight != nil && *policy.MaxInFlight > 0 {
+		slots = append(slots, semaphoreSlot{
+			scope:   core.SemaphoreScopeKey,
+			scopeID: apiKeyID + ":" + string(providerAccess.Provider),
+			limit:   *policy.MaxInFlight,
+		})
+	}
+	if policy.UpstreamMaxInFlight != nil && *policy.UpstreamMaxInFlight > 0 && providerAccess.ProviderKey != "" {
+		slots = append(slots, semaphoreSlot{
+			scope:   core.SemaphoreScopeUpstream,
+			scopeID: string(providerAccess.Provider) + ":" + upstreamKeyID(providerAccess.ProviderKey),
+			limit:   *policy.UpstreamMaxInFlight,
+		})
+	}
+	return slots
+}
+
+// upstreamKeyID 以雜湊識別上游 provider key，避免真實憑證寫入 Redis
+func upstreamKeyID(providerKey string) string {
+	sum := sha256.Sum256([]byte(providerKey))
+	return hex.EncodeToString(sum[:8])
+}
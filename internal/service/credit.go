@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// maxCreditApplyAttempts 同一冪等鍵被沖銷後最多重新套用的次數
+const maxCreditApplyAttempts = 5
+
+// CreditService 預付額度：帳本只新增分錄，餘額以原子 $inc 維護。
+// 使用者所屬組織有帳戶時由組織扣款，否則使用者自己的帳戶；兩者皆無時不受預付限制。
+// 分錄先以冪等鍵寫入再累加餘額，重送不會重複扣款。
+type CreditService struct {
+	trace            *telemetry.Trace
+	logger           *zap.Logger
+	config           *config.Configuration
+	accountRepo      *mongoDb.CreditAccountRepository
+	ledgerRepo       *mongoDb.CreditLedgerRepository
+	organizationRepo *mongoDb.OrganizationRepository
+	quotaService     *QuotaService
+	entityCache      *EntityCacheService
+}
+
+func NewCreditService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	accountRepo *mongoDb.CreditAccountRepository,
+	ledgerRepo *mongoDb.CreditLedgerRepository,
+	organizationRepo *mongoDb.OrganizationRepository,
+	quotaService *QuotaService,
+	entityCache *EntityCacheService,
+) *CreditService {
+	return &CreditService{
+		trace:            trace,
+		logger:           logger,
+		config:           config,
+		accountRepo:      accountRepo,
+		ledgerRepo:       ledgerRepo,
+		organizationRepo: organizationRepo,
+		quotaService:     quotaService,
+		entityCache:      entityCache,
+	}
+}
+
+// Guard 請求前檢查扣款帳戶餘額；餘額不高於 MinBalance 時回傳 InsufficientCredit。
+// 回傳的帳戶供呼叫端寫入標頭（無帳戶時為 nil）；讀取錯誤不阻斷主流程。
+func (s *CreditService) Guard(ctx context.Context, userID string) (*model.CreditAccount, error) {
+	if !s.config.Credit.Enabled {
+		return nil, nil
+	}
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	account, err := s.payer(ctx, userID)
+	if err != nil {
+		span.SetAttributes(attribute.String("credit.check_error", err.Error()))
+		return nil, nil
+	}
+	if account == nil {
+		return nil, nil
+	}
+	span.SetAttributes(
+		attribute.String("credit.scope", string(account.Scope)),
+		attribute.Float64("credit.balance", account.Balance),
+	)
+	if account.Balance <= s.config.Credit.MinBalance {
+		return account, cErr.InsufficientCredit(fmt.Sprintf("%s credit balance insufficient", account.Scope))
+	}
+	return account, nil
+}
+
+// Debit 依 Pricing 計價扣款；同一 requestID 只扣一次，成本為 0 或無帳戶時略過
+func (s *CreditService) Debit(ctx context.Context, userID, requestID, modelName string, inputTokens, outputTokens int) error {
+	if !s.config.Credit.Enabled || requestID == "" {
+		return nil
+	}
+	cost := s.quotaService.EstimateCost(modelName, inputTokens, outputTokens)
+	if cost <= 0 {
+		return nil
+	}
+
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	account, err := s.payer(ctx, userID)
+	if err != nil {
+		end(err)
+		return err
+	}
+	if account == nil {
+		return nil
+	}
+	span.SetAttributes(
+		attribute.String("credit.scope", string(account.Scope)),
+		attribute.String("credit.request_id", requestID),
+		attribute.Float64("credit.cost", cost),
+	)
+
+	entry := &model.CreditLedgerEntry{
+		Scope:          account.Scope,
+		ScopeID:        account.ScopeID,
+		Type:           core.CreditEntryDebit,
+		Amount:         -cost,
+		IdempotencyKey: string(core.CreditEntryDebit) + ":" + requestID,
+		UserID:         userID,
+		Model:          modelName,
+		InputTokens:    inputTokens,
+		OutputTokens:   outputTokens,
+	}
+	applied, err := s.apply(ctx, entry)
+	if err != nil {
+		end(err)
+		return err
+	}
+	if !applied {
+		span.SetAttributes(attribute.Bool("credit.duplicate", true))
+	}
+	return nil
+}
+
+// TopUp 儲值
+func (s *CreditService) TopUp(ctx context.Context, scope core.CreditScope, scopeID string, req *dto.CreditOperationDto) (*dto.CreditBalanceDto, error) {
+	if req.Amount <= 0 {
+		return nil, cErr.BadRequestParams("amount must be positive")
+	}
+	return s.operate(ctx, scope, scopeID, core.CreditEntryTopUp, req)
+}
+
+// Adjust 人工調整（可正可負）
+func (s *CreditService) Adjust(ctx context.Context, scope core.CreditScope, scopeID string, req *dto.CreditOperationDto) (*dto.CreditBalanceDto, error) {
+	if req.Amount == 0 {
+		return nil, cErr.BadRequestParams("amount must not be zero")
+	}
+	return s.operate(ctx, scope, scopeID, core.CreditEntryAdjust, req)
+}
+
+// Refund 退款
+func (s *CreditService) Refund(ctx context.Context, scope core.CreditScope, scopeID string, req *dto.CreditOperationDto) (*dto.CreditBalanceDto, error) {
+	if req.Amount <= 0 {
+		return nil, cErr.BadRequestParams("amount must be positive")
+	}
+	return s.operate(ctx, scope, scopeID, core.CreditEntryRefund, req)
+}
+
+// GetBalance 取得帳戶餘額
+func (s *CreditService) GetBalance(ctx context.Context, scope core.CreditScope, scopeID string) (*dto.CreditBalanceDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if err := validateCreditScope(scope, scopeID); err != nil {
+		return nil, err
+	}
+	account, err := s.accountRepo.GetByScope(ctx, scope, scopeID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("credit account not found")
+		}
+		end(err)
+		return nil, cErr.DatabaseError("mongodb get credit account failed")
+	}
+	return creditBalanceToDto(account), nil
+}
+
+// ListLedger 帳戶分錄（新到舊），entryType 為空字串時不篩選
+func (s *CreditService) ListLedger(ctx context.Context, scope core.CreditScope, scopeID string, entryType core.CreditEntryType, page, size int64) ([]*dto.CreditLedgerEntryDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if err := validateCreditScope(scope, scopeID); err != nil {
+		return nil, err
+	}
+	filter := bson.M{}
+	if entryType != "" {
+		filter["type"] = entryType
+	}
+	entries, err := s.ledgerRepo.ListByScope(ctx, scope, scopeID, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("mongodb list credit ledger failed")
+	}
+	results := make([]*dto.CreditLedgerEntryDto, 0, len(entries))
+	for _, entry := range entries {
+		results = append(results, &dto.CreditLedgerEntryDto{
+			ID:           entry.ID.Hex(),
+			Type:         entry.Type,
+			Amount:       entry.Amount,
+			UserID:       entry.UserID,
+			Model:        entry.Model,
+			InputTokens:  entry.InputTokens,
+			OutputTokens: entry.OutputTokens,
+			Note:         entry.Note,
+			CreatedAt:    entry.CreatedAt,
+		})
+	}
+	return results, nil
+}
+
+// operate 管理端入帳：確認帳戶主體存在後寫入分錄；冪等鍵重複時回傳目前餘額
+func (s *CreditService) operate(ctx context.Context, scope core.CreditScope, scopeID string, entryType core.CreditEntryType, req *dto.CreditOperationDto) (*dto.CreditBalanceDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if err := validateCreditScope(scope, scopeID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureOwner(ctx, scope, scopeID); err != nil {
+		end(err)
+		return nil, err
+	}
+
+	idempotencyKey := req.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+	span.SetAttributes(
+		attribute.String("credit.scope", string(scope)),
+		attribute.String("credit.type", string(entryType)),
+		attribute.Float64("credit.amount", req.Amount),
+	)
+	entry := &model.CreditLedgerEntry{
+		Scope:          scope,
+		ScopeID:        scopeID,
+		Type:           entryType,
+		Amount:         req.Amount,
+		IdempotencyKey: fmt.Sprintf("%s:%s:%s:%s", entryType, scope, scopeID, idempotencyKey),
+		Note:           req.Note,
+	}
+	if _, err := s.apply(ctx, entry); err != nil {
+		end(err)
+		return nil, err
+	}
+	return s.GetBalance(ctx, scope, scopeID)
+}
+
+// apply 寫入分錄並累加餘額；冪等鍵已存在時回傳 false（不重複累加）。
+// 帳本只新增不修改：餘額更新失敗時另寫一筆沖銷分錄，重試時以下一個嘗試序號的冪等鍵重新套用
+func (s *CreditService) apply(ctx context.Context, entry *model.CreditLedgerEntry) (bool, error) {
+	baseKey := entry.IdempotencyKey
+	for attempt := 0; attempt < maxCreditApplyAttempts; attempt++ {
+		entry.ID = primitive.NilObjectID
+		entry.IdempotencyKey = creditAttemptKey(baseKey, attempt)
+		if err := s.ledgerRepo.Insert(ctx, entry); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				return false, cErr.DatabaseError("mongodb insert credit ledger failed")
+			}
+			reversed, err := s.ledgerRepo.ExistsByIdempotencyKey(ctx, creditReversalKey(entry.IdempotencyKey))
+			if err != nil {
+				return false, cErr.DatabaseError("mongodb get credit ledger failed")
+			}
+			if !reversed {
+				return false, nil
+			}
+			continue
+		}
+		if _, err := s.accountRepo.IncrBalance(ctx, entry.Scope, entry.ScopeID, entry.Amount, s.config.Pricing.Currency); err != nil {
+			s.reverse(ctx, entry, err)
+			return false, cErr.DatabaseError("mongodb update credit balance failed")
+		}
+		return true, nil
+	}
+	return false, cErr.Conflict("credit entry was reversed too many times")
+}
+
+// reverse 沖銷餘額未更新成功的分錄（金額相反、不累加餘額）；沖銷也失敗時需人工對帳
+func (s *CreditService) reverse(ctx context.Context, entry *model.CreditLedgerEntry, cause error) {
+	fields := []zap.Field{
+		zap.String("scope", string(entry.Scope)),
+		zap.String("scopeID", entry.ScopeID),
+		zap.String("ledgerID", entry.ID.Hex()),
+		zap.Float64("amount", entry.Amount),
+		zap.Error(cause),
+	}
+	reversal := &model.CreditLedgerEntry{
+		Scope:          entry.Scope,
+		ScopeID:        entry.ScopeID,
+		Type:           core.CreditEntryReversal,
+		Amount:         -entry.Amount,
+		IdempotencyKey: creditReversalKey(entry.IdempotencyKey),
+		UserID:         entry.UserID,
+		Note:           "reverses " + entry.ID.Hex() + ": balance update failed",
+	}
+	if err := s.ledgerRepo.Insert(context.WithoutCancel(ctx), reversal); err != nil && !mongo.IsDuplicateKeyError(err) {
+		s.logger.Error("credit balance update failed and ledger entry could not be reversed",
+			append(fields, zap.NamedError("reverseError", err))...)
+		return
+	}
+	s.logger.Error("credit balance update failed, ledger entry reversed", fields...)
+}
+
+// creditAttemptKey 第一次嘗試沿用原冪等鍵，沖銷後的重試加上序號
+func creditAttemptKey(key string, attempt int) string {
+	if attempt == 0 {
+		return key
+	}
+	return fmt.Sprintf("%s:retry:%d", key, attempt)
+}
+
+func creditReversalKey(key string) string {
+	return string(core.CreditEntryReversal) + ":" + key
+}
+
+// payer 取得扣款帳戶：組織帳戶優先，其次使用者帳戶；皆無時回傳 nil
+func (s *CreditService) payer(ctx context.Context, userID string) (*model.CreditAccount, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, cErr.BadRequestParams("invalid user ID: not a valid ObjectID")
+	}
+	user, err := s.entityCache.GetUser(ctx, userObjectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("user not found")
+		}
+		return nil, cErr.DatabaseError("mongodb get user failed")
+	}
+
+	candidates := make([]model.CreditAccount, 0, 2)
+	if user.OrgID != nil && !user.OrgID.IsZero() {
+		candidates = append(candidates, model.CreditAccount{Scope: core.CreditScopeOrg, ScopeID: user.OrgID.Hex()})
+	}
+	candidates = append(candidates, model.CreditAccount{Scope: core.CreditScopeUser, ScopeID: user.ID.Hex()})
+	for _, candidate := range candidates {
+		account, err := s.accountRepo.GetByScope(ctx, candidate.Scope, candidate.ScopeID)
+		if err == nil {
+			return account, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.DatabaseError("mongodb get credit account failed")
+		}
+	}
+	return nil, nil
+}
+
+// ensureOwner 確認帳戶主體（使用者 / 組織）存在
+func (s *CreditService) ensureOwner(ctx context.Context, scope core.CreditScope, scopeID string) error {
+	objectID, _ := primitive.ObjectIDFromHex(scopeID)
+	var err error
+	switch scope {
+	case core.CreditScopeUser:
+		_, err = s.entityCache.GetUser(ctx, objectID)
+	case core.CreditScopeOrg:
+		_, err = s.organizationRepo.GetByID(ctx, objectID)
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return cErr.NotFound(fmt.Sprintf("%s not found", scope))
+		}
+		return cErr.DatabaseError(fmt.Sprintf("mongodb get %s failed", scope))
+	}
+	return nil
+}
+
+func validateCreditScope(scope core.CreditScope, scopeID string) error {
+	if scope != core.CreditScopeUser && scope != core.CreditScopeOrg {
+		return cErr.BadRequestParams("scope must be user or org")
+	}
+	if _, err := primitive.ObjectIDFromHex(scopeID); err != nil {
+		return cErr.BadRequestParams("invalid scope ID: not a valid ObjectID")
+	}
+	return nil
+}
+
+func creditBalanceToDto(account *model.CreditAccount) *dto.CreditBalanceDto {
+	return &dto.CreditBalanceDto{
+		Scope:     account.Scope,
+		ScopeID:   account.ScopeID,
+		Balance:   account.Balance,
+		Currency:  account.Currency,
+		UpdatedAt: account.UpdatedAt,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"interchange/internal/core"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/telemetry"
 	"io"
 	"net/http"
@@ -17,17 +18,22 @@ import (
 
 type OpenAIService struct {
 	HTTPClient *http.Client
+	clients    *outbound.Clients
 	trace      *telemetry.Trace
 }
 
-func NewOpenAIService(trace *telemetry.Trace, client *http.Client) Service {
-	return &OpenAIService{HTTPClient: client, trace: trace}
+func NewOpenAIService(trace *telemetry.Trace, clients *outbound.Clients) Service {
+	return &OpenAIService{HTTPClient: clients.For(core.ProviderOpenAI), clients: clients, trace: trace}
 }
 
 func (s *OpenAIService) GenerateEmbedding(ctx context.Context, req *EmbeddingRequestBody, apiKey string) (*EmbeddingResponse, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1" + string(core.OpenAIEmbeddingEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAIEmbeddingEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.embeddings.generate")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -47,14 +53,17 @@ func (s *OpenAIService) GenerateEmbedding(ctx context.Context, req *EmbeddingReq
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// 3) 發送請求
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai api request failed")
+		return nil, outbound.RequestError(err, "openai api request failed")
 	}
 	defer resp.Body.Close()
 
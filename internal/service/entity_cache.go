@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/pkg/lru"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultEntityCacheLocalSize       = 10000
+	defaultEntityCacheLocalTTLSeconds = 30
+	defaultEntityCacheRedisTTLSeconds = 300
+)
+
+// EntityCacheService API Key / 使用者文件的兩層快取：程序內 LRU → Redis → Mongo。
+// 管理端修改後呼叫 Invalidate*，遞增世代值、刪除 Redis 並以 pub/sub 通知所有 replica 清除本機快取；
+// 失效前讀到的舊文件不會寫回 Redis（世代值比對）或本機快取（失效序號比對）。
+// 通知遺失時以本機 TTL 為最長不一致時間。回傳的文件為共用物件，呼叫端不可修改。
+// Redis 中的 provider key 以 SecretKey 加密；未設定 SecretKey 時 API Key 文件不寫入 Redis。
+type EntityCacheService struct {
+	trace          *telemetry.Trace
+	logger         *zap.Logger
+	config         *config.Configuration
+	userRepo       *mongoDb.UserRepository
+	userApiKeyRepo *mongoDb.UserAPIKeyRepository
+	entityRepo     *redisDb.EntityCacheRepository
+	apiKeys        *lru.Cache
+	users          *lru.Cache
+
+	localMu    sync.Mutex
+	localEpoch uint64 // 本機失效序號；載入期間有失效時不寫入本機快取
+}
+
+func NewEntityCacheService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	userRepo *mongoDb.UserRepository,
+	userApiKeyRepo *mongoDb.UserAPIKeyRepository,
+	entityRepo *redisDb.EntityCacheRepository,
+) (*EntityCacheService, func()) {
+	size := config.EntityCache.LocalSize
+	if size <= 0 {
+		size = defaultEntityCacheLocalSize
+	}
+	localTTL := time.Duration(config.EntityCache.LocalTTLSeconds) * time.Second
+	if localTTL <= 0 {
+		localTTL = defaultEntityCacheLocalTTLSeconds * time.Second
+	}
+	s := &EntityCacheService{
+		trace:          trace,
+		logger:         logger,
+		config:         config,
+		userRepo:       userRepo,
+		userApiKeyRepo: userApiKeyRepo,
+		entityRepo:     entityRepo,
+		apiKeys:        lru.New(size, localTTL),
+		users:          lru.New(size, localTTL),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go s.subscribe(ctx, done)
+	cleanup := func() {
+		cancel()
+		<-done
+	}
+	return s, cleanup
+}
+
+// GetAPIKey 取得 API Key 文件；錯誤沿用 Mongo 回傳（查無資料為 mongo.ErrNoDocuments）
+func (s *EntityCacheService) GetAPIKey(ctx context.Context, id primitive.ObjectID) (*model.UserAPIKey, error) {
+	if !s.config.EntityCache.Enabled {
+		return s.userApiKeyRepo.GetByID(ctx, id)
+	}
+	if cached, ok := s.apiKeys.Get(id.Hex()); ok {
+		return cached.(*model.UserAPIKey), nil
+	}
+
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	epoch := s.currentEpoch()
+	sealer, sealed := s.providerKeySealer()
+	generation, cacheable := "", false
+	if sealed {
+		generation, cacheable = s.generation(ctx, core.CacheEntityAPIKey, id.Hex())
+	}
+	var apiKey model.UserAPIKey
+	if cacheable && s.loadRedis(ctx, core.CacheEntityAPIKey, id.Hex(), &apiKey) {
+		if err := openProviderKeys(sealer, &apiKey); err == nil {
+			s.setLocal(core.CacheEntityAPIKey, id.Hex(), &apiKey, epoch)
+			return &apiKey, nil
+		}
+		s.logger.Warn("entity cache provider key decrypt failed", zap.String("id", id.Hex()))
+	}
+	found, err := s.userApiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		if copied, err := sealProviderKeys(sealer, found); err == nil {
+			s.storeRedis(ctx, core.CacheEntityAPIKey, id.Hex(), generation, copied)
+		}
+	}
+	s.setLocal(core.CacheEntityAPIKey, id.Hex(), found, epoch)
+	return found, nil
+}
+
+// GetUser 取得使用者文件；錯誤沿用 Mongo 回傳（查無資料為 mongo.ErrNoDocuments）
+func (s *EntityCacheService) GetUser(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	if !s.config.EntityCache.Enabled {
+		return s.userRepo.GetByID(ctx, id)
+	}
+	if cached, ok := s.users.Get(id.Hex()); ok {
+		return cached.(*model.User), nil
+	}
+
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	epoch := s.currentEpoch()
+	generation, cacheable := s.generation(ctx, core.CacheEntityUser, id.Hex())
+	var user model.User
+	if cacheable && s.loadRedis(ctx, core.CacheEntityUser, id.Hex(), &user) {
+		s.setLocal(core.CacheEntityUser, id.Hex(), &user, epoch)
+		return &user, nil
+	}
+	found, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		s.storeRedis(ctx, core.CacheEntityUser, id.Hex(), generation, found)
+	}
+	s.setLocal(core.CacheEntityUser, id.Hex(), found, epoch)
+	return found, nil
+}
+
+// InvalidateAPIKey API Key 文件異動後清除各層快取
+func (s *EntityCacheService) InvalidateAPIKey(ctx context.Context, id primitive.ObjectID) {
+	s.invalidate(ctx, core.CacheEntityAPIKey, id.Hex())
+}
+
+// InvalidateUser 使用者文件異動後清除各層快取
+func (s *EntityCacheService) InvalidateUser(ctx context.Context, id primitive.ObjectID) {
+	s.invalidate(ctx, core.CacheEntityUser, id.Hex())
+}
+
+// invalidate 未啟用時仍清除，避免切換開關後讀到舊資料
+func (s *EntityCacheService) invalidate(ctx context.Context, entity core.CacheEntity, identifier string) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	s.deleteLocal(entity, identifier)
+	if err := s.entityRepo.Invalidate(ctx, entity, identifier, s.redisTTL()); err != nil {
+		s.logger.Warn("entity cache delete failed", zap.String("entity", string(entity)), zap.String("id", identifier), zap.Error(err))
+	}
+	if err := s.entityRepo.Publish(ctx, string(entity)+":"+identifier); err != nil {
+		s.logger.Warn("entity cache publish failed", zap.String("entity", string(entity)), zap.String("id", identifier), zap.Error(err))
+	}
+}
+
+// subscribe 接收其他 replica 的失效通知並清除本機快取（斷線由 redis client 自動重連）
+func (s *EntityCacheService) subscribe(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	pubsub := s.entityRepo.Subscribe(ctx)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			entity, identifier, found := strings.Cut(message.Payload, ":")
+			if !found {
+				continue
+			}
+			s.deleteLocal(core.CacheEntity(entity), identifier)
+		}
+	}
+}
+
+func (s *EntityCacheService) currentEpoch() uint64 {
+	s.localMu.Lock()
+	defer s.localMu.Unlock()
+	return s.localEpoch
+}
+
+// setLocal 載入期間（epoch 之後）沒有任何失效時才寫入本機快取
+func (s *EntityCacheService) setLocal(entity core.CacheEntity, identifier string, value interface{}, epoch uint64) {
+	s.localMu.Lock()
+	defer s.localMu.Unlock()
+	if s.localEpoch == epoch {
+		s.local(entity).Set(identifier, value)
+	}
+}
+
+func (s *EntityCacheService) deleteLocal(entity core.CacheEntity, identifier string) {
+	s.localMu.Lock()
+	defer s.localMu.Unlock()
+	s.localEpoch++
+	s.local(entity).Delete(identifier)
+}
+
+// generation 讀取 Mongo 前的世代值；Redis 異常時回傳 false（本次不讀寫 Redis）
+func (s *EntityCacheService) generation(ctx context.Context, entity core.CacheEntity, identifier string) (string, bool) {
+	generation, err := s.entityRepo.Generation(ctx, entity, identifier)
+	if err != nil {
+		s.logger.Warn("entity cache generation failed", zap.String("entity", string(entity)), zap.Error(err))
+		return "", false
+	}
+	return generation, true
+}
+
+func (s *EntityCacheService) local(entity core.CacheEntity) *lru.Cache {
+	if entity == core.CacheEntityUser {
+		return s.users
+	}
+	return s.apiKeys
+}
+
+// loadRedis Redis 異常或解碼失敗時視為未命中
+func (s *EntityCacheService) loadRedis(ctx context.Context, entity core.CacheEntity, identifier string, out interface{}) bool {
+	value, err := s.entityRepo.Get(ctx, entity, identifier)
+	if err != nil {
+		s.logger.Warn("entity cache get failed", zap.String("entity", string(entity)), zap.Error(err))
+		return false
+	}
+	if value == nil {
+		return false
+	}
+	if err := bson.Unmarshal(value, out); err != nil {
+		s.logger.Warn("entity cache decode failed", zap.String("entity", string(entity)), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// storeRedis 世代值與讀取 Mongo 前相同時才寫入，避免覆蓋期間的失效
+func (s *EntityCacheService) storeRedis(ctx context.Context, entity core.CacheEntity, identifier string, generation string, value interface{}) {
+	encoded, err := bson.Marshal(value)
+	if err != nil {
+		return
+	}
+	if _, err := s.entityRepo.Set(ctx, entity, identifier, generation, encoded, s.redisTTL()); err != nil {
+		s.logger.Warn("entity cache set failed", zap.String("entity", string(entity)), zap.Error(err))
+	}
+}
+
+func (s *EntityCacheService) redisTTL() time.Duration {
+	ttl := time.Duration(s.config.EntityCache.RedisTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultEntityCacheRedisTTLSeconds * time.Second
+	}
+	return ttl
+}
+
+// providerKeySealer 以 SecretKey 衍生的 AES-256-GCM；未設定 SecretKey 時回傳 false
+func (s *EntityCacheService) providerKeySealer() (cipher.AEAD, bool) {
+	if s.config.EntityCache.SecretKey == "" {
+		return nil, false
+	}
+	key := sha256.Sum256([]byte(s.config.EntityCache.SecretKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, false
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, false
+	}
+	return aead, true
+}
+
+// sealProviderKeys 回傳 provider key 已加密的副本（不修改共用的原文件）
+func sealProviderKeys(aead cipher.AEAD, apiKey *model.UserAPIKey) (*model.UserAPIKey, error) {
+	copied := *apiKey
+	copied.ProviderAccess = make([]model.ProviderAccess, len(apiKey.ProviderAccess))
+	copy(copied.ProviderAccess, apiKey.ProviderAccess)
+	for i := range copied.ProviderAccess {
+		if copied.ProviderAccess[i].ProviderKey == "" {
+			continue
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		sealed := aead.Seal(nonce, nonce, []byte(copied.ProviderAccess[i].ProviderKey), nil)
+		copied.ProviderAccess[i].ProviderKey = base64.StdEncoding.EncodeToString(sealed)
+	}
+	return &copied, nil
+}
+
+// openProviderKeys 就地解密 Redis 讀出的 provider key
+func openProviderKeys(aead cipher.AEAD, apiKey *model.UserAPIKey) error {
+	for i := range apiKey.ProviderAccess {
+		if apiKey.ProviderAccess[i].ProviderKey == "" {
+			continue
+		}
+		sealed, err := base64.StdEncoding.DecodeString(apiKey.ProviderAccess[i].ProviderKey)
+		if err != nil {
+			return err
+		}
+		if len(sealed) < aead.NonceSize() {
+			return errors.New("sealed provider key too short")
+		}
+		plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+		if err != nil {
+			return err
+		}
+		apiKey.ProviderAccess[i].ProviderKey = string(plain)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"interchange/internal/core"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/telemetry"
 	"io"
 	"mime/multipart"
@@ -20,11 +21,12 @@ import (
 
 type OpenAIService struct {
 	HTTPClient *http.Client
+	clients    *outbound.Clients
 	trace      *telemetry.Trace
 }
 
-func NewOpenAIService(trace *telemetry.Trace, client *http.Client) Service {
-	return &OpenAIService{HTTPClient: client, trace: trace}
+func NewOpenAIService(trace *telemetry.Trace, clients *outbound.Clients) Service {
+	return &OpenAIService{HTTPClient: clients.For(core.ProviderOpenAI), clients: clients, trace: trace}
 }
 
 // GenerateV1 呼叫 OpenAI /images/generations（JSON）。
@@ -33,9 +35,13 @@ func NewOpenAIService(trace *telemetry.Trace, client *http.Client) Service {
 //   - 對外請求/非 2xx：ExternalRequestError
 //   - 回應解析失敗：ExternalResponseFormatError
 func (s *OpenAIService) GenerateV1(ctx context.Context, req *ImageGenerationRequestBody, apiKey string) (*ImageGenerationResponse, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1/" + string(core.OpenAIImageGenerateEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAIImageGenerateEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.images.generate")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -55,14 +61,17 @@ func (s *OpenAIService) GenerateV1(ctx context.Context, req *ImageGenerationRequ
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// 3) 請求
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai image api request failed")
+		return nil, outbound.RequestError(err, "openai image api request failed")
 	}
 	defer resp.Body.Close()
 
@@ -106,9 +115,13 @@ func (s *OpenAIService) GenerateV1(ctx context.Context, req *ImageGenerationRequ
 // EditV1 呼叫 OpenAI /images/edits（multipart）。
 // 失敗分類同上；檔案處理/欄位寫入錯誤：InternalServer。
 func (s *OpenAIService) EditV1(ctx context.Context, req *ImageEditRequestBody, apiKey string) (*ImageGenerationResponse, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1/" + string(core.OpenAIImageEditEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAIImageEditEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.images.edits")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -265,14 +278,17 @@ func (s *OpenAIService) EditV1(ctx context.Context, req *ImageEditRequestBody, a
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// 3) 請求
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai edit request failed")
+		return nil, outbound.RequestError(err, "openai edit request failed")
 	}
 	defer resp.Body.Close()
 
@@ -299,9 +315,13 @@ func (s *OpenAIService) EditV1(ctx context.Context, req *ImageEditRequestBody, a
 // VariationV1 呼叫 OpenAI /images/variations（multipart）。
 // 失敗分類同上；檔案處理/欄位寫入錯誤：InternalServer。
 func (s *OpenAIService) VariationV1(ctx context.Context, req *ImageVariantRequestBody, apiKey string) (*ImageGenerationResponse, error) {
-	url := string(core.OpenAIAPIBaseURL) + "/v1/" + string(core.OpenAIImageVariationEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAIImageVariationEndpoint))
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.images.variations")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -365,14 +385,17 @@ func (s *OpenAIService) VariationV1(ctx context.Context, req *ImageVariantReques
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(httpReq, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// 3) 請求
 	resp, err := s.HTTPClient.Do(httpReq)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai variation request failed")
+		return nil, outbound.RequestError(err, "openai variation request failed")
 	}
 	defer resp.Body.Close()
 
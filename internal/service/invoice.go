@@ -0,0 +1,399 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// InvoiceService 依已儲存的用量紀錄（含記錄當下的計價成本）產生月結帳單。
+// 帳單產生後不再修改；同一對象同一帳期重複產生時回傳既有帳單。只能產生已結束的帳期。
+type InvoiceService struct {
+	trace       *telemetry.Trace
+	logger      *zap.Logger
+	config      *config.Configuration
+	usageRepo   *mongoDb.UsageRecordRepository
+	invoiceRepo *mongoDb.InvoiceRepository
+}
+
+func NewInvoiceService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	usageRepo *mongoDb.UsageRecordRepository,
+	invoiceRepo *mongoDb.InvoiceRepository,
+) *InvoiceService {
+	return &InvoiceService{
+		trace:       trace,
+		logger:      logger,
+		config:      config,
+		usageRepo:   usageRepo,
+		invoiceRepo: invoiceRepo,
+	}
+}
+
+// Generate 產生單一對象的帳單；scopeID 為空時產生帳期內所有有用量的對象。
+// 未啟用 USAGE_RECORD 時拒絕產生（帳單不可變，避免鎖定錯誤的零元帳單）
+func (s *InvoiceService) Generate(ctx context.Context, req *dto.GenerateInvoiceDto) ([]*dto.InvoiceDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if !s.config.UsageRecord.Enabled {
+		return nil, cErr.Conflict("usage recording (USAGE_RECORD.ENABLED) is disabled; invoices cannot be generated")
+	}
+	if req.Scope != core.InvoiceScopeProject && req.Scope != core.InvoiceScopeUser {
+		return nil, cErr.BadRequestParams("scope must be project or user")
+	}
+	start, finish, err := s.periodRange(req.Period)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("invoice.scope", string(req.Scope)),
+		attribute.String("invoice.period", req.Period),
+	)
+
+	scopeIDs := []string{req.ScopeID}
+	if req.ScopeID == "" {
+		if scopeIDs, err = s.scopeIDs(ctx, req.Scope, start, finish); err != nil {
+			end(err)
+			return nil, err
+		}
+	}
+
+	invoices := make([]*dto.InvoiceDto, 0, len(scopeIDs))
+	for _, scopeID := range scopeIDs {
+		invoice, err := s.generateOne(ctx, req.Scope, scopeID, req.Period, start, finish)
+		if err != nil {
+			end(err)
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, nil
+}
+
+// GetInvoice 取得帳單（含明細）
+func (s *InvoiceService) GetInvoice(ctx context.Context, id primitive.ObjectID) (*dto.InvoiceDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("invoice not found")
+		}
+		end(err)
+		return nil, cErr.DatabaseError("mongodb get invoice failed")
+	}
+	return invoiceModelToDto(invoice), nil
+}
+
+// ListInvoices 帳單列表（不含明細）
+func (s *InvoiceService) ListInvoices(ctx context.Context, filter bson.M, page, size int64) ([]*dto.InvoiceDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	invoices, err := s.invoiceRepo.List(ctx, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("mongodb list invoices failed")
+	}
+	results := make([]*dto.InvoiceDto, 0, len(invoices))
+	for _, invoice := range invoices {
+		results = append(results, invoiceModelToDto(invoice))
+	}
+	return results, nil
+}
+
+// Render 輸出帳單，回傳內容與 Content-Type
+func (s *InvoiceService) Render(invoice *dto.InvoiceDto, format core.InvoiceFormat) ([]byte, string, error) {
+	switch format {
+	case core.InvoiceFormatJSON:
+		body, err := json.MarshalIndent(invoice, "", "  ")
+		return body, "application/json; charset=utf-8", err
+	case core.InvoiceFormatCSV:
+		body, err := invoiceCSV(invoice)
+		return body, "text/csv; charset=utf-8", err
+	case core.InvoiceFormatHTML:
+		var buffer bytes.Buffer
+		err := invoiceHTML.Execute(&buffer, map[string]any{"Issuer": s.config.Invoice.IssuerName, "Invoice": invoice})
+		return buffer.Bytes(), "text/html; charset=utf-8", err
+	}
+	return nil, "", cErr.BadRequestParams("format must be one of json, csv, html")
+}
+
+// generateOne 已有帳單時直接回傳（帳單不可變），否則依用量計算後寫入；帳期內無用量時不寫入
+func (s *InvoiceService) generateOne(ctx context.Context, scope core.InvoiceScope, scopeID, period string, start, finish time.Time) (*dto.InvoiceDto, error) {
+	existing, err := s.invoiceRepo.GetByPeriod(ctx, scope, scopeID, period)
+	if err == nil {
+		return invoiceModelToDto(existing), nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, cErr.DatabaseError("mongodb get invoice failed")
+	}
+
+	filter := mongoDb.UsageRecordFilter{From: start, To: finish}
+	if scope == core.InvoiceScopeProject {
+		filter.ProjectName = scopeID
+	} else {
+		filter.UserID = scopeID
+	}
+	aggregates, err := s.usageRepo.Aggregate(ctx, filter, []string{"provider", "model", "endpoint"}, "", "", 0)
+	if err != nil {
+		return nil, cErr.DatabaseError("mongodb aggregate usage failed")
+	}
+	if len(aggregates) == 0 {
+		// 對象 ID 打錯或帳期內尚無紀錄時不保存空帳單，之後仍可重新產生
+		return nil, cErr.NotFound(fmt.Sprintf("no usage recorded for %s %s in %s", scope, scopeID, period))
+	}
+
+	id := primitive.NewObjectID()
+	invoice := &model.Invoice{
+		ID:          id,
+		Number:      fmt.Sprintf("INV-%s-%s", strings.ReplaceAll(period, "-", ""), strings.ToUpper(id.Hex()[16:])),
+		Scope:       scope,
+		ScopeID:     scopeID,
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   finish,
+		Currency:    s.config.Pricing.Currency,
+		LineItems:   make([]model.InvoiceLineItem, 0, len(aggregates)),
+		MarkupRate:  s.config.Invoice.MarkupRate,
+		TaxRate:     s.config.Invoice.TaxRate,
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, aggregate := range aggregates {
+		invoice.LineItems = append(invoice.LineItems, model.InvoiceLineItem{
+			Provider:       aggregate.Group.Provider,
+			Model:          aggregate.Group.Model,
+			Endpoint:       aggregate.Group.Endpoint,
+			Requests:       aggregate.Requests,
+			CachedRequests: aggregate.CachedRequests,
+			InputTokens:    aggregate.TokensPrompt + aggregate.InputTokens,
+			OutputTokens:   aggregate.TokensCompletion + aggregate.OutputTokens,
+			TokensTotal:    aggregate.TokensTotal,
+			Cost:           aggregate.Cost,
+		})
+		invoice.Requests += aggregate.Requests
+		invoice.TokensTotal += aggregate.TokensTotal
+		invoice.Subtotal += aggregate.Cost
+	}
+	invoice.Subtotal = roundAmount(invoice.Subtotal)
+	invoice.Markup = roundAmount(invoice.Subtotal * invoice.MarkupRate)
+	invoice.Tax = roundAmount((invoice.Subtotal + invoice.Markup) * invoice.TaxRate)
+	invoice.Total = roundAmount(invoice.Subtotal + invoice.Markup + invoice.Tax)
+
+	if err := s.invoiceRepo.Insert(ctx, invoice); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// 其他程序同時產生，回傳先寫入者
+			if existing, getErr := s.invoiceRepo.GetByPeriod(ctx, scope, scopeID, period); getErr == nil {
+				return invoiceModelToDto(existing), nil
+			}
+		}
+		return nil, cErr.DatabaseError("mongodb insert invoice failed")
+	}
+	s.logger.Info("invoice generated",
+		zap.String("number", invoice.Number),
+		zap.String("scope", string(scope)),
+		zap.String("scopeID", scopeID),
+		zap.String("period", period),
+		zap.Float64("total", invoice.Total))
+	return invoiceModelToDto(invoice), nil
+}
+
+// scopeIDs 帳期內有用量的專案或使用者
+func (s *InvoiceService) scopeIDs(ctx context.Context, scope core.InvoiceScope, start, finish time.Time) ([]string, error) {
+	dimension := "project"
+	if scope == core.InvoiceScopeUser {
+		dimension = "user"
+	}
+	aggregates, err := s.usageRepo.Aggregate(ctx, mongoDb.UsageRecordFilter{From: start, To: finish}, []string{dimension}, "", "", 0)
+	if err != nil {
+		return nil, cErr.DatabaseError("mongodb aggregate usage failed")
+	}
+	scopeIDs := make([]string, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		scopeID := aggregate.Group.ProjectName
+		if scope == core.InvoiceScopeUser {
+			scopeID = aggregate.Group.UserID
+		}
+		if scopeID != "" {
+			scopeIDs = append(scopeIDs, scopeID)
+		}
+	}
+	return scopeIDs, nil
+}
+
+// periodRange 帳期（YYYY-MM，依設定時區）的起訖；帳期未結束時拒絕
+func (s *InvoiceService) periodRange(period string) (time.Time, time.Time, error) {
+	location := time.UTC
+	if s.config.Invoice.Timezone != "" {
+		loaded, err := time.LoadLocation(s.config.Invoice.Timezone)
+		if err != nil {
+			return time.Time{}, time.Time{}, cErr.InternalServer("invalid invoice timezone: " + s.config.Invoice.Timezone)
+		}
+		location = loaded
+	}
+	start, err := time.ParseInLocation("2006-01", period, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, cErr.BadRequestParams("period must be YYYY-MM")
+	}
+	finish := start.AddDate(0, 1, 0)
+	if finish.After(time.Now()) {
+		return time.Time{}, time.Time{}, cErr.BadRequestParams("period has not ended yet")
+	}
+	return start.UTC(), finish.UTC(), nil
+}
+
+// PreviousPeriod 目前時間（依設定時區）的上一個帳期
+func (s *InvoiceService) PreviousPeriod() string {
+	location, err := time.LoadLocation(s.config.Invoice.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	now := time.Now().In(location)
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location).AddDate(0, -1, 0).Format("2006-01")
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func invoiceModelToDto(invoice *model.Invoice) *dto.InvoiceDto {
+	result := &dto.InvoiceDto{
+		ID:          invoice.ID.Hex(),
+		Number:      invoice.Number,
+		Scope:       invoice.Scope,
+		ScopeID:     invoice.ScopeID,
+		Period:      invoice.Period,
+		PeriodStart: invoice.PeriodStart,
+		PeriodEnd:   invoice.PeriodEnd,
+		Currency:    invoice.Currency,
+		Requests:    invoice.Requests,
+		TokensTotal: invoice.TokensTotal,
+		Subtotal:    invoice.Subtotal,
+		MarkupRate:  invoice.MarkupRate,
+		Markup:      invoice.Markup,
+		TaxRate:     invoice.TaxRate,
+		Tax:         invoice.Tax,
+		Total:       invoice.Total,
+		GeneratedAt: invoice.GeneratedAt,
+	}
+	for _, item := range invoice.LineItems {
+		result.LineItems = append(result.LineItems, dto.InvoiceLineItemDto{
+			Provider:       item.Provider,
+			Model:          item.Model,
+			Endpoint:       item.Endpoint,
+			Requests:       item.Requests,
+			CachedRequests: item.CachedRequests,
+			InputTokens:    item.InputTokens,
+			OutputTokens:   item.OutputTokens,
+			TokensTotal:    item.TokensTotal,
+			Cost:           item.Cost,
+		})
+	}
+	return result
+}
+
+// invoiceCSV 明細列在前，合計列（subtotal / markup / tax / total）在後
+func invoiceCSV(invoice *dto.InvoiceDto) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	rows := [][]string{{"number", "scope", "scopeID", "period", "currency", "provider", "model", "endpoint",
+		"requests", "cachedRequests", "inputTokens", "outputTokens", "tokensTotal", "cost"}}
+	prefix := []string{invoice.Number, string(invoice.Scope), invoice.ScopeID, invoice.Period, invoice.Currency}
+	for _, item := range invoice.LineItems {
+		rows = append(rows, append(append([]string{}, prefix...),
+			item.Provider, item.Model, item.Endpoint,
+			strconv.FormatInt(item.Requests, 10),
+			strconv.FormatInt(item.CachedRequests, 10),
+			strconv.FormatInt(item.InputTokens, 10),
+			strconv.FormatInt(item.OutputTokens, 10),
+			strconv.FormatInt(item.TokensTotal, 10),
+			strconv.FormatFloat(item.Cost, 'f', -1, 64),
+		))
+	}
+	for _, summary := range []struct {
+		label  string
+		amount float64
+	}{
+		{"subtotal", invoice.Subtotal},
+		{"markup", invoice.Markup},
+		{"tax", invoice.Tax},
+		{"total", invoice.Total},
+	} {
+		rows = append(rows, append(append([]string{}, prefix...),
+			summary.label, "", "", "", "", "", "", "", strconv.FormatFloat(summary.amount, 'f', 2, 64)))
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+var invoiceHTML = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"amount":  func(value float64) string { return strconv.FormatFloat(value, 'f', 2, 64) },
+	"percent": func(value float64) string { return strconv.FormatFloat(value*100, 'f', -1, 64) + "%" },
+	"date":    func(value time.Time) string { return value.Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Invoice.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 32px; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-top: 16px; }
+th, td { border: 1px solid #ccc; padding: 6px 8px; font-size: 13px; }
+th { background: #f4f4f4; text-align: left; }
+td.number { text-align: right; }
+.summary td { border: none; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Issuer}} Statement</h1>
+<p>
+Number: {{.Invoice.Number}}<br>
+{{.Invoice.Scope}}: {{.Invoice.ScopeID}}<br>
+Period: {{.Invoice.Period}} ({{date .Invoice.PeriodStart}} ~ {{date .Invoice.PeriodEnd}})<br>
+Generated: {{.Invoice.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}
+</p>
+<table>
+<tr><th>Provider</th><th>Model</th><th>Endpoint</th><th>Requests</th><th>Input tokens</th><th>Output tokens</th><th>Total tokens</th><th>Cost ({{.Invoice.Currency}})</th></tr>
+{{range .Invoice.LineItems}}<tr><td>{{.Provider}}</td><td>{{.Model}}</td><td>{{.Endpoint}}</td><td class="number">{{.Requests}}</td><td class="number">{{.InputTokens}}</td><td class="number">{{.OutputTokens}}</td><td class="number">{{.TokensTotal}}</td><td class="number">{{amount .Cost}}</td></tr>
+{{end}}</table>
+<table class="summary">
+<tr><td>Requests</td><td class="number">{{.Invoice.Requests}}</td></tr>
+<tr><td>Tokens</td><td class="number">{{.Invoice.TokensTotal}}</td></tr>
+<tr><td>Subtotal</td><td class="number">{{amount .Invoice.Subtotal}}</td></tr>
+<tr><td>Markup ({{percent .Invoice.MarkupRate}})</td><td class="number">{{amount .Invoice.Markup}}</td></tr>
+<tr><td>Tax ({{percent .Invoice.TaxRate}})</td><td class="number">{{amount .Invoice.Tax}}</td></tr>
+<tr><td><strong>Total ({{.Invoice.Currency}})</strong></td><td class="number"><strong>{{amount .Invoice.Total}}</strong></td></tr>
+</table>
+</body>
+</html>
+`))
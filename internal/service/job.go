@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultJobLockTTLSeconds = 300
+	defaultJobTimeout        = 10 * time.Minute
+	jobRecordTimeout         = 10 * time.Second
+)
+
+// errJobLocked 其他 replica 正持有該工作的租約鎖
+var errJobLocked = errors.New("job is running on another instance")
+
+// JobDefinition 排程工作定義（由 cron 套件註冊）
+type JobDefinition struct {
+	Name        string
+	Description string
+	Spec        string        // cron 格式（含秒）
+	Local       bool          // 每個 replica 各自執行，不取分散式鎖（例如寫回本機緩衝）
+	Timeout     time.Duration // 單次執行逾時（未設定為 10 分鐘）
+	Run         func(ctx context.Context) (string, error)
+}
+
+// JobService 排程工作登錄與執行：排程觸發先以排程時段認領（同一時段只執行一次），
+// 每次執行再取得 Redis 租約鎖（執行中定期續約，避免與手動觸發重疊），
+// 開始與結束都寫入執行紀錄；暫停狀態存於 Mongo，所有 replica 共用。
+type JobService struct {
+	trace      *telemetry.Trace
+	logger     *zap.Logger
+	config     *config.Configuration
+	jobRepo    *mongoDb.JobRepository
+	jobRunRepo *mongoDb.JobRunRepository
+	lockRepo   *redisDb.JobLockRepository
+	instance   string
+
+	mu    sync.RWMutex
+	jobs  map[string]*JobDefinition
+	names []string
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+func NewJobService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	jobRepo *mongoDb.JobRepository,
+	jobRunRepo *mongoDb.JobRunRepository,
+	lockRepo *redisDb.JobLockRepository,
+) (*JobService, func()) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &JobService{
+		trace:      trace,
+		logger:     logger,
+		config:     config,
+		jobRepo:    jobRepo,
+		jobRunRepo: jobRunRepo,
+		lockRepo:   lockRepo,
+		instance:   fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		jobs:       make(map[string]*JobDefinition),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	// 結束時中斷執行中的工作並等待收尾（寫入結果、釋放鎖）
+	cleanup := func() {
+		cancel()
+		s.running.Wait()
+	}
+	return s, cleanup
+}
+
+// Register 登錄工作；名稱重複時回傳錯誤
+func (s *JobService) Register(definition JobDefinition) error {
+	if definition.Name == "" || definition.Run == nil {
+		return fmt.Errorf("job definition requires name and run")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[definition.Name]; ok {
+		return fmt.Errorf("job %s already registered", definition.Name)
+	}
+	s.jobs[definition.Name] = &definition
+	s.names = append(s.names, definition.Name)
+	return nil
+}
+
+// Definitions 依登錄順序回傳所有工作
+func (s *JobService) Definitions() []JobDefinition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	definitions := make([]JobDefinition, 0, len(s.names))
+	for _, name := range s.names {
+		definitions = append(definitions, *s.jobs[name])
+	}
+	return definitions
+}
+
+// RunScheduled 排程觸發：已暫停、該排程時段已由其他 replica 認領或其他 replica 正在執行時略過。
+// slot 為本次觸發對應的排程時間（所有 replica 相同）
+func (s *JobService) RunScheduled(name string, slot time.Time) {
+	definition := s.definition(name)
+	if definition == nil {
+		return
+	}
+	s.running.Add(1)
+	defer s.running.Done()
+
+	ctx, cancel := context.WithTimeout(s.ctx, jobRecordTimeout)
+	state, err := s.jobRepo.GetByName(ctx, name)
+	cancel()
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		s.logger.Warn("load job state failed, skip run", zap.String("job", name), zap.Error(err))
+		return
+	}
+	if state != nil && state.Paused {
+		s.logger.Debug("job paused, skip run", zap.String("job", name))
+		return
+	}
+	if !definition.Local {
+		ctx, cancel := context.WithTimeout(s.ctx, jobRecordTimeout)
+		claimed, err := s.lockRepo.ClaimSlot(ctx, name, slot.Unix(), s.instance, s.lease())
+		cancel()
+		if err != nil {
+			s.logger.Warn("claim job slot failed, skip run", zap.String("job", name), zap.Error(err))
+			return
+		}
+		if !claimed {
+			s.logger.Debug("job slot claimed by another instance, skip run", zap.String("job", name), zap.Time("slot", slot))
+			return
+		}
+	}
+
+	run, token, err := s.start(s.ctx, definition, core.JobTriggerSchedule, "")
+	if err != nil {
+		if errors.Is(err, errJobLocked) {
+			s.logger.Debug("job locked by another instance, skip run", zap.String("job", name))
+		} else {
+			s.logger.Warn("start job failed", zap.String("job", name), zap.Error(err))
+		}
+		return
+	}
+	s.execute(definition, run, token)
+}
+
+// Trigger 手動觸發（暫停中也可執行）：取得鎖後於背景執行，立即回傳執行紀錄
+func (s *JobService) Trigger(ctx context.Context, name string, triggeredBy string) (*dto.JobRunDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(attribute.String("job.name", name))
+
+	definition := s.definition(name)
+	if definition == nil {
+		return nil, cErr.NotFound(fmt.Sprintf("job %s not found", name))
+	}
+	run, token, err := s.start(ctx, definition, core.JobTriggerManual, triggeredBy)
+	if err != nil {
+		end(err)
+		if errors.Is(err, errJobLocked) {
+			return nil, cErr.Conflict(fmt.Sprintf("job %s is already running", name))
+		}
+		return nil, cErr.DatabaseError("start job failed")
+	}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.execute(definition, run, token)
+	}()
+	return modelToJobRunDto(run), nil
+}
+
+// ListJobs 所有已登錄工作與其狀態
+func (s *JobService) ListJobs(ctx context.Context) ([]*dto.JobDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	states, err := s.jobRepo.List(ctx)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListJobs error")
+	}
+	stateByName := make(map[string]*model.JobState, len(states))
+	for _, state := range states {
+		stateByName[state.Name] = state
+	}
+
+	definitions := s.Definitions()
+	results := make([]*dto.JobDto, 0, len(definitions))
+	for i := range definitions {
+		results = append(results, s.jobDto(ctx, &definitions[i], stateByName[definitions[i].Name]))
+	}
+	return results, nil
+}
+
+// SetPaused 暫停 / 恢復排程（不影響執行中的工作與手動觸發）
+func (s *JobService) SetPaused(ctx context.Context, name string, paused bool, operator string) (*dto.JobDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(attribute.String("job.name", name), attribute.Bool("job.paused", paused))
+
+	definition := s.definition(name)
+	if definition == nil {
+		return nil, cErr.NotFound(fmt.Sprintf("job %s not found", name))
+	}
+	if err := s.jobRepo.SetPaused(ctx, name, paused, operator); err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database SetJobPaused error")
+	}
+	state, err := s.jobRepo.GetByName(ctx, name)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database GetJob error")
+	}
+	s.logger.Info("job pause state changed", zap.String("job", name), zap.Bool("paused", paused), zap.String("operator", operator))
+	return s.jobDto(ctx, definition, state), nil
+}
+
+// ListRuns 執行紀錄（新到舊）
+func (s *JobService) ListRuns(ctx context.Context, job string, status core.JobRunStatus, page, size int64) ([]*dto.JobRunDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	filter := bson.M{}
+	if job != "" {
+		filter["job"] = job
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	runs, err := s.jobRunRepo.List(ctx, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListJobRuns error")
+	}
+	results := make([]*dto.JobRunDto, 0, len(runs))
+	for _, run := range runs {
+		results = append(results, modelToJobRunDto(run))
+	}
+	return results, nil
+}
+
+// GetRun 單筆執行紀錄
+func (s *JobService) GetRun(ctx context.Context, id primitive.ObjectID) (*dto.JobRunDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	run, err := s.jobRunRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("job run with id %s not found", id.Hex()))
+		}
+		end(err)
+		return nil, cErr.DatabaseError("database GetJobRun error")
+	}
+	return modelToJobRunDto(run), nil
+}
+
+// start 取得租約鎖（Local 工作不取鎖）並寫入 running 紀錄；紀錄寫入失敗不阻擋執行
+func (s *JobService) start(ctx context.Context, definition *JobDefinition, trigger core.JobTrigger, triggeredBy string) (*model.JobRun, string, error) {
+	token := s.instance + ":" + primitive.NewObjectID().Hex()
+	if !definition.Local {
+		acquired, err := s.lockRepo.Acquire(ctx, definition.Name, token, s.lease())
+		if err != nil {
+			return nil, "", err
+		}
+		if !acquired {
+			return nil, "", errJobLocked
+		}
+	}
+
+	run := &model.JobRun{
+		ID:          primitive.NewObjectID(),
+		Job:         definition.Name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Instance:    s.instance,
+		Status:      core.JobRunStatusRunning,
+		StartedAt:   time.Now().UTC(),
+	}
+	if err := s.jobRunRepo.Insert(ctx, run); err != nil {
+		s.logger.Warn("insert job run failed", zap.String("job", definition.Name), zap.Error(err))
+	}
+	return run, token, nil
+}
+
+// execute 執行工作並寫回結果；執行中定期續約，租約遺失（被其他 replica 取得）時中斷本次執行
+func (s *JobService) execute(definition *JobDefinition, run *model.JobRun, token string) {
+	timeout := definition.Timeout
+	if timeout <= 0 {
+		timeout = defaultJobTimeout
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	ctx, span, end := s.trace.WithSpan(ctx)
+	span.SetAttributes(attribute.String("job.name", definition.Name), attribute.String("job.trigger", string(run.Trigger)))
+
+	stopRenew := make(chan struct{})
+	renewDone := make(chan struct{})
+	go s.keepAlive(ctx, definition, token, cancel, stopRenew, renewDone)
+
+	message, runErr := invokeJob(ctx, definition)
+	close(stopRenew)
+	<-renewDone
+	cancel()
+	end(runErr)
+
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Message = message
+	run.Status = core.JobRunStatusSucceeded
+	if runErr != nil {
+		run.Status = core.JobRunStatusFailed
+		run.Error = runErr.Error()
+		s.logger.Warn("job failed", zap.String("job", definition.Name), zap.Int64("durationMs", run.DurationMs), zap.Error(runErr))
+	} else {
+		s.logger.Info("job finished", zap.String("job", definition.Name), zap.Int64("durationMs", run.DurationMs), zap.String("message", message))
+	}
+
+	recordCtx, recordCancel := context.WithTimeout(context.Background(), jobRecordTimeout)
+	defer recordCancel()
+	if err := s.jobRunRepo.Finish(recordCtx, run); err != nil {
+		s.logger.Warn("finish job run failed", zap.String("job", definition.Name), zap.Error(err))
+	}
+	if err := s.jobRepo.RecordLastRun(recordCtx, run); err != nil {
+		s.logger.Warn("record job last run failed", zap.String("job", definition.Name), zap.Error(err))
+	}
+	if !definition.Local {
+		if err := s.lockRepo.Release(recordCtx, definition.Name, token); err != nil {
+			s.logger.Warn("release job lock failed", zap.String("job", definition.Name), zap.Error(err))
+		}
+	}
+}
+
+// keepAlive 每 1/3 租約續約一次
+func (s *JobService) keepAlive(
+	ctx context.Context,
+	definition *JobDefinition,
+	token string,
+	cancel context.CancelFunc,
+	stop, done chan struct{},
+) {
+	defer close(done)
+	if definition.Local {
+		return
+	}
+	lease := s.lease()
+	ticker := time.NewTicker(lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := s.lockRepo.Renew(ctx, definition.Name, token, lease)
+			if err != nil {
+				s.logger.Warn("renew job lock failed", zap.String("job", definition.Name), zap.Error(err))
+				continue
+			}
+			if !renewed {
+				s.logger.Warn("job lock lost, cancel run", zap.String("job", definition.Name))
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// invokeJob 執行工作本體；panic 轉為錯誤，避免中斷排程器
+func invokeJob(ctx context.Context, definition *JobDefinition) (message string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panic: %v", recovered)
+		}
+	}()
+	return definition.Run(ctx)
+}
+
+func (s *JobService) definition(name string) *JobDefinition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.jobs[name]
+}
+
+func (s *JobService) lease() time.Duration {
+	seconds := s.config.Job.LockTTLSeconds
+	if seconds <= 0 {
+		seconds = defaultJobLockTTLSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// jobDto 合併工作定義與共享狀態；Running 以是否有人持有租約鎖判斷
+func (s *JobService) jobDto(ctx context.Context, definition *JobDefinition, state *model.JobState) *dto.JobDto {
+	result := &dto.JobDto{
+		Name:        definition.Name,
+		Description: definition.Description,
+		Spec:        definition.Spec,
+		Local:       definition.Local,
+	}
+	if state != nil {
+		result.Paused = state.Paused
+		result.PausedBy = state.PausedBy
+		result.PausedAt = state.PausedAt
+		result.LastRunAt = state.LastRunAt
+		result.LastStatus = state.LastStatus
+		result.LastDurationMs = state.LastDurationMs
+	}
+	if !definition.Local {
+		if holder, err := s.lockRepo.Holder(ctx, definition.Name); err == nil {
+			result.Running = holder != ""
+		}
+	}
+	return result
+}
+
+func modelToJobRunDto(m *model.JobRun) *dto.JobRunDto {
+	return &dto.JobRunDto{
+		ID:          m.ID.Hex(),
+		Job:         m.Job,
+		Trigger:     m.Trigger,
+		TriggeredBy: m.TriggeredBy,
+		Instance:    m.Instance,
+		Status:      m.Status,
+		Message:     m.Message,
+		Error:       m.Error,
+		StartedAt:   m.StartedAt,
+		FinishedAt:  m.FinishedAt,
+		DurationMs:  m.DurationMs,
+	}
+}
@@ -0,0 +1,491 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// 每位使用者同時待審核的申請上限
+const defaultKeyRequestMaxPending = 5
+
+// KeyRequestService API Key 申請：使用者送出（pending），editor / admin 核准（可修改內容）或駁回；
+// 核准時經 UserAPIKeyService.Create 核發，申請與審核結果都保留
+type KeyRequestService struct {
+	trace          *telemetry.Trace
+	logger         *zap.Logger
+	keyRequestRepo *mongoDb.KeyRequestRepository
+	entityCache    *EntityCacheService
+	apiKeyService  *UserAPIKeyService
+	notification   *NotificationService
+	audit          *AuditService
+}
+
+func NewKeyRequestService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	keyRequestRepo *mongoDb.KeyRequestRepository,
+	entityCache *EntityCacheService,
+	apiKeyService *UserAPIKeyService,
+	notification *NotificationService,
+	audit *AuditService,
+) *KeyRequestService {
+	return &KeyRequestService{
+		trace:          trace,
+		logger:         logger,
+		keyRequestRepo: keyRequestRepo,
+		entityCache:    entityCache,
+		apiKeyService:  apiKeyService,
+		notification:   notification,
+		audit:          audit,
+	}
+}
+
+// Submit 使用者送出申請；帳號需為啟用中，待審核的申請數有上限
+func (s *KeyRequestService) Submit(ctx context.Context, userID primitive.ObjectID, req *dto.CreateKeyRequestDto) (*dto.KeyRequestDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(attribute.String("user.id", userID.Hex()))
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	if user.Status != core.StatusActive || user.Role == core.RoleBanned {
+		return nil, cErr.Forbidden("user is not allowed to request api keys")
+	}
+	if err := validateKeyRequestAccess(req.ProviderAccess); err != nil {
+		return nil, err
+	}
+	pending, err := s.keyRequestRepo.CountPending(ctx, userID)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database CountPendingKeyRequests error")
+	}
+	if pending >= defaultKeyRequestMaxPending {
+		return nil, cErr.Conflict(fmt.Sprintf("too many pending key requests (max %d)", defaultKeyRequestMaxPending))
+	}
+
+	created, err := s.keyRequestRepo.Create(ctx, &model.KeyRequest{
+		UserID:         userID,
+		KeyName:        req.KeyName,
+		ProviderAccess: keyRequestAccessDtosToModels(req.ProviderAccess),
+		Justification:  req.Justification,
+		Status:         core.StatusPending,
+	})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database CreateKeyRequest error")
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      AuditActor{Type: core.AuditActorUser, ID: userID.Hex()},
+		Action:     core.AuditActionKeyRequestSubmitted,
+		TargetType: core.AuditTargetKeyRequest,
+		TargetID:   created.ID.Hex(),
+		After:      created,
+	})
+	s.emit(ctx, NotificationMessage{
+		Event:   core.NotificationEventKeyRequested,
+		Subject: fmt.Sprintf("User %s requested API key %q", userLabel(user), created.KeyName),
+		UserID:  userID.Hex(),
+		Data: map[string]interface{}{
+			"requestID":     created.ID.Hex(),
+			"keyName":       created.KeyName,
+			"providers":     keyRequestProviders(created.ProviderAccess),
+			"justification": created.Justification,
+		},
+	})
+	return modelToKeyRequestDto(created), nil
+}
+
+// ListByUser 使用者自己的申請（新到舊）
+func (s *KeyRequestService) ListByUser(ctx context.Context, userID primitive.ObjectID, page, size int64) ([]*dto.KeyRequestDto, error) {
+	return s.List(ctx, bson.M{"userID": userID}, page, size)
+}
+
+// List 管理端查詢（新到舊）
+func (s *KeyRequestService) List(ctx context.Context, filter bson.M, page, size int64) ([]*dto.KeyRequestDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	requests, err := s.keyRequestRepo.List(ctx, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListKeyRequests error")
+	}
+	results := make([]*dto.KeyRequestDto, 0, len(requests))
+	for _, request := range requests {
+		results = append(results, modelToKeyRequestDto(request))
+	}
+	return results, nil
+}
+
+// GetByID 單筆申請
+func (s *KeyRequestService) GetByID(ctx context.Context, id primitive.ObjectID) (*dto.KeyRequestDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	request, err := s.getRequest(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	return modelToKeyRequestDto(request), nil
+}
+
+// Approve 核准並核發 API Key；審核者需為 editor / admin 且不可審核自己的申請。
+// 先以條件更新取得審核權（避免重複核發），核發失敗時退回待審核。
+func (s *KeyRequestService) Approve(ctx context.Context, id primitive.ObjectID, reviewer string, req *dto.ApproveKeyRequestDto) (*dto.KeyRequestDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(attribute.String("key_request.id", id.Hex()))
+
+	request, err := s.getPendingForReview(ctx, id, reviewer)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	requester, err := s.getUser(ctx, request.UserID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	if requester.Status != core.StatusActive {
+		return nil, cErr.Conflict(fmt.Sprintf("requester is %s", requester.Status))
+	}
+
+	create := &dto.CreateUserAPIKeyDto{
+		KeyName:        request.KeyName,
+		ProviderAccess: keyRequestAccessToProviderDtos(request.ProviderAccess),
+	}
+	if req.KeyName != nil {
+		create.KeyName = *req.KeyName
+	}
+	if req.ProviderAccess != nil {
+		create.ProviderAccess = *req.ProviderAccess
+	}
+	if err := resolveProviderKeys(create.ProviderAccess, req.ProviderKeys); err != nil {
+		return nil, err
+	}
+	approvedAccess := providerDtosToKeyRequestAccess(create.ProviderAccess)
+
+	now := time.Now().UTC()
+	claimed, err := s.keyRequestRepo.Decide(ctx, id, core.StatusApproved, reviewer, req.Note, approvedAccess, now)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database DecideKeyRequest error")
+	}
+	if !claimed {
+		return nil, cErr.Conflict("key request has already been reviewed")
+	}
+
+	issued, err := s.apiKeyService.Create(ctx, request.UserID, create)
+	if err != nil {
+		end(err)
+		if reopenErr := s.keyRequestRepo.Reopen(ctx, id); reopenErr != nil {
+			s.logger.Error("reopen key request after issue failure failed", zap.String("requestID", id.Hex()), zap.Error(reopenErr))
+		}
+		return nil, err
+	}
+	apiKeyID, _ := primitive.ObjectIDFromHex(issued.ID)
+	if err := s.keyRequestRepo.SetAPIKeyID(ctx, id, apiKeyID); err != nil {
+		s.logger.Error("link issued api key to key request failed",
+			zap.String("requestID", id.Hex()),
+			zap.String("apiKeyID", issued.ID),
+			zap.Error(err),
+		)
+	}
+
+	approved := *request
+	approved.Status = core.StatusApproved
+	approved.ReviewedBy = reviewer
+	approved.ReviewNote = req.Note
+	approved.ReviewedAt = &now
+	approved.ApprovedAccess = approvedAccess
+	approved.APIKeyID = &apiKeyID
+	approved.UpdatedAt = now
+
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      AuditActor{Type: core.AuditActorAdmin, ID: reviewer},
+		Action:     core.AuditActionKeyRequestApproved,
+		TargetType: core.AuditTargetKeyRequest,
+		TargetID:   id.Hex(),
+		Before:     request,
+		After:      &approved,
+		Reason:     req.Note,
+	})
+	s.emit(ctx, NotificationMessage{
+		Event:   core.NotificationEventKeyApproved,
+		Subject: fmt.Sprintf("API key request %q was approved", create.KeyName),
+		UserID:  request.UserID.Hex(),
+		KeyID:   issued.ID,
+		Data: map[string]interface{}{
+			"requestID": id.Hex(),
+			"keyName":   create.KeyName,
+			"apiKeyID":  issued.ID,
+			"providers": keyRequestProviders(approvedAccess),
+			"note":      req.Note,
+		},
+	})
+
+	result := modelToKeyRequestDto(&approved)
+	result.APIKey = issued
+	return result, nil
+}
+
+// Reject 駁回申請（需填寫原因）
+func (s *KeyRequestService) Reject(ctx context.Context, id primitive.ObjectID, reviewer string, req *dto.RejectKeyRequestDto) (*dto.KeyRequestDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(attribute.String("key_request.id", id.Hex()))
+
+	request, err := s.getPendingForReview(ctx, id, reviewer)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	now := time.Now().UTC()
+	claimed, err := s.keyRequestRepo.Decide(ctx, id, core.StatusRejected, reviewer, req.Reason, nil, now)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database DecideKeyRequest error")
+	}
+	if !claimed {
+		return nil, cErr.Conflict("key request has already been reviewed")
+	}
+
+	rejected := *request
+	rejected.Status = core.StatusRejected
+	rejected.ReviewedBy = reviewer
+	rejected.ReviewNote = req.Reason
+	rejected.ReviewedAt = &now
+	rejected.UpdatedAt = now
+
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      AuditActor{Type: core.AuditActorAdmin, ID: reviewer},
+		Action:     core.AuditActionKeyRequestRejected,
+		TargetType: core.AuditTargetKeyRequest,
+		TargetID:   id.Hex(),
+		Before:     request,
+		After:      &rejected,
+		Reason:     req.Reason,
+	})
+	s.emit(ctx, NotificationMessage{
+		Event:   core.NotificationEventKeyRejected,
+		Subject: fmt.Sprintf("API key request %q was rejected", request.KeyName),
+		UserID:  request.UserID.Hex(),
+		Data: map[string]interface{}{
+			"requestID": id.Hex(),
+			"keyName":   request.KeyName,
+			"reason":    req.Reason,
+		},
+	})
+	return modelToKeyRequestDto(&rejected), nil
+}
+
+// getPendingForReview 檢查審核者權限與申請狀態
+func (s *KeyRequestService) getPendingForReview(ctx context.Context, id primitive.ObjectID, reviewer string) (*model.KeyRequest, error) {
+	reviewerID, err := primitive.ObjectIDFromHex(reviewer)
+	if err != nil {
+		return nil, cErr.Forbidden("reviewer identity required")
+	}
+	reviewerUser, err := s.entityCache.GetUser(ctx, reviewerID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.Forbidden("reviewer not found")
+		}
+		return nil, cErr.DatabaseError("database GetUser error")
+	}
+	if reviewerUser.Status != core.StatusActive || (reviewerUser.Role != core.RoleAdmin && reviewerUser.Role != core.RoleEditor) {
+		return nil, cErr.Forbidden("only active editor or admin users can review key requests")
+	}
+
+	request, err := s.getRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.UserID == reviewerID {
+		return nil, cErr.Forbidden("cannot review your own key request")
+	}
+	if request.Status != core.StatusPending {
+		return nil, cErr.Conflict(fmt.Sprintf("key request is %s", request.Status))
+	}
+	return request, nil
+}
+
+func (s *KeyRequestService) getRequest(ctx context.Context, id primitive.ObjectID) (*model.KeyRequest, error) {
+	request, err := s.keyRequestRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("key request with id %s not found", id.Hex()))
+		}
+		return nil, cErr.DatabaseError("database GetKeyRequest error")
+	}
+	return request, nil
+}
+
+func (s *KeyRequestService) getUser(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	user, err := s.entityCache.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("user with id %s not found", id.Hex()))
+		}
+		return nil, cErr.DatabaseError("database GetUser error")
+	}
+	return user, nil
+}
+
+func (s *KeyRequestService) emit(ctx context.Context, message NotificationMessage) {
+	if err := s.notification.Emit(ctx, message); err != nil {
+		s.logger.Warn("emit key request notification failed",
+			zap.String("event", string(message.Event)),
+			zap.String("userID", message.UserID),
+			zap.Error(err),
+		)
+	}
+}
+
+// validateKeyRequestAccess provider 需為支援的名稱且不可重複
+func validateKeyRequestAccess(access []dto.KeyRequestAccessDto) error {
+	seen := make(map[core.ProviderName]struct{}, len(access))
+	for _, item := range access {
+		if !validate.IsValidProviderName(string(item.Provider)) {
+			return cErr.BadRequestBody(fmt.Sprintf("unsupported provider %s", item.Provider))
+		}
+		if _, ok := seen[item.Provider]; ok {
+			return cErr.BadRequestBody(fmt.Sprintf("duplicate provider %s", item.Provider))
+		}
+		seen[item.Provider] = struct{}{}
+		if (item.LimitPeriod == nil) != (item.LimitCount == nil) {
+			return cErr.BadRequestBody("limitPeriod and limitCount must be set together")
+		}
+		if item.ExpireTime != nil && !item.ExpireTime.After(time.Now()) {
+			return cErr.BadRequestBody("expireTime must be in the future")
+		}
+	}
+	return nil
+}
+
+func keyRequestProviders(access []model.KeyRequestAccess) []string {
+	providers := make([]string, 0, len(access))
+	for _, item := range access {
+		providers = append(providers, string(item.Provider))
+	}
+	return providers
+}
+
+func keyRequestAccessDtosToModels(access []dto.KeyRequestAccessDto) []model.KeyRequestAccess {
+	result := make([]model.KeyRequestAccess, len(access))
+	for i, item := range access {
+		result[i] = model.KeyRequestAccess{
+			Provider:    item.Provider,
+			ApiScopes:   item.ApiScopes,
+			LimitPeriod: item.LimitPeriod,
+			LimitCount:  item.LimitCount,
+			ExpireTime:  item.ExpireTime,
+		}
+	}
+	return result
+}
+
+func keyRequestAccessModelsToDtos(access []model.KeyRequestAccess) []dto.KeyRequestAccessDto {
+	result := make([]dto.KeyRequestAccessDto, len(access))
+	for i, item := range access {
+		result[i] = dto.KeyRequestAccessDto{
+			Provider:    item.Provider,
+			ApiScopes:   item.ApiScopes,
+			LimitPeriod: item.LimitPeriod,
+			LimitCount:  item.LimitCount,
+			ExpireTime:  item.ExpireTime,
+		}
+	}
+	return result
+}
+
+// keyRequestAccessToProviderDtos 依申請內容產生核發用的 provider 權限（啟用中）
+func keyRequestAccessToProviderDtos(access []model.KeyRequestAccess) []dto.ProviderAccessDto {
+	result := make([]dto.ProviderAccessDto, len(access))
+	for i, item := range access {
+		result[i] = dto.ProviderAccessDto{
+			Provider:    item.Provider,
+			Status:      core.StatusActive,
+			LimitPeriod: item.LimitPeriod,
+			LimitCount:  item.LimitCount,
+			ApiScopes:   item.ApiScopes,
+			ExpireTime:  item.ExpireTime,
+		}
+	}
+	return result
+}
+
+// resolveProviderKeys 未帶上游金鑰的 provider 以 providerKeys 補上；仍有缺少時回傳錯誤（核發的 key 無法呼叫上游）
+func resolveProviderKeys(access []dto.ProviderAccessDto, providerKeys map[core.ProviderName]string) error {
+	var missing []string
+	for i := range access {
+		if access[i].ProviderKey == "" {
+			access[i].ProviderKey = providerKeys[access[i].Provider]
+		}
+		if access[i].ProviderKey == "" {
+			missing = append(missing, string(access[i].Provider))
+		}
+	}
+	if len(missing) > 0 {
+		return cErr.BadRequestParams("upstream provider key required for: " + strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func providerDtosToKeyRequestAccess(access []dto.ProviderAccessDto) []model.KeyRequestAccess {
+	result := make([]model.KeyRequestAccess, len(access))
+	for i, item := range access {
+		result[i] = model.KeyRequestAccess{
+			Provider:    item.Provider,
+			ApiScopes:   item.ApiScopes,
+			LimitPeriod: item.LimitPeriod,
+			LimitCount:  item.LimitCount,
+			ExpireTime:  item.ExpireTime,
+		}
+	}
+	return result
+}
+
+func modelToKeyRequestDto(m *model.KeyRequest) *dto.KeyRequestDto {
+	result := &dto.KeyRequestDto{
+		ID:             m.ID.Hex(),
+		UserID:         m.UserID.Hex(),
+		KeyName:        m.KeyName,
+		ProviderAccess: keyRequestAccessModelsToDtos(m.ProviderAccess),
+		Justification:  m.Justification,
+		Status:         m.Status,
+		ReviewedBy:     m.ReviewedBy,
+		ReviewNote:     m.ReviewNote,
+		ReviewedAt:     m.ReviewedAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+	if len(m.ApprovedAccess) > 0 {
+		result.ApprovedAccess = keyRequestAccessModelsToDtos(m.ApprovedAccess)
+	}
+	if m.APIKeyID != nil {
+		result.APIKeyID = m.APIKeyID.Hex()
+	}
+	return result
+}
@@ -0,0 +1,636 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLifecycleWarnDays  = 7
+	defaultLifecycleBatchSize = 500
+)
+
+// LifecycleSweepResult 單輪閒置掃描結果
+type LifecycleSweepResult struct {
+	UsersWarned        int
+	UsersSuspended     int
+	UsersCleared       int
+	KeysFlagged        int
+	KeysUnflagged      int
+	ProvidersWarned    int
+	ProvidersSuspended int
+	ProvidersCleared   int
+}
+
+// LifecycleService 閒置帳號生命週期：
+// 使用者 / provider 存取超過 WarnDays 前先通知，通知後至少 WarnDays 且閒置期滿才停用；
+// 使用者停用時其 API Key 一併標記（owner_inactive），擁有者恢復後移除標記。
+// 重新啟用可由管理端或使用者本人（self-service，僅限因閒置停用者）發起，每次狀態變化都寫入稽核紀錄
+type LifecycleService struct {
+	trace        *telemetry.Trace
+	logger       *zap.Logger
+	config       *config.Configuration
+	userRepo     *mongoDb.UserRepository
+	apiKeyRepo   *mongoDb.UserAPIKeyRepository
+	entityCache  *EntityCacheService
+	notification *NotificationService
+	audit        *AuditService
+}
+
+func NewLifecycleService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	userRepo *mongoDb.UserRepository,
+	apiKeyRepo *mongoDb.UserAPIKeyRepository,
+	entityCache *EntityCacheService,
+	notification *NotificationService,
+	audit *AuditService,
+) *LifecycleService {
+	return &LifecycleService{
+		trace:        trace,
+		logger:       logger,
+		config:       config,
+		userRepo:     userRepo,
+		apiKeyRepo:   apiKeyRepo,
+		entityCache:  entityCache,
+		notification: notification,
+		audit:        audit,
+	}
+}
+
+// Sweep 執行一輪閒置掃描：使用者通知 / 停用、移除已恢復擁有者的 API Key 標記、provider 存取通知 / 停用
+func (s *LifecycleService) Sweep(ctx context.Context, actor AuditActor) (*LifecycleSweepResult, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	result := &LifecycleSweepResult{}
+	now := time.Now().UTC()
+	if days := s.config.Lifecycle.UserInactiveDays; days > 0 {
+		if err := s.sweepUsers(ctx, actor, days, now, result); err != nil {
+			end(err)
+			return result, err
+		}
+	}
+	if err := s.unflagRecoveredKeys(ctx, actor, result); err != nil {
+		end(err)
+		return result, err
+	}
+	if days := s.config.Lifecycle.KeyInactiveDays; days > 0 {
+		if err := s.sweepProviderAccess(ctx, actor, days, now, result); err != nil {
+			end(err)
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// ReactivateUser 管理端重新啟用 suspended 使用者（不論停用原因），並移除其 API Key 的停用標記
+func (s *LifecycleService) ReactivateUser(ctx context.Context, id primitive.ObjectID, actor AuditActor) (*dto.UserResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	if user.Status != core.StatusSuspended {
+		return nil, cErr.Conflict(fmt.Sprintf("user is %s, not suspended", user.Status))
+	}
+	if err := s.reactivateUser(ctx, actor, user, time.Now().UTC()); err != nil {
+		end(err)
+		return nil, err
+	}
+	updated, err := s.getUser(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	return modelToUserResponseDto(updated), nil
+}
+
+// ReactivateProviderAccess 管理端將 suspended 的 provider 存取恢復為 active（不論停用原因）
+func (s *LifecycleService) ReactivateProviderAccess(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	apiKeyID primitive.ObjectID,
+	provider core.ProviderName,
+	actor AuditActor,
+) (*dto.UserAPIKeyResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	key, err := s.getAPIKey(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	if key.UserID != userID {
+		return nil, cErr.NotFound(fmt.Sprintf("api key with id %s not found for user %s", apiKeyID.Hex(), userID.Hex()))
+	}
+	access := findProvider(key.ProviderAccess, provider)
+	if access == nil {
+		return nil, cErr.NotFound(fmt.Sprintf("provider %s not found on api key", provider))
+	}
+	if access.Status != core.StatusSuspended {
+		return nil, cErr.Conflict(fmt.Sprintf("provider access is %s, not suspended", access.Status))
+	}
+	if err := s.reactivateProvider(ctx, actor, key, access, time.Now().UTC()); err != nil {
+		end(err)
+		return nil, err
+	}
+	updated, err := s.getAPIKey(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	return modelToUserAPIKeyResponseDto(updated), nil
+}
+
+// SelfReactivate 使用者以自己的 API Key 恢復因閒置停用的帳號，以及這把 key 因閒置停用的 provider 存取；
+// 管理端停用的帳號 / 存取不能自行恢復
+func (s *LifecycleService) SelfReactivate(ctx context.Context, apiKeyID primitive.ObjectID) (*dto.ReactivationResultDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if !s.config.Lifecycle.SelfServiceReactivation {
+		return nil, cErr.Forbidden("self-service reactivation is disabled")
+	}
+	key, err := s.getAPIKey(ctx, apiKeyID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	user, err := s.getUser(ctx, key.UserID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("user.id", user.ID.Hex()), attribute.String("api_key.id", key.ID.Hex()))
+
+	actor := AuditActor{Type: core.AuditActorUser, ID: user.ID.Hex()}
+	now := time.Now().UTC()
+	result := &dto.ReactivationResultDto{Providers: make([]core.ProviderName, 0)}
+	switch {
+	case user.Status == core.StatusSuspended && user.SuspendReason == core.SuspendReasonInactive:
+		if err := s.reactivateUser(ctx, actor, user, now); err != nil {
+			end(err)
+			return nil, err
+		}
+		result.UserReactivated = true
+	case user.Status != core.StatusActive:
+		return nil, cErr.Forbidden("account is not active; contact an administrator")
+	}
+
+	for i := range key.ProviderAccess {
+		access := &key.ProviderAccess[i]
+		if access.Status != core.StatusSuspended || access.SuspendReason != core.SuspendReasonStale {
+			continue
+		}
+		if err := s.reactivateProvider(ctx, actor, key, access, now); err != nil {
+			end(err)
+			return nil, err
+		}
+		result.Providers = append(result.Providers, access.Provider)
+	}
+
+	if !result.UserReactivated && len(result.Providers) == 0 {
+		return nil, cErr.Conflict("nothing to reactivate")
+	}
+	return result, nil
+}
+
+// sweepUsers 依最後使用時間（從未使用時為建立時間）通知、停用或取消通知
+func (s *LifecycleService) sweepUsers(ctx context.Context, actor AuditActor, days int, now time.Time, result *LifecycleSweepResult) error {
+	inactiveFor, warnFor := s.windows(days)
+	suspendBefore := now.Add(-inactiveFor)
+	warnBefore := suspendBefore.Add(warnFor)
+
+	// 寬限期內的已通知者不取出；期間恢復使用者待寬限期結束才取消通知（恢復使用即不會被停用）
+	users, err := s.userRepo.ListInactivityCandidates(ctx, warnBefore, now.Add(-warnFor), s.batchSize())
+	if err != nil {
+		return cErr.DatabaseError("database ListInactivityCandidates error")
+	}
+	for _, user := range users {
+		seen := user.CreatedAt
+		if user.LastSeen != nil && !user.LastSeen.IsZero() {
+			seen = *user.LastSeen
+		}
+
+		switch {
+		case user.InactivityWarnedAt != nil && seen.After(*user.InactivityWarnedAt):
+			// 通知後又有使用，取消這次停用
+			if _, err := s.userRepo.SetInactivityWarning(ctx, user.ID, nil); err != nil {
+				return cErr.DatabaseError("database SetInactivityWarning error")
+			}
+			s.entityCache.InvalidateUser(ctx, user.ID)
+			s.audit.Record(ctx, AuditEntry{
+				Actor:      actor,
+				Action:     core.AuditActionUserInactivityCleared,
+				TargetType: core.AuditTargetUser,
+				TargetID:   user.ID.Hex(),
+				Before:     map[string]interface{}{"inactivityWarnedAt": *user.InactivityWarnedAt},
+				Reason:     "active again since " + seen.Format(time.RFC3339),
+			})
+			result.UsersCleared++
+
+		case user.InactivityWarnedAt == nil:
+			if !seen.Before(warnBefore) {
+				continue
+			}
+			suspendAt := maxTime(seen.Add(inactiveFor), now.Add(warnFor))
+			if _, err := s.userRepo.SetInactivityWarning(ctx, user.ID, &now); err != nil {
+				return cErr.DatabaseError("database SetInactivityWarning error")
+			}
+			s.entityCache.InvalidateUser(ctx, user.ID)
+			s.audit.Record(ctx, AuditEntry{
+				Actor:      actor,
+				Action:     core.AuditActionUserInactivityWarned,
+				TargetType: core.AuditTargetUser,
+				TargetID:   user.ID.Hex(),
+				After:      map[string]interface{}{"inactivityWarnedAt": now, "suspendAt": suspendAt},
+				Reason:     "no activity since " + seen.Format(time.RFC3339),
+			})
+			s.emit(ctx, NotificationMessage{
+				Event:   core.NotificationEventUserInactive,
+				Subject: fmt.Sprintf("User %s will be suspended at %s due to inactivity", userLabel(user), suspendAt.Format(time.RFC3339)),
+				UserID:  user.ID.Hex(),
+				Data: map[string]interface{}{
+					"lastSeen":     seen,
+					"inactiveDays": days,
+					"suspendAt":    suspendAt,
+				},
+				DedupKey: "inactive:user:" + user.ID.Hex() + ":" + strconv.FormatInt(seen.Unix(), 10),
+				DedupTTL: 24 * time.Hour,
+			})
+			result.UsersWarned++
+
+		case seen.Before(suspendBefore) && !now.Before(user.InactivityWarnedAt.Add(warnFor)):
+			suspended, err := s.userRepo.SuspendActive(ctx, user.ID, core.SuspendReasonInactive, now)
+			if err != nil {
+				return cErr.DatabaseError("database SuspendActive error")
+			}
+			if !suspended {
+				continue
+			}
+			s.entityCache.InvalidateUser(ctx, user.ID)
+			s.audit.Record(ctx, AuditEntry{
+				Actor:      actor,
+				Action:     core.AuditActionUserSuspended,
+				TargetType: core.AuditTargetUser,
+				TargetID:   user.ID.Hex(),
+				Before:     map[string]interface{}{"status": user.Status},
+				After:      map[string]interface{}{"status": core.StatusSuspended, "suspendReason": core.SuspendReasonInactive},
+				Reason:     "no activity since " + seen.Format(time.RFC3339),
+			})
+			flagged, err := s.flagUserKeys(ctx, actor, user.ID, now)
+			result.KeysFlagged += flagged
+			if err != nil {
+				return err
+			}
+			s.emit(ctx, NotificationMessage{
+				Event:   core.NotificationEventUserSuspended,
+				Subject: fmt.Sprintf("User %s was suspended due to inactivity", userLabel(user)),
+				UserID:  user.ID.Hex(),
+				Data: map[string]interface{}{
+					"lastSeen":     seen,
+					"inactiveDays": days,
+					"keysFlagged":  flagged,
+				},
+				DedupKey: "suspended:user:" + user.ID.Hex() + ":" + strconv.FormatInt(now.Unix(), 10),
+				DedupTTL: 24 * time.Hour,
+			})
+			result.UsersSuspended++
+		}
+	}
+	return nil
+}
+
+// sweepProviderAccess 依 provider 最後使用時間（從未使用時為 key 建立時間）通知、停用或取消通知
+func (s *LifecycleService) sweepProviderAccess(ctx context.Context, actor AuditActor, days int, now time.Time, result *LifecycleSweepResult) error {
+	inactiveFor, warnFor := s.windows(days)
+	suspendBefore := now.Add(-inactiveFor)
+	warnBefore := suspendBefore.Add(warnFor)
+
+	keys, err := s.apiKeyRepo.ListInactivityCandidates(ctx, warnBefore, now.Add(-warnFor), s.batchSize())
+	if err != nil {
+		return cErr.DatabaseError("mongodb list inactive api keys failed")
+	}
+	for _, key := range keys {
+		changed := false
+		for i := range key.ProviderAccess {
+			access := &key.ProviderAccess[i]
+			if access.Status != core.StatusActive {
+				continue
+			}
+			seen := key.CreatedAt
+			if access.LastSeen != nil && !access.LastSeen.IsZero() {
+				seen = *access.LastSeen
+			}
+			entry := AuditEntry{
+				Actor:      actor,
+				TargetType: core.AuditTargetProviderAccess,
+				TargetID:   key.ID.Hex(),
+				Provider:   access.Provider,
+			}
+
+			switch {
+			case access.InactivityWarnedAt != nil && seen.After(*access.InactivityWarnedAt):
+				if err := s.apiKeyRepo.SetProviderInactivityWarning(ctx, key.ID, access.Provider, nil); err != nil {
+					return cErr.DatabaseError("mongodb clear provider inactivity warning failed")
+				}
+				entry.Action = core.AuditActionProviderInactivityCleared
+				entry.Before = map[string]interface{}{"inactivityWarnedAt": *access.InactivityWarnedAt}
+				entry.Reason = "active again since " + seen.Format(time.RFC3339)
+				s.audit.Record(ctx, entry)
+				result.ProvidersCleared++
+				changed = true
+
+			case access.InactivityWarnedAt == nil:
+				if !seen.Before(warnBefore) {
+					continue
+				}
+				suspendAt := maxTime(seen.Add(inactiveFor), now.Add(warnFor))
+				if err := s.apiKeyRepo.SetProviderInactivityWarning(ctx, key.ID, access.Provider, &now); err != nil {
+					return cErr.DatabaseError("mongodb set provider inactivity warning failed")
+				}
+				entry.Action = core.AuditActionProviderInactivityWarned
+				entry.After = map[string]interface{}{"inactivityWarnedAt": now, "suspendAt": suspendAt}
+				entry.Reason = "no activity since " + seen.Format(time.RFC3339)
+				s.audit.Record(ctx, entry)
+				s.emit(ctx, NotificationMessage{
+					Event:   core.NotificationEventKeyInactive,
+					Subject: fmt.Sprintf("API key %s (%s) will be suspended at %s due to inactivity", key.KeyName, access.Provider, suspendAt.Format(time.RFC3339)),
+					KeyID:   key.ID.Hex(),
+					UserID:  key.UserID.Hex(),
+					Data: map[string]interface{}{
+						"keyName":      key.KeyName,
+						"provider":     access.Provider,
+						"lastSeen":     seen,
+						"inactiveDays": days,
+						"suspendAt":    suspendAt,
+					},
+					DedupKey: "inactive:key:" + key.ID.Hex() + ":" + string(access.Provider) + ":" + strconv.FormatInt(seen.Unix(), 10),
+					DedupTTL: 24 * time.Hour,
+				})
+				result.ProvidersWarned++
+				changed = true
+
+			case seen.Before(suspendBefore) && !now.Before(access.InactivityWarnedAt.Add(warnFor)):
+				suspended, err := s.apiKeyRepo.SuspendProviderAccess(ctx, key.ID, access.Provider, core.SuspendReasonStale)
+				if err != nil {
+					return cErr.DatabaseError("mongodb suspend provider access failed")
+				}
+				if !suspended {
+					continue
+				}
+				entry.Action = core.AuditActionProviderSuspended
+				entry.Before = map[string]interface{}{"status": access.Status}
+				entry.After = map[string]interface{}{"status": core.StatusSuspended, "suspendReason": core.SuspendReasonStale}
+				entry.Reason = "no activity since " + seen.Format(time.RFC3339)
+				s.audit.Record(ctx, entry)
+				s.emit(ctx, NotificationMessage{
+					Event:   core.NotificationEventKeySuspended,
+					Subject: fmt.Sprintf("API key %s (%s) was suspended due to inactivity", key.KeyName, access.Provider),
+					KeyID:   key.ID.Hex(),
+					UserID:  key.UserID.Hex(),
+					Data: map[string]interface{}{
+						"keyName":      key.KeyName,
+						"provider":     access.Provider,
+						"lastSeen":     seen,
+						"inactiveDays": days,
+					},
+					DedupKey: "suspended:key:" + key.ID.Hex() + ":" + string(access.Provider) + ":" + strconv.FormatInt(now.Unix(), 10),
+					DedupTTL: 24 * time.Hour,
+				})
+				result.ProvidersSuspended++
+				changed = true
+			}
+		}
+		if changed {
+			s.entityCache.InvalidateAPIKey(ctx, key.ID)
+		}
+	}
+	return nil
+}
+
+// unflagRecoveredKeys 擁有者已恢復為 active（例如管理端直接改狀態）的 API Key 移除 owner_inactive 標記
+func (s *LifecycleService) unflagRecoveredKeys(ctx context.Context, actor AuditActor, result *LifecycleSweepResult) error {
+	keys, err := s.apiKeyRepo.ListBySuspendReason(ctx, core.SuspendReasonOwnerInactive, 0)
+	if err != nil {
+		return cErr.DatabaseError("mongodb list flagged api keys failed")
+	}
+	ownerActive := make(map[primitive.ObjectID]bool)
+	for _, key := range keys {
+		active, ok := ownerActive[key.UserID]
+		if !ok {
+			owner, err := s.entityCache.GetUser(ctx, key.UserID)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return cErr.DatabaseError("database GetUser error")
+			}
+			active = err == nil && owner.Status == core.StatusActive
+			ownerActive[key.UserID] = active
+		}
+		if !active {
+			continue
+		}
+		unflagged, err := s.unflagKey(ctx, actor, key, "owner is active again")
+		if err != nil {
+			return err
+		}
+		if unflagged {
+			result.KeysUnflagged++
+		}
+	}
+	return nil
+}
+
+// reactivateUser 使用者恢復為 active 並移除其 API Key 的 owner_inactive 標記
+func (s *LifecycleService) reactivateUser(ctx context.Context, actor AuditActor, user *model.User, now time.Time) error {
+	reactivated, err := s.userRepo.Reactivate(ctx, user.ID, now)
+	if err != nil {
+		return cErr.DatabaseError("database Reactivate error")
+	}
+	if !reactivated {
+		return cErr.Conflict("user is no longer suspended")
+	}
+	s.entityCache.InvalidateUser(ctx, user.ID)
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      actor,
+		Action:     core.AuditActionUserReactivated,
+		TargetType: core.AuditTargetUser,
+		TargetID:   user.ID.Hex(),
+		Before:     map[string]interface{}{"status": user.Status, "suspendReason": user.SuspendReason, "suspendedAt": user.SuspendedAt},
+		After:      map[string]interface{}{"status": core.StatusActive, "lastSeen": now},
+	})
+
+	keys, err := s.apiKeyRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return cErr.DatabaseError("mongodb list api keys failed")
+	}
+	for _, key := range keys {
+		if key.SuspendReason != core.SuspendReasonOwnerInactive {
+			continue
+		}
+		if _, err := s.unflagKey(ctx, actor, key, "owner reactivated"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reactivateProvider provider 存取恢復為 active
+func (s *LifecycleService) reactivateProvider(ctx context.Context, actor AuditActor, key *model.UserAPIKey, access *model.ProviderAccess, now time.Time) error {
+	reactivated, err := s.apiKeyRepo.ReactivateProviderAccess(ctx, key.ID, access.Provider, now)
+	if err != nil {
+		return cErr.DatabaseError("mongodb reactivate provider access failed")
+	}
+	if !reactivated {
+		return cErr.Conflict("provider access is no longer suspended")
+	}
+	s.entityCache.InvalidateAPIKey(ctx, key.ID)
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      actor,
+		Action:     core.AuditActionProviderReactivated,
+		TargetType: core.AuditTargetProviderAccess,
+		TargetID:   key.ID.Hex(),
+		Provider:   access.Provider,
+		Before:     map[string]interface{}{"status": access.Status, "suspendReason": access.SuspendReason},
+		After:      map[string]interface{}{"status": core.StatusActive, "lastSeen": now},
+	})
+	return nil
+}
+
+// flagUserKeys 標記使用者所有尚未標記的 API Key，回傳標記數
+func (s *LifecycleService) flagUserKeys(ctx context.Context, actor AuditActor, userID primitive.ObjectID, now time.Time) (int, error) {
+	keys, err := s.apiKeyRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return 0, cErr.DatabaseError("mongodb list api keys failed")
+	}
+	flagged := 0
+	for _, key := range keys {
+		ok, err := s.apiKeyRepo.SetSuspendFlag(ctx, key.ID, core.SuspendReasonOwnerInactive, now)
+		if err != nil {
+			return flagged, cErr.DatabaseError("mongodb flag api key failed")
+		}
+		if !ok {
+			continue
+		}
+		s.entityCache.InvalidateAPIKey(ctx, key.ID)
+		s.audit.Record(ctx, AuditEntry{
+			Actor:      actor,
+			Action:     core.AuditActionAPIKeyFlagged,
+			TargetType: core.AuditTargetAPIKey,
+			TargetID:   key.ID.Hex(),
+			After:      map[string]interface{}{"suspendReason": core.SuspendReasonOwnerInactive, "suspendedAt": now},
+			Reason:     "owner suspended due to inactivity",
+		})
+		flagged++
+	}
+	return flagged, nil
+}
+
+// unflagKey 移除 owner_inactive 標記
+func (s *LifecycleService) unflagKey(ctx context.Context, actor AuditActor, key *model.UserAPIKey, reason string) (bool, error) {
+	ok, err := s.apiKeyRepo.ClearSuspendFlag(ctx, key.ID, core.SuspendReasonOwnerInactive)
+	if err != nil {
+		return false, cErr.DatabaseError("mongodb unflag api key failed")
+	}
+	if !ok {
+		return false, nil
+	}
+	s.entityCache.InvalidateAPIKey(ctx, key.ID)
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      actor,
+		Action:     core.AuditActionAPIKeyUnflagged,
+		TargetType: core.AuditTargetAPIKey,
+		TargetID:   key.ID.Hex(),
+		Before:     map[string]interface{}{"suspendReason": key.SuspendReason, "suspendedAt": key.SuspendedAt},
+		Reason:     reason,
+	})
+	return true, nil
+}
+
+func (s *LifecycleService) getUser(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("user with id %s not found", id.Hex()))
+		}
+		return nil, cErr.DatabaseError("database GetUserByID error")
+	}
+	return user, nil
+}
+
+func (s *LifecycleService) getAPIKey(ctx context.Context, id primitive.ObjectID) (*model.UserAPIKey, error) {
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("api key with id %s not found", id.Hex()))
+		}
+		return nil, cErr.DatabaseError("mongodb get api key failed")
+	}
+	return key, nil
+}
+
+// emit 通知失敗只記 log，不影響狀態轉換
+func (s *LifecycleService) emit(ctx context.Context, message NotificationMessage) {
+	if err := s.notification.Emit(ctx, message); err != nil {
+		s.logger.Warn("emit lifecycle notification failed",
+			zap.String("event", string(message.Event)),
+			zap.String("userID", message.UserID),
+			zap.String("apiKeyID", message.KeyID),
+			zap.Error(err),
+		)
+	}
+}
+
+// windows 閒置期與通知期；通知期需小於閒置期
+func (s *LifecycleService) windows(days int) (inactiveFor, warnFor time.Duration) {
+	warnDays := s.config.Lifecycle.WarnDays
+	if warnDays <= 0 {
+		warnDays = defaultLifecycleWarnDays
+	}
+	if warnDays >= days {
+		warnDays = days - 1
+	}
+	return time.Duration(days) * 24 * time.Hour, time.Duration(warnDays) * 24 * time.Hour
+}
+
+func (s *LifecycleService) batchSize() int64 {
+	if s.config.Lifecycle.BatchSize > 0 {
+		return s.config.Lifecycle.BatchSize
+	}
+	return defaultLifecycleBatchSize
+}
+
+func userLabel(user *model.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.ID.Hex()
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+	"interchange/utils/validate"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaintenanceRefreshSeconds    = 30
+	defaultMaintenanceRetryAfterSeconds = 300
+	defaultMaintenanceMessage           = "service is under maintenance, please retry later"
+	maintenanceLoadTimeout              = 5 * time.Second
+)
+
+// MaintenanceService 維護模式：開關存於 Redis hash，每個 replica 保留一份本機快照供熱路徑判斷。
+// 管理端異動後以 pub/sub 通知所有 replica 重新載入；通知遺失時以定期重新載入為最長不一致時間。
+// 排程時間窗於判斷當下比對，不需額外觸發。Redis 無法讀取時沿用上一份快照（啟動時為空，即不擋請求）
+type MaintenanceService struct {
+	trace           *telemetry.Trace
+	logger          *zap.Logger
+	config          *config.Configuration
+	maintenanceRepo *redisDb.MaintenanceRepository
+	audit           *AuditService
+
+	mu       sync.RWMutex
+	switches []*core.MaintenanceSwitch // 只整批替換，不修改元素
+}
+
+func NewMaintenanceService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	maintenanceRepo *redisDb.MaintenanceRepository,
+	audit *AuditService,
+) (*MaintenanceService, func()) {
+	s := &MaintenanceService{
+		trace:           trace,
+		logger:          logger,
+		config:          config,
+		maintenanceRepo: maintenanceRepo,
+		audit:           audit,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.reload(ctx)
+	done := make(chan struct{})
+	go s.watch(ctx, done)
+	cleanup := func() {
+		cancel()
+		<-done
+	}
+	return s, cleanup
+}
+
+// Check 判斷請求是否因維護被擋；回傳最精確（endpoint > provider > global）且未放行此 API Key 的生效開關，沒有則為 nil
+func (s *MaintenanceService) Check(provider core.ProviderName, endpoint string, apiKeyID string) *core.MaintenanceSwitch {
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched *core.MaintenanceSwitch
+	for _, sw := range s.switches {
+		if !sw.ActiveAt(now) || !maintenanceMatches(sw, provider, endpoint) || maintenanceAllows(sw, apiKeyID) {
+			continue
+		}
+		if matched == nil || maintenanceRank(sw.Scope) > maintenanceRank(matched.Scope) {
+			matched = sw
+		}
+	}
+	return matched
+}
+
+// Notice 組出回給 client 的維護說明；Retry-After 依序取設定值、距結束時間秒數、預設值
+func (s *MaintenanceService) Notice(sw *core.MaintenanceSwitch) dto.MaintenanceNoticeDto {
+	message := sw.Message
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+	return dto.MaintenanceNoticeDto{
+		Scope:      sw.Scope,
+		Provider:   sw.Provider,
+		Endpoint:   sw.Endpoint,
+		Message:    message,
+		RetryAfter: s.retryAfter(sw, time.Now()),
+		EndsAt:     sw.EndsAt,
+	}
+}
+
+// List 所有開關（直接讀 Redis，含未啟用與排程中）
+func (s *MaintenanceService) List(ctx context.Context) ([]*dto.MaintenanceDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	switches, err := s.load(ctx)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("redis ListMaintenance error")
+	}
+	fields := make([]string, 0, len(switches))
+	for field := range switches {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	now := time.Now()
+	results := make([]*dto.MaintenanceDto, 0, len(fields))
+	for _, field := range fields {
+		results = append(results, maintenanceToDto(switches[field], now))
+	}
+	return results, nil
+}
+
+// Upsert 新增或覆寫單一開關，並通知所有 replica 重新載入
+func (s *MaintenanceService) Upsert(ctx context.Context, operator string, req *dto.UpsertMaintenanceDto) (*dto.MaintenanceDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	target := dto.MaintenanceTargetDto{Scope: req.Scope, Provider: req.Provider, Endpoint: req.Endpoint}
+	if err := normalizeMaintenanceTarget(&target); err != nil {
+		return nil, cErr.BadRequestBody(err.Error())
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.StartsAt.Before(*req.EndsAt) {
+		return nil, cErr.BadRequestBody("startsAt must be earlier than endsAt")
+	}
+
+	allowKeys := make([]string, 0, len(req.AllowKeys))
+	seen := make(map[string]struct{}, len(req.AllowKeys))
+	for _, key := range req.AllowKeys {
+		key = strings.ToLower(key)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		allowKeys = append(allowKeys, key)
+	}
+	sw := &core.MaintenanceSwitch{
+		Scope:             target.Scope,
+		Provider:          target.Provider,
+		Endpoint:          target.Endpoint,
+		Enabled:           *req.Enabled,
+		StartsAt:          utcTime(req.StartsAt),
+		EndsAt:            utcTime(req.EndsAt),
+		Message:           strings.TrimSpace(req.Message),
+		RetryAfterSeconds: req.RetryAfterSeconds,
+		AllowKeys:         allowKeys,
+		UpdatedBy:         operator,
+		UpdatedAt:         time.Now().UTC(),
+	}
+
+	existing, err := s.load(ctx)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("redis GetMaintenance error")
+	}
+	value, err := json.Marshal(sw)
+	if err != nil {
+		end(err)
+		return nil, cErr.InternalServer("marshal maintenance switch failed")
+	}
+	if err := s.maintenanceRepo.Set(ctx, sw.Field(), value); err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("redis SetMaintenance error")
+	}
+	s.changed(ctx, sw.Field())
+
+	before := existing[sw.Field()]
+	s.audit.Record(ctx, AuditEntry{
+		Action:     core.AuditActionMaintenanceUpdated,
+		TargetType: core.AuditTargetMaintenance,
+		TargetID:   sw.Field(),
+		Provider:   sw.Provider,
+		Before:     maintenanceAuditSnapshot(before),
+		After:      maintenanceAuditSnapshot(sw),
+	})
+	s.logger.Info("maintenance switch updated",
+		zap.String("field", sw.Field()),
+		zap.Bool("enabled", sw.Enabled),
+		zap.String("operator", operator),
+	)
+	return maintenanceToDto(sw, time.Now()), nil
+}
+
+// Delete 移除單一開關，並通知所有 replica 重新載入
+func (s *MaintenanceService) Delete(ctx context.Context, operator string, target *dto.MaintenanceTargetDto) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if err := normalizeMaintenanceTarget(target); err != nil {
+		return cErr.BadRequestParams(err.Error())
+	}
+	field := (&core.MaintenanceSwitch{Scope: target.Scope, Provider: target.Provider, Endpoint: target.Endpoint}).Field()
+
+	existing, err := s.load(ctx)
+	if err != nil {
+		end(err)
+		return cErr.DatabaseError("redis GetMaintenance error")
+	}
+	deleted, err := s.maintenanceRepo.Delete(ctx, field)
+	if err != nil {
+		end(err)
+		return cErr.DatabaseError("redis DeleteMaintenance error")
+	}
+	if !deleted {
+		return cErr.NotFound(fmt.Sprintf("maintenance switch %s not found", field))
+	}
+	s.changed(ctx, field)
+
+	s.audit.Record(ctx, AuditEntry{
+		Action:     core.AuditActionMaintenanceRemoved,
+		TargetType: core.AuditTargetMaintenance,
+		TargetID:   field,
+		Provider:   target.Provider,
+		Before:     maintenanceAuditSnapshot(existing[field]),
+		After:      map[string]interface{}{},
+	})
+	s.logger.Info("maintenance switch removed", zap.String("field", field), zap.String("operator", operator))
+	return nil
+}
+
+// changed 本機立即重新載入並通知其他 replica
+func (s *MaintenanceService) changed(ctx context.Context, field string) {
+	s.reload(ctx)
+	if err := s.maintenanceRepo.Publish(ctx, field); err != nil {
+		s.logger.Warn("maintenance publish failed", zap.String("field", field), zap.Error(err))
+	}
+}
+
+// watch 接收其他 replica 的異動通知並定期重新載入（斷線由 redis client 自動重連）
+func (s *MaintenanceService) watch(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	pubsub := s.maintenanceRepo.Subscribe(ctx)
+	defer pubsub.Close()
+
+	interval := time.Duration(s.config.Maintenance.RefreshSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultMaintenanceRefreshSeconds * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-messages:
+			if !ok {
+				return
+			}
+			s.reload(ctx)
+		case <-ticker.C:
+			s.reload(ctx)
+		}
+	}
+}
+
+// reload 從 Redis 重新載入本機快照；失敗時保留原快照
+func (s *MaintenanceService) reload(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, maintenanceLoadTimeout)
+	defer cancel()
+
+	switches, err := s.load(ctx)
+	if err != nil {
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			s.logger.Warn("maintenance reload failed, keep previous snapshot", zap.Error(err))
+		}
+		return
+	}
+	snapshot := make([]*core.MaintenanceSwitch, 0, len(switches))
+	for _, sw := range switches {
+		snapshot = append(snapshot, sw)
+	}
+	s.mu.Lock()
+	s.switches = snapshot
+	s.mu.Unlock()
+}
+
+// load 讀取所有開關（field → 開關）；無法解析的項目略過
+func (s *MaintenanceService) load(ctx context.Context) (map[string]*core.MaintenanceSwitch, error) {
+	values, err := s.maintenanceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switches := make(map[string]*core.MaintenanceSwitch, len(values))
+	for field, value := range values {
+		sw := &core.MaintenanceSwitch{}
+		if err := json.Unmarshal([]byte(value), sw); err != nil {
+			s.logger.Warn("invalid maintenance switch", zap.String("field", field), zap.Error(err))
+			continue
+		}
+		switches[field] = sw
+	}
+	return switches, nil
+}
+
+func (s *MaintenanceService) retryAfter(sw *core.MaintenanceSwitch, now time.Time) int64 {
+	if sw.RetryAfterSeconds > 0 {
+		return sw.RetryAfterSeconds
+	}
+	if sw.EndsAt != nil {
+		if seconds := int64(sw.EndsAt.Sub(now).Seconds()) + 1; seconds > 0 {
+			return seconds
+		}
+	}
+	if s.config.Maintenance.DefaultRetryAfterSeconds > 0 {
+		return int64(s.config.Maintenance.DefaultRetryAfterSeconds)
+	}
+	return defaultMaintenanceRetryAfterSeconds
+}
+
+// normalizeMaintenanceTarget 檢查 scope 與 provider / endpoint 的組合並標準化 endpoint
+func normalizeMaintenanceTarget(target *dto.MaintenanceTargetDto) error {
+	if target.Provider != "" && !validate.IsValidProviderName(string(target.Provider)) {
+		return fmt.Errorf("invalid provider %s", target.Provider)
+	}
+	switch target.Scope {
+	case core.MaintenanceScopeGlobal:
+		if target.Provider != "" || target.Endpoint != "" {
+			return fmt.Errorf("global maintenance must not specify provider or endpoint")
+		}
+	case core.MaintenanceScopeProvider:
+		if target.Provider == "" {
+			return fmt.Errorf("provider is required for provider maintenance")
+		}
+		if target.Endpoint != "" {
+			return fmt.Errorf("provider maintenance must not specify endpoint")
+		}
+	case core.MaintenanceScopeEndpoint:
+		if !strings.HasPrefix(target.Endpoint, "/") {
+			return fmt.Errorf("endpoint is required and must start with /")
+		}
+		target.Endpoint = normalizeMaintenanceEndpoint(target.Endpoint)
+	default:
+		return fmt.Errorf("invalid scope %s", target.Scope)
+	}
+	return nil
+}
+
+// normalizeMaintenanceEndpoint 去除多餘斜線，保留結尾的 "/*"
+func normalizeMaintenanceEndpoint(endpoint string) string {
+	if strings.HasSuffix(endpoint, "/*") {
+		return path.Clean(strings.TrimSuffix(endpoint, "/*")) + "/*"
+	}
+	return path.Clean(endpoint)
+}
+
+// maintenanceMatches 開關是否涵蓋此 provider / endpoint；endpoint 規則與 ApiScope 相同（精準或 "/xxx/*"）
+func maintenanceMatches(sw *core.MaintenanceSwitch, provider core.ProviderName, endpoint string) bool {
+	switch sw.Scope {
+	case core.MaintenanceScopeGlobal:
+		return true
+	case core.MaintenanceScopeProvider:
+		return sw.Provider == provider
+	case core.MaintenanceScopeEndpoint:
+		if sw.Provider != "" && sw.Provider != provider {
+			return false
+		}
+		if sw.Endpoint == endpoint {
+			return true
+		}
+		if strings.HasSuffix(sw.Endpoint, "/*") {
+			base := strings.TrimSuffix(sw.Endpoint, "/*")
+			return endpoint == base || strings.HasPrefix(endpoint, base+"/")
+		}
+	}
+	return false
+}
+
+func maintenanceAllows(sw *core.MaintenanceSwitch, apiKeyID string) bool {
+	if apiKeyID == "" {
+		return false
+	}
+	for _, key := range sw.AllowKeys {
+		if key == apiKeyID {
+			return true
+		}
+	}
+	return false
+}
+
+func maintenanceRank(scope core.MaintenanceScope) int {
+	switch scope {
+	case core.MaintenanceScopeEndpoint:
+		return 2
+	case core.MaintenanceScopeProvider:
+		return 1
+	}
+	return 0
+}
+
+// maintenanceAuditSnapshot 稽核比對用快照（不含更新者與時間）
+func maintenanceAuditSnapshot(sw *core.MaintenanceSwitch) interface{} {
+	if sw == nil {
+		return map[string]interface{}{}
+	}
+	snapshot := *sw
+	snapshot.UpdatedBy = ""
+	return snapshot
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func maintenanceToDto(sw *core.MaintenanceSwitch, now time.Time) *dto.MaintenanceDto {
+	allowKeys := sw.AllowKeys
+	if allowKeys == nil {
+		allowKeys = []string{}
+	}
+	return &dto.MaintenanceDto{
+		Scope:             sw.Scope,
+		Provider:          sw.Provider,
+		Endpoint:          sw.Endpoint,
+		Enabled:           sw.Enabled,
+		Active:            sw.ActiveAt(now),
+		StartsAt:          sw.StartsAt,
+		EndsAt:            sw.EndsAt,
+		Message:           sw.Message,
+		RetryAfterSeconds: sw.RetryAfterSeconds,
+		AllowKeys:         allowKeys,
+		UpdatedBy:         sw.UpdatedBy,
+		UpdatedAt:         sw.UpdatedAt,
+	}
+}
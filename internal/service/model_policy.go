@@ -0,0 +1,133 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"interchange/internal/database/mongodb/model"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/service/models"
+)
+
+// ResolveModel 依 ProviderAccess.Models 將別名改寫為實際模型，並檢查是否允許使用。
+// 未設定模型限制時原樣回傳。
+func ResolveModel(providerAccess *model.ProviderAccess, requested string) (string, error) {
+	if providerAccess == nil || providerAccess.Models == nil {
+		return requested, nil
+	}
+	policy := providerAccess.Models
+	resolved := requested
+	if target, ok := policy.Aliases[requested]; ok && target != "" {
+		resolved = target
+	}
+	if !ModelAllowed(policy, resolved) {
+		return "", cErr.Forbidden("model not allowed: " + requested)
+	}
+	return resolved, nil
+}
+
+// ModelAllowed deny 優先；allow 為空代表不限制
+func ModelAllowed(policy *model.ModelPolicy, name string) bool {
+	if policy == nil {
+		return true
+	}
+	if matchModel(policy.Deny, name) {
+		return false
+	}
+	return len(policy.Allow) == 0 || matchModel(policy.Allow, name)
+}
+
+// FilterModels 只保留此 key 可用的模型，並附上指向可用模型的別名
+func FilterModels(providerAccess *model.ProviderAccess, list *models.ListResponse) *models.ListResponse {
+	if providerAccess == nil || providerAccess.Models == nil || list == nil {
+		return list
+	}
+	policy := providerAccess.Models
+	filtered := &models.ListResponse{Object: list.Object, Data: make([]models.Model, 0, len(list.Data))}
+	available := make(map[string]models.Model, len(list.Data))
+	for _, item := range list.Data {
+		if ModelAllowed(policy, item.ID) {
+			filtered.Data = append(filtered.Data, item)
+			available[item.ID] = item
+		}
+	}
+	for alias, target := range policy.Aliases {
+		if item, ok := available[target]; ok {
+			item.ID = alias
+			filtered.Data = append(filtered.Data, item)
+		}
+	}
+	return filtered
+}
+
+// ResolveBodyModel 檢查 JSON body 的 model 欄位並改寫別名（MCP passthrough 用）。
+// 非 JSON 物件或沒有 model 欄位時原樣回傳。
+func ResolveBodyModel(providerAccess *model.ProviderAccess, body []byte) ([]byte, string, error) {
+	if providerAccess == nil || providerAccess.Models == nil || len(bytes.TrimSpace(body)) == 0 {
+		return body, "", nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body, "", nil
+	}
+	raw, ok := fields["model"]
+	if !ok {
+		return body, "", nil
+	}
+	var requested string
+	if err := json.Unmarshal(raw, &requested); err != nil {
+		return nil, "", cErr.BadRequestBody("invalid model field")
+	}
+	resolved, err := ResolveModel(providerAccess, requested)
+	if err != nil {
+		return nil, "", err
+	}
+	if resolved == requested {
+		return body, resolved, nil
+	}
+	fields["model"], _ = json.Marshal(resolved)
+	rewritten, err := json.Marshal(fields)
+	if err != nil {
+		return nil, "", cErr.InternalServer("rewrite model field failed")
+	}
+	return rewritten, resolved, nil
+}
+
+// ResolvePathModel 檢查路徑中的 models/{model}（例如 Gemini 的 models/gemini-pro:generateContent）並改寫別名
+func ResolvePathModel(providerAccess *model.ProviderAccess, action string) (string, error) {
+	if providerAccess == nil || providerAccess.Models == nil {
+		return action, nil
+	}
+	segments := strings.Split(action, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] != "models" || segments[i+1] == "" {
+			continue
+		}
+		name, method, _ := strings.Cut(segments[i+1], ":")
+		resolved, err := ResolveModel(providerAccess, name)
+		if err != nil {
+			return "", err
+		}
+		if method != "" {
+			resolved += ":" + method
+		}
+		segments[i+1] = resolved
+		return strings.Join(segments, "/"), nil
+	}
+	return action, nil
+}
+
+// matchModel glob 比對（path.Match 語法，例如 gpt-4o*）
+func matchModel(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"interchange/internal/core"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/telemetry"
 	"io"
 	"net/http"
@@ -16,19 +17,24 @@ import (
 
 type OpenAIService struct {
 	HTTPClient *http.Client
+	clients    *outbound.Clients
 	trace      *telemetry.Trace
 }
 
-func NewOpenAIService(trace *telemetry.Trace, client *http.Client) Service {
-	return &OpenAIService{HTTPClient: client, trace: trace}
+func NewOpenAIService(trace *telemetry.Trace, clients *outbound.Clients) Service {
+	return &OpenAIService{HTTPClient: clients.For(core.ProviderOpenAI), clients: clients, trace: trace}
 }
 
 func (s *OpenAIService) List(ctx context.Context, apiKey string) (*ListResponse, error) {
 
-	url := string(core.OpenAIAPIBaseURL) + "/v1" + string(core.OpenAIModelsEndpoint)
+	url, urlErr := s.clients.URL(core.ProviderOpenAI, "v1", string(core.OpenAIModelsEndpoint))
 
 	ctx, span, end := s.trace.WithSpan(ctx, "openai.models.list")
 	defer end(nil)
+	if urlErr != nil {
+		end(urlErr)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 
 	span.SetAttributes(
 		attribute.String("ai.provider", "openai"),
@@ -41,14 +47,17 @@ func (s *OpenAIService) List(ctx context.Context, apiKey string) (*ListResponse,
 		end(err)
 		return nil, cErr.InternalServer("create http request failed")
 	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	if err := s.clients.Authorize(req, core.ProviderOpenAI, apiKey); err != nil {
+		end(err)
+		return nil, cErr.ServiceUnavailable("openai provider is not available")
+	}
 	req.Header.Set("Accept", "application/json")
 
 	// 發送
 	resp, err := s.HTTPClient.Do(req)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("openai api request failed")
+		return nil, outbound.RequestError(err, "openai api request failed")
 	}
 	defer resp.Body.Close()
 
@@ -0,0 +1,1017 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/lru"
+	"interchange/internal/pkg/outbound"
+	"interchange/internal/telemetry"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNotificationRuleRefreshSeconds = 60
+	defaultNotificationExpiryWarnDays     = 7
+	defaultNotificationUpstreamCooldown   = 900
+	defaultNotificationSpendWindowMinutes = 60
+	defaultNotificationSpendBaselineHours = 24
+	defaultNotificationSpendMultiplier    = 3
+	defaultNotificationSpendMinCost       = 1
+	notificationRecentSize                = 10000
+	notificationRecentTTL                 = 30 * time.Second
+	notificationEmitTimeout               = 5 * time.Second
+	notificationRedacted                  = "[redacted]"
+)
+
+// 規則未設定門檻時的配額通知門檻（百分比）
+var defaultNotificationThresholds = []int{50, 80, 100}
+
+// NotificationMessage 待送出的事件。
+// Scope 非空時只有該層級（與 global）的規則會收到；DedupKey 非空時 DedupTTL 內相同 key 只送一次（跨 replica）。
+// Secret 為只能給對象本人的欄位（例如驗證碼）：帶 Secret 的事件只送 ScopeID 明確指定本人的使用者 / key 規則，投遞紀錄只存遮蔽後的 payload，
+// 明文只在建立當下直接投遞一次；沒有符合的規則時改寄到使用者信箱（不建立投遞紀錄）。
+type NotificationMessage struct {
+	Event     core.NotificationEvent
+	Subject   string
+	Scope     core.NotificationScope
+	KeyID     string
+	UserID    string
+	OrgID     string
+	Threshold int
+	Data      map[string]interface{}
+	DedupKey  string
+	DedupTTL  time.Duration
+	Secret    map[string]interface{}
+}
+
+// NotificationService 事件通知：依訂閱規則產生投遞紀錄，由背景 worker 送出 webhook / email 並重試。
+// 啟用中的規則快取於本機（定期重新載入，本機修改立即生效），請求熱路徑上只做記憶體比對。
+type NotificationService struct {
+	trace          *telemetry.Trace
+	logger         *zap.Logger
+	config         *config.Configuration
+	ruleRepo       *mongoDb.NotificationRuleRepository
+	deliveryRepo   *mongoDb.NotificationDeliveryRepository
+	userApiKeyRepo *mongoDb.UserAPIKeyRepository
+	usageRepo      *mongoDb.UsageRecordRepository
+	notifyRepo     *redisDb.NotificationRepository
+	entityCache    *EntityCacheService
+	recent         *lru.Cache
+	webhookClient  *http.Client
+
+	mu       sync.RWMutex
+	rules    []*model.NotificationRule
+	loadedAt time.Time
+	secrets  sync.WaitGroup // 進行中的 Secret 直接投遞；關閉時等待送完
+}
+
+func NewNotificationService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	ruleRepo *mongoDb.NotificationRuleRepository,
+	deliveryRepo *mongoDb.NotificationDeliveryRepository,
+	userApiKeyRepo *mongoDb.UserAPIKeyRepository,
+	usageRepo *mongoDb.UsageRecordRepository,
+	notifyRepo *redisDb.NotificationRepository,
+	entityCache *EntityCacheService,
+) (*NotificationService, func(), error) {
+	s := &NotificationService{
+		trace:          trace,
+		logger:         logger,
+		config:         config,
+		ruleRepo:       ruleRepo,
+		deliveryRepo:   deliveryRepo,
+		userApiKeyRepo: userApiKeyRepo,
+		usageRepo:      usageRepo,
+		notifyRepo:     notifyRepo,
+		entityCache:    entityCache,
+		recent:         lru.New(notificationRecentSize, notificationRecentTTL),
+	}
+	// webhook 使用獨立 client：沿用出口代理與 CA 設定，逾時為 Notification.TimeoutMs
+	webhookClient, err := outbound.NewClient(config, s.timeout())
+	if err != nil {
+		return nil, nil, fmt.Errorf("notification webhook client: %w", err)
+	}
+	s.webhookClient = webhookClient
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go s.run(stop, done)
+	cleanup := func() {
+		close(stop)
+		<-done
+		s.secrets.Wait()
+	}
+	return s, cleanup, nil
+}
+
+// ObserveBudgets 請求放行前回報各層級剩餘額度；用量跨過規則門檻時（每個視窗每個門檻一次）非同步送出通知。
+// 同時跨過多個門檻時只送最高者。
+func (s *NotificationService) ObserveBudgets(ctx context.Context, provider string, budgets []core.QuotaBudget) {
+	if !s.config.Notification.Enabled {
+		return
+	}
+	rules := s.snapshot()
+	if len(rules) == 0 {
+		return
+	}
+	for _, budget := range budgets {
+		if budget.Limit <= 0 {
+			continue
+		}
+		usedPercent := (budget.Limit - budget.Remaining) / budget.Limit * 100
+		message := NotificationMessage{
+			Event: core.NotificationEventQuotaThreshold,
+			Scope: core.NotificationScope(budget.Scope),
+		}
+		switch budget.Scope {
+		case core.QuotaScopeKey:
+			message.KeyID = budget.ScopeID
+		case core.QuotaScopeUser:
+			message.UserID = budget.ScopeID
+		case core.QuotaScopeOrg:
+			message.OrgID = budget.ScopeID
+		}
+
+		threshold := 0
+		for _, rule := range rules {
+			if !s.matches(rule, &message) {
+				continue
+			}
+			for _, candidate := range ruleThresholds(rule) {
+				if usedPercent >= float64(candidate) && candidate > threshold {
+					threshold = candidate
+				}
+			}
+		}
+		if threshold == 0 {
+			continue
+		}
+
+		window := string(budget.Scope) + ":" + budget.ScopeID + ":" + string(budget.Metric)
+		if budget.Scope == core.QuotaScopeKey {
+			// key 的次數限制依 provider 分開計算
+			window += ":" + provider
+		}
+		ttl := time.Duration(budget.ResetSeconds) * time.Second
+		if ttl <= 0 {
+			ttl = 24 * time.Hour
+		}
+		message.Threshold = threshold
+		message.Subject = fmt.Sprintf("%s %s quota reached %d%%", budget.Scope, budget.Metric, threshold)
+		message.Data = map[string]interface{}{
+			"scope":        budget.Scope,
+			"scopeID":      budget.ScopeID,
+			"metric":       budget.Metric,
+			"limit":        budget.Limit,
+			"remaining":    budget.Remaining,
+			"usedPercent":  usedPercent,
+			"threshold":    threshold,
+			"resetSeconds": budget.ResetSeconds,
+		}
+		if provider != "" {
+			message.Data["provider"] = provider
+		}
+		message.DedupKey = "quota:" + window + ":" + strconv.Itoa(threshold)
+		message.DedupTTL = ttl
+		s.emitAsync(ctx, message)
+	}
+}
+
+// NotifyUpstreamFailure 上游 endpoint 熔斷（連續失敗）時通知 global 規則；同一 endpoint 在冷卻時間內只送一次
+func (s *NotificationService) NotifyUpstreamFailure(provider core.ProviderName, endpoint string, lastFailure string) {
+	if !s.config.Notification.Enabled {
+		return
+	}
+	cooldown := s.config.Notification.UpstreamCooldownSeconds
+	if cooldown <= 0 {
+		cooldown = defaultNotificationUpstreamCooldown
+	}
+	s.emitAsync(context.Background(), NotificationMessage{
+		Event:   core.NotificationEventUpstreamFailure,
+		Subject: fmt.Sprintf("upstream %s %s is failing (circuit opened)", provider, endpoint),
+		Scope:   core.NotificationScopeGlobal,
+		Data: map[string]interface{}{
+			"provider":    provider,
+			"endpoint":    endpoint,
+			"lastFailure": lastFailure,
+		},
+		DedupKey: "upstream:" + string(provider) + ":" + endpoint,
+		DedupTTL: time.Duration(cooldown) * time.Second,
+	})
+}
+
+// Emit 依規則產生投遞紀錄（由背景 worker 送出）；沒有符合的規則或已送過時不做事
+func (s *NotificationService) Emit(ctx context.Context, message NotificationMessage) error {
+	if !s.config.Notification.Enabled {
+		if len(message.Secret) > 0 {
+			return cErr.Conflict("notifications are disabled")
+		}
+		return nil
+	}
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(attribute.String("notification.event", string(message.Event)))
+
+	// 未限定層級的事件補上所屬組織，讓組織規則也能收到
+	if message.Scope == "" && message.OrgID == "" && message.UserID != "" {
+		if userID, err := primitive.ObjectIDFromHex(message.UserID); err == nil {
+			if user, err := s.entityCache.GetUser(ctx, userID); err == nil && user.OrgID != nil && !user.OrgID.IsZero() {
+				message.OrgID = user.OrgID.Hex()
+			}
+		}
+	}
+
+	matched := make([]*model.NotificationRule, 0)
+	for _, rule := range s.snapshot() {
+		if s.matches(rule, &message) {
+			matched = append(matched, rule)
+		}
+	}
+	span.SetAttributes(attribute.Int("notification.rules", len(matched)))
+	if len(matched) == 0 {
+		if len(message.Secret) > 0 {
+			if err := s.sendSecretToUser(ctx, &message); err != nil {
+				end(err)
+				return err
+			}
+		}
+		return nil
+	}
+
+	if message.DedupKey != "" {
+		if _, ok := s.recent.Get(message.DedupKey); ok {
+			return nil
+		}
+		first, err := s.notifyRepo.MarkOnce(ctx, message.DedupKey, message.DedupTTL)
+		if err != nil {
+			end(err)
+			return cErr.DatabaseError("redis mark notification failed")
+		}
+		s.recent.Set(message.DedupKey, true)
+		if !first {
+			return nil
+		}
+	}
+
+	deliveries, err := s.buildDeliveries(matched, &message)
+	if err != nil {
+		s.releaseDedup(ctx, message.DedupKey)
+		end(err)
+		return err
+	}
+	if len(message.Secret) > 0 {
+		// worker 不送出 Secret 紀錄；直接投遞期間不取件，之後仍未完成（例如 replica 中途停止）時由 worker 轉為 dead
+		hold := time.Now().UTC().Add(3 * s.timeout() * time.Duration(len(deliveries)))
+		for _, delivery := range deliveries {
+			delivery.Secret = true
+			delivery.Attempts = 1
+			delivery.NextAttemptAt = hold
+		}
+	}
+	if err := s.deliveryRepo.InsertMany(ctx, deliveries); err != nil {
+		s.releaseDedup(ctx, message.DedupKey)
+		end(err)
+		return cErr.DatabaseError("database InsertNotificationDeliveries error")
+	}
+	if len(message.Secret) > 0 {
+		s.secrets.Add(1)
+		go func() {
+			defer s.secrets.Done()
+			s.deliverSecret(deliveries, message.Secret)
+		}()
+	}
+	return nil
+}
+
+// releaseDedup 投遞紀錄未寫入時釋放去重標記，避免事件在整個 DedupTTL 內遺失
+func (s *NotificationService) releaseDedup(ctx context.Context, dedupKey string) {
+	if dedupKey == "" {
+		return
+	}
+	s.recent.Delete(dedupKey)
+	if err := s.notifyRepo.Unmark(context.WithoutCancel(ctx), dedupKey); err != nil {
+		s.logger.Warn("release notification dedup failed", zap.String("dedupKey", dedupKey), zap.Error(err))
+	}
+}
+
+// emitAsync 熱路徑呼叫：不阻塞請求，失敗只記 log
+func (s *NotificationService) emitAsync(ctx context.Context, message NotificationMessage) {
+	if message.DedupKey != "" {
+		if _, ok := s.recent.Get(message.DedupKey); ok {
+			return
+		}
+	}
+	go func() {
+		emitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notificationEmitTimeout)
+		defer cancel()
+		if err := s.Emit(emitCtx, message); err != nil {
+			s.logger.Warn("emit notification failed", zap.String("event", string(message.Event)), zap.Error(err))
+		}
+	}()
+}
+
+// matches 規則是否訂閱此事件且對象相符
+func (s *NotificationService) matches(rule *model.NotificationRule, message *NotificationMessage) bool {
+	if !containsEvent(rule.Events, message.Event) {
+		return false
+	}
+	if len(message.Secret) > 0 {
+		return secretRecipient(rule, message)
+	}
+	if message.Scope != "" && rule.Scope != message.Scope && rule.Scope != core.NotificationScopeGlobal {
+		return false
+	}
+	if message.Threshold > 0 && !containsInt(ruleThresholds(rule), message.Threshold) {
+		return false
+	}
+	switch rule.Scope {
+	case core.NotificationScopeKey:
+		return scopeIDMatches(rule.ScopeID, message.KeyID)
+	case core.NotificationScopeUser:
+		return scopeIDMatches(rule.ScopeID, message.UserID)
+	case core.NotificationScopeOrg:
+		return scopeIDMatches(rule.ScopeID, message.OrgID)
+	case core.NotificationScopeGlobal:
+		return true
+	}
+	return false
+}
+
+// buildDeliveries 每條規則的每個管道一筆投遞紀錄，共用同一個事件 ID 與 payload（Secret 欄位以遮蔽值存放）
+func (s *NotificationService) buildDeliveries(rules []*model.NotificationRule, message *NotificationMessage) ([]*model.NotificationDelivery, error) {
+	payload := newNotificationPayload(message, redactedSecret(message.Secret))
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, cErr.InternalServer("encode notification payload failed")
+	}
+
+	deliveries := make([]*model.NotificationDelivery, 0, len(rules))
+	newDelivery := func(rule *model.NotificationRule, channel core.NotificationChannel, target string) *model.NotificationDelivery {
+		return &model.NotificationDelivery{
+			RuleID:        rule.ID,
+			EventID:       payload.ID,
+			Event:         message.Event,
+			Channel:       channel,
+			Target:        target,
+			Subject:       message.Subject,
+			Payload:       string(encoded),
+			Status:        core.NotificationDeliveryPending,
+			NextAttemptAt: payload.OccurredAt,
+		}
+	}
+	for _, rule := range rules {
+		if rule.WebhookURL != "" {
+			deliveries = append(deliveries, newDelivery(rule, core.NotificationChannelWebhook, rule.WebhookURL))
+		}
+		for _, email := range rule.Emails {
+			deliveries = append(deliveries, newDelivery(rule, core.NotificationChannelEmail, email))
+		}
+	}
+	return deliveries, nil
+}
+
+func newNotificationPayload(message *NotificationMessage, secret map[string]interface{}) dto.NotificationPayloadDto {
+	data := message.Data
+	if len(secret) > 0 {
+		data = make(map[string]interface{}, len(message.Data)+len(secret))
+		for key, value := range message.Data {
+			data[key] = value
+		}
+		for key, value := range secret {
+			data[key] = value
+		}
+	}
+	return dto.NotificationPayloadDto{
+		ID:         uuid.NewString(),
+		Event:      message.Event,
+		Subject:    message.Subject,
+		OccurredAt: time.Now().UTC(),
+		KeyID:      message.KeyID,
+		UserID:     message.UserID,
+		OrgID:      message.OrgID,
+		Data:       data,
+	}
+}
+
+func redactedSecret(secret map[string]interface{}) map[string]interface{} {
+	if len(secret) == 0 {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(secret))
+	for key := range secret {
+		redacted[key] = notificationRedacted
+	}
+	return redacted
+}
+
+// sendSecretToUser 帶 Secret 的事件沒有符合的規則時，直接寄到使用者信箱（不建立投遞紀錄）
+func (s *NotificationService) sendSecretToUser(ctx context.Context, message *NotificationMessage) error {
+	userID, err := primitive.ObjectIDFromHex(message.UserID)
+	if err != nil {
+		return cErr.Conflict("no notification channel for the recipient")
+	}
+	user, err := s.entityCache.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return cErr.NotFound(fmt.Sprintf("user with id %s not found", message.UserID))
+		}
+		return cErr.DatabaseError("database GetUser error")
+	}
+	if user.Email == "" {
+		return cErr.Conflict("no notification rule or email for the recipient")
+	}
+
+	encoded, err := json.Marshal(newNotificationPayload(message, message.Secret))
+	if err != nil {
+		return cErr.InternalServer("encode notification payload failed")
+	}
+	if err := s.sendEmail(&model.NotificationDelivery{
+		Event:   message.Event,
+		Channel: core.NotificationChannelEmail,
+		Target:  user.Email,
+		Subject: message.Subject,
+		Payload: string(encoded),
+	}); err != nil {
+		s.logger.Warn("send notification to user email failed", zap.String("userID", message.UserID), zap.Error(err))
+		return cErr.ServiceUnavailable("send notification email failed")
+	}
+	return nil
+}
+
+// scanExpiringKeys 找出 ExpiryWarnDays 內到期的 API Key provider 設定並通知（每個到期時間只送一次）
+func (s *NotificationService) scanExpiringKeys(ctx context.Context) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	days := s.config.Notification.ExpiryWarnDays
+	if days <= 0 {
+		days = defaultNotificationExpiryWarnDays
+	}
+	now := time.Now().UTC()
+	until := now.Add(time.Duration(days) * 24 * time.Hour)
+	apiKeys, err := s.userApiKeyRepo.List(ctx, bson.M{"providerAccess.expireTime": bson.M{"$gt": now, "$lte": until}})
+	if err != nil {
+		end(err)
+		s.logger.Warn("scan expiring api keys failed", zap.Error(err))
+		return
+	}
+
+	for _, apiKey := range apiKeys {
+		for _, access := range apiKey.ProviderAccess {
+			if access.ExpireTime == nil || !access.ExpireTime.After(now) || access.ExpireTime.After(until) {
+				continue
+			}
+			expireAt := access.ExpireTime.UTC()
+			message := NotificationMessage{
+				Event:   core.NotificationEventKeyExpiring,
+				Subject: fmt.Sprintf("API key %s (%s) expires at %s", apiKey.KeyName, access.Provider, expireAt.Format(time.RFC3339)),
+				KeyID:   apiKey.ID.Hex(),
+				UserID:  apiKey.UserID.Hex(),
+				Data: map[string]interface{}{
+					"keyName":    apiKey.KeyName,
+					"provider":   access.Provider,
+					"expireTime": expireAt,
+					"daysLeft":   int(expireAt.Sub(now).Hours() / 24),
+				},
+				DedupKey: "expiring:" + apiKey.ID.Hex() + ":" + string(access.Provider) + ":" + strconv.FormatInt(expireAt.Unix(), 10),
+				DedupTTL: expireAt.Sub(now) + 24*time.Hour,
+			}
+			if err := s.Emit(ctx, message); err != nil {
+				s.logger.Warn("emit key expiring notification failed", zap.String("apiKeyID", apiKey.ID.Hex()), zap.Error(err))
+			}
+		}
+	}
+}
+
+// scanSpendAnomalies 比較各 API Key 最近視窗的花費與基準期間的平均，超過倍數時通知（每個視窗一次）
+func (s *NotificationService) scanSpendAnomalies(ctx context.Context) {
+	if !s.config.UsageRecord.Enabled {
+		return
+	}
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	windowMinutes := s.config.Notification.SpendWindowMinutes
+	if windowMinutes <= 0 {
+		windowMinutes = defaultNotificationSpendWindowMinutes
+	}
+	baselineHours := s.config.Notification.SpendBaselineHours
+	if baselineHours <= 0 {
+		baselineHours = defaultNotificationSpendBaselineHours
+	}
+	multiplier := s.config.Notification.SpendMultiplier
+	if multiplier <= 0 {
+		multiplier = defaultNotificationSpendMultiplier
+	}
+	minCost := s.config.Notification.SpendMinCost
+	if minCost <= 0 {
+		minCost = defaultNotificationSpendMinCost
+	}
+
+	window := time.Duration(windowMinutes) * time.Minute
+	baseline := time.Duration(baselineHours) * time.Hour
+	now := time.Now().UTC()
+	windowStart := now.Add(-window)
+
+	recent, err := s.usageRepo.Aggregate(ctx, mongoDb.UsageRecordFilter{From: windowStart, To: now}, []string{"apiKey", "user"}, "", "", 0)
+	if err != nil {
+		end(err)
+		s.logger.Warn("aggregate recent spend failed", zap.Error(err))
+		return
+	}
+	previous, err := s.usageRepo.Aggregate(ctx, mongoDb.UsageRecordFilter{From: windowStart.Add(-baseline), To: windowStart}, []string{"apiKey"}, "", "", 0)
+	if err != nil {
+		end(err)
+		s.logger.Warn("aggregate baseline spend failed", zap.Error(err))
+		return
+	}
+	baselineCost := make(map[string]float64, len(previous))
+	for _, aggregate := range previous {
+		baselineCost[aggregate.Group.APIKeyID] += aggregate.Cost
+	}
+
+	windows := float64(baseline) / float64(window)
+	for _, aggregate := range recent {
+		keyID := aggregate.Group.APIKeyID
+		if keyID == "" || aggregate.Cost < minCost {
+			continue
+		}
+		average := baselineCost[keyID] / windows
+		if aggregate.Cost <= average*multiplier {
+			continue
+		}
+		message := NotificationMessage{
+			Event:   core.NotificationEventSpendAnomaly,
+			Subject: fmt.Sprintf("API key %s spent %.4f in the last %d minutes (baseline %.4f)", keyID, aggregate.Cost, windowMinutes, average),
+			KeyID:   keyID,
+			UserID:  aggregate.Group.UserID,
+			Data: map[string]interface{}{
+				"windowMinutes": windowMinutes,
+				"cost":          aggregate.Cost,
+				"baselineCost":  average,
+				"multiplier":    multiplier,
+				"requests":      aggregate.Requests,
+				"currency":      s.config.Pricing.Currency,
+			},
+			DedupKey: "spend:" + keyID + ":" + strconv.FormatInt(now.Truncate(window).Unix(), 10),
+			DedupTTL: window,
+		}
+		if err := s.Emit(ctx, message); err != nil {
+			s.logger.Warn("emit spend anomaly notification failed", zap.String("apiKeyID", keyID), zap.Error(err))
+		}
+	}
+}
+
+// snapshot 目前快取的啟用規則（呼叫端不可修改）
+func (s *NotificationService) snapshot() []*model.NotificationRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules
+}
+
+// refreshRules 重新載入啟用中的規則；失敗時保留舊快取
+func (s *NotificationService) refreshRules(ctx context.Context) {
+	rules, err := s.ruleRepo.ListEnabled(ctx)
+	if err != nil {
+		s.logger.Warn("load notification rules failed", zap.Error(err))
+		return
+	}
+	s.mu.Lock()
+	s.rules = rules
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+}
+
+// rulesStale 快取是否超過重新載入間隔
+func (s *NotificationService) rulesStale() bool {
+	seconds := s.config.Notification.RuleRefreshSeconds
+	if seconds <= 0 {
+		seconds = defaultNotificationRuleRefreshSeconds
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Since(s.loadedAt) >= time.Duration(seconds)*time.Second
+}
+
+// 新增通知規則
+func (s *NotificationService) CreateRule(ctx context.Context, req *dto.CreateNotificationRuleDto, createdBy string) (*dto.NotificationRuleDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	scopeID := req.ScopeID
+	if req.Scope == core.NotificationScopeGlobal {
+		scopeID = ""
+	}
+	if err := validateWebhookURL(req.WebhookURL); err != nil {
+		return nil, err
+	}
+	if req.WebhookURL == "" && len(req.Emails) == 0 {
+		return nil, cErr.BadRequestParams("webhookURL or emails is required")
+	}
+	secret := req.WebhookSecret
+	if req.WebhookURL != "" && secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			end(err)
+			return nil, cErr.InternalServer("generate webhook secret failed")
+		}
+		secret = generated
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	created, err := s.ruleRepo.Create(ctx, &model.NotificationRule{
+		Name:          req.Name,
+		Scope:         req.Scope,
+		ScopeID:       scopeID,
+		Events:        req.Events,
+		Thresholds:    normalizeThresholds(req.Thresholds),
+		WebhookURL:    req.WebhookURL,
+		WebhookSecret: secret,
+		Emails:        req.Emails,
+		Enabled:       enabled,
+		CreatedBy:     createdBy,
+	})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database CreateNotificationRule error")
+	}
+	s.refreshRules(ctx)
+
+	result := modelToNotificationRuleDto(created)
+	result.WebhookSecret = secret
+	return result, nil
+}
+
+// 列出通知規則（scope / scopeID 為空時不限制）
+func (s *NotificationService) ListRules(ctx context.Context, scope core.NotificationScope, scopeID string, page, size int64) ([]*dto.NotificationRuleDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	filter := bson.M{}
+	if scope != "" {
+		filter["scope"] = scope
+	}
+	if scopeID != "" {
+		filter["scopeID"] = scopeID
+	}
+	rules, err := s.ruleRepo.List(ctx, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListNotificationRules error")
+	}
+	results := make([]*dto.NotificationRuleDto, 0, len(rules))
+	for _, rule := range rules {
+		results = append(results, modelToNotificationRuleDto(rule))
+	}
+	return results, nil
+}
+
+// 取得通知規則
+func (s *NotificationService) GetRule(ctx context.Context, id primitive.ObjectID) (*dto.NotificationRuleDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	rule, err := s.getRule(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	return modelToNotificationRuleDto(rule), nil
+}
+
+// 更新通知規則（更新後至少須保留一個管道）
+func (s *NotificationService) UpdateRule(ctx context.Context, id primitive.ObjectID, req *dto.UpdateNotificationRuleDto) (*dto.NotificationRuleDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	rule, err := s.getRule(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+
+	set := bson.M{}
+	unset := bson.M{}
+	if req.Name != nil {
+		set["name"] = *req.Name
+	}
+	if req.Events != nil {
+		set["events"] = *req.Events
+	}
+	if req.Thresholds != nil {
+		set["thresholds"] = normalizeThresholds(*req.Thresholds)
+	}
+	if req.Enabled != nil {
+		set["enabled"] = *req.Enabled
+	}
+	webhookURL, emails := rule.WebhookURL, rule.Emails
+	if req.WebhookURL != nil {
+		if err := validateWebhookURL(*req.WebhookURL); err != nil {
+			return nil, err
+		}
+		webhookURL = *req.WebhookURL
+		if webhookURL == "" {
+			unset["webhookURL"] = ""
+			unset["webhookSecret"] = ""
+		} else {
+			set["webhookURL"] = webhookURL
+		}
+	}
+	if req.WebhookSecret != nil && webhookURL != "" {
+		set["webhookSecret"] = *req.WebhookSecret
+	} else if webhookURL != "" && rule.WebhookSecret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			end(err)
+			return nil, cErr.InternalServer("generate webhook secret failed")
+		}
+		set["webhookSecret"] = generated
+	}
+	if req.Emails != nil {
+		emails = *req.Emails
+		set["emails"] = emails
+	}
+	if webhookURL == "" && len(emails) == 0 {
+		return nil, cErr.BadRequestParams("webhookURL or emails is required")
+	}
+
+	if _, err := s.ruleRepo.UpdateByID(ctx, id, set, unset); err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database UpdateNotificationRule error")
+	}
+	s.refreshRules(ctx)
+
+	updated, err := s.getRule(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	result := modelToNotificationRuleDto(updated)
+	if secret, ok := set["webhookSecret"].(string); ok && req.WebhookSecret == nil {
+		// 新產生的金鑰只回傳這一次
+		result.WebhookSecret = secret
+	}
+	return result, nil
+}
+
+// 刪除通知規則（既有投遞紀錄保留）
+func (s *NotificationService) DeleteRule(ctx context.Context, id primitive.ObjectID) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	deleted, err := s.ruleRepo.DeleteByID(ctx, id)
+	if err != nil {
+		end(err)
+		return cErr.DatabaseError("database DeleteNotificationRule error")
+	}
+	if deleted == 0 {
+		return cErr.NotFound(fmt.Sprintf("notification rule %s not found", id.Hex()))
+	}
+	s.refreshRules(ctx)
+	return nil
+}
+
+// TestRule 對規則的所有管道送出一筆測試事件（不論規則是否啟用）
+func (s *NotificationService) TestRule(ctx context.Context, id primitive.ObjectID) ([]*dto.NotificationDeliveryDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	rule, err := s.getRule(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	deliveries, err := s.buildDeliveries([]*model.NotificationRule{rule}, &NotificationMessage{
+		Event:   core.NotificationEventTest,
+		Subject: fmt.Sprintf("test notification for rule %s", rule.Name),
+		Data:    map[string]interface{}{"ruleID": rule.ID.Hex()},
+	})
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	if err := s.deliveryRepo.InsertMany(ctx, deliveries); err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database InsertNotificationDeliveries error")
+	}
+	results := make([]*dto.NotificationDeliveryDto, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		results = append(results, modelToNotificationDeliveryDto(delivery))
+	}
+	return results, nil
+}
+
+// 投遞紀錄（status / ruleID / event 為空時不限制）
+func (s *NotificationService) ListDeliveries(
+	ctx context.Context,
+	status core.NotificationDeliveryStatus,
+	ruleID string,
+	event core.NotificationEvent,
+	page, size int64,
+) ([]*dto.NotificationDeliveryDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if ruleID != "" {
+		ruleObjectID, err := primitive.ObjectIDFromHex(ruleID)
+		if err != nil {
+			return nil, cErr.BadRequestParams("invalid ruleID")
+		}
+		filter["ruleID"] = ruleObjectID
+	}
+	if event != "" {
+		filter["event"] = event
+	}
+	deliveries, err := s.deliveryRepo.List(ctx, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListNotificationDeliveries error")
+	}
+	results := make([]*dto.NotificationDeliveryDto, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		results = append(results, modelToNotificationDeliveryDto(delivery))
+	}
+	return results, nil
+}
+
+// RetryDelivery 將 dead-letter 重新排入投遞
+func (s *NotificationService) RetryDelivery(ctx context.Context, id primitive.ObjectID) (*dto.NotificationDeliveryDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	matched, err := s.deliveryRepo.Requeue(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database RequeueNotificationDelivery error")
+	}
+	if matched == 0 {
+		// Secret 紀錄只存遮蔽後的 payload，不可重送
+		return nil, cErr.NotFound(fmt.Sprintf("dead notification delivery %s not found or not retryable", id.Hex()))
+	}
+	delivery, err := s.deliveryRepo.GetByID(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database GetNotificationDelivery error")
+	}
+	return modelToNotificationDeliveryDto(delivery), nil
+}
+
+func (s *NotificationService) getRule(ctx context.Context, id primitive.ObjectID) (*model.NotificationRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("notification rule %s not found", id.Hex()))
+		}
+		return nil, cErr.DatabaseError("database GetNotificationRule error")
+	}
+	return rule, nil
+}
+
+// ruleThresholds 規則的配額門檻（未設定時使用預設）
+func ruleThresholds(rule *model.NotificationRule) []int {
+	if len(rule.Thresholds) == 0 {
+		return defaultNotificationThresholds
+	}
+	return rule.Thresholds
+}
+
+// normalizeThresholds 去重並排序
+func normalizeThresholds(thresholds []int) []int {
+	if len(thresholds) == 0 {
+		return nil
+	}
+	seen := make(map[int]bool, len(thresholds))
+	normalized := make([]int, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		if !seen[threshold] {
+			seen[threshold] = true
+			normalized = append(normalized, threshold)
+		}
+	}
+	sort.Ints(normalized)
+	return normalized
+}
+
+// validateWebhookURL 空字串視為未設定；只接受 http / https
+func validateWebhookURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	parsed, err := url.ParseRequestURI(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return cErr.BadRequestParams("webhookURL must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// scopeIDMatches 規則未指定對象時套用至該層級所有對象
+// secretRecipient 帶 Secret 的事件只送給明確指定本人的規則：使用者規則的 ScopeID 等於 UserID，
+// 或 key 規則的 ScopeID 等於 KeyID；ScopeID 為空（套用所有對象）的規則與 org / global 規則一律排除
+func secretRecipient(rule *model.NotificationRule, message *NotificationMessage) bool {
+	switch rule.Scope {
+	case core.NotificationScopeUser:
+		return message.UserID != "" && rule.ScopeID == message.UserID
+	case core.NotificationScopeKey:
+		return message.KeyID != "" && rule.ScopeID == message.KeyID
+	}
+	return false
+}
+
+func scopeIDMatches(ruleScopeID string, identifier string) bool {
+	return identifier != "" && (ruleScopeID == "" || ruleScopeID == identifier)
+}
+
+func containsEvent(events []core.NotificationEvent, event core.NotificationEvent) bool {
+	for _, candidate := range events {
+		if candidate == event {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func modelToNotificationRuleDto(m *model.NotificationRule) *dto.NotificationRuleDto {
+	return &dto.NotificationRuleDto{
+		ID:         m.ID.Hex(),
+		Name:       m.Name,
+		Scope:      m.Scope,
+		ScopeID:    m.ScopeID,
+		Events:     m.Events,
+		Thresholds: m.Thresholds,
+		WebhookURL: m.WebhookURL,
+		Emails:     m.Emails,
+		Enabled:    m.Enabled,
+		CreatedBy:  m.CreatedBy,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func modelToNotificationDeliveryDto(m *model.NotificationDelivery) *dto.NotificationDeliveryDto {
+	return &dto.NotificationDeliveryDto{
+		ID:            m.ID.Hex(),
+		RuleID:        m.RuleID.Hex(),
+		EventID:       m.EventID,
+		Event:         m.Event,
+		Channel:       m.Channel,
+		Target:        m.Target,
+		Subject:       m.Subject,
+		Payload:       m.Payload,
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		NextAttemptAt: m.NextAttemptAt,
+		DeliveredAt:   m.DeliveredAt,
+		CreatedAt:     m.CreatedAt,
+	}
+}
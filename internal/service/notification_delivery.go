@@ -0,0 +1,364 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/dto"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNotificationWorkerIntervalMs   = 2000
+	defaultNotificationBatchSize          = 50
+	defaultNotificationMaxAttempts        = 6
+	defaultNotificationBackoffBaseSeconds = 30
+	defaultNotificationTimeoutMs          = 10000
+	defaultNotificationExpiryScanSeconds  = 3600
+	defaultNotificationSpendScanSeconds   = 900
+	notificationMaxBackoff                = time.Hour
+	notificationResponseDrainBytes        = 64 << 10
+)
+
+// webhook 標頭
+const (
+	NotificationHeaderEvent     = "X-Interchange-Event"
+	NotificationHeaderDelivery  = "X-Interchange-Delivery"
+	NotificationHeaderTimestamp = "X-Interchange-Timestamp"
+	NotificationHeaderSignature = "X-Interchange-Signature"
+)
+
+// errNotificationUndeliverable 重試也不會成功（規則已刪除、SMTP 未設定），直接轉為 dead
+var errNotificationUndeliverable = errors.New("notification undeliverable")
+
+// SignNotification webhook 簽章：sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))。
+// 接收端以相同方式計算後比對，並檢查 timestamp 與現在時間的差距以防重放。
+func SignNotification(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// run 背景 worker：定期重新載入規則、投遞到期的紀錄、檢查即將到期的 API Key 與花費異常（間隔於啟動時決定）
+func (s *NotificationService) run(stop, done chan struct{}) {
+	defer close(done)
+	if !s.config.Notification.Enabled {
+		return
+	}
+
+	interval := time.Duration(s.config.Notification.WorkerIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultNotificationWorkerIntervalMs * time.Millisecond
+	}
+	scanInterval := time.Duration(s.config.Notification.ExpiryScanIntervalSeconds) * time.Second
+	if scanInterval <= 0 {
+		scanInterval = defaultNotificationExpiryScanSeconds * time.Second
+	}
+	spendInterval := time.Duration(s.config.Notification.SpendScanIntervalSeconds) * time.Second
+	if spendInterval <= 0 {
+		spendInterval = defaultNotificationSpendScanSeconds * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var scannedAt, spendScannedAt time.Time
+	for {
+		if s.rulesStale() {
+			s.refreshRules(context.Background())
+		}
+		if time.Since(scannedAt) >= scanInterval {
+			scannedAt = time.Now()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			s.scanExpiringKeys(ctx)
+			cancel()
+		}
+		if time.Since(spendScannedAt) >= spendInterval {
+			spendScannedAt = time.Now()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			s.scanSpendAnomalies(ctx)
+			cancel()
+		}
+		s.deliverDue(stop)
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue 逐筆取出到期紀錄投遞，每輪最多 BatchSize 筆；收到停止訊號時不再取件
+func (s *NotificationService) deliverDue(stop chan struct{}) {
+	batchSize := s.config.Notification.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultNotificationBatchSize
+	}
+	for i := 0; i < batchSize; i++ {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*s.timeout())
+		delivery, err := s.deliveryRepo.ClaimDue(ctx, time.Now().UTC(), 3*s.timeout())
+		if err != nil {
+			cancel()
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				s.logger.Warn("claim notification delivery failed", zap.Error(err))
+			}
+			return
+		}
+		s.deliver(ctx, delivery)
+		cancel()
+	}
+}
+
+// deliver 投遞一筆並記錄結果；Secret 紀錄（直接投遞未完成者）不以遮蔽後的 payload 送出，直接轉為 dead
+func (s *NotificationService) deliver(ctx context.Context, delivery *model.NotificationDelivery) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if delivery.Secret {
+		sendErr := fmt.Errorf("%w: one-time secret is no longer available", errNotificationUndeliverable)
+		end(sendErr)
+		s.recordResult(ctx, delivery, sendErr, true)
+		return
+	}
+	sendErr := s.send(ctx, delivery)
+	if sendErr != nil {
+		end(sendErr)
+	}
+	s.recordResult(ctx, delivery, sendErr, false)
+}
+
+// deliverSecret 帶 Secret 的投遞紀錄建立後直接送出（payload 補回明文，不寫回紀錄）；
+// 失敗直接轉為 dead，不以遮蔽後的內容重試
+func (s *NotificationService) deliverSecret(deliveries []*model.NotificationDelivery, secret map[string]interface{}) {
+	for _, delivery := range deliveries {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*s.timeout())
+		ctx, _, end := s.trace.WithSpan(ctx)
+
+		outgoing := *delivery
+		payload, sendErr := withNotificationSecret(delivery.Payload, secret)
+		if sendErr == nil {
+			outgoing.Payload = payload
+			sendErr = s.send(ctx, &outgoing)
+		}
+		s.recordResult(ctx, delivery, sendErr, true)
+		end(sendErr)
+		cancel()
+	}
+}
+
+// withNotificationSecret 將 Secret 欄位放回已編碼的 payload
+func withNotificationSecret(encoded string, secret map[string]interface{}) (string, error) {
+	var payload dto.NotificationPayloadDto
+	if err := json.Unmarshal([]byte(encoded), &payload); err != nil {
+		return "", fmt.Errorf("%w: %v", errNotificationUndeliverable, err)
+	}
+	if payload.Data == nil {
+		payload.Data = make(map[string]interface{}, len(secret))
+	}
+	for key, value := range secret {
+		payload.Data[key] = value
+	}
+	withSecret, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errNotificationUndeliverable, err)
+	}
+	return string(withSecret), nil
+}
+
+func (s *NotificationService) send(ctx context.Context, delivery *model.NotificationDelivery) error {
+	switch delivery.Channel {
+	case core.NotificationChannelWebhook:
+		return s.sendWebhook(ctx, delivery)
+	case core.NotificationChannelEmail:
+		return s.sendEmail(delivery)
+	}
+	return fmt.Errorf("%w: unknown channel %s", errNotificationUndeliverable, delivery.Channel)
+}
+
+// recordResult 記錄投遞結果；失敗依指數退避安排下次嘗試，次數用盡或 final 時轉為 dead
+func (s *NotificationService) recordResult(ctx context.Context, delivery *model.NotificationDelivery, sendErr error, final bool) {
+	now := time.Now().UTC()
+	if sendErr == nil {
+		if err := s.deliveryRepo.MarkDelivered(ctx, delivery.ID, now); err != nil {
+			s.logger.Warn("mark notification delivered failed", zap.String("deliveryID", delivery.ID.Hex()), zap.Error(err))
+		}
+		return
+	}
+
+	maxAttempts := s.config.Notification.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultNotificationMaxAttempts
+	}
+	dead := final || delivery.Attempts >= maxAttempts || errors.Is(sendErr, errNotificationUndeliverable)
+	s.logger.Warn("notification delivery failed",
+		zap.String("deliveryID", delivery.ID.Hex()),
+		zap.String("channel", string(delivery.Channel)),
+		zap.Int("attempts", delivery.Attempts),
+		zap.Bool("dead", dead),
+		zap.Error(sendErr),
+	)
+	if err := s.deliveryRepo.MarkFailed(ctx, delivery.ID, sendErr.Error(), now.Add(s.backoff(delivery.Attempts)), dead); err != nil {
+		s.logger.Warn("mark notification failed failed", zap.String("deliveryID", delivery.ID.Hex()), zap.Error(err))
+	}
+}
+
+// sendWebhook 以規則目前的金鑰簽章後 POST；2xx 視為成功
+func (s *NotificationService) sendWebhook(ctx context.Context, delivery *model.NotificationDelivery) error {
+	rule, err := s.ruleRepo.GetByID(ctx, delivery.RuleID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("%w: rule deleted", errNotificationUndeliverable)
+		}
+		return err
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	requestCtx, cancel := context.WithTimeout(ctx, s.timeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodPost, delivery.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errNotificationUndeliverable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "interchange-webhook")
+	req.Header.Set(NotificationHeaderEvent, string(delivery.Event))
+	req.Header.Set(NotificationHeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(NotificationHeaderTimestamp, timestamp)
+	req.Header.Set(NotificationHeaderSignature, SignNotification(rule.WebhookSecret, timestamp, body))
+
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, notificationResponseDrainBytes))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendEmail 以 SMTP 寄出（伺服器支援時使用 STARTTLS；Username 為空時不驗證）
+func (s *NotificationService) sendEmail(delivery *model.NotificationDelivery) error {
+	smtpConfig := s.config.Notification.SMTP
+	if smtpConfig.Host == "" || smtpConfig.From == "" {
+		return fmt.Errorf("%w: smtp not configured", errNotificationUndeliverable)
+	}
+	port := smtpConfig.Port
+	if port <= 0 {
+		port = 587
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(smtpConfig.Host, strconv.Itoa(port)), s.timeout())
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(s.timeout()))
+	client, err := smtp.NewClient(conn, smtpConfig.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: smtpConfig.Host}); err != nil {
+			return err
+		}
+	}
+	if smtpConfig.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", smtpConfig.Username, smtpConfig.Password, smtpConfig.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(smtpConfig.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(delivery.Target); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(notificationEmail(smtpConfig.From, delivery)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// notificationEmail 純文字信件：摘要 + 格式化後的事件內容
+func notificationEmail(from string, delivery *model.NotificationDelivery) []byte {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(delivery.Payload), "", "  "); err != nil {
+		pretty.Reset()
+		pretty.WriteString(delivery.Payload)
+	}
+
+	var message strings.Builder
+	message.WriteString("From: " + from + "\r\n")
+	message.WriteString("To: " + delivery.Target + "\r\n")
+	message.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", "[interchange] "+delivery.Subject) + "\r\n")
+	message.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(delivery.Subject + "\r\n\r\n")
+	message.WriteString(strings.ReplaceAll(pretty.String(), "\n", "\r\n") + "\r\n")
+	return []byte(message.String())
+}
+
+// backoff 第 n 次失敗後等待 base * 2^(n-1)，上限 1 小時
+func (s *NotificationService) backoff(attempts int) time.Duration {
+	base := time.Duration(s.config.Notification.BackoffBaseSeconds) * time.Second
+	if base <= 0 {
+		base = defaultNotificationBackoffBaseSeconds * time.Second
+	}
+	wait := base
+	for i := 1; i < attempts && wait < notificationMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > notificationMaxBackoff {
+		wait = notificationMaxBackoff
+	}
+	return wait
+}
+
+func (s *NotificationService) timeout() time.Duration {
+	ms := s.config.Notification.TimeoutMs
+	if ms <= 0 {
+		ms = defaultNotificationTimeoutMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OrganizationService struct {
+	trace            *telemetry.Trace
+	organizationRepo *repository.OrganizationRepository
+	userRepo         *repository.UserRepository
+	entityCache      *EntityCacheService
+}
+
+func NewOrganizationService(
+	trace *telemetry.Trace,
+	organizationRepo *repository.OrganizationRepository,
+	userRepo *repository.UserRepository,
+	entityCache *EntityCacheService,
+) *OrganizationService {
+	return &OrganizationService{trace: trace, organizationRepo: organizationRepo, userRepo: userRepo, entityCache: entityCache}
+}
+
+// 新增組織
+func (s *OrganizationService) CreateOrganization(ctx context.Context, dto *dto.CreateOrganizationDto) (*dto.OrganizationResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	organization := &model.Organization{
+		ID:          primitive.NewObjectID(),
+		Name:        dto.Name,
+		Description: dto.Description,
+		Status:      dto.Status,
+		QuotaPolicy: quotaPolicyDtoToModel(dto.QuotaPolicy),
+	}
+	created, err := s.organizationRepo.Create(ctx, organization)
+	if err != nil {
+		return nil, cErr.DatabaseError("database CreateOrganization error")
+	}
+	return modelToOrganizationResponseDto(created), nil
+}
+
+// 依 id 查詢組織
+func (s *OrganizationService) GetOrganizationByID(ctx context.Context, id primitive.ObjectID) (*dto.OrganizationResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	organization, err := s.organizationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("organization not found")
+		}
+		return nil, cErr.DatabaseError("database GetOrganizationByID error")
+	}
+	return modelToOrganizationResponseDto(organization), nil
+}
+
+// 列舉組織（支援分頁、篩選）
+func (s *OrganizationService) ListOrganizations(ctx context.Context, filter bson.M, page, size int64) ([]*dto.OrganizationResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	organizations, err := s.organizationRepo.List(ctx, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		return nil, cErr.DatabaseError("database ListOrganizations error")
+	}
+	resp := make([]*dto.OrganizationResponseDto, len(organizations))
+	for i, o := range organizations {
+		resp[i] = modelToOrganizationResponseDto(o)
+	}
+	return resp, nil
+}
+
+// 更新組織基本資訊
+func (s *OrganizationService) UpdateOrganizationByID(ctx context.Context, id primitive.ObjectID, dto *dto.UpdateOrganizationDto) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	update := bson.M{}
+	if dto.Name != nil {
+		update["name"] = *dto.Name
+	}
+	if dto.Description != nil {
+		update["description"] = *dto.Description
+	}
+	if dto.Status != nil {
+		update["status"] = *dto.Status
+	}
+
+	matchedCount, err := s.organizationRepo.UpdateByID(ctx, id, update)
+	if err != nil {
+		return cErr.DatabaseError("database UpdateOrganizationByID error")
+	}
+	if matchedCount == 0 {
+		return cErr.NotFound(fmt.Sprintf("organization with id %s not found", id.Hex()))
+	}
+	return nil
+}
+
+// 刪除組織
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, id primitive.ObjectID) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if err := s.organizationRepo.DeleteByID(ctx, id); err != nil {
+		return cErr.DatabaseError("database DeleteOrganization error")
+	}
+	return nil
+}
+
+// 指派使用者所屬組織；orgID 為 nil 時移出組織
+func (s *OrganizationService) AssignUser(ctx context.Context, userID primitive.ObjectID, orgID *primitive.ObjectID) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if orgID != nil {
+		if _, err := s.organizationRepo.GetByID(ctx, *orgID); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return cErr.NotFound(fmt.Sprintf("organization with id %s not found", orgID.Hex()))
+			}
+			return cErr.DatabaseError("database GetOrganizationByID error")
+		}
+	}
+	matchedCount, err := s.userRepo.UpdateOrgID(ctx, userID, orgID)
+	if err != nil {
+		return cErr.DatabaseError("database UpdateUserOrgID error")
+	}
+	if matchedCount == 0 {
+		return cErr.NotFound(fmt.Sprintf("user with id %s not found", userID.Hex()))
+	}
+	s.entityCache.InvalidateUser(ctx, userID)
+	return nil
+}
+
+func modelToOrganizationResponseDto(m *model.Organization) *dto.OrganizationResponseDto {
+	return &dto.OrganizationResponseDto{
+		ID:          m.ID.Hex(),
+		Name:        m.Name,
+		Description: m.Description,
+		Status:      m.Status,
+		QuotaPolicy: quotaPolicyModelToDto(m.QuotaPolicy),
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
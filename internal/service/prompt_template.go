@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/service/chat"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ChatPayload.Extensions 中引用樣板的欄位
+const (
+	extensionTemplate     = "template"
+	extensionTemplateVars = "vars"
+)
+
+// TemplateUsage 本次請求使用的樣板（寫入 usage log）
+type TemplateUsage struct {
+	Name    string
+	Version int
+}
+
+type PromptTemplateService struct {
+	trace        *telemetry.Trace
+	templateRepo *repository.PromptTemplateRepository
+}
+
+func NewPromptTemplateService(
+	trace *telemetry.Trace,
+	templateRepo *repository.PromptTemplateRepository,
+) *PromptTemplateService {
+	return &PromptTemplateService{trace: trace, templateRepo: templateRepo}
+}
+
+// 新增樣板版本（同名自動遞增版本，舊版本保留）
+func (s *PromptTemplateService) CreateVersion(ctx context.Context, req *dto.CreatePromptTemplateDto, createdBy string) (*dto.PromptTemplateResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if strings.ContainsAny(req.Name, "@/ ") {
+		return nil, cErr.BadRequestParams("template name must not contain '@', '/' or spaces")
+	}
+	messages := make([]model.PromptMessage, len(req.Messages))
+	for i, message := range req.Messages {
+		if _, err := template.New(req.Name).Parse(message.Content); err != nil {
+			return nil, cErr.BadRequestParams(fmt.Sprintf("messages[%d]: invalid template: %v", i, err))
+		}
+		messages[i] = model.PromptMessage{Role: message.Role, Content: message.Content}
+	}
+
+	created, err := s.templateRepo.CreateVersion(ctx, &model.PromptTemplate{
+		Name:        req.Name,
+		Description: req.Description,
+		Messages:    messages,
+		Variables:   req.Variables,
+		CreatedBy:   createdBy,
+	})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database CreatePromptTemplate error")
+	}
+	return modelToPromptTemplateResponseDto(created), nil
+}
+
+// 列出各樣板最新版本
+func (s *PromptTemplateService) ListTemplates(ctx context.Context, filter bson.M, page, size int64) ([]*dto.PromptTemplateResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	templates, err := s.templateRepo.ListLatest(ctx, core.ListOptions{Filter: filter, Page: page, Size: size})
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListPromptTemplates error")
+	}
+	return modelsToPromptTemplateResponseDtos(templates), nil
+}
+
+// 列出樣板所有版本
+func (s *PromptTemplateService) ListVersions(ctx context.Context, name string) ([]*dto.PromptTemplateResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	templates, err := s.templateRepo.ListVersions(ctx, name)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database ListPromptTemplateVersions error")
+	}
+	if len(templates) == 0 {
+		return nil, cErr.NotFound(fmt.Sprintf("prompt template %s not found", name))
+	}
+	return modelsToPromptTemplateResponseDtos(templates), nil
+}
+
+// 取得指定版本
+func (s *PromptTemplateService) GetVersion(ctx context.Context, name string, version int) (*dto.PromptTemplateResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	found, err := s.templateRepo.GetVersion(ctx, name, version)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound(fmt.Sprintf("prompt template %s@%d not found", name, version))
+		}
+		end(err)
+		return nil, cErr.DatabaseError("database GetPromptTemplate error")
+	}
+	return modelToPromptTemplateResponseDto(found), nil
+}
+
+// 刪除樣板（所有版本）
+func (s *PromptTemplateService) DeleteTemplate(ctx context.Context, name string) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	deleted, err := s.templateRepo.DeleteByName(ctx, name)
+	if err != nil {
+		end(err)
+		return cErr.DatabaseError("database DeletePromptTemplate error")
+	}
+	if deleted == 0 {
+		return cErr.NotFound(fmt.Sprintf("prompt template %s not found", name))
+	}
+	return nil
+}
+
+// ApplyToChat 轉發前改寫訊息：
+//  1. extensions.template（name 或 name@version）渲染後置於呼叫端訊息之前，並移除 template / vars
+//  2. ProviderAccess.Prompt 的強制 system message 置於最前 / 最後
+//
+// 回傳使用的樣板（未使用時為 nil）。
+func (s *PromptTemplateService) ApplyToChat(ctx context.Context, providerAccess *model.ProviderAccess, payload *chat.ChatPayload) (*TemplateUsage, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	var usage *TemplateUsage
+	if reference, ok := payload.Extensions[extensionTemplate].(string); ok && reference != "" {
+		vars, _ := payload.Extensions[extensionTemplateVars].(map[string]interface{})
+		found, err := s.resolve(ctx, reference)
+		if err != nil {
+			end(err)
+			return nil, err
+		}
+		rendered, err := renderPromptTemplate(found, vars)
+		if err != nil {
+			end(err)
+			return nil, err
+		}
+		payload.Messages = append(rendered, payload.Messages...)
+		usage = &TemplateUsage{Name: found.Name, Version: found.Version}
+		span.SetAttributes(
+			attribute.String("template.name", found.Name),
+			attribute.Int("template.version", found.Version),
+		)
+	}
+	if payload.Extensions != nil {
+		delete(payload.Extensions, extensionTemplate)
+		delete(payload.Extensions, extensionTemplateVars)
+		if len(payload.Extensions) == 0 {
+			payload.Extensions = nil
+		}
+	}
+
+	if providerAccess != nil && providerAccess.Prompt != nil {
+		if providerAccess.Prompt.Prepend != "" {
+			payload.Messages = append([]chat.ChatMessage{{Role: "system", Content: providerAccess.Prompt.Prepend}}, payload.Messages...)
+		}
+		if providerAccess.Prompt.Append != "" {
+			payload.Messages = append(payload.Messages, chat.ChatMessage{Role: "system", Content: providerAccess.Prompt.Append})
+		}
+	}
+	return usage, nil
+}
+
+// resolve 解析 name@version（未指定版本取最新）
+func (s *PromptTemplateService) resolve(ctx context.Context, reference string) (*model.PromptTemplate, error) {
+	name, versionText, hasVersion := strings.Cut(reference, "@")
+	var (
+		found *model.PromptTemplate
+		err   error
+	)
+	if hasVersion {
+		version, parseErr := strconv.Atoi(versionText)
+		if parseErr != nil || version <= 0 {
+			return nil, cErr.BadRequestParams("invalid template version: " + reference)
+		}
+		found, err = s.templateRepo.GetVersion(ctx, name, version)
+	} else {
+		found, err = s.templateRepo.GetLatest(ctx, name)
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("prompt template not found: " + reference)
+		}
+		return nil, cErr.DatabaseError("database GetPromptTemplate error")
+	}
+	return found, nil
+}
+
+// renderPromptTemplate 以 text/template 渲染訊息（缺少變數時回 400）
+func renderPromptTemplate(found *model.PromptTemplate, vars map[string]interface{}) ([]chat.ChatMessage, error) {
+	var missing []string
+	for _, name := range found.Variables {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, cErr.BadRequestParams("missing template vars: " + strings.Join(missing, ", "))
+	}
+
+	messages := make([]chat.ChatMessage, len(found.Messages))
+	for i, message := range found.Messages {
+		parsed, err := template.New(found.Name).Option("missingkey=error").Parse(message.Content)
+		if err != nil {
+			return nil, cErr.InternalServer(fmt.Sprintf("invalid stored template %s@%d", found.Name, found.Version))
+		}
+		var rendered strings.Builder
+		if err := parsed.Execute(&rendered, vars); err != nil {
+			return nil, cErr.BadRequestParams(fmt.Sprintf("render template %s@%d failed: %v", found.Name, found.Version, err))
+		}
+		messages[i] = chat.ChatMessage{Role: message.Role, Content: rendered.String()}
+	}
+	return messages, nil
+}
+
+func modelToPromptTemplateResponseDto(m *model.PromptTemplate) *dto.PromptTemplateResponseDto {
+	messages := make([]dto.PromptMessageDto, len(m.Messages))
+	for i, message := range m.Messages {
+		messages[i] = dto.PromptMessageDto{Role: message.Role, Content: message.Content}
+	}
+	return &dto.PromptTemplateResponseDto{
+		ID:          m.ID.Hex(),
+		Name:        m.Name,
+		Version:     m.Version,
+		Description: m.Description,
+		Messages:    messages,
+		Variables:   m.Variables,
+		CreatedBy:   m.CreatedBy,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func modelsToPromptTemplateResponseDtos(templates []*model.PromptTemplate) []*dto.PromptTemplateResponseDto {
+	resp := make([]*dto.PromptTemplateResponseDto, len(templates))
+	for i, t := range templates {
+		resp[i] = modelToPromptTemplateResponseDto(t)
+	}
+	return resp
+}
@@ -8,6 +8,7 @@ import (
 
 	"interchange/internal/core"
 	cErr "interchange/internal/pkg/error"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/telemetry"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -25,15 +26,15 @@ type ForwardParams struct {
 	Body        io.Reader         // 請求 body（可直接塞 c.Request.Body）
 }
 type ProxyService struct {
-	httpClient *http.Client
-	trace      *telemetry.Trace
+	clients *outbound.Clients
+	trace   *telemetry.Trace
 }
 
-func NewProxyService(trace *telemetry.Trace, client *http.Client) *ProxyService {
-	// client 建議在 DI 時就統一設好 Transport／Timeout
+func NewProxyService(trace *telemetry.Trace, clients *outbound.Clients) *ProxyService {
+	// 各 provider 的 Transport／Timeout 由 OUTBOUND 設定於 DI 時建立
 	return &ProxyService{
-		httpClient: client,
-		trace:      trace,
+		clients: clients,
+		trace:   trace,
 	}
 }
 
@@ -68,15 +69,9 @@ func (service *ProxyService) Forward(ctx context.Context, req ForwardParams) (*h
 	// 1) 複製上游 header（去除 hop-by-hop 與由我們管理的授權）
 	copySafeHeaders(req.Header, request.Header)
 
-	// 2) 依 provider 注入授權
-	switch req.Provider {
-	case core.ProviderOpenAI:
-		// OpenAI: Authorization: Bearer <key>
-		request.Header.Set("Authorization", "Bearer "+req.ProviderKey)
-	case core.ProviderGemini:
-		// Gemini/Google AI: 走 header（亦可改走 query key）
-		request.Header.Set("x-goog-api-key", req.ProviderKey)
-	default:
+	// 2) 依 provider 設定注入授權與預設標頭
+	if err := service.clients.Authorize(request, req.Provider, req.ProviderKey); err != nil {
+		end(err)
 		return nil, cErr.Forbidden("unsupported provider")
 	}
 
@@ -85,10 +80,10 @@ func (service *ProxyService) Forward(ctx context.Context, req ForwardParams) (*h
 		request.Header.Set("Accept", "application/json")
 	}
 
-	resp, err := service.httpClient.Do(request)
+	resp, err := service.clients.For(req.Provider).Do(request)
 	if err != nil {
 		end(err)
-		return nil, cErr.ExternalRequestError("provider request failed")
+		return nil, outbound.RequestError(err, "provider request failed")
 	}
 
 	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// QuotaService 負責 user / org 層級的彙總配額（跨 API Key 累計 requests / tokens / cost）
+type QuotaService struct {
+	trace            *telemetry.Trace
+	config           *config.Configuration
+	userRepo         *mongoDb.UserRepository
+	organizationRepo *mongoDb.OrganizationRepository
+	quotaRepo        *redisDb.QuotaRepository
+	entityCache      *EntityCacheService
+}
+
+func NewQuotaService(
+	trace *telemetry.Trace,
+	config *config.Configuration,
+	userRepo *mongoDb.UserRepository,
+	organizationRepo *mongoDb.OrganizationRepository,
+	quotaRepo *redisDb.QuotaRepository,
+	entityCache *EntityCacheService,
+) *QuotaService {
+	return &QuotaService{
+		trace:            trace,
+		config:           config,
+		userRepo:         userRepo,
+		organizationRepo: organizationRepo,
+		quotaRepo:        quotaRepo,
+		entityCache:      entityCache,
+	}
+}
+
+// Check 計算使用者與其所屬組織的剩餘額度（不扣款）。
+// 回傳的 budgets 依 user → org 排列；是否阻擋由呼叫端（RateLimit middleware）判斷。
+func (s *QuotaService) Check(ctx context.Context, userID primitive.ObjectID) ([]core.QuotaBudget, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	user, organization, err := s.loadOwners(ctx, userID)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+
+	budgets := make([]core.QuotaBudget, 0, 6)
+	if user.QuotaPolicy != nil {
+		userBudgets, err := s.budgets(ctx, core.QuotaScopeUser, user.ID.Hex(), user.QuotaPolicy)
+		if err != nil {
+			end(err)
+			return nil, err
+		}
+		budgets = append(budgets, userBudgets...)
+	}
+	if organization != nil && organization.QuotaPolicy != nil {
+		orgBudgets, err := s.budgets(ctx, core.QuotaScopeOrg, organization.ID.Hex(), organization.QuotaPolicy)
+		if err != nil {
+			end(err)
+			return nil, err
+		}
+		budgets = append(budgets, orgBudgets...)
+	}
+
+	span.SetAttributes(
+		attribute.String("user.id", userID.Hex()),
+		attribute.Int("quota.budget_count", len(budgets)),
+	)
+	return budgets, nil
+}
+
+type quotaReservationKey struct{}
+
+// QuotaReservation 一次請求在 user / org 配額預留的用量：RateLimit 於上游呼叫前以 Reserve 預留 1 次請求，
+// handler 以 Settle 依實際用量結算，未結算（請求被拒或上游失敗）時由 RateLimit 以 Release 退回
+type QuotaReservation struct {
+	holds    []redisDb.QuotaHold
+	reserved core.QuotaUsage
+	done     int32 // 已結算或退回
+}
+
+// WithQuotaReservation 將預留放入 context，供 handler 結算
+func WithQuotaReservation(ctx context.Context, reservation *QuotaReservation) context.Context {
+	return context.WithValue(ctx, quotaReservationKey{}, reservation)
+}
+
+// QuotaReservationFromContext 取出預留（未經 RateLimit 或預留失敗時為 nil）
+func QuotaReservationFromContext(ctx context.Context) *QuotaReservation {
+	reservation, _ := ctx.Value(quotaReservationKey{}).(*QuotaReservation)
+	return reservation
+}
+
+// Reserve 以 Lua 原子地檢查 user / org 剩餘額度並預留 1 次請求，避免並行請求超出上限；
+// 任一層級的請求數用盡、或 tokens / 成本已達上限時回傳 RateLimitExceeded。
+// tokens 與成本於請求前無法得知，由 Settle 補記。讀取錯誤不阻斷主流程（回傳 nil，由 Settle 直接記帳）。
+func (s *QuotaService) Reserve(ctx context.Context, userID string) (*QuotaReservation, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, nil
+	}
+	user, organization, err := s.loadOwners(ctx, userObjectID)
+	if err != nil {
+		span.SetAttributes(attribute.String("quota.reserve_error", err.Error()))
+		return nil, nil
+	}
+
+	reservation := &QuotaReservation{reserved: core.QuotaUsage{Requests: 1}}
+	if user.QuotaPolicy != nil {
+		reservation.holds = append(reservation.holds, quotaHold(core.QuotaScopeUser, user.ID.Hex(), user.QuotaPolicy))
+	}
+	if organization != nil && organization.QuotaPolicy != nil {
+		reservation.holds = append(reservation.holds, quotaHold(core.QuotaScopeOrg, organization.ID.Hex(), organization.QuotaPolicy))
+	}
+	exhausted, metric, err := s.quotaRepo.Reserve(ctx, reservation.holds, reservation.reserved)
+	if err != nil {
+		span.SetAttributes(attribute.String("quota.reserve_error", err.Error()))
+		return nil, nil
+	}
+	if exhausted >= 0 {
+		cause := cErr.RateLimitExceeded(fmt.Sprintf("%s quota exceeded: %s", reservation.holds[exhausted].Scope, metric))
+		end(cause)
+		return nil, cause
+	}
+	span.SetAttributes(
+		attribute.String("user.id", userID),
+		attribute.Int("quota.hold_count", len(reservation.holds)),
+	)
+	return reservation, nil
+}
+
+// Settle 請求完成後依實際用量結算：使用者與其所屬組織各計 1 次請求、tokens 與估算成本（扣除已預留的部分）。
+// context 中沒有預留時（未經 RateLimit 或預留失敗）直接記帳。
+// 快取命中以 0 tokens 結算：計入 1 次請求，不計 tokens 與成本。
+func (s *QuotaService) Settle(ctx context.Context, userID string, modelName string, inputTokens, outputTokens int) error {
+	reservation := QuotaReservationFromContext(ctx)
+	if reservation == nil {
+		return s.Charge(ctx, userID, modelName, inputTokens, outputTokens)
+	}
+	if !atomic.CompareAndSwapInt32(&reservation.done, 0, 1) {
+		return nil
+	}
+
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	cost := s.EstimateCost(modelName, inputTokens, outputTokens)
+	span.SetAttributes(
+		attribute.String("user.id", userID),
+		attribute.String("quota.model", modelName),
+		attribute.Int("quota.tokens", inputTokens+outputTokens),
+		attribute.Float64("quota.cost", cost),
+	)
+	err := s.adjust(ctx, reservation, core.QuotaUsage{
+		Requests: 1 - reservation.reserved.Requests,
+		Tokens:   int64(inputTokens+outputTokens) - reservation.reserved.Tokens,
+		Cost:     cost - reservation.reserved.Cost,
+	})
+	if err != nil {
+		end(err)
+	}
+	return err
+}
+
+// Release 退回尚未結算的預留（請求被拒、上游失敗或未回應時）
+func (s *QuotaService) Release(ctx context.Context, reservation *QuotaReservation) error {
+	if reservation == nil || !atomic.CompareAndSwapInt32(&reservation.done, 0, 1) {
+		return nil
+	}
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	err := s.adjust(ctx, reservation, core.QuotaUsage{
+		Requests: -reservation.reserved.Requests,
+		Tokens:   -reservation.reserved.Tokens,
+		Cost:     -reservation.reserved.Cost,
+	})
+	if err != nil {
+		end(err)
+	}
+	return err
+}
+
+// adjust 將差額套用到預留的各層級
+func (s *QuotaService) adjust(ctx context.Context, reservation *QuotaReservation, delta core.QuotaUsage) error {
+	if delta == (core.QuotaUsage{}) {
+		return nil
+	}
+	for _, hold := range reservation.holds {
+		if err := s.quotaRepo.Add(ctx, hold.Scope, hold.ScopeIdentifier, hold.Period, hold.WindowSeconds, delta); err != nil {
+			return cErr.RateLimiterUnavailable(fmt.Sprintf("redis adjust %s quota usage failed", hold.Scope))
+		}
+	}
+	return nil
+}
+
+// Charge 直接記帳（未經預留時使用）：使用者與其所屬組織各自累加 1 次請求、tokens 與估算成本。
+func (s *QuotaService) Charge(ctx context.Context, userID string, modelName string, inputTokens, outputTokens int) error {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		end(err)
+		return cErr.BadRequestParams("invalid user ID: not a valid ObjectID")
+	}
+	user, organization, err := s.loadOwners(ctx, userObjectID)
+	if err != nil {
+		end(err)
+		return err
+	}
+
+	usage := core.QuotaUsage{
+		Requests: 1,
+		Tokens:   int64(inputTokens + outputTokens),
+		Cost:     s.EstimateCost(modelName, inputTokens, outputTokens),
+	}
+	span.SetAttributes(
+		attribute.String("user.id", userID),
+		attribute.String("quota.model", modelName),
+		attribute.Int64("quota.tokens", usage.Tokens),
+		attribute.Float64("quota.cost", usage.Cost),
+	)
+
+	if user.QuotaPolicy != nil {
+		period := user.QuotaPolicy.LimitPeriod
+		if err := s.quotaRepo.Add(ctx, core.QuotaScopeUser, user.ID.Hex(), period, periodToDuration(period), usage); err != nil {
+			end(err)
+			return cErr.RateLimiterUnavailable("redis add user quota usage failed")
+		}
+	}
+	if organization != nil && organization.QuotaPolicy != nil {
+		period := organization.QuotaPolicy.LimitPeriod
+		if err := s.quotaRepo.Add(ctx, core.QuotaScopeOrg, organization.ID.Hex(), period, periodToDuration(period), usage); err != nil {
+			end(err)
+			return cErr.RateLimiterUnavailable("redis add org quota usage failed")
+		}
+	}
+	return nil
+}
+
+// EstimateCost 依 Pricing 設定估算單次請求成本；未設定的模型使用預設單價。
+func (s *QuotaService) EstimateCost(modelName string, inputTokens, outputTokens int) float64 {
+	price := s.config.Pricing.Default
+	if modelPrice, ok := s.config.Pricing.Models[modelName]; ok {
+		price = modelPrice
+	}
+	return price.PerRequest +
+		float64(inputTokens)/1000*price.PromptPer1K +
+		float64(outputTokens)/1000*price.CompletionPer1K
+}
+
+// GetUserQuota 取得使用者配額政策與目前用量
+func (s *QuotaService) GetUserQuota(ctx context.Context, userID primitive.ObjectID) (*dto.QuotaStatusDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("user not found")
+		}
+		return nil, cErr.DatabaseError("mongodb get user failed")
+	}
+	return s.quotaStatus(ctx, core.QuotaScopeUser, user.ID.Hex(), user.QuotaPolicy)
+}
+
+// UpdateUserQuota 設定使用者配額政策；policy 為 nil 時移除
+func (s *QuotaService) UpdateUserQuota(ctx context.Context, userID primitive.ObjectID, policy *dto.QuotaPolicyDto) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	matchedCount, err := s.userRepo.UpdateQuotaPolicy(ctx, userID, quotaPolicyDtoToModel(policy))
+	if err != nil {
+		return cErr.DatabaseError("mongodb update user quota policy failed")
+	}
+	if matchedCount == 0 {
+		return cErr.NotFound(fmt.Sprintf("user with id %s not found", userID.Hex()))
+	}
+	s.entityCache.InvalidateUser(ctx, userID)
+	return nil
+}
+
+// ResetUserQuotaUsage 清除使用者目前視窗的累計用量
+func (s *QuotaService) ResetUserQuotaUsage(ctx context.Context, userID primitive.ObjectID) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return cErr.NotFound("user not found")
+		}
+		return cErr.DatabaseError("mongodb get user failed")
+	}
+	if user.QuotaPolicy == nil {
+		return nil
+	}
+	if err := s.quotaRepo.Reset(ctx, core.QuotaScopeUser, user.ID.Hex(), user.QuotaPolicy.LimitPeriod); err != nil {
+		return cErr.DatabaseError("redis reset user quota failed")
+	}
+	return nil
+}
+
+// GetOrganizationQuota 取得組織配額政策與目前用量
+func (s *QuotaService) GetOrganizationQuota(ctx context.Context, orgID primitive.ObjectID) (*dto.QuotaStatusDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	organization, err := s.organizationRepo.GetByID(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("organization not found")
+		}
+		return nil, cErr.DatabaseError("mongodb get organization failed")
+	}
+	return s.quotaStatus(ctx, core.QuotaScopeOrg, organization.ID.Hex(), organization.QuotaPolicy)
+}
+
+// UpdateOrganizationQuota 設定組織配額政策；policy 為 nil 時移除
+func (s *QuotaService) UpdateOrganizationQuota(ctx context.Context, orgID primitive.ObjectID, policy *dto.QuotaPolicyDto) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	matchedCount, err := s.organizationRepo.UpdateQuotaPolicy(ctx, orgID, quotaPolicyDtoToModel(policy))
+	if err != nil {
+		return cErr.DatabaseError("mongodb update organization quota policy failed")
+	}
+	if matchedCount == 0 {
+		return cErr.NotFound(fmt.Sprintf("organization with id %s not found", orgID.Hex()))
+	}
+	return nil
+}
+
+// ResetOrganizationQuotaUsage 清除組織目前視窗的累計用量
+func (s *QuotaService) ResetOrganizationQuotaUsage(ctx context.Context, orgID primitive.ObjectID) error {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	organization, err := s.organizationRepo.GetByID(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return cErr.NotFound("organization not found")
+		}
+		return cErr.DatabaseError("mongodb get organization failed")
+	}
+	if organization.QuotaPolicy == nil {
+		return nil
+	}
+	if err := s.quotaRepo.Reset(ctx, core.QuotaScopeOrg, organization.ID.Hex(), organization.QuotaPolicy.LimitPeriod); err != nil {
+		return cErr.DatabaseError("redis reset organization quota failed")
+	}
+	return nil
+}
+
+// loadOwners 取得使用者與其所屬（啟用中）組織；組織不存在或非啟用時視為無組織配額
+func (s *QuotaService) loadOwners(ctx context.Context, userID primitive.ObjectID) (*model.User, *model.Organization, error) {
+	user, err := s.entityCache.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, cErr.NotFound("user not found")
+		}
+		return nil, nil, cErr.DatabaseError("mongodb get user failed")
+	}
+	if user.OrgID == nil || user.OrgID.IsZero() {
+		return user, nil, nil
+	}
+	organization, err := s.organizationRepo.GetByID(ctx, *user.OrgID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return user, nil, nil
+		}
+		return nil, nil, cErr.DatabaseError("mongodb get organization failed")
+	}
+	if organization.Status != core.StatusActive {
+		return user, nil, nil
+	}
+	return user, organization, nil
+}
+
+// budgets 將政策與 Redis 目前用量換算成各計量單位的剩餘額度
+func (s *QuotaService) budgets(ctx context.Context, scope core.QuotaScope, scopeID string, policy *model.QuotaPolicy) ([]core.QuotaBudget, error) {
+	usage, ttlSec, err := s.quotaRepo.GetUsage(ctx, scope, scopeID, policy.LimitPeriod)
+	if err != nil {
+		return nil, cErr.RateLimiterUnavailable("redis get quota usage failed: " + err.Error())
+	}
+	return policyBudgets(scope, scopeID, policy, usage, ttlSec), nil
+}
+
+func (s *QuotaService) quotaStatus(ctx context.Context, scope core.QuotaScope, scopeID string, policy *model.QuotaPolicy) (*dto.QuotaStatusDto, error) {
+	status := &dto.QuotaStatusDto{
+		Scope:   scope,
+		ScopeID: scopeID,
+		Policy:  quotaPolicyModelToDto(policy),
+		Budgets: []core.QuotaBudget{},
+	}
+	if policy == nil {
+		return status, nil
+	}
+	usage, ttlSec, err := s.quotaRepo.GetUsage(ctx, scope, scopeID, policy.LimitPeriod)
+	if err != nil {
+		return nil, cErr.DatabaseError("redis get quota usage failed")
+	}
+	status.Usage = usage
+	status.ResetSeconds = ttlSec
+	status.Budgets = policyBudgets(scope, scopeID, policy, usage, ttlSec)
+	return status, nil
+}
+
+func policyBudgets(scope core.QuotaScope, scopeID string, policy *model.QuotaPolicy, usage core.QuotaUsage, ttlSec int64) []core.QuotaBudget {
+	budgets := make([]core.QuotaBudget, 0, 3)
+	add := func(metric core.QuotaMetric, limit, used float64) {
+		budgets = append(budgets, core.QuotaBudget{
+			Scope:        scope,
+			ScopeID:      scopeID,
+			Metric:       metric,
+			Limit:        limit,
+			Remaining:    limit - used,
+			ResetSeconds: ttlSec,
+		})
+	}
+	if policy.RequestLimit != nil && *policy.RequestLimit > 0 {
+		add(core.QuotaMetricRequests, float64(*policy.RequestLimit), float64(usage.Requests))
+	}
+	if policy.TokenLimit != nil && *policy.TokenLimit > 0 {
+		add(core.QuotaMetricTokens, float64(*policy.TokenLimit), float64(usage.Tokens))
+	}
+	if policy.CostLimit != nil && *policy.CostLimit > 0 {
+		add(core.QuotaMetricCost, *policy.CostLimit, usage.Cost)
+	}
+	return budgets
+}
+
+// quotaHold 政策轉為預留時檢查的上限（未設定或 <= 0 的計量單位不限制）
+func quotaHold(scope core.QuotaScope, scopeID string, policy *model.QuotaPolicy) redisDb.QuotaHold {
+	hold := redisDb.QuotaHold{
+		Scope:           scope,
+		ScopeIdentifier: scopeID,
+		Period:          policy.LimitPeriod,
+		WindowSeconds:   periodToDuration(policy.LimitPeriod),
+		RequestLimit:    -1,
+		TokenLimit:      -1,
+		CostLimit:       -1,
+	}
+	if policy.RequestLimit != nil && *policy.RequestLimit > 0 {
+		hold.RequestLimit = *policy.RequestLimit
+	}
+	if policy.TokenLimit != nil && *policy.TokenLimit > 0 {
+		hold.TokenLimit = *policy.TokenLimit
+	}
+	if policy.CostLimit != nil && *policy.CostLimit > 0 {
+		hold.CostLimit = *policy.CostLimit
+	}
+	return hold
+}
+
+// QuotaPolicy DTO to model
+func quotaPolicyDtoToModel(policy *dto.QuotaPolicyDto) *model.QuotaPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &model.QuotaPolicy{
+		LimitPeriod:  policy.LimitPeriod,
+		RequestLimit: policy.RequestLimit,
+		TokenLimit:   policy.TokenLimit,
+		CostLimit:    policy.CostLimit,
+	}
+}
+
+// QuotaPolicy model to DTO
+func quotaPolicyModelToDto(policy *model.QuotaPolicy) *dto.QuotaPolicyDto {
+	if policy == nil {
+		return nil
+	}
+	return &dto.QuotaPolicyDto{
+		LimitPeriod:  policy.LimitPeriod,
+		RequestLimit: policy.RequestLimit,
+		TokenLimit:   policy.TokenLimit,
+		CostLimit:    policy.CostLimit,
+	}
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"interchange/config"
 	"interchange/internal/core"
+	"interchange/internal/pkg/outbound"
 	"interchange/internal/service/audio"
 	"interchange/internal/service/chat"
 	"interchange/internal/service/embedding"
@@ -9,7 +11,9 @@ import (
 	"interchange/internal/service/models"
 )
 
+// Registry 依 provider 取得實作；Get 時依 PROVIDERS 設定檢查 provider 是否啟用及是否開放該功能
 type Registry struct {
+	config           *config.Configuration
 	ChatServices     map[core.ProviderName]chat.Service
 	ImagesServices   map[core.ProviderName]images.Service
 	AudioServices    map[core.ProviderName]audio.Service
@@ -21,6 +25,9 @@ func (r *Registry) RegisterChat(provider core.ProviderName, service chat.Service
 	r.ChatServices[provider] = service
 }
 func (r *Registry) GetChat(provider core.ProviderName) (chat.Service, bool) {
+	if !r.enabled(provider, core.ProviderCapabilityChat) {
+		return nil, false
+	}
 	svc, ok := r.ChatServices[provider]
 	return svc, ok
 }
@@ -29,6 +36,9 @@ func (r *Registry) RegisterImages(provider core.ProviderName, service images.Ser
 	r.ImagesServices[provider] = service
 }
 func (r *Registry) GetImages(provider core.ProviderName) (images.Service, bool) {
+	if !r.enabled(provider, core.ProviderCapabilityImages) {
+		return nil, false
+	}
 	svc, ok := r.ImagesServices[provider]
 	return svc, ok
 }
@@ -36,6 +46,9 @@ func (r *Registry) RegisterAudio(provider core.ProviderName, service audio.Servi
 	r.AudioServices[provider] = service
 }
 func (r *Registry) GetAudio(provider core.ProviderName) (audio.Service, bool) {
+	if !r.enabled(provider, core.ProviderCapabilityAudio) {
+		return nil, false
+	}
 	svc, ok := r.AudioServices[provider]
 	return svc, ok
 }
@@ -44,6 +57,9 @@ func (r *Registry) RegisterEmbedding(provider core.ProviderName, service embeddi
 	r.EmbeddingService[provider] = service
 }
 func (r *Registry) GetEmbedding(provider core.ProviderName) (embedding.Service, bool) {
+	if !r.enabled(provider, core.ProviderCapabilityEmbeddings) {
+		return nil, false
+	}
 	svc, ok := r.EmbeddingService[provider]
 	return svc, ok
 }
@@ -51,6 +67,14 @@ func (r *Registry) RegisterModels(provider core.ProviderName, service models.Ser
 	r.ModelsServices[provider] = service
 }
 func (r *Registry) GetModels(provider core.ProviderName) (models.Service, bool) {
+	if !r.enabled(provider, core.ProviderCapabilityModels) {
+		return nil, false
+	}
 	svc, ok := r.ModelsServices[provider]
 	return svc, ok
 }
+
+// enabled 未注入設定時視為全部啟用
+func (r *Registry) enabled(provider core.ProviderName, capability core.ProviderCapability) bool {
+	return r.config == nil || outbound.Supports(r.config, provider, capability)
+}
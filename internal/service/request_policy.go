@@ -0,0 +1,227 @@
+package service
+
+import (
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	cErr "interchange/internal/pkg/error"
+)
+
+// PolicyVars 強制值的替換變數
+type PolicyVars struct {
+	UserID     string // API Key 擁有者
+	ExternalID string // 呼叫端帶入的 userID
+}
+
+var fileHeaderType = reflect.TypeOf(&multipart.FileHeader{})
+
+// ApplyRequestPolicy 依 ProviderAccess.Request 檢查並改寫請求 payload（struct 指標，欄位以 json / form tag 命名）。
+// 順序：移除欄位 → 強制值 → 允許值 → 數值範圍；違規欄位彙整為 BadRequestParams 回傳。
+func ApplyRequestPolicy(providerAccess *model.ProviderAccess, payload interface{}, vars PolicyVars) error {
+	if providerAccess == nil || providerAccess.Request == nil {
+		return nil
+	}
+	policy := providerAccess.Request
+
+	value := reflect.ValueOf(payload)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return cErr.InternalServer("request policy requires a struct pointer")
+	}
+	value = value.Elem()
+
+	var violations []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		name := policyFieldName(value.Type().Field(i))
+		if name == "" || !field.CanSet() {
+			continue
+		}
+
+		if slices.Contains(policy.Strip, name) {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		if forced, ok := policy.Force[name]; ok {
+			if err := setForcedValue(field, forced, vars); err != nil {
+				violations = append(violations, fmt.Sprintf("%s (cannot force value: %v)", name, err))
+			}
+		}
+		if allowed, ok := policy.Allowed[name]; ok {
+			if text, set := stringValue(field); set && !slices.Contains(allowed, text) {
+				violations = append(violations, fmt.Sprintf("%s (allowed: %s)", name, strings.Join(allowed, ", ")))
+			}
+		}
+		if limit, ok := policy.Limits[name]; ok {
+			violations = append(violations, applyLimit(field, name, limit)...)
+		}
+	}
+
+	if len(violations) > 0 {
+		return cErr.BadRequestParams("request policy violation: " + strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// policyFieldName 取 json 或 form tag 名稱（去除 omitempty 與 [] 後綴）
+func policyFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		tag := field.Tag.Get(key)
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		return strings.TrimSuffix(name, "[]")
+	}
+	return ""
+}
+
+// applyLimit 數值比對值；字串比對長度、上傳檔案比對位元組數。未設定（nil / 0）的欄位略過。
+func applyLimit(field reflect.Value, name string, limit model.NumericLimit) []string {
+	target := field
+	if target.Kind() == reflect.Ptr && target.Type() != fileHeaderType {
+		if target.IsNil() {
+			return nil
+		}
+		target = target.Elem()
+	}
+
+	check := func(current float64, label string) (float64, string) {
+		switch {
+		case limit.Max != nil && current > *limit.Max:
+			return *limit.Max, fmt.Sprintf("%s (%s %v exceeds max %v)", name, label, current, *limit.Max)
+		case limit.Min != nil && current < *limit.Min:
+			return *limit.Min, fmt.Sprintf("%s (%s %v below min %v)", name, label, current, *limit.Min)
+		}
+		return current, ""
+	}
+
+	switch {
+	case target.Type() == fileHeaderType:
+		if target.IsNil() {
+			return nil
+		}
+		// 檔案無法夾值，一律拒絕
+		if _, violation := check(float64(target.Interface().(*multipart.FileHeader).Size), "size"); violation != "" {
+			return []string{violation}
+		}
+	case target.Kind() == reflect.Slice && target.Type().Elem() == fileHeaderType:
+		var violations []string
+		for j := 0; j < target.Len(); j++ {
+			if header := target.Index(j).Interface().(*multipart.FileHeader); header != nil {
+				if _, violation := check(float64(header.Size), "size"); violation != "" {
+					violations = append(violations, violation)
+				}
+			}
+		}
+		return violations
+	case target.Kind() == reflect.String:
+		if target.Len() == 0 {
+			return nil
+		}
+		// 字串長度無法夾值，一律拒絕
+		if _, violation := check(float64(utf8.RuneCountInString(target.String())), "length"); violation != "" {
+			return []string{violation}
+		}
+	case target.CanInt():
+		if target.Int() == 0 && field.Kind() != reflect.Ptr {
+			return nil
+		}
+		clamped, violation := check(float64(target.Int()), "value")
+		if violation == "" {
+			return nil
+		}
+		if limit.Action == core.LimitActionReject {
+			return []string{violation}
+		}
+		target.SetInt(int64(clamped))
+	case target.CanFloat():
+		if target.Float() == 0 && field.Kind() != reflect.Ptr {
+			return nil
+		}
+		clamped, violation := check(target.Float(), "value")
+		if violation == "" {
+			return nil
+		}
+		if limit.Action == core.LimitActionReject {
+			return []string{violation}
+		}
+		target.SetFloat(clamped)
+	}
+	return nil
+}
+
+// stringValue 取字串欄位（含 *string 與自訂字串型別）；未設定時 set=false
+func stringValue(field reflect.Value) (string, bool) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return "", false
+		}
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.String || field.Len() == 0 {
+		return "", false
+	}
+	return field.String(), true
+}
+
+// setForcedValue 將強制值轉成欄位型別後寫入（指標欄位自動配置）
+func setForcedValue(field reflect.Value, forced interface{}, vars PolicyVars) error {
+	if text, ok := forced.(string); ok {
+		text = strings.ReplaceAll(text, core.PolicyVarUserID, vars.UserID)
+		text = strings.ReplaceAll(text, core.PolicyVarExternalID, vars.ExternalID)
+		forced = text
+	}
+
+	target := field
+	if field.Kind() == reflect.Ptr {
+		target = reflect.New(field.Type().Elem()).Elem()
+	}
+	if err := convertInto(target, forced); err != nil {
+		return err
+	}
+	if field.Kind() == reflect.Ptr {
+		pointer := reflect.New(field.Type().Elem())
+		pointer.Elem().Set(target)
+		field.Set(pointer)
+	}
+	return nil
+}
+
+// convertInto 支援 string / bool / 整數 / 浮點數 與 interface{} 欄位
+func convertInto(target reflect.Value, value interface{}) error {
+	text := fmt.Sprint(value)
+	switch {
+	case target.Kind() == reflect.Interface:
+		target.Set(reflect.ValueOf(value))
+	case target.Kind() == reflect.String:
+		target.SetString(text)
+	case target.Kind() == reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		target.SetBool(parsed)
+	case target.CanInt():
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		target.SetInt(int64(parsed))
+	case target.CanFloat():
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", target.Type())
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	redisDb "interchange/internal/database/redis/repository"
+	"interchange/internal/service/chat"
+	"interchange/internal/service/embedding"
+	"interchange/internal/telemetry"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultResponseCacheTTLSeconds = 3600
+	defaultResponseCacheMaxBytes   = 1 << 20
+)
+
+// ResponseCacheService 確定性請求的回應快取（Redis）。
+// key 為命名空間 + provider + 正規化 payload（含 model）的雜湊，命名空間預設為 API Key ID，
+// 不同租戶不會共用回應；Redis 異常時視為未命中，不影響主流程。
+type ResponseCacheService struct {
+	trace     *telemetry.Trace
+	logger    *zap.Logger
+	config    *config.Configuration
+	cacheRepo *redisDb.ResponseCacheRepository
+}
+
+func NewResponseCacheService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	cacheRepo *redisDb.ResponseCacheRepository,
+) *ResponseCacheService {
+	return &ResponseCacheService{
+		trace:     trace,
+		logger:    logger,
+		config:    config,
+		cacheRepo: cacheRepo,
+	}
+}
+
+// Enabled 全域開關與 API Key 設定皆啟用
+func (s *ResponseCacheService) Enabled(providerAccess *model.ProviderAccess) bool {
+	return s.config.ResponseCache.Enabled && providerAccess != nil &&
+		providerAccess.Cache != nil && providerAccess.Cache.Enabled
+}
+
+// ChatKey 只有確定性的 chat 請求（temperature=0、單一結果、非串流）可快取
+func (s *ResponseCacheService) ChatKey(
+	apiKeyID string,
+	provider core.ProviderName,
+	providerAccess *model.ProviderAccess,
+	payload *chat.ChatPayload,
+) (string, bool) {
+	key, ok := deterministicChatKey(provider, payload)
+	if !ok {
+		return "", false
+	}
+	return namespacedCacheKey(responseCacheNamespace(apiKeyID, providerAccess), key), true
+}
+
+// EmbeddingKey embedding 請求皆為確定性
+func (s *ResponseCacheService) EmbeddingKey(
+	apiKeyID string,
+	provider core.ProviderName,
+	providerAccess *model.ProviderAccess,
+	payload *embedding.EmbeddingRequestBody,
+) (string, bool) {
+	key, ok := embeddingRequestKey(provider, payload)
+	if !ok {
+		return "", false
+	}
+	return namespacedCacheKey(responseCacheNamespace(apiKeyID, providerAccess), key), true
+}
+
+// Get 讀取快取並解碼至 out；未命中或錯誤回傳 false
+func (s *ResponseCacheService) Get(ctx context.Context, key string, out interface{}) bool {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	value, err := s.cacheRepo.Get(ctx, key)
+	if err != nil {
+		s.logger.Warn("response cache get failed", zap.Error(err))
+		return false
+	}
+	if value == nil {
+		return false
+	}
+	if err := json.Unmarshal(value, out); err != nil {
+		s.logger.Warn("response cache decode failed", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// Set 寫入快取（TTL 依 API Key 設定）；超過單筆上限不寫入
+func (s *ResponseCacheService) Set(ctx context.Context, providerAccess *model.ProviderAccess, key string, value interface{}) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	maxBytes := s.config.ResponseCache.MaxEntryBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultResponseCacheMaxBytes
+	}
+	if len(encoded) > maxBytes {
+		return
+	}
+	if err := s.cacheRepo.Set(ctx, key, encoded, s.ttl(providerAccess)); err != nil {
+		s.logger.Warn("response cache set failed", zap.Error(err))
+	}
+}
+
+func (s *ResponseCacheService) ttl(providerAccess *model.ProviderAccess) time.Duration {
+	if providerAccess != nil && providerAccess.Cache != nil && providerAccess.Cache.TTLSeconds != nil && *providerAccess.Cache.TTLSeconds > 0 {
+		return time.Duration(*providerAccess.Cache.TTLSeconds) * time.Second
+	}
+	seconds := s.config.ResponseCache.DefaultTTLSeconds
+	if seconds <= 0 {
+		seconds = defaultResponseCacheTTLSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// CacheBypass 呼叫端帶 Cache-Control: no-cache / no-store 時略過快取讀取
+func CacheBypass(cacheControl string) bool {
+	cacheControl = strings.ToLower(cacheControl)
+	return strings.Contains(cacheControl, "no-cache") || strings.Contains(cacheControl, "no-store")
+}
+
+// deterministicChatKey 確定性 chat 請求的雜湊 key（回應快取與請求合併共用）；非確定性請求回傳 false
+func deterministicChatKey(provider core.ProviderName, payload *chat.ChatPayload) (string, bool) {
+	if payload.Temperature == nil || *payload.Temperature != 0 {
+		return "", false
+	}
+	if (payload.N != nil && *payload.N > 1) || (payload.Stream != nil && *payload.Stream) {
+		return "", false
+	}
+	// 去除不影響輸出的欄位
+	normalized := *payload
+	normalized.User = nil
+	normalized.Metadata = nil
+	normalized.SafetyIDentifier = nil
+	normalized.Store = nil
+	normalized.PromptCacheKey = nil
+	normalized.ServiceTier = nil
+	normalized.Stream = nil
+	normalized.StreamOptions = nil
+	normalized.Extensions = nil
+	return cacheKey("chat", provider, normalized)
+}
+
+// embeddingRequestKey embedding 請求的雜湊 key（忽略 user）
+func embeddingRequestKey(provider core.ProviderName, payload *embedding.EmbeddingRequestBody) (string, bool) {
+	normalized := *payload
+	normalized.User = ""
+	return cacheKey("embedding", provider, normalized)
+}
+
+// responseCacheNamespace 管理者設定的命名空間，未設定時為 API Key ID
+func responseCacheNamespace(apiKeyID string, providerAccess *model.ProviderAccess) string {
+	if providerAccess != nil && providerAccess.Cache != nil && providerAccess.Cache.Namespace != "" {
+		return providerAccess.Cache.Namespace
+	}
+	return apiKeyID
+}
+
+// namespacedCacheKey 將命名空間併入雜湊（key 本身不露出命名空間）
+func namespacedCacheKey(namespace string, key string) string {
+	sum := sha256.Sum256([]byte(namespace + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheKey 以 JSON 序列化（struct 欄位順序固定、map key 排序）後雜湊
+func cacheKey(kind string, provider core.ProviderName, normalized interface{}) (string, bool) {
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(encoded)
+	return kind + ":" + string(provider) + ":" + hex.EncodeToString(sum[:]), true
+}
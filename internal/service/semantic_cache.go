@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	"interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/service/chat"
+	"interchange/internal/service/embedding"
+	"interchange/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSemanticEmbeddingModel  = "text-embedding-3-small"
+	defaultSemanticThreshold       = 0.95
+	defaultSemanticTTLSeconds      = 86400
+	defaultSemanticMaxEntries      = 10000
+	defaultSemanticRefreshSeconds  = 60
+	defaultSemanticMaxPromptLength = 8000
+	defaultSemanticIndexMemoryMB   = 512
+	semanticEntryOverheadBytes     = 256 // 每筆項目除向量與字串外的估計額外開銷
+)
+
+// SemanticQuery 一次查詢的內容；未命中時交給 Store 寫入，避免重複計算 embedding
+type SemanticQuery struct {
+	Namespace   string
+	Model       string
+	ContextHash string
+	Prompt      string
+	Embedding   []float32
+	ttl         time.Duration
+}
+
+// semanticIndex 單一命名空間的記憶體索引（暴力搜尋 cosine；向量已正規化，內積即相似度）
+type semanticIndex struct {
+	entries  []*model.SemanticCacheEntry
+	loadedAt time.Time
+	usedAt   time.Time // 最後使用時間，超過記憶體上限時依此淘汰
+	bytes    int64     // entries 的估計記憶體用量
+	hits     int64
+	misses   int64
+}
+
+// SemanticCacheService chat 語意快取：以最後一則 user 訊息的 embedding 比對同命名空間、同模型、同上下文的歷史回應。
+// 項目持久化於 Mongo（TTL index 自動過期），查詢使用各 replica 的記憶體索引並定期重新載入；
+// 索引總用量超過 MaxIndexMemoryMB 時以 LRU 整個淘汰命名空間（下次查詢再從 Mongo 載入）。
+type SemanticCacheService struct {
+	trace     *telemetry.Trace
+	logger    *zap.Logger
+	config    *config.Configuration
+	metric    *telemetry.Metric
+	registry  *Registry
+	cacheRepo *repository.SemanticCacheRepository
+
+	mu         sync.RWMutex
+	indexes    map[string]*semanticIndex
+	indexBytes int64 // 所有命名空間索引的估計記憶體用量
+}
+
+func NewSemanticCacheService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	metric *telemetry.Metric,
+	registry *Registry,
+	cacheRepo *repository.SemanticCacheRepository,
+) *SemanticCacheService {
+	return &SemanticCacheService{
+		trace:     trace,
+		logger:    logger,
+		config:    config,
+		metric:    metric,
+		registry:  registry,
+		cacheRepo: cacheRepo,
+		indexes:   make(map[string]*semanticIndex),
+	}
+}
+
+// Enabled 全域開關與 API Key 設定皆啟用
+func (s *SemanticCacheService) Enabled(providerAccess *model.ProviderAccess) bool {
+	return s.config.SemanticCache.Enabled && providerAccess != nil &&
+		providerAccess.Semantic != nil && providerAccess.Semantic.Enabled
+}
+
+// Lookup 查詢語意快取。命中時回傳快取結果；未命中時回傳可交給 Store 的 query（不適用或 embedding 失敗時兩者皆為 nil）。
+// 快取異常一律視為未命中，不影響主流程。
+func (s *SemanticCacheService) Lookup(
+	ctx context.Context,
+	apiKeyID string,
+	provider core.ProviderName,
+	providerAccess *model.ProviderAccess,
+	payload *chat.ChatPayload,
+) (*chat.ChatResult, *SemanticQuery) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if !s.Enabled(providerAccess) {
+		return nil, nil
+	}
+	prompt, contextHash, ok := semanticPrompt(provider, payload)
+	if !ok {
+		return nil, nil
+	}
+	namespace := providerAccess.Semantic.Namespace
+	if namespace == "" {
+		namespace = apiKeyID
+	}
+	span.SetAttributes(attribute.String("semantic_cache.namespace", namespace))
+
+	vector, err := s.embed(ctx, provider, providerAccess, prompt)
+	if err != nil {
+		s.logger.Warn("semantic cache embedding failed", zap.String("namespace", namespace), zap.Error(err))
+		s.record(provider, "error")
+		return nil, nil
+	}
+	query := &SemanticQuery{
+		Namespace:   namespace,
+		Model:       payload.Model,
+		ContextHash: contextHash,
+		Prompt:      prompt,
+		Embedding:   vector,
+		ttl:         s.ttl(),
+	}
+
+	index, err := s.index(ctx, namespace)
+	if err != nil {
+		s.logger.Warn("semantic cache load failed", zap.String("namespace", namespace), zap.Error(err))
+		s.record(provider, "error")
+		return nil, query
+	}
+
+	threshold := s.threshold(providerAccess)
+	now := time.Now().UTC()
+	s.mu.RLock()
+	var (
+		best       *model.SemanticCacheEntry
+		similarity float64
+	)
+	for _, entry := range index.entries {
+		if entry.Model != query.Model || entry.ContextHash != query.ContextHash || !entry.ExpiresAt.After(now) {
+			continue
+		}
+		if score := dot(entry.Embedding, vector); score >= threshold && score > similarity {
+			best, similarity = entry, score
+		}
+	}
+	s.mu.RUnlock()
+
+	if best == nil {
+		s.count(index, false)
+		s.record(provider, "miss")
+		return nil, query
+	}
+	var result chat.ChatResult
+	if err := json.Unmarshal([]byte(best.Result), &result); err != nil {
+		s.logger.Warn("semantic cache decode failed", zap.String("namespace", namespace), zap.Error(err))
+		s.record(provider, "error")
+		return nil, query
+	}
+	s.count(index, true)
+	s.record(provider, "hit")
+	span.SetAttributes(attribute.Float64("semantic_cache.similarity", similarity))
+	return &result, nil
+}
+
+// Store 寫入上游回應（Mongo + 本機索引）；超過命名空間上限時刪除最舊項目
+func (s *SemanticCacheService) Store(ctx context.Context, query *SemanticQuery, result *chat.ChatResult) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if query == nil || result == nil {
+		return
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	entry := &model.SemanticCacheEntry{
+		Namespace:   query.Namespace,
+		Model:       query.Model,
+		ContextHash: query.ContextHash,
+		Prompt:      query.Prompt,
+		Embedding:   query.Embedding,
+		Result:      string(encoded),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(query.ttl),
+	}
+	if err := s.cacheRepo.Insert(ctx, entry); err != nil {
+		s.logger.Warn("semantic cache store failed", zap.String("namespace", query.Namespace), zap.Error(err))
+		return
+	}
+
+	maxEntries := s.maxEntries()
+	trim := false
+	s.mu.Lock()
+	if index, ok := s.indexes[query.Namespace]; ok {
+		entries := append([]*model.SemanticCacheEntry{entry}, index.entries...)
+		if len(entries) > maxEntries {
+			entries = entries[:maxEntries]
+			trim = true
+		}
+		s.replaceEntries(index, entries)
+		index.usedAt = time.Now()
+		s.evict(query.Namespace)
+	}
+	s.mu.Unlock()
+
+	if trim {
+		if err := s.cacheRepo.TrimNamespace(ctx, query.Namespace, int64(maxEntries)); err != nil {
+			s.logger.Warn("semantic cache trim failed", zap.String("namespace", query.Namespace), zap.Error(err))
+		}
+	}
+}
+
+// Invalidate 清除命名空間（namespace 為空字串時清除全部）；其他 replica 於下次重新載入時同步
+func (s *SemanticCacheService) Invalidate(ctx context.Context, namespace string) (int64, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	deleted, err := s.cacheRepo.DeleteByNamespace(ctx, namespace)
+	if err != nil {
+		end(err)
+		return 0, cErr.DatabaseError("database DeleteSemanticCache error")
+	}
+	s.mu.Lock()
+	if namespace == "" {
+		s.indexes = make(map[string]*semanticIndex)
+		s.indexBytes = 0
+	} else {
+		s.dropIndex(namespace)
+	}
+	s.mu.Unlock()
+	return deleted, nil
+}
+
+// Stats 各命名空間項目數（Mongo）與本 replica 的命中率
+func (s *SemanticCacheService) Stats(ctx context.Context) ([]*dto.SemanticCacheStatsDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	counts, err := s.cacheRepo.CountByNamespace(ctx)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("database CountSemanticCache error")
+	}
+	stats := make(map[string]*dto.SemanticCacheStatsDto, len(counts))
+	for _, count := range counts {
+		stats[count.Namespace] = &dto.SemanticCacheStatsDto{Namespace: count.Namespace, Entries: count.Count}
+	}
+	s.mu.RLock()
+	for namespace, index := range s.indexes {
+		stat, ok := stats[namespace]
+		if !ok {
+			stat = &dto.SemanticCacheStatsDto{Namespace: namespace}
+			stats[namespace] = stat
+		}
+		stat.Hits, stat.Misses = index.hits, index.misses
+		if total := index.hits + index.misses; total > 0 {
+			stat.HitRate = float64(index.hits) / float64(total)
+		}
+	}
+	s.mu.RUnlock()
+
+	resp := make([]*dto.SemanticCacheStatsDto, 0, len(stats))
+	for _, stat := range stats {
+		resp = append(resp, stat)
+	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Namespace < resp[j].Namespace })
+	return resp, nil
+}
+
+// index 取得命名空間索引；不存在或超過重新載入間隔時從 Mongo 載入（保留命中統計）
+func (s *SemanticCacheService) index(ctx context.Context, namespace string) (*semanticIndex, error) {
+	s.mu.RLock()
+	index, ok := s.indexes[namespace]
+	fresh := ok && time.Since(index.loadedAt) < s.refreshInterval()
+	s.mu.RUnlock()
+	if fresh {
+		return index, nil
+	}
+
+	entries, err := s.cacheRepo.ListByNamespace(ctx, namespace, int64(s.maxEntries()))
+	if err != nil {
+		if ok {
+			return index, err
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, ok = s.indexes[namespace]
+	if !ok {
+		index = &semanticIndex{}
+		s.indexes[namespace] = index
+	}
+	s.replaceEntries(index, entries)
+	index.loadedAt = time.Now()
+	index.usedAt = index.loadedAt
+	s.evict(namespace)
+	return index, nil
+}
+
+// replaceEntries 更新索引內容與記憶體用量（呼叫端需持有寫鎖）
+func (s *SemanticCacheService) replaceEntries(index *semanticIndex, entries []*model.SemanticCacheEntry) {
+	var bytes int64
+	for _, entry := range entries {
+		bytes += semanticEntryBytes(entry)
+	}
+	s.indexBytes += bytes - index.bytes
+	index.entries = entries
+	index.bytes = bytes
+}
+
+// dropIndex 移除命名空間索引（呼叫端需持有寫鎖）
+func (s *SemanticCacheService) dropIndex(namespace string) {
+	if index, ok := s.indexes[namespace]; ok {
+		s.indexBytes -= index.bytes
+		delete(s.indexes, namespace)
+	}
+}
+
+// evict 總用量超過上限時依最後使用時間淘汰整個命名空間，保留正在使用的 keep（呼叫端需持有寫鎖）
+func (s *SemanticCacheService) evict(keep string) {
+	limit := s.maxIndexBytes()
+	for s.indexBytes > limit {
+		oldest := ""
+		var oldestAt time.Time
+		for namespace, index := range s.indexes {
+			if namespace == keep {
+				continue
+			}
+			if oldest == "" || index.usedAt.Before(oldestAt) {
+				oldest, oldestAt = namespace, index.usedAt
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		s.dropIndex(oldest)
+		s.logger.Debug("semantic cache namespace evicted", zap.String("namespace", oldest))
+	}
+}
+
+// embed 以設定的 embedding provider 計算正規化向量
+func (s *SemanticCacheService) embed(ctx context.Context, provider core.ProviderName, providerAccess *model.ProviderAccess, prompt string) ([]float32, error) {
+	embeddingProvider := core.ProviderName(s.config.SemanticCache.EmbeddingProvider)
+	if embeddingProvider == "" {
+		embeddingProvider = core.ProviderOpenAI
+	}
+	apiKey := s.config.SemanticCache.EmbeddingAPIKey
+	if apiKey == "" {
+		// 未設定專用金鑰時只能借用同一 provider 的 key
+		if embeddingProvider != provider {
+			return nil, cErr.ServiceUnavailable("semantic cache embedding api key is not configured")
+		}
+		apiKey = providerAccess.ProviderKey
+	}
+	embeddingService, ok := s.registry.GetEmbedding(embeddingProvider)
+	if !ok {
+		return nil, cErr.ServiceUnavailable("embedding provider not available: " + string(embeddingProvider))
+	}
+	embeddingModel := s.config.SemanticCache.EmbeddingModel
+	if embeddingModel == "" {
+		embeddingModel = defaultSemanticEmbeddingModel
+	}
+
+	resp, err := embeddingService.GenerateEmbedding(ctx, &embedding.EmbeddingRequestBody{Input: prompt, Model: embeddingModel}, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 || len(resp.Data[0].Embedding) == 0 {
+		return nil, cErr.ExternalRequestError("empty embedding response")
+	}
+	return normalize(resp.Data[0].Embedding), nil
+}
+
+func (s *SemanticCacheService) count(index *semanticIndex, hit bool) {
+	s.mu.Lock()
+	index.usedAt = time.Now()
+	if hit {
+		index.hits++
+	} else {
+		index.misses++
+	}
+	s.mu.Unlock()
+}
+
+func (s *SemanticCacheService) record(provider core.ProviderName, outcome string) {
+	if s.metric == nil || s.metric.SemanticCacheLookup == nil {
+		return
+	}
+	s.metric.SemanticCacheLookup.WithLabelValues(string(provider), outcome).Inc()
+}
+
+func (s *SemanticCacheService) threshold(providerAccess *model.ProviderAccess) float64 {
+	if providerAccess.Semantic.Threshold != nil && *providerAccess.Semantic.Threshold > 0 {
+		return *providerAccess.Semantic.Threshold
+	}
+	if s.config.SemanticCache.DefaultThreshold > 0 {
+		return s.config.SemanticCache.DefaultThreshold
+	}
+	return defaultSemanticThreshold
+}
+
+func (s *SemanticCacheService) ttl() time.Duration {
+	seconds := s.config.SemanticCache.TTLSeconds
+	if seconds <= 0 {
+		seconds = defaultSemanticTTLSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (s *SemanticCacheService) maxEntries() int {
+	if s.config.SemanticCache.MaxEntriesPerNamespace > 0 {
+		return s.config.SemanticCache.MaxEntriesPerNamespace
+	}
+	return defaultSemanticMaxEntries
+}
+
+func (s *SemanticCacheService) maxIndexBytes() int64 {
+	megabytes := s.config.SemanticCache.MaxIndexMemoryMB
+	if megabytes <= 0 {
+		megabytes = defaultSemanticIndexMemoryMB
+	}
+	return int64(megabytes) << 20
+}
+
+func (s *SemanticCacheService) refreshInterval() time.Duration {
+	seconds := s.config.SemanticCache.RefreshSeconds
+	if seconds <= 0 {
+		seconds = defaultSemanticRefreshSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// semanticPrompt 取出最後一則 user 純文字訊息，其餘影響輸出的內容（provider、模型、先前訊息、工具、輸出格式）做成上下文雜湊。
+// 串流、多筆結果或最後一則非 user 文字訊息時不適用。
+func semanticPrompt(provider core.ProviderName, payload *chat.ChatPayload) (string, string, bool) {
+	if (payload.N != nil && *payload.N > 1) || (payload.Stream != nil && *payload.Stream) || len(payload.Messages) == 0 {
+		return "", "", false
+	}
+	last := payload.Messages[len(payload.Messages)-1]
+	prompt, ok := last.Content.(string)
+	prompt = strings.TrimSpace(prompt)
+	if !ok || last.Role != "user" || prompt == "" || len(prompt) > defaultSemanticMaxPromptLength {
+		return "", "", false
+	}
+
+	encoded, err := json.Marshal(struct {
+		Provider       core.ProviderName      `json:"provider"`
+		Model          string                 `json:"model"`
+		Messages       []chat.ChatMessage     `json:"messages"`
+		Tools          interface{}            `json:"tools,omitempty"`
+		ToolChoice     interface{}            `json:"tool_choice,omitempty"`
+		Functions      interface{}            `json:"functions,omitempty"`
+		ResponseFormat map[string]interface{} `json:"response_format,omitempty"`
+	}{provider, payload.Model, payload.Messages[:len(payload.Messages)-1], payload.Tools, payload.ToolChoice, payload.Functions, payload.ResponseFormat})
+	if err != nil {
+		return "", "", false
+	}
+	sum := sha256.Sum256(encoded)
+	return prompt, hex.EncodeToString(sum[:]), true
+}
+
+// semanticEntryBytes 項目在記憶體索引中的估計大小（float32 向量 + 字串 + 固定開銷）
+func semanticEntryBytes(entry *model.SemanticCacheEntry) int64 {
+	return int64(len(entry.Embedding)*4+len(entry.Prompt)+len(entry.Result)+len(entry.Model)+len(entry.ContextHash)+len(entry.Namespace)) +
+		semanticEntryOverheadBytes
+}
+
+// normalize 轉為單位向量（float32 以節省記憶體與儲存空間）
+func normalize(vector []float64) []float32 {
+	var norm float64
+	for _, value := range vector {
+		norm += value * value
+	}
+	norm = math.Sqrt(norm)
+	normalized := make([]float32, len(vector))
+	if norm == 0 {
+		return normalized
+	}
+	for i, value := range vector {
+		normalized[i] = float32(value / norm)
+	}
+	return normalized
+}
+
+// dot 向量內積；維度不同（更換 embedding 模型）時視為不相似
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
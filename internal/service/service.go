@@ -1,6 +1,7 @@
 package service
 
 import (
+	"interchange/config"
 	"interchange/internal/core"
 	"interchange/internal/service/audio"
 	"interchange/internal/service/chat"
@@ -15,6 +16,28 @@ import (
 var ProviderSet = wire.NewSet(
 	NewUserService,
 	NewUserAPIKeyService,
+	NewOrganizationService,
+	NewQuotaService,
+	NewConcurrencyService,
+	NewUpstreamQueueService,
+	NewUpstreamRetryService,
+	NewCircuitBreakerService,
+	NewPromptTemplateService,
+	NewResponseCacheService,
+	NewSemanticCacheService,
+	NewCoalescingService,
+	NewEntityCacheService,
+	NewActivityBufferService,
+	NewUsageService,
+	NewCreditService,
+	NewInvoiceService,
+	NewNotificationService,
+	NewJobService,
+	NewAuditService,
+	NewLifecycleService,
+	NewKeyRequestService,
+	NewMaintenanceService,
+	NewAnomalyService,
 	chat.NewOpenAIService,
 	images.NewOpenAIService,
 	audio.NewOpenAIService,
@@ -26,6 +49,7 @@ var ProviderSet = wire.NewSet(
 
 // ProvideRegistryWithServices
 func ProvideRegistryWithServices(
+	config *config.Configuration,
 	openAIChat chat.Service,
 	openAIImages images.Service,
 	openAIAudio audio.Service,
@@ -33,6 +57,7 @@ func ProvideRegistryWithServices(
 	openAIModels models.Service,
 ) *Registry {
 	reg := &Registry{
+		config:           config,
 		ChatServices:     make(map[core.ProviderName]chat.Service),
 		ImagesServices:   make(map[core.ProviderName]images.Service),
 		AudioServices:    make(map[core.ProviderName]audio.Service),
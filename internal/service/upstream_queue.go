@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/database/mongodb/model"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUpstreamQueueMaxWaitMs = 30000
+	defaultUpstreamPauseMs        = 1000
+	// 各 API Key 宣告的上游並行上限在此時間內未再出現即不列入計算
+	upstreamCapacityDeclarationTTL = 10 * time.Minute
+)
+
+// UpstreamQueueService 每個上游 provider key 一條本機排隊佇列。
+// 上游飽和（達並行上限或回 429 暫停中）時請求進入佇列：
+// 高優先層級一律先出列，同層級內以加權公平排隊（WFQ）分配給各 API Key。
+type UpstreamQueueService struct {
+	trace  *telemetry.Trace
+	metric *telemetry.Metric
+	logger *zap.Logger
+	config *config.Configuration
+
+	mu     sync.Mutex
+	queues map[string]*upstreamQueue
+}
+
+func NewUpstreamQueueService(
+	trace *telemetry.Trace,
+	metric *telemetry.Metric,
+	logger *zap.Logger,
+	config *config.Configuration,
+) *UpstreamQueueService {
+	return &UpstreamQueueService{
+		trace:  trace,
+		metric: metric,
+		logger: logger,
+		config: config,
+		queues: make(map[string]*upstreamQueue),
+	}
+}
+
+// upstreamQueue 單一上游 key 的佇列狀態
+type upstreamQueue struct {
+	mu          sync.Mutex
+	capacity    int // 本機並行上限（0 代表不限）；取各 API Key 宣告值中最小者
+	declared    map[string]capacityDeclaration
+	inFlight    int
+	pausedUntil time.Time
+	pauseTimer  *time.Timer
+	virtualTime float64
+	lastFinish  map[string]float64 // 各 API Key 最後一筆的虛擬完成時間
+	sequence    uint64
+	waiters     []*queueWaiter
+}
+
+// capacityDeclaration 單一 API Key 宣告的上游並行上限
+type capacityDeclaration struct {
+	limit  int
+	seenAt time.Time
+}
+
+// queueWaiter 排隊中的請求
+type queueWaiter struct {
+	apiKeyID string
+	provider string
+	tier     core.PriorityTier
+	finish   float64
+	sequence uint64
+	ready    chan struct{}
+}
+
+// Wait 依 ProviderAccess 進入對應上游 key 的佇列，輪到時回傳 release（請求結束時呼叫）。
+// 未啟用或未綁定 provider key 時直接放行。
+func (s *UpstreamQueueService) Wait(ctx context.Context, apiKeyID string, providerAccess *model.ProviderAccess) (func(), error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	noop := func() {}
+	if !s.config.Concurrency.Queue.Enabled || providerAccess == nil || providerAccess.ProviderKey == "" {
+		return noop, nil
+	}
+
+	upstreamID := upstreamKeyID(providerAccess.ProviderKey)
+	tier, weight, capacity := core.PriorityTierStandard, 1, 0
+	if policy := providerAccess.Concurrency; policy != nil {
+		if policy.Priority != "" {
+			tier = policy.Priority
+		}
+		if policy.Weight != nil && *policy.Weight > 0 {
+			weight = *policy.Weight
+		}
+		if policy.UpstreamMaxInFlight != nil && *policy.UpstreamMaxInFlight > 0 {
+			capacity = *policy.UpstreamMaxInFlight
+		}
+	}
+	provider := string(providerAccess.Provider)
+	span.SetAttributes(
+		attribute.String("queue.upstream", upstreamID),
+		attribute.String("queue.tier", string(tier)),
+		attribute.Int("queue.weight", weight),
+	)
+
+	queue := s.queue(upstreamID)
+	startedAt := time.Now()
+
+	queue.mu.Lock()
+	queue.declare(apiKeyID, capacity, startedAt)
+	if len(queue.waiters) == 0 && queue.available(startedAt) {
+		queue.inFlight++
+		queue.mu.Unlock()
+		s.observeWait(provider, tier, "immediate", 0)
+		return s.releaser(upstreamID, queue), nil
+	}
+	if maxDepth := s.config.Concurrency.Queue.MaxDepth; maxDepth > 0 && len(queue.waiters) >= maxDepth {
+		queue.mu.Unlock()
+		s.observeWait(provider, tier, "rejected", 0)
+		cause := cErr.RateLimitExceeded("upstream queue is full")
+		end(cause)
+		return noop, cause
+	}
+	waiter := queue.enqueue(apiKeyID, provider, tier, weight)
+	// 上限調整後可能已有空位，立即嘗試出列
+	s.dispatch(upstreamID, queue)
+	queue.mu.Unlock()
+
+	timer := time.NewTimer(s.maxWait())
+	defer timer.Stop()
+
+	var cause error
+	select {
+	case <-waiter.ready:
+		s.observeWait(provider, tier, "dispatched", time.Since(startedAt))
+		return s.releaser(upstreamID, queue), nil
+	case <-timer.C:
+		cause = cErr.RateLimitExceeded("upstream saturated: queue wait exceeded")
+	case <-ctx.Done():
+		cause = cErr.GatewayTimeout("request cancelled while waiting in upstream queue")
+	}
+
+	// 逾時與出列可能同時發生：已出列則視為取得
+	queue.mu.Lock()
+	if !queue.remove(waiter) {
+		queue.mu.Unlock()
+		s.observeWait(provider, tier, "dispatched", time.Since(startedAt))
+		return s.releaser(upstreamID, queue), nil
+	}
+	s.setDepth(upstreamID, queue.waiters)
+	queue.mu.Unlock()
+
+	s.observeWait(provider, tier, "timeout", time.Since(startedAt))
+	end(cause)
+	return noop, cause
+}
+
+// Pause 上游回 429 時暫停該上游 key 的出列，直到 retryAfter 後再依序放行
+func (s *UpstreamQueueService) Pause(upstreamID string, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		ms := s.config.Concurrency.Queue.DefaultPauseMs
+		if ms <= 0 {
+			ms = defaultUpstreamPauseMs
+		}
+		retryAfter = time.Duration(ms) * time.Millisecond
+	}
+	if s.metric.UpstreamThrottled != nil {
+		s.metric.UpstreamThrottled.WithLabelValues(upstreamID).Inc()
+	}
+
+	queue := s.queue(upstreamID)
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	until := time.Now().Add(retryAfter)
+	if !until.After(queue.pausedUntil) {
+		return
+	}
+	queue.pausedUntil = until
+	if queue.pauseTimer != nil {
+		queue.pauseTimer.Stop()
+	}
+	queue.pauseTimer = time.AfterFunc(retryAfter, func() {
+		queue.mu.Lock()
+		defer queue.mu.Unlock()
+		s.dispatch(upstreamID, queue)
+	})
+	s.logger.Warn("upstream throttled, queue paused",
+		zap.String("upstream", upstreamID),
+		zap.Duration("retryAfter", retryAfter),
+	)
+}
+
+// Transport 包裝 RoundTripper：觀察上游 429 回應並依 Retry-After 暫停對應佇列
+func (s *UpstreamQueueService) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		if providerKey := requestProviderKey(req.Header); providerKey != "" {
+			s.Pause(upstreamKeyID(providerKey), parseRetryAfter(resp.Header))
+		}
+		return resp, err
+	})
+}
+
+// queue 取得（或建立）上游 key 的佇列
+func (s *UpstreamQueueService) queue(upstreamID string) *upstreamQueue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queue, ok := s.queues[upstreamID]
+	if !ok {
+		queue = &upstreamQueue{
+			lastFinish: make(map[string]float64),
+			declared:   make(map[string]capacityDeclaration),
+		}
+		s.queues[upstreamID] = queue
+	}
+	return queue
+}
+
+// releaser 回傳只會執行一次的 release：釋放並行數並喚醒下一位
+func (s *UpstreamQueueService) releaser(upstreamID string, queue *upstreamQueue) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			queue.mu.Lock()
+			defer queue.mu.Unlock()
+			if queue.inFlight > 0 {
+				queue.inFlight--
+			}
+			s.dispatch(upstreamID, queue)
+		})
+	}
+}
+
+// dispatch 在可用時依序喚醒排隊者（需持有 queue.mu）
+func (s *UpstreamQueueService) dispatch(upstreamID string, queue *upstreamQueue) {
+	now := time.Now()
+	for len(queue.waiters) > 0 && queue.available(now) {
+		waiter := queue.next()
+		queue.remove(waiter)
+		queue.virtualTime = waiter.finish
+		queue.inFlight++
+		close(waiter.ready)
+	}
+	if len(queue.waiters) == 0 {
+		// 佇列清空時重置虛擬時間，避免閒置的 API Key 累積優勢
+		queue.virtualTime = 0
+		queue.lastFinish = make(map[string]float64)
+	}
+	s.setDepth(upstreamID, queue.waiters)
+}
+
+func (s *UpstreamQueueService) maxWait() time.Duration {
+	ms := s.config.Concurrency.Queue.MaxWaitMs
+	if ms <= 0 {
+		ms = defaultUpstreamQueueMaxWaitMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (s *UpstreamQueueService) observeWait(provider string, tier core.PriorityTier, outcome string, waited time.Duration) {
+	if s.metric.UpstreamQueueWait == nil {
+		return
+	}
+	s.metric.UpstreamQueueWait.WithLabelValues(provider, string(tier), outcome).Observe(waited.Seconds())
+}
+
+// setDepth 依層級回報目前排隊數（需持有 queue.mu）
+func (s *UpstreamQueueService) setDepth(upstreamID string, waiters []*queueWaiter) {
+	if s.metric.UpstreamQueueDepth == nil {
+		return
+	}
+	provider := ""
+	depth := map[core.PriorityTier]int{
+		core.PriorityTierProduction: 0,
+		core.PriorityTierStandard:   0,
+		core.PriorityTierBatch:      0,
+	}
+	for _, waiter := range waiters {
+		provider = waiter.provider
+		depth[waiter.tier]++
+	}
+	if provider == "" {
+		// 佇列已清空：歸零所有曾出現過的 label
+		s.metric.UpstreamQueueDepth.DeletePartialMatch(map[string]string{string(core.MetricLabelUpstream): upstreamID})
+		return
+	}
+	for tier, count := range depth {
+		s.metric.UpstreamQueueDepth.WithLabelValues(provider, upstreamID, string(tier)).Set(float64(count))
+	}
+}
+
+// available 是否可立即放行（未暫停且未達並行上限）
+func (queue *upstreamQueue) available(now time.Time) bool {
+	if now.Before(queue.pausedUntil) {
+		return false
+	}
+	return queue.capacity <= 0 || queue.inFlight < queue.capacity
+}
+
+// declare 記錄 API Key 宣告的上游並行上限並重算 capacity（需持有 queue.mu）。
+// 共用同一上游 key 的 API Key 可能設定不同上限，取最嚴格者，避免彼此覆寫；
+// 過期的宣告（例如已刪除或改掉上限的 key）不再列入
+func (queue *upstreamQueue) declare(apiKeyID string, limit int, now time.Time) {
+	if limit > 0 {
+		queue.declared[apiKeyID] = capacityDeclaration{limit: limit, seenAt: now}
+	} else {
+		delete(queue.declared, apiKeyID)
+	}
+	capacity := 0
+	for id, declaration := range queue.declared {
+		if now.Sub(declaration.seenAt) > upstreamCapacityDeclarationTTL {
+			delete(queue.declared, id)
+			continue
+		}
+		if capacity == 0 || declaration.limit < capacity {
+			capacity = declaration.limit
+		}
+	}
+	queue.capacity = capacity
+}
+
+// enqueue 依 WFQ 計算虛擬完成時間後加入佇列
+func (queue *upstreamQueue) enqueue(apiKeyID, provider string, tier core.PriorityTier, weight int) *queueWaiter {
+	start := queue.virtualTime
+	if last, ok := queue.lastFinish[apiKeyID]; ok && last > start {
+		start = last
+	}
+	finish := start + 1/float64(weight)
+	queue.lastFinish[apiKeyID] = finish
+	queue.sequence++
+	waiter := &queueWaiter{
+		apiKeyID: apiKeyID,
+		provider: provider,
+		tier:     tier,
+		finish:   finish,
+		sequence: queue.sequence,
+		ready:    make(chan struct{}),
+	}
+	queue.waiters = append(queue.waiters, waiter)
+	return waiter
+}
+
+// next 取出下一位：優先層級高者優先，其次虛擬完成時間較早者，最後依到達順序
+func (queue *upstreamQueue) next() *queueWaiter {
+	var best *queueWaiter
+	for _, waiter := range queue.waiters {
+		if best == nil {
+			best = waiter
+			continue
+		}
+		if waiter.tier.Rank() != best.tier.Rank() {
+			if waiter.tier.Rank() > best.tier.Rank() {
+				best = waiter
+			}
+			continue
+		}
+		if waiter.finish < best.finish || (waiter.finish == best.finish && waiter.sequence < best.sequence) {
+			best = waiter
+		}
+	}
+	return best
+}
+
+// remove 自佇列移除；不在佇列中（已出列）回傳 false
+func (queue *upstreamQueue) remove(target *queueWaiter) bool {
+	for i, waiter := range queue.waiters {
+		if waiter == target {
+			queue.waiters = append(queue.waiters[:i], queue.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// roundTripperFunc 讓函式實作 http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// requestProviderKey 從上游請求標頭取出 provider key（Bearer / api-key / x-goog-api-key）
+func requestProviderKey(header http.Header) string {
+	if auth := header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	if key := header.Get("api-key"); key != "" {
+		return key
+	}
+	return header.Get("x-goog-api-key")
+}
+
+// parseRetryAfter 解析 retry-after-ms / Retry-After（秒數或 HTTP 日期）
+func parseRetryAfter(header http.Header) time.Duration {
+	if v := header.Get("retry-after-ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms > 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(v, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
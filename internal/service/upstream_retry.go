@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"interchange/config"
+	"interchange/internal/core"
+	"interchange/internal/pkg/outbound"
+	"interchange/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRetryInitialBackoffMs = 200
+	defaultRetryMaxBackoffMs     = 5000
+	defaultRetryMultiplier       = 2
+	defaultRetryJitter           = 0.2
+	defaultRetryMaxRetryAfterMs  = 30000
+	defaultRetryMaxBufferBytes   = 32 << 20
+)
+
+var defaultRetryOnStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// UpstreamRetryService 以 RoundTripper 形式為所有上游呼叫（typed service 與 ProxyService.Forward）提供重試。
+// 重試只發生在回應交還呼叫端之前，因此串流輸出開始後不會重試。
+type UpstreamRetryService struct {
+	trace  *telemetry.Trace
+	logger *zap.Logger
+	config *config.Configuration
+}
+
+func NewUpstreamRetryService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+) *UpstreamRetryService {
+	return &UpstreamRetryService{
+		trace:  trace,
+		logger: logger,
+		config: config,
+	}
+}
+
+// Transport 包裝 RoundTripper：依 provider / endpoint 策略重試，每次嘗試各自建立子 span
+func (s *UpstreamRetryService) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if !s.config.Retry.Enabled {
+			return next.RoundTrip(req)
+		}
+		provider := outbound.ProviderFromURL(s.config, req.URL.String())
+		policy := s.policy(provider, req.URL.Path)
+		if policy.MaxAttempts <= 1 {
+			return next.RoundTrip(req)
+		}
+
+		ctx := req.Context()
+		getBody, onceBody, err := s.rewindableBody(req)
+		if err != nil {
+			return nil, err
+		}
+		if getBody == nil {
+			// body 過大無法暫存：以複本只送一次（不修改呼叫端的 request）
+			onceReq := req.Clone(ctx)
+			onceReq.Body = onceBody
+			return next.RoundTrip(onceReq)
+		}
+
+		for attempt := 1; ; attempt++ {
+			// 每次嘗試使用獨立的 request 複本，呼叫端的 request 保持不變
+			attemptReq := req.Clone(ctx)
+			attemptReq.GetBody = getBody
+			if attempt > 1 || req.GetBody == nil {
+				body, err := getBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+
+			resp, err := s.attempt(next, attemptReq, provider, attempt)
+			wait, retry := s.shouldRetry(policy, attempt, resp, err)
+			if !retry {
+				return resp, err
+			}
+			if resp != nil {
+				// 丟棄本次回應，讓連線可重用
+				_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+				resp.Body.Close()
+			}
+			s.logger.Warn("retrying upstream request",
+				zap.String("provider", string(provider)),
+				zap.String("path", req.URL.Path),
+				zap.Int("attempt", attempt),
+				zap.Duration("backoff", wait),
+			)
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	})
+}
+
+// attempt 單次嘗試（子 span 記錄嘗試序號與結果）
+func (s *UpstreamRetryService) attempt(next http.RoundTripper, req *http.Request, provider core.ProviderName, attempt int) (*http.Response, error) {
+	ctx, span, end := s.trace.WithSpan(req.Context(), "upstream.attempt")
+	span.SetAttributes(
+		attribute.String("ai.provider", string(provider)),
+		attribute.String("http.method", req.Method),
+		attribute.String("http.path", req.URL.Path),
+		attribute.Int("retry.attempt", attempt),
+	)
+
+	resp, err := next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		end(fmt.Errorf("upstream status %d", resp.StatusCode))
+	} else {
+		end(nil)
+	}
+	return resp, nil
+}
+
+// shouldRetry 判斷是否重試並計算等待時間
+func (s *UpstreamRetryService) shouldRetry(policy config.RetryPolicy, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+	if err != nil {
+		if !policy.RetryOnConnectionError || errors.Is(err, ErrCircuitOpen) ||
+			errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		return backoffDuration(policy, attempt), true
+	}
+
+	retryable := false
+	for _, status := range policy.RetryOnStatus {
+		if resp.StatusCode == status {
+			retryable = true
+			break
+		}
+	}
+	if !retryable {
+		return 0, false
+	}
+
+	if policy.RespectRetryAfter {
+		if retryAfter := parseRetryAfter(resp.Header); retryAfter > 0 {
+			if retryAfter > time.Duration(policy.MaxRetryAfterMs)*time.Millisecond {
+				// 上游要求等待過久，直接回傳讓呼叫端處理
+				return 0, false
+			}
+			return retryAfter, true
+		}
+	}
+	return backoffDuration(policy, attempt), true
+}
+
+// rewindableBody 回傳可重複取得 body 的函式；body 超過暫存上限時 getBody 為 nil，
+// 改回傳只能送一次的 body（已讀部分接回原 body）。不修改傳入的 request
+func (s *UpstreamRetryService) rewindableBody(req *http.Request) (getBody func() (io.ReadCloser, error), onceBody io.ReadCloser, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return func() (io.ReadCloser, error) { return http.NoBody, nil }, nil, nil
+	}
+	if req.GetBody != nil {
+		return req.GetBody, nil, nil
+	}
+
+	limit := s.config.Retry.MaxBufferBytes
+	if limit <= 0 {
+		limit = defaultRetryMaxBufferBytes
+	}
+	if req.ContentLength > limit {
+		return nil, req.Body, nil
+	}
+	buffered, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil {
+		req.Body.Close()
+		return nil, nil, err
+	}
+	if int64(len(buffered)) > limit {
+		return nil, struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buffered), req.Body), req.Body}, nil
+	}
+	req.Body.Close()
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buffered)), nil
+	}, nil, nil
+}
+
+// policy 依 endpoint → provider → default 解析策略並補上預設值
+func (s *UpstreamRetryService) policy(provider core.ProviderName, path string) config.RetryPolicy {
+	policy := s.config.Retry.Default
+	if providerRetry, ok := s.config.Retry.Providers[string(provider)]; ok {
+		if providerRetry.Policy.MaxAttempts > 0 {
+			policy = providerRetry.Policy
+		}
+		matched := ""
+		for prefix, endpointPolicy := range providerRetry.Endpoints {
+			if endpointPolicy.MaxAttempts > 0 && strings.HasPrefix(path, prefix) && len(prefix) > len(matched) {
+				matched, policy = prefix, endpointPolicy
+			}
+		}
+	}
+
+	if policy.InitialBackoffMs <= 0 {
+		policy.InitialBackoffMs = defaultRetryInitialBackoffMs
+	}
+	if policy.MaxBackoffMs <= 0 {
+		policy.MaxBackoffMs = defaultRetryMaxBackoffMs
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = defaultRetryMultiplier
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		policy.Jitter = defaultRetryJitter
+	}
+	if len(policy.RetryOnStatus) == 0 {
+		policy.RetryOnStatus = defaultRetryOnStatus
+	}
+	if policy.MaxRetryAfterMs <= 0 {
+		policy.MaxRetryAfterMs = defaultRetryMaxRetryAfterMs
+	}
+	return policy
+}
+
+// backoffDuration 指數退避加抖動：initial * multiplier^(attempt-1)，上限 maxBackoff
+func backoffDuration(policy config.RetryPolicy, attempt int) time.Duration {
+	backoff := float64(policy.InitialBackoffMs) * math.Pow(policy.Multiplier, float64(attempt-1))
+	if backoff > float64(policy.MaxBackoffMs) {
+		backoff = float64(policy.MaxBackoffMs)
+	}
+	backoff *= 1 - policy.Jitter*rand.Float64()
+	return time.Duration(backoff) * time.Millisecond
+}
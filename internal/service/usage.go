@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"interchange/config"
+	fluentdModel "interchange/internal/database/fluentd/model"
+	fluentdDb "interchange/internal/database/fluentd/repository"
+	"interchange/internal/database/mongodb/model"
+	mongoDb "interchange/internal/database/mongodb/repository"
+	"interchange/internal/dto"
+	cErr "interchange/internal/pkg/error"
+	"interchange/internal/telemetry"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUsageMaxResultRows = 10000
+	defaultUsageRollupDays    = 2
+	usageLoggedAtLayout       = "2006-01-02 15:04:05.999999 UTC"
+)
+
+// UsageService 用量紀錄：同時送 Fluentd 與寫入 Mongo time-series、依計價扣預付額度，並提供管理端彙總查詢
+type UsageService struct {
+	trace         *telemetry.Trace
+	logger        *zap.Logger
+	config        *config.Configuration
+	logRepository *fluentdDb.LogRepository
+	usageRepo     *mongoDb.UsageRecordRepository
+	rollupRepo    *mongoDb.UsageRollupRepository
+	quotaService  *QuotaService
+	creditService *CreditService
+}
+
+func NewUsageService(
+	trace *telemetry.Trace,
+	logger *zap.Logger,
+	config *config.Configuration,
+	logRepository *fluentdDb.LogRepository,
+	usageRepo *mongoDb.UsageRecordRepository,
+	rollupRepo *mongoDb.UsageRollupRepository,
+	quotaService *QuotaService,
+	creditService *CreditService,
+) *UsageService {
+	return &UsageService{
+		trace:         trace,
+		logger:        logger,
+		config:        config,
+		logRepository: logRepository,
+		usageRepo:     usageRepo,
+		rollupRepo:    rollupRepo,
+		quotaService:  quotaService,
+		creditService: creditService,
+	}
+}
+
+// Record 記錄單次請求用量；回傳 Fluentd 的錯誤，Mongo 寫入與扣款失敗只記 log。
+// 命中快取的請求不呼叫上游，不扣預付額度。
+func (s *UsageService) Record(ctx context.Context, usage fluentdModel.AIUsageLog) error {
+	err := s.logRepository.LogUsage(ctx, usage)
+
+	inputTokens := usage.TokensPrompt + usage.InputToken
+	outputTokens := usage.TokensCompletion + usage.OutputToken
+	// 扣款冪等鍵：呼叫端帶 Idempotency-Key 時為 apiKeyID + 該值（重試不重複扣款），
+	// 否則為閘道產生的請求 ID（每次 HTTP 請求各自扣款）；trace ID 可由呼叫端指定，不可作為冪等鍵
+	if !usage.Cached && usage.UserID != "" {
+		requestID := usage.GatewayRequestID
+		if usage.IdempotencyKey != "" && usage.APIKeyID != "" {
+			requestID = "idem:" + usage.APIKeyID + ":" + usage.IdempotencyKey
+		} else if requestID == "" {
+			// 未經 APIKey middleware 的請求：每次呼叫視為獨立請求
+			requestID = uuid.NewString()
+		}
+		if debitErr := s.creditService.Debit(ctx, usage.UserID, requestID, usage.Model, inputTokens, outputTokens); debitErr != nil {
+			s.logger.Warn("credit debit failed", zap.String("requestID", requestID), zap.Error(debitErr))
+		}
+	}
+	if !s.config.UsageRecord.Enabled {
+		return err
+	}
+	record := toUsageRecord(usage)
+	if !usage.Cached {
+		record.Cost = s.quotaService.EstimateCost(usage.Model, inputTokens, outputTokens)
+	}
+	if insertErr := s.usageRepo.Insert(ctx, record); insertErr != nil {
+		s.logger.Warn("usage record insert failed", zap.String("requestID", usage.RequestID), zap.Error(insertErr))
+	}
+	return err
+}
+
+// Aggregate 依條件彙總用量
+func (s *UsageService) Aggregate(ctx context.Context, query *dto.UsageQueryDto) ([]*dto.UsageAggregateDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	filter := mongoDb.UsageRecordFilter{
+		UserID:      query.UserID,
+		APIKeyID:    query.APIKeyID,
+		ProjectName: query.Project,
+		Provider:    query.Provider,
+		Model:       query.Model,
+		Endpoint:    query.Endpoint,
+	}
+	var err error
+	if filter.From, err = parseUsageTime(query.From); err != nil {
+		return nil, cErr.BadRequestParams("invalid from: " + err.Error())
+	}
+	if filter.To, err = parseUsageTime(query.To); err != nil {
+		return nil, cErr.BadRequestParams("invalid to: " + err.Error())
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, cErr.BadRequestParams("from must be earlier than to")
+	}
+	for _, dimension := range query.GroupBy {
+		if _, ok := mongoDb.UsageGroupFields[dimension]; !ok {
+			return nil, cErr.BadRequestParams("unsupported groupBy: " + dimension)
+		}
+	}
+	switch query.Bucket {
+	case "", "hour", "day", "month":
+	default:
+		return nil, cErr.BadRequestParams("bucket must be one of hour, day, month")
+	}
+	if query.Timezone != "" {
+		if _, err := time.LoadLocation(query.Timezone); err != nil {
+			return nil, cErr.BadRequestParams("invalid timezone: " + query.Timezone)
+		}
+	}
+
+	limit := s.config.UsageRecord.MaxResultRows
+	if limit <= 0 {
+		limit = defaultUsageMaxResultRows
+	}
+	aggregates, err := s.usageRepo.Aggregate(ctx, filter, query.GroupBy, query.Bucket, query.Timezone, int64(limit))
+	if err != nil {
+		end(err)
+		s.logger.Error("usage aggregate failed", zap.Error(err))
+		return nil, cErr.DatabaseError("mongodb aggregate usage failed")
+	}
+
+	results := make([]*dto.UsageAggregateDto, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		results = append(results, &dto.UsageAggregateDto{
+			Bucket:           aggregate.Group.Bucket,
+			UserID:           aggregate.Group.UserID,
+			APIKeyID:         aggregate.Group.APIKeyID,
+			Project:          aggregate.Group.ProjectName,
+			Provider:         aggregate.Group.Provider,
+			Model:            aggregate.Group.Model,
+			Endpoint:         aggregate.Group.Endpoint,
+			Requests:         aggregate.Requests,
+			CachedRequests:   aggregate.CachedRequests,
+			TokensPrompt:     aggregate.TokensPrompt,
+			TokensCompletion: aggregate.TokensCompletion,
+			InputTokens:      aggregate.InputTokens,
+			OutputTokens:     aggregate.OutputTokens,
+			TokensTotal:      aggregate.TokensTotal,
+			Cost:             aggregate.Cost,
+		})
+	}
+	return results, nil
+}
+
+// Rollup 以用量紀錄重算最近 days 天（含今天，UTC）的每日彙總並覆寫，回傳寫入筆數；未啟用 USAGE_RECORD 時不處理
+func (s *UsageService) Rollup(ctx context.Context, days int) (int64, error) {
+	if !s.config.UsageRecord.Enabled {
+		return 0, nil
+	}
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	if days <= 0 {
+		days = defaultUsageRollupDays
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	filter := mongoDb.UsageRecordFilter{From: today.AddDate(0, 0, 1-days), To: now}
+	aggregates, err := s.usageRepo.Aggregate(ctx, filter, []string{"user", "apiKey", "provider", "model"}, "day", "UTC", 0)
+	if err != nil {
+		end(err)
+		s.logger.Error("usage rollup aggregate failed", zap.Error(err))
+		return 0, cErr.DatabaseError("mongodb aggregate usage failed")
+	}
+
+	rollups := make([]*model.UsageRollup, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		if aggregate.Group.Bucket == nil {
+			continue
+		}
+		rollups = append(rollups, &model.UsageRollup{
+			Day:              aggregate.Group.Bucket.UTC(),
+			UserID:           aggregate.Group.UserID,
+			APIKeyID:         aggregate.Group.APIKeyID,
+			Provider:         aggregate.Group.Provider,
+			Model:            aggregate.Group.Model,
+			Requests:         aggregate.Requests,
+			CachedRequests:   aggregate.CachedRequests,
+			TokensPrompt:     aggregate.TokensPrompt,
+			TokensCompletion: aggregate.TokensCompletion,
+			InputTokens:      aggregate.InputTokens,
+			OutputTokens:     aggregate.OutputTokens,
+			TokensTotal:      aggregate.TokensTotal,
+			Cost:             aggregate.Cost,
+		})
+	}
+	written, err := s.rollupRepo.UpsertMany(ctx, rollups)
+	if err != nil {
+		end(err)
+		return 0, cErr.DatabaseError("mongodb upsert usage rollups failed")
+	}
+	return written, nil
+}
+
+func toUsageRecord(usage fluentdModel.AIUsageLog) *model.UsageRecord {
+	timestamp, err := time.Parse(usageLoggedAtLayout, usage.LoggedAt)
+	if err != nil {
+		timestamp = time.Now().UTC()
+	}
+	return &model.UsageRecord{
+		Timestamp: timestamp,
+		Meta: model.UsageRecordMeta{
+			UserID:      usage.UserID,
+			APIKeyID:    usage.APIKeyID,
+			ProjectName: usage.ProjectName,
+			Provider:    usage.Provider,
+			Model:       usage.Model,
+			Endpoint:    usage.Endpoint,
+		},
+		RequestID:        usage.RequestID,
+		ExternalID:       usage.ExternalID,
+		DisplayName:      usage.DisplayName,
+		TokensPrompt:     usage.TokensPrompt,
+		TokensCompletion: usage.TokensCompletion,
+		TextToken:        usage.TextToken,
+		AudioToken:       usage.AudioToken,
+		ImageToken:       usage.ImageToken,
+		InputToken:       usage.InputToken,
+		OutputToken:      usage.OutputToken,
+		TokensTotal:      usage.TokensTotal,
+		TemplateName:     usage.TemplateName,
+		TemplateVersion:  usage.TemplateVersion,
+		Cached:           usage.Cached,
+		CacheType:        usage.CacheType,
+		Coalesced:        usage.Coalesced,
+	}
+}
+
+// parseUsageTime 接受 RFC3339 或 YYYY-MM-DD（UTC）；空字串回傳零值
+func parseUsageTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
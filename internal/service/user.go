@@ -19,12 +19,21 @@ import (
 )
 
 type UserService struct {
-	trace    *telemetry.Trace
-	userRepo *repository.UserRepository
+	trace       *telemetry.Trace
+	userRepo    *repository.UserRepository
+	entityCache *EntityCacheService
+	activity    *ActivityBufferService
+	audit       *AuditService
 }
 
-func NewUserService(trace *telemetry.Trace, userRepo *repository.UserRepository) *UserService {
-	return &UserService{trace: trace, userRepo: userRepo}
+func NewUserService(
+	trace *telemetry.Trace,
+	userRepo *repository.UserRepository,
+	entityCache *EntityCacheService,
+	activity *ActivityBufferService,
+	audit *AuditService,
+) *UserService {
+	return &UserService{trace: trace, userRepo: userRepo, entityCache: entityCache, activity: activity, audit: audit}
 }
 
 // 新增用戶（管理專用，input/output 皆為 DTO）
@@ -45,15 +54,16 @@ func (s *UserService) CreateUser(ctx context.Context, dto *dto.CreateUserDto) (*
 	if err != nil {
 		return nil, cErr.DatabaseError("database CreateUser error")
 	}
+	s.recordAudit(ctx, core.AuditActionUserCreated, created.ID, nil, created)
 	return modelToUserResponseDto(created), nil
 }
 
-// 依 id 查詢
+// 依 id 查詢（經文件快取）
 func (s *UserService) GetUserByID(ctx context.Context, id primitive.ObjectID) (*dto.UserResponseDto, error) {
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
-	user, err := s.userRepo.GetByID(ctx, id)
+	user, err := s.entityCache.GetUser(ctx, id)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, cErr.NotFound("user not found")
@@ -64,6 +74,23 @@ func (s *UserService) GetUserByID(ctx context.Context, id primitive.ObjectID) (*
 	return modelToUserResponseDto(user), nil
 }
 
+// GetUserByExternalID 以外部登入平台的使用者 ID 取得使用者（session 驗證用）
+func (s *UserService) GetUserByExternalID(ctx context.Context, externalID string) (*dto.UserResponseDto, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	user, err := s.userRepo.GetByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, cErr.NotFound("user not found")
+		}
+		end(err)
+		return nil, cErr.DatabaseError("database GetUserByExternalID error")
+	}
+
+	return modelToUserResponseDto(user), nil
+}
+
 // 管理後台列舉用戶（支援分頁、篩選，回傳 Response DTO）
 func (s *UserService) ListUsers(ctx context.Context, filter bson.M, page, size int64) ([]*dto.UserResponseDto, error) {
 	ctx, _, end := s.trace.WithSpan(ctx)
@@ -110,6 +137,7 @@ func (s *UserService) UpdateUserByID(ctx context.Context, id primitive.ObjectID,
 		update["status"] = *dto.Status
 	}
 
+	before := s.snapshot(ctx, id)
 	matchedCount, err := s.userRepo.UpdateByID(ctx, id, update)
 	if err != nil {
 		return cErr.DatabaseError("database UpdateUserByID error")
@@ -118,6 +146,8 @@ func (s *UserService) UpdateUserByID(ctx context.Context, id primitive.ObjectID,
 		notFound := cErr.NotFound(fmt.Sprintf("user with id %s not found", id.Hex()))
 		return notFound
 	}
+	s.entityCache.InvalidateUser(ctx, id)
+	s.recordAudit(ctx, core.AuditActionUserUpdated, id, before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -126,6 +156,7 @@ func (s *UserService) UpdateUserStatus(ctx context.Context, id primitive.ObjectI
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	matchedCount, err := s.userRepo.UpdateStatus(ctx, id, dto.Status)
 	if err != nil {
 		return cErr.DatabaseError("database UpdateUserStatus error")
@@ -134,20 +165,19 @@ func (s *UserService) UpdateUserStatus(ctx context.Context, id primitive.ObjectI
 		notFound := cErr.NotFound(fmt.Sprintf("user with id %s not found", id.Hex()))
 		return notFound
 	}
+	s.entityCache.InvalidateUser(ctx, id)
+	s.recordAudit(ctx, core.AuditActionUserStatusChanged, id, before, s.snapshot(ctx, id))
 	return nil
 }
+
+// 更新最後使用時間（啟用 ENTITY_CACHE 時批次寫入）
 func (s *UserService) UpdateUserLastSeen(ctx context.Context, id primitive.ObjectID) error {
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
-	matchedCount, err := s.userRepo.UpdateLastSeen(ctx, id, time.Now().UTC())
-	if err != nil {
+	if err := s.activity.TouchUser(ctx, id); err != nil {
 		return cErr.DatabaseError("database UpdateUserLastSeen error")
 	}
-	if matchedCount == 0 {
-		notFound := cErr.NotFound(fmt.Sprintf("user with id %s not found", id.Hex()))
-		return notFound
-	}
 	return nil
 }
 
@@ -156,6 +186,7 @@ func (s *UserService) UpdateUserRole(ctx context.Context, id primitive.ObjectID,
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	matchedCount, err := s.userRepo.UpdateRole(ctx, id, dto.Role)
 	if err != nil {
 		return cErr.DatabaseError("database UpdateUserRole error")
@@ -164,6 +195,8 @@ func (s *UserService) UpdateUserRole(ctx context.Context, id primitive.ObjectID,
 		notFound := cErr.NotFound(fmt.Sprintf("user with id %s not found", id.Hex()))
 		return notFound
 	}
+	s.entityCache.InvalidateUser(ctx, id)
+	s.recordAudit(ctx, core.AuditActionUserRoleChanged, id, before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -172,9 +205,14 @@ func (s *UserService) DeleteUser(ctx context.Context, id primitive.ObjectID) err
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userRepo.DeleteByID(ctx, id); err != nil {
 		return cErr.DatabaseError("database DeleteUser error")
 	}
+	s.entityCache.InvalidateUser(ctx, id)
+	if before != nil {
+		s.recordAudit(ctx, core.AuditActionUserDeleted, id, before, nil)
+	}
 	return nil
 }
 
@@ -183,10 +221,31 @@ func (s *UserService) ListInactiveUsers(ctx context.Context, sinceDays int) ([]*
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 	since := time.Now().Add(-time.Duration(sinceDays) * 24 * time.Hour)
-	filter := bson.M{"last_seen": bson.M{"$lt": since}}
+	filter := bson.M{"lastSeen": bson.M{"$lt": since}}
 	return s.ListUsers(ctx, filter, 0, 1000)
 }
 
+// snapshot 讀取稽核用的使用者快照（直接讀 DB，不經快取；讀取失敗回傳 nil）
+func (s *UserService) snapshot(ctx context.Context, id primitive.ObjectID) *model.User {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+func (s *UserService) recordAudit(ctx context.Context, action core.AuditAction, id primitive.ObjectID, before, after *model.User) {
+	entry := AuditEntry{Action: action, TargetType: core.AuditTargetUser, TargetID: id.Hex()}
+	// 避免 typed nil 被視為有快照
+	if before != nil {
+		entry.Before = before
+	}
+	if after != nil {
+		entry.After = after
+	}
+	s.audit.Record(ctx, entry)
+}
+
 func modelToUserResponseDto(m *model.User) *dto.UserResponseDto {
 	resp := &dto.UserResponseDto{
 		ID:          m.ID.Hex(),
@@ -195,8 +254,16 @@ func modelToUserResponseDto(m *model.User) *dto.UserResponseDto {
 		Email:       m.Email,
 		Role:        m.Role,
 		Status:      m.Status,
+		QuotaPolicy: quotaPolicyModelToDto(m.QuotaPolicy),
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
+
+		InactivityWarnedAt: m.InactivityWarnedAt,
+		SuspendReason:      m.SuspendReason,
+		SuspendedAt:        m.SuspendedAt,
+	}
+	if m.OrgID != nil {
+		resp.OrgID = m.OrgID.Hex()
 	}
 	if m.LastSeen == nil || !m.LastSeen.IsZero() {
 		resp.LastSeen = m.LastSeen
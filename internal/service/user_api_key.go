@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"interchange/config"
@@ -18,6 +19,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +30,10 @@ type UserAPIKeyService struct {
 	rateLimiterRepo *redisDb.RateLimiterRepository
 	config          *config.Configuration
 	logger          *zap.Logger
+	entityCache     *EntityCacheService
+	activity        *ActivityBufferService
+	audit           *AuditService
+	notification    *NotificationService
 }
 
 func NewUserAPIKeyService(
@@ -37,6 +43,10 @@ func NewUserAPIKeyService(
 	rateLimiterRepo *redisDb.RateLimiterRepository,
 	config *config.Configuration,
 	logger *zap.Logger,
+	entityCache *EntityCacheService,
+	activity *ActivityBufferService,
+	audit *AuditService,
+	notification *NotificationService,
 ) *UserAPIKeyService {
 	return &UserAPIKeyService{
 		trace:           trace,
@@ -45,6 +55,10 @@ func NewUserAPIKeyService(
 		rateLimiterRepo: rateLimiterRepo,
 		config:          config,
 		logger:          logger,
+		entityCache:     entityCache,
+		activity:        activity,
+		audit:           audit,
+		notification:    notification,
 	}
 }
 
@@ -58,6 +72,37 @@ func (s *UserAPIKeyService) Create(
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	created, err := s.create(ctx, userID, dto)
+	if err != nil {
+		return nil, err
+	}
+	return modelToUserAPIKeyResponseDto(created), nil
+}
+
+// CreateRevealed 同 Create，另回傳未遮蔽的 key 值；僅供 CLI 初始化管理者等無其他管道取得 key 的場合
+func (s *UserAPIKeyService) CreateRevealed(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	dto *dto.CreateUserAPIKeyDto,
+) (*dto.UserAPIKeyResponseDto, string, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	created, err := s.create(ctx, userID, dto)
+	if err != nil {
+		return nil, "", err
+	}
+	return modelToUserAPIKeyResponseDto(created), created.KeyValue, nil
+}
+
+func (s *UserAPIKeyService) create(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	dto *dto.CreateUserAPIKeyDto,
+) (*model.UserAPIKey, error) {
+	ctx, _, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
 	apiKeyID := primitive.NewObjectID()
 	apiKeyVal := dto.KeyValue
 	if apiKeyVal == "" {
@@ -78,6 +123,7 @@ func (s *UserAPIKeyService) Create(
 		ProviderAccess: providerAccessDtosToModels(dto.ProviderAccess),
 		CreatedAt:      time.Now(),
 	}
+	s.applyDefaultLifetime(key.ProviderAccess)
 
 	// 初始化每個 provider 的限流視窗與計數（Redis）
 	apiKeyHex := key.ID.Hex()
@@ -94,7 +140,8 @@ func (s *UserAPIKeyService) Create(
 	if err != nil {
 		return nil, cErr.DatabaseError("mongodb create api key failed")
 	}
-	return modelToUserAPIKeyResponseDto(created), nil
+	s.recordAudit(ctx, core.AuditActionAPIKeyCreated, core.AuditTargetAPIKey, created.ID, "", nil, created)
+	return created, nil
 }
 
 // ValidateKey 驗證外部送入的 API Key 字串是否合法並取回對應的 UserAPIKey 模型。
@@ -119,7 +166,7 @@ func (s *UserAPIKeyService) ValidateKey(ctx context.Context, apiKey string) (*mo
 		end(err)
 		return nil, cErr.UnauthorizedApiKey("invalid api key: user ID is not a valid ObjectID")
 	}
-	user, err := s.userRepo.GetByID(ctx, userID)
+	user, err := s.entityCache.GetUser(ctx, userID)
 	if err != nil {
 		return nil, cErr.DatabaseError("mongodb get user failed")
 	}
@@ -137,7 +184,7 @@ func (s *UserAPIKeyService) ValidateKey(ctx context.Context, apiKey string) (*mo
 		return nil, cErr.UnauthorizedApiKey("invalid api key: key ID is not a valid ObjectID")
 	}
 
-	apiKeyModel, err := s.userApiKeyRepo.GetByID(ctx, apiKeyID)
+	apiKeyModel, err := s.entityCache.GetAPIKey(ctx, apiKeyID)
 	if err != nil {
 		return nil, cErr.DatabaseError("mongodb get api key failed")
 	}
@@ -148,7 +195,9 @@ func (s *UserAPIKeyService) ValidateKey(ctx context.Context, apiKey string) (*mo
 	return apiKeyModel, nil
 }
 
-// ValidateProviderAccess 驗證 API Key 是否有指定 provider 的存取權限，且該權限為啟用中且未過期，更新狀態為過期的會自動標記。
+// ValidateProviderAccess 驗證 API Key 是否有指定 provider 的存取權限，且該權限為啟用中且未過期。
+// 已過期（狀態為 expired 或 expireTime 已到）回傳 API_KEY_EXPIRED；排程尚未標記的會順手標記為 expired。
+// 擁有者因閒置停用（key 帶 owner_inactive 標記）、provider 因閒置或異常用量停用、key 需重新驗證時回傳 FORBIDDEN。
 func (s *UserAPIKeyService) ValidateProviderAccess(
 	ctx context.Context,
 	data *model.UserAPIKey,
@@ -157,6 +206,57 @@ func (s *UserAPIKeyService) ValidateProviderAccess(
 	ctx, span, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 	now := time.Now().UTC()
+
+	// 異常用量：需擁有者以驗證碼重新驗證（/account/reverify）
+	if data.SuspendReason == core.SuspendReasonReverify {
+		cause := cErr.Forbidden("API key requires re-verification after unusual activity; confirm with the code sent to the owner via /account/reverify")
+		return nil, s.rejectAccess(span, end, "api_key_reverify_required", provider, data.ID, cause)
+	}
+
+	// 閒置停用：擁有者停用中的 key（擁有者已恢復時標記由排程移除，這裡直接放行）與閒置停用的 provider
+	if data.SuspendReason != "" {
+		if owner, err := s.entityCache.GetUser(ctx, data.UserID); err != nil || owner.Status != core.StatusActive {
+			cause := cErr.Forbidden("API key suspended: owner account is suspended")
+			return nil, s.rejectAccess(span, end, "api_key_suspended", provider, data.ID, cause)
+		}
+	}
+	if access := findProvider(data.ProviderAccess, provider); access != nil &&
+		access.Status == core.StatusSuspended && access.SuspendReason == core.SuspendReasonStale {
+		cause := cErr.Forbidden("Provider access suspended due to inactivity")
+		return nil, s.rejectAccess(span, end, "provider_access_inactive", provider, data.ID, cause)
+	}
+	if access := findProvider(data.ProviderAccess, provider); access != nil &&
+		access.Status == core.StatusSuspended && access.SuspendReason == core.SuspendReasonAnomaly {
+		cause := cErr.Forbidden("Provider access suspended due to unusual activity; contact an administrator")
+		return nil, s.rejectAccess(span, end, "provider_access_anomaly", provider, data.ID, cause)
+	}
+
+	if access := findProvider(data.ProviderAccess, provider); access != nil && providerAccessExpired(access, now) {
+		if access.Status == core.StatusActive {
+			// 標記失敗不影響本次回應，下一輪過期掃描會再處理
+			if _, err := s.userApiKeyRepo.ExpireProviderAccess(ctx, data.ID, provider, now); err != nil {
+				s.logger.Warn("mark provider access expired failed",
+					zap.String("apiKeyID", data.ID.Hex()),
+					zap.String("provider", string(provider)),
+					zap.Error(err),
+				)
+			} else {
+				s.entityCache.InvalidateAPIKey(ctx, data.ID)
+				s.recordExpired(ctx, data.ID, provider)
+				s.notification.emitAsync(ctx, keyExpiredMessage(ExpiredProviderAccess{
+					APIKeyID:   data.ID,
+					UserID:     data.UserID,
+					KeyName:    data.KeyName,
+					Provider:   provider,
+					ExpireTime: access.ExpireTime.UTC(),
+				}))
+			}
+		}
+
+		cause := cErr.APIKeyExpired("Provider access has expired")
+		return nil, s.rejectAccess(span, end, "provider_access_expired", provider, data.ID, cause)
+	}
+
 	providerAccess, err := getActiveProvider(data.ProviderAccess, provider)
 	if err != nil || providerAccess == nil {
 		s.trace.ApplyTraceAttributes(span, struct {
@@ -174,34 +274,6 @@ func (s *UserAPIKeyService) ValidateProviderAccess(
 		return nil, cErr.UnauthorizedApiKey("No active provider access found")
 	}
 
-	if providerAccess.ExpireTime != nil && providerAccess.ExpireTime.Before(now) {
-		err := cErr.UnauthorizedApiKey("Provider access has expired")
-		updateErr := s.UpdateProviderStatus(ctx, data.ID, providerAccess.Provider, string(core.StatusExpired))
-		if updateErr != nil {
-			s.trace.ApplyTraceAttributes(span, struct {
-				Status   string `trace:"auth.status"`
-				Provider string `trace:"auth.provider"`
-				Error    string `trace:"error"`
-			}{
-				Status:   "expire_mark_failed",
-				Provider: string(providerAccess.Provider),
-				Error:    updateErr.Error(),
-			})
-			end(updateErr)
-			return nil, updateErr
-		}
-
-		s.trace.ApplyTraceAttributes(span, struct {
-			Status   string `trace:"auth.status"`
-			Provider string `trace:"auth.provider"`
-		}{
-			Status:   "provider_access_expired",
-			Provider: string(providerAccess.Provider),
-		})
-		end(err)
-		return nil, err
-	}
-
 	// 成功：畫點
 	s.trace.ApplyTraceAttributes(span, struct {
 		Status       string `trace:"auth.status"`
@@ -216,6 +288,28 @@ func (s *UserAPIKeyService) ValidateProviderAccess(
 	return providerAccess, nil
 }
 
+// rejectAccess 記錄拒絕原因於 span 並結束 span，回傳給呼叫端的錯誤
+func (s *UserAPIKeyService) rejectAccess(
+	span trace.Span,
+	end func(error),
+	status string,
+	provider core.ProviderName,
+	apiKeyID primitive.ObjectID,
+	cause *cErr.Error,
+) error {
+	s.trace.ApplyTraceAttributes(span, struct {
+		Status   string `trace:"auth.status"`
+		Provider string `trace:"auth.provider"`
+		APIKeyID string `trace:"auth.api_key_id,omitempty"`
+	}{
+		Status:   status,
+		Provider: string(provider),
+		APIKeyID: apiKeyID.Hex(),
+	})
+	end(cause)
+	return cause
+}
+
 // GetByID 依 API Key ID 取得單一 API Key 的回應 DTO。
 func (s *UserAPIKeyService) GetByID(ctx context.Context, id primitive.ObjectID) (*dto.UserAPIKeyResponseDto, error) {
 	ctx, _, end := s.trace.WithSpan(ctx)
@@ -249,9 +343,14 @@ func (s *UserAPIKeyService) DeleteByID(ctx context.Context, id primitive.ObjectI
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.DeleteByID(ctx, id); err != nil {
 		return cErr.DatabaseError("mongodb delete api key failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	if before != nil {
+		s.recordAudit(ctx, core.AuditActionAPIKeyDeleted, core.AuditTargetAPIKey, id, "", before, nil)
+	}
 	return nil
 }
 
@@ -260,9 +359,17 @@ func (s *UserAPIKeyService) DeleteAllByUserID(ctx context.Context, userID primit
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	keys, err := s.userApiKeyRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return cErr.DatabaseError("mongodb list api keys failed")
+	}
 	if err := s.userApiKeyRepo.DeleteAllByUserID(ctx, userID); err != nil {
 		return cErr.DatabaseError("mongodb delete user api keys failed")
 	}
+	for _, key := range keys {
+		s.entityCache.InvalidateAPIKey(ctx, key.ID)
+		s.recordAudit(ctx, core.AuditActionAPIKeyDeleted, core.AuditTargetAPIKey, key.ID, "", key, nil)
+	}
 	return nil
 }
 
@@ -271,9 +378,12 @@ func (s *UserAPIKeyService) UpdateKeyName(ctx context.Context, id primitive.Obje
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateKeyName(ctx, id, keyName); err != nil {
 		return cErr.DatabaseError("mongodb update key name failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionAPIKeyRenamed, core.AuditTargetAPIKey, id, "", before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -282,9 +392,12 @@ func (s *UserAPIKeyService) UpdateKeyValue(ctx context.Context, id primitive.Obj
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateKeyValue(ctx, id, keyValue); err != nil {
 		return cErr.DatabaseError("mongodb update key value failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionAPIKeyRotated, core.AuditTargetAPIKey, id, "", before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -294,9 +407,12 @@ func (s *UserAPIKeyService) UpdateProviderAccessAll(ctx context.Context, id prim
 	defer end(nil)
 
 	models := providerAccessDtosToModels(access)
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateProviderAccessAll(ctx, id, models); err != nil {
 		return cErr.DatabaseError("mongodb update providerAccess failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionProviderAccessReplaced, core.AuditTargetAPIKey, id, "", before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -305,9 +421,12 @@ func (s *UserAPIKeyService) UpdateProviderStatus(ctx context.Context, id primiti
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateProviderStatus(ctx, id, provider, status); err != nil {
 		return cErr.DatabaseError("mongodb update key provider status failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionProviderAccessUpdated, core.AuditTargetProviderAccess, id, provider, before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -316,9 +435,12 @@ func (s *UserAPIKeyService) UpdateProviderLimitCount(ctx context.Context, id pri
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateProviderLimitCount(ctx, id, provider, limitCount); err != nil {
 		return cErr.DatabaseError("mongodb update limitCount failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionProviderAccessUpdated, core.AuditTargetProviderAccess, id, provider, before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -327,9 +449,12 @@ func (s *UserAPIKeyService) UpdateProviderUsedCount(ctx context.Context, id prim
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateProviderUsedCount(ctx, id, provider, usedCount); err != nil {
 		return cErr.DatabaseError("mongodb update usedCount failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionProviderAccessUpdated, core.AuditTargetProviderAccess, id, provider, before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -338,30 +463,211 @@ func (s *UserAPIKeyService) UpdateProviderLastResetAt(ctx context.Context, id pr
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateProviderLastResetAt(ctx, id, provider, t); err != nil {
 		return cErr.DatabaseError("mongodb update key lastResetAt failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionProviderAccessUpdated, core.AuditTargetProviderAccess, id, provider, before, s.snapshot(ctx, id))
 	return nil
 }
-func (s *UserAPIKeyService) UpdateProviderExpireTime(ctx context.Context, id primitive.ObjectID, t time.Time) error {
+
+// UpdateProviderExpireTime 更新指定 provider 的到期時間（nil 表示移除期限）。
+func (s *UserAPIKeyService) UpdateProviderExpireTime(ctx context.Context, id primitive.ObjectID, provider core.ProviderName, t *time.Time) error {
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
-	if err := s.userApiKeyRepo.UpdateExpireTime(ctx, id, t); err != nil {
+	before := s.snapshot(ctx, id)
+	if err := s.userApiKeyRepo.UpdateProviderExpireTime(ctx, id, provider, t, ""); err != nil {
 		return cErr.DatabaseError("mongodb update key expireTime failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionProviderAccessUpdated, core.AuditTargetProviderAccess, id, provider, before, s.snapshot(ctx, id))
 
 	return nil
 }
 
+// RenewProviderExpiry 延長或續期指定 provider 的到期時間；原本已過期的權限恢復為 active（其他狀態如 revoked 不變）。
+func (s *UserAPIKeyService) RenewProviderExpiry(
+	ctx context.Context,
+	id primitive.ObjectID,
+	provider core.ProviderName,
+	req *dto.RenewProviderExpiryDto,
+) (*dto.UserAPIKeyResponseDto, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+	span.SetAttributes(attribute.String("api_key.id", id.Hex()), attribute.String("provider", string(provider)))
+
+	choices := 0
+	if req.ExpireTime != nil {
+		choices++
+	}
+	if req.ExtendDays != nil {
+		choices++
+	}
+	if req.NoExpiry {
+		choices++
+	}
+	if choices != 1 {
+		return nil, cErr.BadRequestBody("exactly one of expireTime, extendDays or noExpiry is required")
+	}
+
+	key, err := s.userApiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, cErr.NotFound("api key not found")
+	}
+	access := findProvider(key.ProviderAccess, provider)
+	if access == nil {
+		return nil, cErr.NotFound(fmt.Sprintf("provider %s not found on api key", provider))
+	}
+
+	now := time.Now().UTC()
+	var expireTime *time.Time
+	switch {
+	case req.ExpireTime != nil:
+		if !req.ExpireTime.After(now) {
+			return nil, cErr.BadRequestBody("expireTime must be in the future")
+		}
+		t := req.ExpireTime.UTC()
+		expireTime = &t
+	case req.ExtendDays != nil:
+		base := now
+		if access.ExpireTime != nil && access.ExpireTime.After(now) {
+			base = access.ExpireTime.UTC()
+		}
+		t := base.AddDate(0, 0, *req.ExtendDays)
+		expireTime = &t
+	}
+
+	var status core.Status
+	if access.Status == core.StatusExpired {
+		status = core.StatusActive
+	}
+	if err := s.userApiKeyRepo.UpdateProviderExpireTime(ctx, id, provider, expireTime, status); err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("mongodb update key expireTime failed")
+	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+
+	updated, err := s.userApiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("mongodb get api key failed")
+	}
+	s.recordAudit(ctx, core.AuditActionProviderAccessUpdated, core.AuditTargetProviderAccess, id, provider, key, updated)
+	return modelToUserAPIKeyResponseDto(updated), nil
+}
+
+// ExpiredProviderAccess 過期掃描標記的單一 provider 權限
+type ExpiredProviderAccess struct {
+	APIKeyID   primitive.ObjectID
+	UserID     primitive.ObjectID
+	KeyName    string
+	Provider   core.ProviderName
+	ExpireTime time.Time
+}
+
+// ExpireDueKeys 將 expireTime 已到但仍為 active 的 provider 權限標記為 expired 並送出 key.expired 通知，
+// 回傳本次實際標記的項目（請求驗證時已順手標記並通知的不會重複回傳）；每輪最多處理 batchSize 把 Key。
+func (s *UserAPIKeyService) ExpireDueKeys(ctx context.Context, batchSize int64) ([]ExpiredProviderAccess, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	now := time.Now().UTC()
+	keys, err := s.userApiKeyRepo.ListExpiredActive(ctx, now, batchSize)
+	if err != nil {
+		end(err)
+		return nil, cErr.DatabaseError("mongodb list expired api keys failed")
+	}
+
+	var expired []ExpiredProviderAccess
+	for _, key := range keys {
+		marked := false
+		for _, access := range key.ProviderAccess {
+			if access.Status != core.StatusActive || !providerAccessExpired(&access, now) {
+				continue
+			}
+			ok, err := s.userApiKeyRepo.ExpireProviderAccess(ctx, key.ID, access.Provider, now)
+			if err != nil {
+				s.logger.Warn("expire provider access failed",
+					zap.String("apiKeyID", key.ID.Hex()),
+					zap.String("provider", string(access.Provider)),
+					zap.Error(err),
+				)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			marked = true
+			s.recordExpired(ctx, key.ID, access.Provider)
+			item := ExpiredProviderAccess{
+				APIKeyID:   key.ID,
+				UserID:     key.UserID,
+				KeyName:    key.KeyName,
+				Provider:   access.Provider,
+				ExpireTime: access.ExpireTime.UTC(),
+			}
+			if err := s.notification.Emit(ctx, keyExpiredMessage(item)); err != nil {
+				s.logger.Warn("emit key expired notification failed", zap.String("apiKeyID", key.ID.Hex()), zap.Error(err))
+			}
+			expired = append(expired, item)
+		}
+		if marked {
+			s.entityCache.InvalidateAPIKey(ctx, key.ID)
+		}
+	}
+	span.SetAttributes(attribute.Int("api_key.scanned", len(keys)), attribute.Int("api_key.expired", len(expired)))
+	return expired, nil
+}
+
+// ResetElapsedQuotaWindows 將限流視窗已結束的 provider 已用次數歸零（Mongo 的統計值；實際限流以 Redis 視窗為準）。
+// 批次更新不逐一清除文件快取，快取中的 usedCount 於 TTL 後更新。
+func (s *UserAPIKeyService) ResetElapsedQuotaWindows(ctx context.Context) (int64, error) {
+	ctx, span, end := s.trace.WithSpan(ctx)
+	defer end(nil)
+
+	now := time.Now().UTC()
+	var total int64
+	for _, period := range []core.LimitPeriod{core.LimitPeriodDaily, core.LimitPeriodWeekly, core.LimitPeriodMonthly, core.LimitPeriodYearly} {
+		windowStart := now.Add(-time.Duration(periodToDuration(period)) * time.Second)
+		modified, err := s.userApiKeyRepo.ResetElapsedProviderWindows(ctx, period, windowStart, now)
+		if err != nil {
+			end(err)
+			return total, cErr.DatabaseError("mongodb reset provider usage windows failed")
+		}
+		total += modified
+	}
+	span.SetAttributes(attribute.Int64("api_key.reset", total))
+	return total, nil
+}
+
+// applyDefaultLifetime 未指定 expireTime 的 provider 權限套用預設有效天數（APIKey.DefaultLifetimeDays）
+func (s *UserAPIKeyService) applyDefaultLifetime(accessList []model.ProviderAccess) {
+	days := s.config.APIKey.DefaultLifetimeDays
+	if days <= 0 {
+		return
+	}
+	expireTime := time.Now().UTC().AddDate(0, 0, days)
+	for i := range accessList {
+		if accessList[i].ExpireTime == nil {
+			accessList[i].ExpireTime = &expireTime
+		}
+	}
+}
+
 // UpdateProviderFields 以 map 方式局部更新指定 provider 欄位。
 func (s *UserAPIKeyService) UpdateProviderFields(ctx context.Context, id primitive.ObjectID, provider core.ProviderName, fields map[string]interface{}) error {
 	ctx, _, end := s.trace.WithSpan(ctx)
 	defer end(nil)
 
+	before := s.snapshot(ctx, id)
 	if err := s.userApiKeyRepo.UpdateProviderFields(ctx, id, provider, fields); err != nil {
 		return cErr.DatabaseError("mongodb partial update provider fields failed")
 	}
+	s.entityCache.InvalidateAPIKey(ctx, id)
+	s.recordAudit(ctx, core.AuditActionProviderAccessUpdated, core.AuditTargetProviderAccess, id, provider, before, s.snapshot(ctx, id))
 	return nil
 }
 
@@ -406,7 +712,7 @@ func (s *UserAPIKeyService) List(ctx context.Context, query core.UserAPIKeyQuery
 	return resp, nil
 }
 
-// Consume 消耗一次指定 provider 的限流配額（Redis + MongoDB 同步）。
+// Consume 消耗一次指定 provider 的限流配額（Redis + MongoDB 用量）。
 // 回傳：remaining（剩餘次數），或錯誤。
 func (s *UserAPIKeyService) Consume(
 	ctx context.Context,
@@ -469,7 +775,7 @@ func (s *UserAPIKeyService) Consume(
 		attribute.Int("remaining", remaining),
 	)
 
-	// 累計 MongoDB 使用次數
+	// 累計 MongoDB 使用次數（啟用 ENTITY_CACHE 時批次寫入）
 	objectID, oidErr := primitive.ObjectIDFromHex(apiKeyID)
 	if oidErr != nil {
 		err := cErr.BadRequestParams("invalid api key ID: not a valid ObjectID")
@@ -477,13 +783,89 @@ func (s *UserAPIKeyService) Consume(
 		return 0, err
 	}
 
-	if incrErr := s.userApiKeyRepo.IncrProviderUsedCount(ctx, objectID, providerAccess.Provider, 1); incrErr != nil {
+	if incrErr := s.activity.RecordKeyUsage(ctx, objectID, providerAccess.Provider); incrErr != nil {
 		err := cErr.DatabaseError(incrErr.Error())
 		return 0, err
 	}
 
 	return remaining, nil
 }
+
+// snapshot 讀取稽核用的 API Key 快照（直接讀 DB，不經快取；讀取失敗回傳 nil）
+func (s *UserAPIKeyService) snapshot(ctx context.Context, id primitive.ObjectID) *model.UserAPIKey {
+	key, err := s.userApiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+func (s *UserAPIKeyService) recordAudit(
+	ctx context.Context,
+	action core.AuditAction,
+	targetType core.AuditTargetType,
+	id primitive.ObjectID,
+	provider core.ProviderName,
+	before, after *model.UserAPIKey,
+) {
+	entry := AuditEntry{Action: action, TargetType: targetType, TargetID: id.Hex(), Provider: provider}
+	// 避免 typed nil 被視為有快照
+	if before != nil {
+		entry.Before = before
+	}
+	if after != nil {
+		entry.After = after
+	}
+	s.audit.Record(ctx, entry)
+}
+
+// keyExpiredMessage key.expired 通知；排程與請求驗證共用同一個 DedupKey，同一次到期只送一次
+func keyExpiredMessage(item ExpiredProviderAccess) NotificationMessage {
+	return NotificationMessage{
+		Event:   core.NotificationEventKeyExpired,
+		Subject: fmt.Sprintf("API key %s (%s) expired at %s", item.KeyName, item.Provider, item.ExpireTime.Format(time.RFC3339)),
+		KeyID:   item.APIKeyID.Hex(),
+		UserID:  item.UserID.Hex(),
+		Data: map[string]interface{}{
+			"keyName":    item.KeyName,
+			"provider":   item.Provider,
+			"expireTime": item.ExpireTime,
+		},
+		DedupKey: "expired:" + item.APIKeyID.Hex() + ":" + string(item.Provider) + ":" + strconv.FormatInt(item.ExpireTime.Unix(), 10),
+		DedupTTL: 24 * time.Hour,
+	}
+}
+
+// recordExpired 到期標記（排程或請求驗證時）由系統寫入
+func (s *UserAPIKeyService) recordExpired(ctx context.Context, id primitive.ObjectID, provider core.ProviderName) {
+	s.audit.Record(ctx, AuditEntry{
+		Actor:      AuditActor{Type: core.AuditActorSystem},
+		Action:     core.AuditActionProviderAccessExpired,
+		TargetType: core.AuditTargetProviderAccess,
+		TargetID:   id.Hex(),
+		Provider:   provider,
+		Before:     map[string]interface{}{"status": core.StatusActive},
+		After:      map[string]interface{}{"status": core.StatusExpired},
+	})
+}
+
+func findProvider(accessList []model.ProviderAccess, provider core.ProviderName) *model.ProviderAccess {
+	for i := range accessList {
+		if accessList[i].Provider == provider {
+			return &accessList[i]
+		}
+	}
+	return nil
+}
+
+// providerAccessExpired 狀態已標記為 expired，或 active 但 expireTime 已到
+func providerAccessExpired(access *model.ProviderAccess, now time.Time) bool {
+	if access.Status == core.StatusExpired {
+		return true
+	}
+	return access.Status == core.StatusActive && access.ExpireTime != nil && !access.ExpireTime.After(now)
+}
+
 func getActiveProvider(accessList []model.ProviderAccess, provider core.ProviderName) (*model.ProviderAccess, error) {
 	for _, acc := range accessList {
 		if acc.Provider == provider && acc.Status == core.StatusActive {
@@ -513,6 +895,12 @@ func providerAccessDtosToModels(dto []dto.ProviderAccessDto) []model.ProviderAcc
 			ApiScopes:   providerAccess.ApiScopes,
 			LastSeen:    providerAccess.LastSeen,
 			ExpireTime:  providerAccess.ExpireTime,
+			Concurrency: concurrencyPolicyDtoToModel(providerAccess.Concurrency),
+			Models:      modelPolicyDtoToModel(providerAccess.Models),
+			Request:     requestPolicyDtoToModel(providerAccess.Request),
+			Prompt:      promptPolicyDtoToModel(providerAccess.Prompt),
+			Cache:       cachePolicyDtoToModel(providerAccess.Cache),
+			Semantic:    semanticCachePolicyDtoToModel(providerAccess.Semantic),
 		}
 	}
 	return result
@@ -533,11 +921,164 @@ func providerAccessModelsToDtos(model []model.ProviderAccess) []dto.ProviderAcce
 			ApiScopes:   providerAccess.ApiScopes,
 			LastSeen:    providerAccess.LastSeen,
 			ExpireTime:  providerAccess.ExpireTime,
+			Concurrency: concurrencyPolicyModelToDto(providerAccess.Concurrency),
+			Models:      modelPolicyModelToDto(providerAccess.Models),
+			Request:     requestPolicyModelToDto(providerAccess.Request),
+			Prompt:      promptPolicyModelToDto(providerAccess.Prompt),
+			Cache:       cachePolicyModelToDto(providerAccess.Cache),
+			Semantic:    semanticCachePolicyModelToDto(providerAccess.Semantic),
+
+			InactivityWarnedAt: providerAccess.InactivityWarnedAt,
+			SuspendReason:      providerAccess.SuspendReason,
 		}
 	}
 	return result
 }
 
+// ConcurrencyPolicy DTO to model（未指定 mode 預設 reject）
+func concurrencyPolicyDtoToModel(policy *dto.ConcurrencyPolicyDto) *model.ConcurrencyPolicy {
+	if policy == nil {
+		return nil
+	}
+	mode := policy.Mode
+	if mode == "" {
+		mode = core.ConcurrencyModeReject
+	}
+	return &model.ConcurrencyPolicy{
+		MaxInFlight:         policy.MaxInFlight,
+		UpstreamMaxInFlight: policy.UpstreamMaxInFlight,
+		Mode:                mode,
+		QueueTimeoutMs:      policy.QueueTimeoutMs,
+		Priority:            policy.Priority,
+		Weight:              policy.Weight,
+	}
+}
+
+// ConcurrencyPolicy model to DTO
+func concurrencyPolicyModelToDto(policy *model.ConcurrencyPolicy) *dto.ConcurrencyPolicyDto {
+	if policy == nil {
+		return nil
+	}
+	return &dto.ConcurrencyPolicyDto{
+		MaxInFlight:         policy.MaxInFlight,
+		UpstreamMaxInFlight: policy.UpstreamMaxInFlight,
+		Mode:                policy.Mode,
+		QueueTimeoutMs:      policy.QueueTimeoutMs,
+		Priority:            policy.Priority,
+		Weight:              policy.Weight,
+	}
+}
+
+// ModelPolicy DTO to model
+func modelPolicyDtoToModel(policy *dto.ModelPolicyDto) *model.ModelPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &model.ModelPolicy{
+		Allow:   policy.Allow,
+		Deny:    policy.Deny,
+		Aliases: policy.Aliases,
+	}
+}
+
+// ModelPolicy model to DTO
+func modelPolicyModelToDto(policy *model.ModelPolicy) *dto.ModelPolicyDto {
+	if policy == nil {
+		return nil
+	}
+	return &dto.ModelPolicyDto{
+		Allow:   policy.Allow,
+		Deny:    policy.Deny,
+		Aliases: policy.Aliases,
+	}
+}
+
+// RequestPolicy DTO to model（未指定 action 預設 clamp）
+func requestPolicyDtoToModel(policy *dto.RequestPolicyDto) *model.RequestPolicy {
+	if policy == nil {
+		return nil
+	}
+	limits := make(map[string]model.NumericLimit, len(policy.Limits))
+	for field, limit := range policy.Limits {
+		action := limit.Action
+		if action == "" {
+			action = core.LimitActionClamp
+		}
+		limits[field] = model.NumericLimit{Min: limit.Min, Max: limit.Max, Action: action}
+	}
+	return &model.RequestPolicy{
+		Limits:  limits,
+		Allowed: policy.Allowed,
+		Force:   policy.Force,
+		Strip:   policy.Strip,
+	}
+}
+
+// RequestPolicy model to DTO
+func requestPolicyModelToDto(policy *model.RequestPolicy) *dto.RequestPolicyDto {
+	if policy == nil {
+		return nil
+	}
+	limits := make(map[string]dto.NumericLimitDto, len(policy.Limits))
+	for field, limit := range policy.Limits {
+		limits[field] = dto.NumericLimitDto{Min: limit.Min, Max: limit.Max, Action: limit.Action}
+	}
+	return &dto.RequestPolicyDto{
+		Limits:  limits,
+		Allowed: policy.Allowed,
+		Force:   policy.Force,
+		Strip:   policy.Strip,
+	}
+}
+
+// PromptPolicy DTO to model
+func promptPolicyDtoToModel(policy *dto.PromptPolicyDto) *model.PromptPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &model.PromptPolicy{Prepend: policy.Prepend, Append: policy.Append}
+}
+
+// PromptPolicy model to DTO
+func promptPolicyModelToDto(policy *model.PromptPolicy) *dto.PromptPolicyDto {
+	if policy == nil {
+		return nil
+	}
+	return &dto.PromptPolicyDto{Prepend: policy.Prepend, Append: policy.Append}
+}
+
+// CachePolicy DTO to model
+func cachePolicyDtoToModel(policy *dto.CachePolicyDto) *model.CachePolicy {
+	if policy == nil {
+		return nil
+	}
+	return &model.CachePolicy{Enabled: policy.Enabled, TTLSeconds: policy.TTLSeconds, Namespace: policy.Namespace}
+}
+
+// CachePolicy model to DTO
+func cachePolicyModelToDto(policy *model.CachePolicy) *dto.CachePolicyDto {
+	if policy == nil {
+		return nil
+	}
+	return &dto.CachePolicyDto{Enabled: policy.Enabled, TTLSeconds: policy.TTLSeconds, Namespace: policy.Namespace}
+}
+
+// SemanticCachePolicy DTO to model
+func semanticCachePolicyDtoToModel(policy *dto.SemanticCachePolicyDto) *model.SemanticCachePolicy {
+	if policy == nil {
+		return nil
+	}
+	return &model.SemanticCachePolicy{Enabled: policy.Enabled, Threshold: policy.Threshold, Namespace: policy.Namespace}
+}
+
+// SemanticCachePolicy model to DTO
+func semanticCachePolicyModelToDto(policy *model.SemanticCachePolicy) *dto.SemanticCachePolicyDto {
+	if policy == nil {
+		return nil
+	}
+	return &dto.SemanticCachePolicyDto{Enabled: policy.Enabled, Threshold: policy.Threshold, Namespace: policy.Namespace}
+}
+
 // UserAPIKey model to response DTO
 func modelToUserAPIKeyResponseDto(m *model.UserAPIKey) *dto.UserAPIKeyResponseDto {
 	return &dto.UserAPIKeyResponseDto{
@@ -546,6 +1087,8 @@ func modelToUserAPIKeyResponseDto(m *model.UserAPIKey) *dto.UserAPIKeyResponseDt
 		KeyName:        m.KeyName,
 		KeyValue:       maskKey(m.KeyValue),
 		ProviderAccess: providerAccessModelsToDtos(m.ProviderAccess),
+		SuspendReason:  m.SuspendReason,
+		SuspendedAt:    m.SuspendedAt,
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
 	}
@@ -14,6 +14,11 @@ type Metric struct {
 	HttpRequestDuration *prometheus.HistogramVec
 	ProxySuccessTotal   *prometheus.CounterVec
 	ProxyFailTotal      *prometheus.CounterVec
+	UpstreamQueueDepth  *prometheus.GaugeVec
+	UpstreamQueueWait   *prometheus.HistogramVec
+	UpstreamThrottled   *prometheus.CounterVec
+	SemanticCacheLookup *prometheus.CounterVec
+	CoalescedRequests   *prometheus.CounterVec
 	config              *config.Configuration
 }
 
@@ -57,6 +62,42 @@ func NewMetric(config *config.Configuration) *Metric {
 			},
 			labelNames(core.MetricLabelReason),
 		),
+		UpstreamQueueDepth: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: config.App.Name + "_" + string(core.MetricUpstreamQueueDepth),
+				Help: "Requests waiting in the gateway queue per upstream credential",
+			},
+			labelNames(core.MetricLabelProvider, core.MetricLabelUpstream, core.MetricLabelTier),
+		),
+		UpstreamQueueWait: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    config.App.Name + "_" + string(core.MetricUpstreamQueueWait),
+				Help:    "Time spent waiting in the gateway queue (seconds)",
+				Buckets: buckets,
+			},
+			labelNames(core.MetricLabelProvider, core.MetricLabelTier, core.MetricLabelOutcome),
+		),
+		UpstreamThrottled: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: config.App.Name + "_" + string(core.MetricUpstreamThrottled),
+				Help: "Upstream 429 responses that paused the gateway queue",
+			},
+			labelNames(core.MetricLabelUpstream),
+		),
+		SemanticCacheLookup: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: config.App.Name + "_" + string(core.MetricSemanticCacheLookup),
+				Help: "Semantic cache lookups by outcome (hit / miss / error)",
+			},
+			labelNames(core.MetricLabelProvider, core.MetricLabelOutcome),
+		),
+		CoalescedRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: config.App.Name + "_" + string(core.MetricCoalescedRequests),
+				Help: "Upstream calls by coalescing role (leader / local / remote / fallback)",
+			},
+			labelNames(core.MetricLabelProvider, core.MetricLabelOutcome),
+		),
 	}
 }
 